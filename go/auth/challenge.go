@@ -0,0 +1,258 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	libkb "github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	context "golang.org/x/net/context"
+)
+
+const (
+	challengeVersion  = 1
+	challengeNonceLen = 16
+
+	// DefaultChallengeLifetime is how long a challenge can be answered.
+	DefaultChallengeLifetime = 2 * time.Minute
+)
+
+// challengePrefix is prepended to a challenge before it's signed, so that
+// a signature over a challenge can't pass for any other signature made
+// with the device key, and vice versa.
+const challengePrefix = "Keybase sign-in challenge\n"
+
+// Challenge is what a site asks a Keybase user to sign to sign in. It
+// travels as the string that Encode makes, and that string, not the
+// fields, is what's signed.
+type Challenge struct {
+	Version int    `json:"v"`
+	Site    string `json:"site"`
+	Nonce   string `json:"nonce"`
+	Issued  int64  `json:"issued"`
+	Expires int64  `json:"expires"`
+}
+
+// Encode makes the string to give the user to sign.
+func (c Challenge) Encode() (string, error) {
+	return encodeToken(c)
+}
+
+// ParseChallenge undoes Encode.
+func ParseChallenge(s string) (*Challenge, error) {
+	var c Challenge
+	if err := decodeToken(s, &c); err != nil {
+		return nil, BadChallengeError{"can't parse the challenge: " + err.Error()}
+	}
+	if c.Version != challengeVersion {
+		return nil, BadChallengeError{"unknown challenge version"}
+	}
+	return &c, nil
+}
+
+// ChallengeMessage is what the user signs with their device key, via
+// the service's signED25519, to answer challenge.
+func ChallengeMessage(challenge string) []byte {
+	return []byte(challengePrefix + challenge)
+}
+
+// ChallengeResponse is a user's answer to a challenge: who they are, and
+// their signature of the challenge with one of their sibkeys.
+type ChallengeResponse struct {
+	Challenge string       `json:"challenge"`
+	UID       keybase1.UID `json:"uid"`
+	Username  string       `json:"username"`
+	KID       keybase1.KID `json:"kid"`
+	Sig       string       `json:"sig"`
+}
+
+// NewChallengeResponse makes the response to challenge from the
+// signature of ChallengeMessage(challenge).
+func NewChallengeResponse(challenge string, uid keybase1.UID, username libkb.NormalizedUsername, sig keybase1.ED25519SignatureInfo) ChallengeResponse {
+	return ChallengeResponse{
+		Challenge: challenge,
+		UID:       uid,
+		Username:  username.String(),
+		KID:       libkb.NaclSigningKeyPublic(sig.PublicKey).GetKID(),
+		Sig:       hex.EncodeToString(sig.Sig[:]),
+	}
+}
+
+// Encode makes the string to give back to the site.
+func (r ChallengeResponse) Encode() (string, error) {
+	return encodeToken(r)
+}
+
+// ParseChallengeResponse undoes Encode.
+func ParseChallengeResponse(s string) (*ChallengeResponse, error) {
+	var r ChallengeResponse
+	if err := decodeToken(s, &r); err != nil {
+		return nil, BadChallengeError{"can't parse the response: " + err.Error()}
+	}
+	return &r, nil
+}
+
+// verifySig checks that the response's signature is of its challenge,
+// by its KID.
+func (r ChallengeResponse) verifySig() error {
+	if err := verifyEdDSA(r.KID, r.Sig, ChallengeMessage(r.Challenge)); err != nil {
+		return BadChallengeError{err.Error()}
+	}
+	return nil
+}
+
+// verifyEdDSA checks that sig, in hex, is a signature of msg by kid.
+func verifyEdDSA(kid keybase1.KID, sig string, msg []byte) error {
+	key := libkb.KIDToNaclSigningKeyPublic(kid.ToBytes())
+	if key == nil {
+		return errors.New("the key isn't an EdDSA key")
+	}
+	b, err := hex.DecodeString(sig)
+	var s libkb.NaclSignature
+	if err != nil || len(b) != len(s) {
+		return errors.New("can't parse the signature")
+	}
+	copy(s[:], b)
+	if !key.Verify(msg, &s) {
+		return errors.New("bad signature")
+	}
+	return nil
+}
+
+// CredentialChecker checks that a (uid, username, kid) triple is
+// current.  A CredentialAuthority is one.
+type CredentialChecker interface {
+	Check(ctx context.Context, uid keybase1.UID, username libkb.NormalizedUsername, kid keybase1.KID) error
+}
+
+// Challenger issues challenges for a site, and verifies the responses,
+// so that a site's server can let Keybase users sign in with their
+// devices instead of passwords.  Each challenge can be answered once,
+// before it expires, by the same Challenger that issued it.
+type Challenger struct {
+	sync.Mutex
+	site     string
+	checker  CredentialChecker
+	lifetime time.Duration
+	pending  map[string]time.Time
+	now      func() time.Time
+}
+
+// NewChallenger makes a Challenger for site that checks users' keys with
+// checker, usually the server's CredentialAuthority.  A zero lifetime
+// is DefaultChallengeLifetime.
+func NewChallenger(site string, checker CredentialChecker, lifetime time.Duration) *Challenger {
+	if lifetime == 0 {
+		lifetime = DefaultChallengeLifetime
+	}
+	return &Challenger{
+		site:     site,
+		checker:  checker,
+		lifetime: lifetime,
+		pending:  make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Issue makes a new challenge to give to a user.
+func (c *Challenger) Issue() (string, error) {
+	var nonce [challengeNonceLen]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", err
+	}
+	now := c.now()
+	expires := now.Add(c.lifetime)
+	ch := Challenge{
+		Version: challengeVersion,
+		Site:    c.site,
+		Nonce:   hex.EncodeToString(nonce[:]),
+		Issued:  now.Unix(),
+		Expires: expires.Unix(),
+	}
+	s, err := ch.Encode()
+	if err != nil {
+		return "", err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.clean(now)
+	c.pending[ch.Nonce] = expires
+	return s, nil
+}
+
+// Verify checks a user's response to a challenge from Issue, and
+// returns who they are if it's good.  The challenge is used up either
+// way, once the signature checks out.
+func (c *Challenger) Verify(ctx context.Context, response string) (keybase1.UID, libkb.NormalizedUsername, error) {
+	r, err := ParseChallengeResponse(response)
+	if err != nil {
+		return "", "", err
+	}
+	ch, err := ParseChallenge(r.Challenge)
+	if err != nil {
+		return "", "", err
+	}
+	if ch.Site != c.site {
+		return "", "", BadChallengeError{"the challenge is for " + ch.Site}
+	}
+	if !c.now().Before(time.Unix(ch.Expires, 0)) {
+		return "", "", ExpiredChallengeError{}
+	}
+	if err := r.verifySig(); err != nil {
+		return "", "", err
+	}
+	if err := c.consume(ch.Nonce); err != nil {
+		return "", "", err
+	}
+
+	username := libkb.NewNormalizedUsername(r.Username)
+	if err := c.checker.Check(ctx, r.UID, username, r.KID); err != nil {
+		return "", "", err
+	}
+	return r.UID, username, nil
+}
+
+// consume uses up the challenge with nonce, which has to be pending.
+func (c *Challenger) consume(nonce string) error {
+	c.Lock()
+	defer c.Unlock()
+	now := c.now()
+	c.clean(now)
+	if _, ok := c.pending[nonce]; !ok {
+		return ReplayedChallengeError{}
+	}
+	delete(c.pending, nonce)
+	return nil
+}
+
+// clean forgets the challenges that have expired.  Call it with the
+// Challenger locked.
+func (c *Challenger) clean(now time.Time) {
+	for nonce, expires := range c.pending {
+		if !now.Before(expires) {
+			delete(c.pending, nonce)
+		}
+	}
+}
+
+func encodeToken(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeToken(s string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
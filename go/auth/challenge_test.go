@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	libkb "github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	context "golang.org/x/net/context"
+)
+
+// testChecker is a CredentialChecker that knows one user's keys.
+type testChecker struct {
+	user   *testUser
+	checks int
+}
+
+func (c *testChecker) Check(_ context.Context, uid keybase1.UID, username libkb.NormalizedUsername, kid keybase1.KID) error {
+	c.checks++
+	if uid != c.user.uid || !username.Eq(c.user.username) {
+		return BadUsernameError{c.user.username, username}
+	}
+	for _, k := range c.user.keys {
+		if k.Equal(kid) {
+			return nil
+		}
+	}
+	return BadKeyError{uid, kid}
+}
+
+type challengeSetup struct {
+	user    *testUser
+	key     libkb.NaclSigningKeyPair
+	checker *testChecker
+	ch      *Challenger
+	now     time.Time
+}
+
+func newChallengeSetup(t *testing.T) *challengeSetup {
+	key, err := libkb.GenerateNaclSigningKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := newTestUser(1)
+	user.keys[0] = key.GetKID()
+	s := &challengeSetup{
+		user:    user,
+		key:     key,
+		checker: &testChecker{user: user},
+		now:     time.Unix(1440000000, 0),
+	}
+	s.ch = NewChallenger("example.com", s.checker, time.Minute)
+	s.ch.now = func() time.Time { return s.now }
+	return s
+}
+
+// answer answers challenge as the signED25519 RPC would.
+func (s *challengeSetup) answer(t *testing.T, challenge string) string {
+	sig := *s.key.Private.Sign(ChallengeMessage(challenge))
+	r := NewChallengeResponse(challenge, s.user.uid, s.user.username, keybase1.ED25519SignatureInfo{
+		Sig:       keybase1.ED25519Signature(sig),
+		PublicKey: keybase1.ED25519PublicKey(s.key.Public),
+	})
+	ret, err := r.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func (s *challengeSetup) issue(t *testing.T) string {
+	challenge, err := s.ch.Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return challenge
+}
+
+func TestChallengeSignIn(t *testing.T) {
+	s := newChallengeSetup(t)
+	challenge := s.issue(t)
+
+	c, err := ParseChallenge(challenge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Site != "example.com" || c.Expires != s.now.Add(time.Minute).Unix() {
+		t.Errorf("bad challenge: %+v", c)
+	}
+
+	uid, username, err := s.ch.Verify(context.Background(), s.answer(t, challenge))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid != s.user.uid || !username.Eq(s.user.username) {
+		t.Errorf("signed in as %s (%s), expected %s (%s)", username, uid, s.user.username, s.user.uid)
+	}
+	if s.checker.checks != 1 {
+		t.Errorf("the key was checked %d times", s.checker.checks)
+	}
+}
+
+func TestChallengeReplay(t *testing.T) {
+	s := newChallengeSetup(t)
+	response := s.answer(t, s.issue(t))
+	if _, _, err := s.ch.Verify(context.Background(), response); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.ch.Verify(context.Background(), response); err != (ReplayedChallengeError{}) {
+		t.Errorf("replaying a response: got %v", err)
+	}
+
+	// Nor will a challenger answer for challenges it didn't issue.
+	other := NewChallenger("example.com", s.checker, time.Minute)
+	other.now = s.ch.now
+	if _, _, err := other.Verify(context.Background(), s.answer(t, s.issue(t))); err != (ReplayedChallengeError{}) {
+		t.Errorf("answering another challenger's challenge: got %v", err)
+	}
+}
+
+func TestChallengeExpiry(t *testing.T) {
+	s := newChallengeSetup(t)
+	challenge := s.issue(t)
+	s.now = s.now.Add(time.Minute)
+	if _, _, err := s.ch.Verify(context.Background(), s.answer(t, challenge)); err != (ExpiredChallengeError{}) {
+		t.Errorf("answering an expired challenge: got %v", err)
+	}
+
+	// Expired challenges are forgotten when the next is issued.
+	s.issue(t)
+	if n := len(s.ch.pending); n != 1 {
+		t.Errorf("%d challenges pending, expected 1", n)
+	}
+}
+
+func TestChallengeBadResponses(t *testing.T) {
+	s := newChallengeSetup(t)
+
+	// A response for another site.
+	elsewhere := NewChallenger("example.org", s.checker, time.Minute)
+	challenge, err := elsewhere.Issue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.ch.Verify(context.Background(), s.answer(t, challenge)); err == nil {
+		t.Error("a response for another site was accepted")
+	}
+
+	// A signature of another challenge.
+	challenge = s.issue(t)
+	r, err := ParseChallengeResponse(s.answer(t, s.issue(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Challenge = challenge
+	response, err := r.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.ch.Verify(context.Background(), response); err == nil {
+		t.Error("a signature of another challenge was accepted")
+	}
+
+	// A good signature by a key that isn't the user's any more.
+	s.user.keys[0] = genKID()
+	if _, _, err := s.ch.Verify(context.Background(), s.answer(t, challenge)); err == nil {
+		t.Error("a revoked key was accepted")
+	}
+
+	if _, _, err := s.ch.Verify(context.Background(), "not a response"); err == nil {
+		t.Error("garbage was accepted")
+	}
+}
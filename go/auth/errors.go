@@ -33,3 +33,40 @@ type BadKeyError struct {
 func (e BadKeyError) Error() string {
 	return fmt.Sprintf("Bad key error: %s not active for %s", e.kid, e.uid)
 }
+
+// BadChallengeError is raised when a response to a sign-in challenge
+// can't be parsed, is for another site, or isn't signed properly.
+type BadChallengeError struct {
+	msg string
+}
+
+func (e BadChallengeError) Error() string {
+	return "bad challenge response: " + e.msg
+}
+
+// ExpiredChallengeError is raised when a sign-in challenge is answered
+// too late.
+type ExpiredChallengeError struct{}
+
+func (e ExpiredChallengeError) Error() string {
+	return "the challenge has expired"
+}
+
+// ReplayedChallengeError is raised when a sign-in challenge was already
+// answered, or wasn't issued by this Challenger.
+type ReplayedChallengeError struct{}
+
+func (e ReplayedChallengeError) Error() string {
+	return "the challenge was already answered, or wasn't issued here"
+}
+
+// BadRequestTokenError is raised when a request's signed token is
+// missing, can't be parsed, isn't signed properly, is for another
+// request, or was already used.
+type BadRequestTokenError struct {
+	msg string
+}
+
+func (e BadRequestTokenError) Error() string {
+	return "bad request token: " + e.msg
+}
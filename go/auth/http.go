@@ -0,0 +1,272 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	libkb "github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	context "golang.org/x/net/context"
+)
+
+const (
+	requestNonceLen = 16
+
+	// DefaultRequestWindow is how far a signed request's time can be
+	// from the server's.
+	DefaultRequestWindow = time.Minute
+
+	// maxRequestNonces is how many used tokens a RequestVerifier keeps
+	// track of at once.  Once it's full, requests are turned away until
+	// some of the tokens expire.
+	maxRequestNonces = 100000
+
+	// authScheme is the scheme of the Authorization header that carries
+	// a signed request token.
+	authScheme = "Keybase"
+)
+
+// requestPrefix is prepended to a request's payload before it's signed,
+// to keep it apart from everything else the device key signs.
+const requestPrefix = "Keybase signed request\n"
+
+// requestPayload is what the user signs for each request: who they are,
+// when, and a hash of the request's method, host and path.
+type requestPayload struct {
+	UID      keybase1.UID `json:"uid"`
+	Username string       `json:"username"`
+	Time     int64        `json:"time"`
+	Nonce    string       `json:"nonce"`
+	Request  string       `json:"request"`
+}
+
+// requestToken is the signed payload, as it travels in the Authorization
+// header.  The KID is left out of the payload, since the signature is
+// only good under the right key anyway.
+type requestToken struct {
+	Payload string       `json:"payload"`
+	KID     keybase1.KID `json:"kid"`
+	Sig     string       `json:"sig"`
+}
+
+// requestHash hashes the parts of a request that its token is good for.
+// The body isn't signed, so requests should go over TLS.
+func requestHash(method, host, requestURI string) string {
+	h := sha256.Sum256([]byte(method + "\n" + host + "\n" + requestURI))
+	return hex.EncodeToString(h[:])
+}
+
+// User is who signed a request that a RequestVerifier let through.
+type User struct {
+	UID      keybase1.UID
+	Username libkb.NormalizedUsername
+	KID      keybase1.KID
+}
+
+type contextKey int
+
+const userKey contextKey = 0
+
+// WithUser returns a copy of ctx that carries user.
+func WithUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// UserFromContext returns the user that a RequestVerifier's handler put
+// in a request's context.
+func UserFromContext(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(userKey).(User)
+	return user, ok
+}
+
+// RequestVerifier checks the signed request tokens that a RoundTripper
+// adds to requests, so that a server knows which Keybase user, on which
+// of their current devices, sent each one.  Each token is good for one
+// request, within the window of the time it was made.
+type RequestVerifier struct {
+	sync.Mutex
+	checker CredentialChecker
+	window  time.Duration
+	seen    map[string]time.Time // by KID and nonce
+	maxSeen int
+	now     func() time.Time
+}
+
+// NewRequestVerifier makes a RequestVerifier that checks users' keys
+// with checker, usually the server's CredentialAuthority.  A zero
+// window is DefaultRequestWindow.
+func NewRequestVerifier(checker CredentialChecker, window time.Duration) *RequestVerifier {
+	if window == 0 {
+		window = DefaultRequestWindow
+	}
+	return &RequestVerifier{
+		checker: checker,
+		window:  window,
+		seen:    make(map[string]time.Time),
+		maxSeen: maxRequestNonces,
+		now:     time.Now,
+	}
+}
+
+// Verify checks r's token, and returns who signed it.
+func (v *RequestVerifier) Verify(r *http.Request) (*User, error) {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, authScheme+" ") {
+		return nil, BadRequestTokenError{"no Keybase token"}
+	}
+	var token requestToken
+	if err := decodeToken(strings.TrimPrefix(header, authScheme+" "), &token); err != nil {
+		return nil, BadRequestTokenError{"can't parse the token: " + err.Error()}
+	}
+	if err := verifyEdDSA(token.KID, token.Sig, []byte(requestPrefix+token.Payload)); err != nil {
+		return nil, BadRequestTokenError{err.Error()}
+	}
+	var payload requestPayload
+	if err := decodeToken(token.Payload, &payload); err != nil {
+		return nil, BadRequestTokenError{"can't parse the payload: " + err.Error()}
+	}
+	if payload.Request != requestHash(r.Method, r.Host, r.RequestURI) {
+		return nil, BadRequestTokenError{"the token is for another request"}
+	}
+
+	t := time.Unix(payload.Time, 0)
+	now := v.now()
+	if t.Before(now.Add(-v.window)) || t.After(now.Add(v.window)) {
+		return nil, BadRequestTokenError{"the token is too old, or the clocks are off"}
+	}
+
+	// Only tokens signed by one of the user's current keys are
+	// remembered, so that nobody else can fill up seen, and a token
+	// isn't used up if the check fails.
+	user := User{
+		UID:      payload.UID,
+		Username: libkb.NewNormalizedUsername(payload.Username),
+		KID:      token.KID,
+	}
+	if err := v.checker.Check(r.Context(), user.UID, user.Username, user.KID); err != nil {
+		return nil, err
+	}
+	if err := v.remember(token.KID.String()+":"+payload.Nonce, t.Add(v.window), now); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// remember notes that the token with id was used, until expires, and
+// fails if it was already, or if too many unexpired tokens are
+// remembered already.
+func (v *RequestVerifier) remember(id string, expires, now time.Time) error {
+	v.Lock()
+	defer v.Unlock()
+	if _, ok := v.seen[id]; ok {
+		return BadRequestTokenError{"the token was already used"}
+	}
+	if len(v.seen) >= v.maxSeen {
+		for n, e := range v.seen {
+			if !now.Before(e) {
+				delete(v.seen, n)
+			}
+		}
+		if len(v.seen) >= v.maxSeen {
+			return BadRequestTokenError{"too many requests; try again later"}
+		}
+	}
+	v.seen[id] = expires
+	return nil
+}
+
+// Handler wraps next so that it only gets requests with good tokens,
+// with the users who signed them in their contexts; see
+// UserFromContext.  Other requests get 401s.
+func (v *RequestVerifier) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := v.Verify(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", authScheme)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), *user)))
+	})
+}
+
+// Signer signs with a user's device key.  The keybase1.CryptoClient of a
+// connection to the local service is one.
+type Signer interface {
+	SignED25519(ctx context.Context, arg keybase1.SignED25519Arg) (keybase1.ED25519SignatureInfo, error)
+}
+
+// RoundTripper adds a signed request token, for a RequestVerifier to
+// check, to each request it sends.
+type RoundTripper struct {
+	signer   Signer
+	uid      keybase1.UID
+	username libkb.NormalizedUsername
+	base     http.RoundTripper
+}
+
+// NewRoundTripper makes a RoundTripper that signs requests as the user
+// with uid and username, with signer, and sends them with base.  A nil
+// base is http.DefaultTransport.
+func NewRoundTripper(signer Signer, uid keybase1.UID, username libkb.NormalizedUsername, base http.RoundTripper) *RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RoundTripper{
+		signer:   signer,
+		uid:      uid,
+		username: username,
+		base:     base,
+	}
+}
+
+// RoundTrip signs and sends req.  It doesn't change req itself.
+func (t *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	var nonce [requestNonceLen]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	payload, err := encodeToken(requestPayload{
+		UID:      t.uid,
+		Username: t.username.String(),
+		Time:     time.Now().Unix(),
+		Nonce:    hex.EncodeToString(nonce[:]),
+		Request:  requestHash(req.Method, host, req.URL.RequestURI()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	sig, err := t.signer.SignED25519(req.Context(), keybase1.SignED25519Arg{
+		Msg:    []byte(requestPrefix + payload),
+		Reason: "Sign a request to " + host,
+	})
+	if err != nil {
+		return nil, err
+	}
+	token, err := encodeToken(requestToken{
+		Payload: payload,
+		KID:     libkb.NaclSigningKeyPublic(sig.PublicKey).GetKID(),
+		Sig:     hex.EncodeToString(sig.Sig[:]),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signed := new(http.Request)
+	*signed = *req
+	signed.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		signed.Header[k] = v
+	}
+	signed.Header.Set("Authorization", authScheme+" "+token)
+	return t.base.RoundTrip(signed)
+}
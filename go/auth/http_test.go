@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	libkb "github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	context "golang.org/x/net/context"
+)
+
+// testSigner signs as the local service would.
+type testSigner struct {
+	key libkb.NaclSigningKeyPair
+}
+
+func (s testSigner) SignED25519(_ context.Context, arg keybase1.SignED25519Arg) (keybase1.ED25519SignatureInfo, error) {
+	return keybase1.ED25519SignatureInfo{
+		Sig:       keybase1.ED25519Signature(*s.key.Private.Sign(arg.Msg)),
+		PublicKey: keybase1.ED25519PublicKey(s.key.Public),
+	}, nil
+}
+
+// recordingTransport records the requests it sends.
+type recordingTransport struct {
+	reqs []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.reqs = append(t.reqs, req)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type httpSetup struct {
+	*challengeSetup
+	verifier  *RequestVerifier
+	server    *httptest.Server
+	transport *recordingTransport
+	client    *http.Client
+}
+
+func newHTTPSetup(t *testing.T) *httpSetup {
+	s := &httpSetup{challengeSetup: newChallengeSetup(t)}
+	s.verifier = NewRequestVerifier(s.checker, time.Minute)
+	s.server = httptest.NewServer(s.verifier.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := UserFromContext(r.Context())
+		if !ok {
+			t.Error("no user in the request context")
+		}
+		w.Write([]byte(user.Username.String()))
+	})))
+	s.transport = &recordingTransport{}
+	rt := NewRoundTripper(testSigner{s.key}, s.user.uid, s.user.username, s.transport)
+	s.client = &http.Client{Transport: rt}
+	return s
+}
+
+func (s *httpSetup) get(t *testing.T, path string) (int, string) {
+	res, err := s.client.Get(s.server.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return readResponse(t, res)
+}
+
+func (s *httpSetup) resend(t *testing.T, req *http.Request) (int, string) {
+	res, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return readResponse(t, res)
+}
+
+func readResponse(t *testing.T, res *http.Response) (int, string) {
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.StatusCode, string(body)
+}
+
+func TestSignedRequest(t *testing.T) {
+	s := newHTTPSetup(t)
+	defer s.server.Close()
+
+	code, body := s.get(t, "/hello?a=b")
+	if code != http.StatusOK || body != s.user.username.String() {
+		t.Fatalf("got %d %q, expected 200 %q", code, body, s.user.username)
+	}
+
+	// The token can't be used again, or for another request.
+	signed := s.transport.reqs[0]
+	if code, _ := s.resend(t, signed); code != http.StatusUnauthorized {
+		t.Errorf("replayed a request: got %d", code)
+	}
+	other, err := http.NewRequest("GET", s.server.URL+"/goodbye", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Header = signed.Header
+	if code, _ := s.resend(t, other); code != http.StatusUnauthorized {
+		t.Errorf("used a token for another request: got %d", code)
+	}
+
+	// Nor can a request go without one.
+	res, err := http.Get(s.server.URL + "/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code, _ := readResponse(t, res); code != http.StatusUnauthorized {
+		t.Errorf("sent a request without a token: got %d", code)
+	}
+}
+
+func TestSignedRequestStale(t *testing.T) {
+	s := newHTTPSetup(t)
+	defer s.server.Close()
+
+	s.verifier.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if code, _ := s.get(t, "/hello"); code != http.StatusUnauthorized {
+		t.Errorf("sent a stale request: got %d", code)
+	}
+}
+
+func TestSignedRequestRevokedKey(t *testing.T) {
+	s := newHTTPSetup(t)
+	defer s.server.Close()
+
+	good := s.user.keys[0]
+	s.user.keys[0] = genKID()
+	if code, _ := s.get(t, "/hello"); code != http.StatusUnauthorized {
+		t.Errorf("sent a request signed with a revoked key: got %d", code)
+	}
+
+	// A failed check doesn't use up the token.
+	s.user.keys[0] = good
+	if code, _ := s.resend(t, s.transport.reqs[0]); code != http.StatusOK {
+		t.Errorf("token was used up by a failed check: got %d", code)
+	}
+}
+
+func TestSignedRequestFull(t *testing.T) {
+	s := newHTTPSetup(t)
+	defer s.server.Close()
+
+	s.verifier.maxSeen = 2
+	for i := 0; i < 2; i++ {
+		if code, _ := s.get(t, "/hello"); code != http.StatusOK {
+			t.Fatalf("request %d: got %d", i, code)
+		}
+	}
+	if code, _ := s.get(t, "/hello"); code != http.StatusUnauthorized {
+		t.Errorf("sent a request with the verifier full: got %d", code)
+	}
+
+	// Once the remembered tokens expire, there's room again.  The window
+	// is widened so that the new token isn't too old itself.
+	s.verifier.now = func() time.Time { return time.Now().Add(61 * time.Second) }
+	s.verifier.window = 2 * time.Minute
+	if code, _ := s.get(t, "/hello"); code != http.StatusOK {
+		t.Errorf("sent a request after the tokens expired: got %d", code)
+	}
+}
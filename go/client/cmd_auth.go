@@ -0,0 +1,130 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/auth"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+	"golang.org/x/net/context"
+)
+
+func NewCmdAuth(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "auth",
+		Usage: "Sign in to other sites with your Keybase device key",
+		Subcommands: []cli.Command{
+			NewCmdAuthSignChallenge(cl, g),
+		},
+	}
+}
+
+type CmdAuthSignChallenge struct {
+	libkb.Contextified
+	challenge string
+	force     bool
+}
+
+func NewCmdAuthSignChallenge(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "sign-challenge",
+		ArgumentHelp: "<challenge>",
+		Usage:        "Answer a site's sign-in challenge",
+		Description: `Signs a site's sign-in challenge with this device's key, and prints
+   the response to give back to the site, which proves to it that you're
+   the Keybase user you say you are. The response is good only for that
+   site, only once, and only for the next few minutes.`,
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "f, force",
+				Usage: "Don't ask before signing.",
+			},
+		},
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdAuthSignChallenge{Contextified: libkb.NewContextified(g)}, "sign-challenge", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+func (c *CmdAuthSignChallenge) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return errors.New("sign-challenge takes one argument, the challenge")
+	}
+	c.challenge = ctx.Args()[0]
+	c.force = ctx.Bool("force")
+	return nil
+}
+
+func (c *CmdAuthSignChallenge) Run() error {
+	challenge, err := auth.ParseChallenge(c.challenge)
+	if err != nil {
+		return err
+	}
+	if !time.Now().Before(time.Unix(challenge.Expires, 0)) {
+		return auth.ExpiredChallengeError{}
+	}
+
+	sessionCli, err := GetSessionClient(c.G())
+	if err != nil {
+		return err
+	}
+	session, err := sessionCli.CurrentSession(context.TODO(), 0)
+	if err != nil {
+		return err
+	}
+
+	if !c.force {
+		prompt := fmt.Sprintf("Sign in to %s as %s?", challenge.Site, session.Username)
+		ok, err := c.G().UI.GetTerminalUI().PromptYesNo(PromptDescriptorAuthSignChallenge, prompt, libkb.PromptDefaultNo)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return NotConfirmedError{}
+		}
+	}
+
+	protocols := []rpc.Protocol{
+		NewLogUIProtocol(),
+		NewSecretUIProtocol(c.G()),
+	}
+	if err := RegisterProtocols(protocols); err != nil {
+		return err
+	}
+	cryptoCli, err := GetCryptoClient(c.G())
+	if err != nil {
+		return err
+	}
+	sig, err := cryptoCli.SignED25519(context.TODO(), keybase1.SignED25519Arg{
+		Msg:    auth.ChallengeMessage(c.challenge),
+		Reason: fmt.Sprintf("Sign in to %s", challenge.Site),
+	})
+	if err != nil {
+		return err
+	}
+
+	response, err := auth.NewChallengeResponse(c.challenge, session.Uid, libkb.NewNormalizedUsername(session.Username), sig).Encode()
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetDumbOutputUI().Printf("%s\n", response)
+	return nil
+}
+
+func (c *CmdAuthSignChallenge) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		API:       true,
+		KbKeyring: true,
+	}
+}
@@ -17,6 +17,10 @@ func NewCmdCtl(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 		NewCmdCtlReload(cl, g),
 		NewCmdCtlRestart(cl, g),
 		NewCmdCtlLogRotate(cl, g),
+		NewCmdCtlLogLevel(cl, g),
+		NewCmdCtlStats(cl, g),
+		NewCmdCtlOffline(cl, g),
+		NewCmdCtlProfile(cl, g),
 	}
 
 	return cli.Command{
@@ -0,0 +1,70 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/logger"
+	keybase1 "github.com/keybase/client/go/protocol"
+	"golang.org/x/net/context"
+)
+
+var logLevelNames = map[string]keybase1.LogLevel{
+	"debug":    keybase1.LogLevel_DEBUG,
+	"info":     keybase1.LogLevel_INFO,
+	"notice":   keybase1.LogLevel_NOTICE,
+	"warning":  keybase1.LogLevel_WARN,
+	"error":    keybase1.LogLevel_ERROR,
+	"critical": keybase1.LogLevel_CRITICAL,
+}
+
+func NewCmdCtlLogLevel(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "log-level",
+		ArgumentHelp: "<debug|info|notice|warning|error|critical>",
+		Usage:        "Change the keybase service's log level while it runs",
+		Description: `Sets the level of the messages the service writes to its log. The
+   change lasts until the service restarts.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlLogLevel{Contextified: libkb.NewContextified(g)}, "log-level", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+type CmdCtlLogLevel struct {
+	libkb.Contextified
+	level keybase1.LogLevel
+}
+
+func (s *CmdCtlLogLevel) ParseArgv(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) != 1 {
+		return fmt.Errorf("log-level takes a level")
+	}
+	level, ok := logLevelNames[strings.ToLower(args[0])]
+	if !ok {
+		return fmt.Errorf("unknown log level %q; expected debug, info, notice, warning, error or critical", args[0])
+	}
+	s.level = level
+	return nil
+}
+
+func (s *CmdCtlLogLevel) Run() (err error) {
+	cli, err := GetCtlClient(s.G())
+	if err != nil {
+		return err
+	}
+	return cli.SetLogLevel(context.TODO(), keybase1.SetLogLevelArg{Level: s.level, Module: logger.AllModules})
+}
+
+func (s *CmdCtlLogLevel) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
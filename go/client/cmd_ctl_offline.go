@@ -0,0 +1,126 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	"golang.org/x/net/context"
+)
+
+func NewCmdCtlOffline(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "offline",
+		ArgumentHelp: "[on|off|status|discard]",
+		Usage:        "Switch offline mode, or show the operations waiting to be posted",
+		Description: `In offline mode, the service never contacts the Keybase server. Users
+   are loaded from local storage, and track and untrack signatures and
+   favorite folders are queued. Switching offline mode off posts the
+   queued operations; signatures
+   that conflict with the server (because the sig chain changed in the
+   meantime) stay in the queue until they're discarded.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlOffline{Contextified: libkb.NewContextified(g)}, "offline", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+type CmdCtlOffline struct {
+	libkb.Contextified
+	action string
+}
+
+func (s *CmdCtlOffline) ParseArgv(ctx *cli.Context) error {
+	switch len(ctx.Args()) {
+	case 0:
+		s.action = "status"
+	case 1:
+		s.action = ctx.Args()[0]
+	default:
+		return fmt.Errorf("offline takes at most one argument")
+	}
+	switch s.action {
+	case "on", "off", "status", "discard":
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q; expected on, off, status or discard", s.action)
+	}
+}
+
+func (s *CmdCtlOffline) Run() (err error) {
+	cli, err := GetCtlClient(s.G())
+	if err != nil {
+		return err
+	}
+
+	var status keybase1.OfflineStatus
+	switch s.action {
+	case "on", "off":
+		status, err = cli.SetOffline(context.TODO(), keybase1.SetOfflineArg{Offline: s.action == "on"})
+	case "discard":
+		if err = cli.DiscardOfflineConflicts(context.TODO(), 0); err == nil {
+			status, err = cli.GetOfflineStatus(context.TODO(), 0)
+		}
+	default:
+		status, err = cli.GetOfflineStatus(context.TODO(), 0)
+	}
+	if err != nil {
+		return err
+	}
+	s.display(status)
+	return nil
+}
+
+func (s *CmdCtlOffline) display(status keybase1.OfflineStatus) {
+	if status.Offline {
+		GlobUI.Printf("Offline\n")
+	} else {
+		GlobUI.Printf("Online\n")
+	}
+	if len(status.Queue) == 0 {
+		return
+	}
+
+	GlobUI.Printf("\n")
+	i := 0
+	conflicts := false
+	rowfunc := func() []string {
+		if i >= len(status.Queue) {
+			return nil
+		}
+		op := status.Queue[i]
+		i++
+		state := "pending"
+		if len(op.Conflict) > 0 {
+			state = "conflict: " + op.Conflict
+			conflicts = true
+		}
+		seqno := "-"
+		if op.Seqno > 0 {
+			seqno = fmt.Sprintf("%d", op.Seqno)
+		}
+		return []string{
+			op.Type,
+			op.Username,
+			seqno,
+			keybase1.FormatTime(op.Ctime),
+			state,
+		}
+	}
+	GlobUI.Tablify([]string{"Type", "User", "Seqno", "Queued", "State"}, rowfunc)
+
+	if conflicts {
+		GlobUI.Printf("\nConflicting signatures have to be made again; drop them with `keybase ctl offline discard`.\n")
+	}
+}
+
+func (s *CmdCtlOffline) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	"golang.org/x/net/context"
+)
+
+func NewCmdCtlProfile(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "profile",
+		ArgumentHelp: "[cpu|heap|goroutine]",
+		Usage:        "Capture a Go pprof profile of the running service",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlProfile{Contextified: libkb.NewContextified(g)}, "profile", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "d, duration",
+				Value: "30s",
+				Usage: "How long to sample for (CPU profiles only).",
+			},
+		},
+	}
+}
+
+type CmdCtlProfile struct {
+	libkb.Contextified
+	profileType keybase1.ProfileType
+	duration    time.Duration
+}
+
+func (s *CmdCtlProfile) ParseArgv(ctx *cli.Context) error {
+	s.profileType = keybase1.ProfileType_CPU
+	switch len(ctx.Args()) {
+	case 0:
+	case 1:
+		switch ctx.Args()[0] {
+		case "cpu":
+			s.profileType = keybase1.ProfileType_CPU
+		case "heap":
+			s.profileType = keybase1.ProfileType_HEAP
+		case "goroutine":
+			s.profileType = keybase1.ProfileType_GOROUTINE
+		default:
+			return fmt.Errorf("unknown profile type: %s", ctx.Args()[0])
+		}
+	default:
+		return fmt.Errorf("profile takes at most one argument")
+	}
+
+	dur, err := time.ParseDuration(ctx.String("duration"))
+	if err != nil {
+		return err
+	}
+	// The service takes whole seconds.
+	if dur < time.Second {
+		return fmt.Errorf("duration must be at least 1s")
+	}
+	s.duration = dur
+	return nil
+}
+
+func (s *CmdCtlProfile) Run() (err error) {
+	cli, err := GetCtlClient(s.G())
+	if err != nil {
+		return err
+	}
+	filename, err := cli.CaptureProfile(context.TODO(), keybase1.CaptureProfileArg{
+		ProfileType:     s.profileType,
+		DurationSeconds: int(s.duration / time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	if s.profileType == keybase1.ProfileType_CPU {
+		GlobUI.Printf("Profiling service CPU for %s; the profile will be written to %s\n", s.duration/time.Second*time.Second, filename)
+		return nil
+	}
+	GlobUI.Printf("Wrote profile to %s\n", filename)
+	return nil
+}
+
+func (s *CmdCtlProfile) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
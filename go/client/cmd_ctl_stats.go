@@ -0,0 +1,181 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	"golang.org/x/net/context"
+)
+
+func NewCmdCtlStats(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "stats",
+		Usage: "Show call counts and latencies aggregated by the service",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCtlStats{Contextified: libkb.NewContextified(g)}, "stats", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetNoStandalone()
+		},
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "c, category",
+				Usage: "Only show one category (api, xapi, engine, rpc, proof or merkle).",
+			},
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output as JSON (default is text).",
+			},
+			cli.BoolFlag{
+				Name:  "r, reset",
+				Usage: "Reset the counters after printing them.",
+			},
+		},
+	}
+}
+
+type CmdCtlStats struct {
+	libkb.Contextified
+	category    keybase1.StatCategory
+	hasCategory bool
+	json        bool
+	reset       bool
+}
+
+var statCategoryNames = map[string]keybase1.StatCategory{
+	"api":    keybase1.StatCategory_API,
+	"xapi":   keybase1.StatCategory_XAPI,
+	"engine": keybase1.StatCategory_ENGINE,
+	"rpc":    keybase1.StatCategory_RPC,
+	"proof":  keybase1.StatCategory_PROOF,
+	"merkle": keybase1.StatCategory_MERKLE,
+}
+
+func statCategoryName(c keybase1.StatCategory) string {
+	for k, v := range statCategoryNames {
+		if v == c {
+			return k
+		}
+	}
+	return fmt.Sprintf("unknown(%d)", c)
+}
+
+func (s *CmdCtlStats) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) > 0 {
+		return fmt.Errorf("stats doesn't take any arguments")
+	}
+	if c := ctx.String("category"); len(c) > 0 {
+		cat, ok := statCategoryNames[strings.ToLower(c)]
+		if !ok {
+			return fmt.Errorf("unknown category: %s", c)
+		}
+		s.category = cat
+		s.hasCategory = true
+	}
+	s.json = ctx.Bool("json")
+	s.reset = ctx.Bool("reset")
+	return nil
+}
+
+func (s *CmdCtlStats) Run() (err error) {
+	cli, err := GetCtlClient(s.G())
+	if err != nil {
+		return err
+	}
+	stats, err := cli.GetStats(context.TODO(), 0)
+	if err != nil {
+		return err
+	}
+
+	if s.hasCategory {
+		var entries []keybase1.StatEntry
+		for _, e := range stats.Entries {
+			if e.Category == s.category {
+				entries = append(entries, e)
+			}
+		}
+		stats.Entries = entries
+	}
+
+	if s.json {
+		b, err := json.MarshalIndent(stats, "", "    ")
+		if err != nil {
+			return err
+		}
+		err = DisplayJSON(string(b))
+		if err != nil {
+			return err
+		}
+	} else {
+		s.displayTable(stats)
+	}
+
+	if s.reset {
+		return cli.ResetStats(context.TODO(), 0)
+	}
+	return nil
+}
+
+func (s *CmdCtlStats) displayTable(stats keybase1.ServiceStats) {
+	GlobUI.Printf("Stats since %s\n\n", keybase1.FormatTime(stats.Since))
+
+	i := 0
+	rowfunc := func() []string {
+		if i >= len(stats.Entries) {
+			return nil
+		}
+		e := stats.Entries[i]
+		i++
+		var avg int64
+		if e.Count > 0 {
+			avg = e.TotalMs / int64(e.Count)
+		}
+		return []string{
+			statCategoryName(e.Category),
+			e.Name,
+			fmt.Sprintf("%d", e.Count),
+			fmt.Sprintf("%d", e.Errors),
+			fmt.Sprintf("%d", e.TotalMs),
+			fmt.Sprintf("%d", avg),
+			statPercentile(e, 0.5),
+			statPercentile(e, 0.95),
+			fmt.Sprintf("%d", e.MaxMs),
+		}
+	}
+	GlobUI.Tablify([]string{"Category", "Name", "Count", "Errors", "Total ms", "Avg ms", "p50 ms", "p95 ms", "Max ms"}, rowfunc)
+}
+
+// statPercentile estimates the given percentile from the histogram,
+// reporting the upper bound of the bucket it falls in.
+func statPercentile(e keybase1.StatEntry, p float64) string {
+	if e.Count == 0 {
+		return "-"
+	}
+	want := int(p * float64(e.Count))
+	if want < 1 {
+		want = 1
+	}
+	seen := 0
+	for _, b := range e.Histogram {
+		seen += b.Count
+		if seen < want {
+			continue
+		}
+		if b.UpperBoundMs == 0 {
+			return fmt.Sprintf(">%d", e.Histogram[len(e.Histogram)-2].UpperBoundMs)
+		}
+		return fmt.Sprintf("<=%d", b.UpperBoundMs)
+	}
+	return "-"
+}
+
+func (s *CmdCtlStats) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
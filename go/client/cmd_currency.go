@@ -0,0 +1,163 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+)
+
+func NewCmdCurrency(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "currency",
+		Usage: "Manage cryptocurrency addresses",
+		Subcommands: []cli.Command{
+			NewCmdCurrencyAdd(cl, g),
+			NewCmdCurrencyRevoke(cl, g),
+		},
+	}
+}
+
+type CmdCurrencyAdd struct {
+	libkb.Contextified
+	address string
+	typ     string
+	force   bool
+}
+
+func NewCmdCurrencyAdd(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "add",
+		ArgumentHelp: "<address>",
+		Usage:        "Claim a cryptocurrency address",
+		Description: `Claims an address in your sigchain, where anyone who identifies you
+   can see it. You can have one address of each type; the type is worked
+   out from the address unless it's given.`,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "t, type",
+				Usage: "The currency: " + strings.Join(libkb.ListCurrencyTypes(), ", ") + ".",
+			},
+			cli.BoolFlag{
+				Name:  "f, force",
+				Usage: "Overwrite an existing address of the same type.",
+			},
+		},
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCurrencyAdd{Contextified: libkb.NewContextified(g)}, "add", c)
+		},
+	}
+}
+
+func (c *CmdCurrencyAdd) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("Must provide exactly one address.")
+	}
+	c.address = ctx.Args()[0]
+	c.typ = ctx.String("type")
+	c.force = ctx.Bool("force")
+	if len(c.typ) > 0 && libkb.GetCurrencyType(c.typ) == nil {
+		return fmt.Errorf("Unknown currency type %q; expected one of: %s", c.typ, strings.Join(libkb.ListCurrencyTypes(), ", "))
+	}
+	return nil
+}
+
+func (c *CmdCurrencyAdd) Run() error {
+	cli, err := GetCryptocurrencyClient(c.G())
+	if err != nil {
+		return err
+	}
+
+	protocols := []rpc.Protocol{
+		NewSecretUIProtocol(c.G()),
+	}
+	if err = RegisterProtocolsWithContext(protocols, c.G()); err != nil {
+		return err
+	}
+
+	res, err := cli.RegisterAddress(context.TODO(), keybase1.RegisterAddressArg{
+		Address: c.address,
+		Type:    c.typ,
+		Force:   c.force,
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetTerminalUI().Printf("Added %s address %s\n", res.Type, c.address)
+	return nil
+}
+
+func (c *CmdCurrencyAdd) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
+
+type CmdCurrencyRevoke struct {
+	libkb.Contextified
+	address string
+}
+
+func NewCmdCurrencyRevoke(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "revoke",
+		ArgumentHelp: "<address|sig-id>",
+		Usage:        "Revoke a cryptocurrency address",
+		Description: `Revokes the claim of one of your addresses, given by the address
+   itself or by the ID of the signature that claimed it. Your addresses
+   of other types are left alone.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdCurrencyRevoke{Contextified: libkb.NewContextified(g)}, "revoke", c)
+		},
+	}
+}
+
+func (c *CmdCurrencyRevoke) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 1 {
+		return fmt.Errorf("Must provide exactly one address or signature ID.")
+	}
+	c.address = ctx.Args()[0]
+	return nil
+}
+
+func (c *CmdCurrencyRevoke) Run() error {
+	cli, err := GetCryptocurrencyClient(c.G())
+	if err != nil {
+		return err
+	}
+
+	protocols := []rpc.Protocol{
+		NewSecretUIProtocol(c.G()),
+	}
+	if err = RegisterProtocolsWithContext(protocols, c.G()); err != nil {
+		return err
+	}
+
+	err = cli.RevokeAddress(context.TODO(), keybase1.RevokeAddressArg{
+		Address: c.address,
+	})
+	if err != nil {
+		return err
+	}
+	c.G().UI.GetTerminalUI().Printf("Revoked %s\n", c.address)
+	return nil
+}
+
+func (c *CmdCurrencyRevoke) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/net/context"
+)
+
+func NewCmdDbCompact(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "compact",
+		Usage: "Compact the local database's files",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDbCompact{Contextified: libkb.NewContextified(g)}, "compact", c)
+		},
+	}
+}
+
+type CmdDbCompact struct {
+	libkb.Contextified
+}
+
+func (c *CmdDbCompact) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) > 0 {
+		return fmt.Errorf("compact doesn't take any arguments")
+	}
+	return nil
+}
+
+func (c *CmdDbCompact) Run() error {
+	cli, err := GetCtlClient(c.G())
+	if err != nil {
+		return err
+	}
+	return cli.DbCompact(context.TODO(), 0)
+}
+
+func (c *CmdDbCompact) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	"golang.org/x/net/context"
+)
+
+func NewCmdDbEvict(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	var tables []string
+	for _, name := range libkb.DbTableNames {
+		tables = append(tables, name)
+	}
+	sort.Strings(tables)
+
+	return cli.Command{
+		Name:         "evict",
+		ArgumentHelp: "[<username>...]",
+		Usage:        "Evict selected tables or users from the local database",
+		Description: `Deletes the values in the tables given with --table that belong to
+   the users given, so that they're fetched again. With no users, all the
+   values in the tables are evicted; with no tables, all of the users'
+   values. The tables are: ` + strings.Join(tables, ", ") + `.`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDbEvict{Contextified: libkb.NewContextified(g)}, "evict", c)
+		},
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "t, table",
+				Usage: "Evict from this table; repeat for more.",
+				Value: &cli.StringSlice{},
+			},
+		},
+	}
+}
+
+type CmdDbEvict struct {
+	libkb.Contextified
+	tables    []string
+	usernames []string
+}
+
+func (c *CmdDbEvict) ParseArgv(ctx *cli.Context) error {
+	c.tables = ctx.StringSlice("table")
+	c.usernames = ctx.Args()
+	if len(c.tables) == 0 && len(c.usernames) == 0 {
+		return fmt.Errorf("evict needs tables, users, or both")
+	}
+	for _, name := range c.tables {
+		if _, err := libkb.DbTableFromName(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *CmdDbEvict) Run() error {
+	cli, err := GetCtlClient(c.G())
+	if err != nil {
+		return err
+	}
+	if err = RegisterProtocols(nil); err != nil {
+		return err
+	}
+	n, err := cli.DbEvict(context.TODO(), keybase1.DbEvictArg{Tables: c.tables, Usernames: c.usernames})
+	if err != nil {
+		return err
+	}
+	GlobUI.Printf("Evicted %d values\n", n)
+	return nil
+}
+
+func (c *CmdDbEvict) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+	}
+}
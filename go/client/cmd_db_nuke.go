@@ -66,6 +66,10 @@ func NewCmdDb(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 		Name: "db",
 		Subcommands: []cli.Command{
 			NewCmdDbNuke(cl, g),
+			NewCmdDbStats(cl, g),
+			NewCmdDbCompact(cl, g),
+			NewCmdDbVerify(cl, g),
+			NewCmdDbEvict(cl, g),
 		},
 	}
 }
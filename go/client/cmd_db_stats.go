@@ -0,0 +1,88 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/net/context"
+)
+
+func NewCmdDbStats(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "stats",
+		Usage: "Show the number of entries in each table of the local database, and their size",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDbStats{Contextified: libkb.NewContextified(g)}, "stats", c)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output as JSON (default is text).",
+			},
+		},
+	}
+}
+
+type CmdDbStats struct {
+	libkb.Contextified
+	json bool
+}
+
+func (c *CmdDbStats) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) > 0 {
+		return fmt.Errorf("stats doesn't take any arguments")
+	}
+	c.json = ctx.Bool("json")
+	return nil
+}
+
+func (c *CmdDbStats) Run() error {
+	cli, err := GetCtlClient(c.G())
+	if err != nil {
+		return err
+	}
+	stats, err := cli.DbStats(context.TODO(), 0)
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		b, err := json.MarshalIndent(stats, "", "    ")
+		if err != nil {
+			return err
+		}
+		return DisplayJSON(string(b))
+	}
+
+	var entries int
+	var bytes int64
+	i := 0
+	rowfunc := func() []string {
+		if i > len(stats) {
+			return nil
+		}
+		if i == len(stats) {
+			i++
+			return []string{"total", fmt.Sprintf("%d", entries), fmt.Sprintf("%d", bytes)}
+		}
+		s := stats[i]
+		i++
+		entries += s.Entries
+		bytes += s.Bytes
+		return []string{s.Table, fmt.Sprintf("%d", s.Entries), fmt.Sprintf("%d", s.Bytes)}
+	}
+	GlobUI.Tablify([]string{"Table", "Entries", "Bytes"}, rowfunc)
+	return nil
+}
+
+func (c *CmdDbStats) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+	}
+}
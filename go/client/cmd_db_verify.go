@@ -0,0 +1,97 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	"golang.org/x/net/context"
+)
+
+func NewCmdDbVerify(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "verify",
+		Usage: "Check that the sig chains and Merkle roots in the local database still verify",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDbVerify{Contextified: libkb.NewContextified(g)}, "verify", c)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "e, evict",
+				Usage: "Evict the ones that don't, so that they're fetched again.",
+			},
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output as JSON (default is text).",
+			},
+		},
+	}
+}
+
+type CmdDbVerify struct {
+	libkb.Contextified
+	evict bool
+	json  bool
+}
+
+func (c *CmdDbVerify) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) > 0 {
+		return fmt.Errorf("verify doesn't take any arguments")
+	}
+	c.evict = ctx.Bool("evict")
+	c.json = ctx.Bool("json")
+	return nil
+}
+
+func (c *CmdDbVerify) Run() error {
+	cli, err := GetCtlClient(c.G())
+	if err != nil {
+		return err
+	}
+	if err = RegisterProtocols(nil); err != nil {
+		return err
+	}
+	res, err := cli.DbVerify(context.TODO(), keybase1.DbVerifyArg{EvictBad: c.evict})
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		b, err := json.MarshalIndent(res, "", "    ")
+		if err != nil {
+			return err
+		}
+		return DisplayJSON(string(b))
+	}
+
+	if len(res.Problems) > 0 {
+		i := 0
+		rowfunc := func() []string {
+			if i >= len(res.Problems) {
+				return nil
+			}
+			p := res.Problems[i]
+			i++
+			return []string{p.Table, p.Key, p.Problem}
+		}
+		GlobUI.Tablify([]string{"Table", "Key", "Problem"}, rowfunc)
+		GlobUI.Printf("\n")
+	}
+	GlobUI.Printf("Checked %d, %d bad, %d values evicted\n", res.Checked, len(res.Problems), res.Evicted)
+	if len(res.Problems) > 0 && !c.evict {
+		return fmt.Errorf("%d stored values didn't verify; evict them with `keybase db verify --evict`", len(res.Problems))
+	}
+	return nil
+}
+
+func (c *CmdDbVerify) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+	}
+}
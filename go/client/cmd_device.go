@@ -20,6 +20,9 @@ func NewCmdDevice(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comman
 			NewCmdDeviceRemove(cl, g),
 			NewCmdDeviceList(cl, g),
 			NewCmdDeviceAdd(cl, g),
+			NewCmdDeviceRotate(cl, g),
+			NewCmdDeviceExtend(cl, g),
+			NewCmdDeviceProtect(cl, g),
 		},
 	}
 }
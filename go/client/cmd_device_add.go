@@ -5,12 +5,14 @@ package client
 
 import (
 	"fmt"
+	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/keybase/cli"
 	"github.com/keybase/client/go/libcmdline"
 	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
 	rpc "github.com/keybase/go-framed-msgpack-rpc"
 )
 
@@ -18,6 +20,7 @@ import (
 // device provisioning on the provisioner/device X/C1.
 type CmdDeviceAdd struct {
 	libkb.Contextified
+	expireIn int
 }
 
 const cmdDevAddDesc = `When you are adding a new device to your account and you have an 
@@ -33,6 +36,9 @@ func NewCmdDeviceAdd(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Com
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(&CmdDeviceAdd{Contextified: libkb.NewContextified(g)}, "add", c)
 		},
+		Flags: []cli.Flag{
+			expireFlag("When the new device's keys expire (default: never)."),
+		},
 	}
 }
 
@@ -51,7 +57,7 @@ func (c *CmdDeviceAdd) Run() error {
 		return err
 	}
 
-	return cli.DeviceAdd(context.TODO(), 0)
+	return cli.DeviceAdd(context.TODO(), keybase1.DeviceAddArg{ExpireIn: c.expireIn})
 }
 
 // ParseArgv gets the secret phrase from the command args.
@@ -59,7 +65,9 @@ func (c *CmdDeviceAdd) ParseArgv(ctx *cli.Context) error {
 	if len(ctx.Args()) != 0 {
 		return fmt.Errorf("device add takes zero arguments")
 	}
-	return nil
+	var err error
+	c.expireIn, err = parseExpire(ctx.String("expire"), time.Now())
+	return err
 }
 
 // GetUsage says what this command needs to operate.
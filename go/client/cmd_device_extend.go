@@ -0,0 +1,82 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+)
+
+// CmdDeviceExtend is the 'device extend' command.  It pushes back
+// the expiration of the current device's keys.
+type CmdDeviceExtend struct {
+	libkb.Contextified
+	expireIn int
+}
+
+const cmdDevExtendDesc = `Signs the keys of this device into your sigchain again, with a later
+expiration time.  This has to happen before the keys expire; a device
+whose keys have expired has to be provisioned again.`
+
+// NewCmdDeviceExtend creates a new cli.Command.
+func NewCmdDeviceExtend(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:        "extend",
+		Usage:       "Push back the expiration of this device's keys",
+		Description: cmdDevExtendDesc,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDeviceExtend{Contextified: libkb.NewContextified(g)}, "extend", c)
+		},
+		Flags: []cli.Flag{
+			expireFlag("When the keys should expire (default: 1y)."),
+		},
+	}
+}
+
+// Run runs the command in client/server mode.
+func (c *CmdDeviceExtend) Run() error {
+	cli, err := GetDeviceClient()
+	if err != nil {
+		return err
+	}
+	protocols := []rpc.Protocol{
+		NewSecretUIProtocol(c.G()),
+	}
+	if err := RegisterProtocols(protocols); err != nil {
+		return err
+	}
+
+	return cli.DeviceExtend(context.TODO(), keybase1.DeviceExtendArg{ExpireIn: c.expireIn})
+}
+
+// ParseArgv gets the new expiration from the flags.
+func (c *CmdDeviceExtend) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("device extend takes zero arguments")
+	}
+	expire := ctx.String("expire")
+	if len(expire) == 0 {
+		expire = "1y"
+	}
+	var err error
+	c.expireIn, err = parseExpire(expire, time.Now())
+	return err
+}
+
+// GetUsage says what this command needs to operate.
+func (c *CmdDeviceExtend) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
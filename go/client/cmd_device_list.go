@@ -5,6 +5,7 @@ package client
 
 import (
 	"fmt"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -52,12 +53,19 @@ func (c *CmdDeviceList) Run() error {
 
 func (c *CmdDeviceList) output(devs []keybase1.Device) {
 	w := GlobUI.DefaultTabWriter()
-	fmt.Fprintf(w, "Name\tType\tID\n")
-	fmt.Fprintf(w, "==========\t==========\t==========\n")
+	fmt.Fprintf(w, "Name\tType\tID\tExpires\n")
+	fmt.Fprintf(w, "==========\t==========\t==========\t==========\n")
 	for _, v := range devs {
-		fmt.Fprintf(w, "%s\t%s\t%s\n", v.Name, v.Type, v.DeviceID)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Name, v.Type, v.DeviceID, formatDeviceExpiry(v.ETime))
 	}
 	w.Flush()
+
+	now := time.Now()
+	for _, v := range devs {
+		if warning := deviceExpiryWarning(v, now); len(warning) > 0 {
+			c.G().Log.Warning(warning)
+		}
+	}
 }
 
 // ParseArgv does nothing for this command.
@@ -0,0 +1,97 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+)
+
+// CmdDeviceProtect is the 'device protect' command.  It shows or
+// changes the key protector that keeps this device's LKSec client half.
+type CmdDeviceProtect struct {
+	libkb.Contextified
+	protector string
+}
+
+const cmdDevProtectDesc = `This device's secret keys are encrypted with a secret made of a half
+the server keeps and a half derived from your passphrase. A key
+protector can keep the passphrase half on this device, so that the keys
+unlock without the passphrase:
+
+   passphrase     keep nothing; ask for the passphrase (the default)
+   secret-store   keep it in the OS's secret store, such as the OS X keychain
+   machine        keep it in a file, encrypted to this machine's ID; a
+                  copy is no use elsewhere, but anyone who can read your
+                  files here can use it
+
+With no argument, shows the protector in use. Changing it needs the
+passphrase.`
+
+// NewCmdDeviceProtect creates a new cli.Command.
+func NewCmdDeviceProtect(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:         "protect",
+		ArgumentHelp: "[" + strings.Join(libkb.KeyProtectorNames, "|") + "]",
+		Usage:        "Show or change how this device's keys are unlocked",
+		Description:  cmdDevProtectDesc,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDeviceProtect{Contextified: libkb.NewContextified(g)}, "protect", c)
+		},
+	}
+}
+
+// Run runs the command in client/server mode.
+func (c *CmdDeviceProtect) Run() error {
+	cli, err := GetDeviceClient()
+	if err != nil {
+		return err
+	}
+	if len(c.protector) == 0 {
+		name, err := cli.DeviceGetProtector(context.TODO(), 0)
+		if err != nil {
+			return err
+		}
+		c.G().UI.GetTerminalUI().Printf("%s\n", name)
+		return nil
+	}
+
+	protocols := []rpc.Protocol{
+		NewLogUIProtocol(),
+		NewSecretUIProtocol(c.G()),
+	}
+	if err := RegisterProtocols(protocols); err != nil {
+		return err
+	}
+	return cli.DeviceProtect(context.TODO(), keybase1.DeviceProtectArg{Protector: c.protector})
+}
+
+// ParseArgv gets the optional protector name.
+func (c *CmdDeviceProtect) ParseArgv(ctx *cli.Context) error {
+	switch len(ctx.Args()) {
+	case 0:
+	case 1:
+		c.protector = ctx.Args()[0]
+	default:
+		return fmt.Errorf("device protect takes at most one argument")
+	}
+	return nil
+}
+
+// GetUsage says what this command needs to operate.
+func (c *CmdDeviceProtect) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+
+	"golang.org/x/net/context"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+)
+
+// CmdDeviceRotate is the 'device rotate' command.  It replaces the
+// keys of the current device.
+type CmdDeviceRotate struct {
+	libkb.Contextified
+}
+
+const cmdDevRotateDesc = `Generates a new signing key and encryption key for this device,
+signs them into your sigchain with the current signing key, and
+revokes the old keys, all in one step.  The device keeps its name and ID,
+so there's no need to provision it again.`
+
+// NewCmdDeviceRotate creates a new cli.Command.
+func NewCmdDeviceRotate(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:        "rotate",
+		Usage:       "Replace this device's keys",
+		Description: cmdDevRotateDesc,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdDeviceRotate{Contextified: libkb.NewContextified(g)}, "rotate", c)
+		},
+	}
+}
+
+// Run runs the command in client/server mode.
+func (c *CmdDeviceRotate) Run() error {
+	cli, err := GetDeviceClient()
+	if err != nil {
+		return err
+	}
+	protocols := []rpc.Protocol{
+		NewSecretUIProtocol(c.G()),
+	}
+	if err := RegisterProtocols(protocols); err != nil {
+		return err
+	}
+
+	return cli.DeviceRotate(context.TODO(), 0)
+}
+
+// ParseArgv checks that there are no arguments.
+func (c *CmdDeviceRotate) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) != 0 {
+		return fmt.Errorf("device rotate takes zero arguments")
+	}
+	return nil
+}
+
+// GetUsage says what this command needs to operate.
+func (c *CmdDeviceRotate) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:    true,
+		KbKeyring: true,
+		API:       true,
+	}
+}
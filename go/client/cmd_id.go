@@ -19,6 +19,8 @@ type CmdID struct {
 	libkb.Contextified
 	user           string
 	trackStatement bool
+	forceRecheck   bool
+	cacheOnly      bool
 	useDelegateUI  bool
 }
 
@@ -32,16 +34,23 @@ func (v *CmdID) ParseArgv(ctx *cli.Context) error {
 		v.user = ctx.Args()[0]
 	}
 	v.trackStatement = ctx.Bool("track-statement")
+	v.forceRecheck = ctx.Bool("force-recheck")
+	v.cacheOnly = ctx.Bool("cache-only")
+	if v.forceRecheck && v.cacheOnly {
+		return fmt.Errorf("--force-recheck and --cache-only don't go together.")
+	}
 	v.useDelegateUI = ctx.Bool("delegate-identify-ui")
 	return nil
 }
 
 func (v *CmdID) makeArg() keybase1.IdentifyArg {
 	return keybase1.IdentifyArg{
-		UserAssertion:  v.user,
-		TrackStatement: v.trackStatement,
-		UseDelegateUI:  v.useDelegateUI,
-		Reason:         keybase1.IdentifyReason{Reason: "CLI id command"},
+		UserAssertion:    v.user,
+		TrackStatement:   v.trackStatement,
+		ForceRemoteCheck: v.forceRecheck,
+		CacheOnly:        v.cacheOnly,
+		UseDelegateUI:    v.useDelegateUI,
+		Reason:           keybase1.IdentifyReason{Reason: "CLI id command"},
 	}
 }
 
@@ -84,6 +93,14 @@ func NewCmdID(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 				Name:  "t, track-statement",
 				Usage: "Output a tracking statement (in JSON format).",
 			},
+			cli.BoolFlag{
+				Name:  "force-recheck",
+				Usage: "Check every proof, even if it was checked recently.",
+			},
+			cli.BoolFlag{
+				Name:  "cache-only",
+				Usage: "Don't check any proofs; show the cached results, however old.",
+			},
 		},
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(NewCmdIDRunner(g), "id", c)
@@ -0,0 +1,50 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/net/context"
+)
+
+type CmdLock struct {
+	libkb.Contextified
+}
+
+func NewCmdLock(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "lock",
+		Usage: "Forget the passphrase and unlocked keys, but stay logged in",
+		Description: `Zeroes the passphrase stream and secret keys the service keeps
+   unlocked, so that the passphrase is needed again to use them; "keybase
+   unlock" asks for it right away. To lock
+   them when the screen locks, run this from the screen locker's hook; on
+   Linux and OS X, sending the service SIGUSR1 does the same.
+
+   The service also locks them on its own after the timeouts in the
+   secret_cache.idle_timeout and secret_cache.max_lifetime config
+   settings (for example "15m" and "8h").`,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdLock{Contextified: libkb.NewContextified(g)}, "lock", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetNoStandalone()
+		},
+	}
+}
+
+func (v *CmdLock) ParseArgv(*cli.Context) error { return nil }
+
+func (v *CmdLock) Run() error {
+	cli, err := GetCtlClient(v.G())
+	if err != nil {
+		return err
+	}
+	return cli.Lock(context.TODO(), 0)
+}
+
+func (v *CmdLock) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
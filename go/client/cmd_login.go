@@ -23,11 +23,18 @@ func NewCmdLogin(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(NewCmdLoginRunner(g), "login", c)
 		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "switch",
+				Usage: "Make username the active account, staying logged in as the current one.",
+			},
+		},
 	}
 }
 
 type CmdLogin struct {
 	username string
+	doSwitch bool
 	libkb.Contextified
 }
 
@@ -49,6 +56,11 @@ func (c *CmdLogin) Run() error {
 	if err != nil {
 		return err
 	}
+	if c.doSwitch {
+		if err := client.SwitchUser(context.TODO(), keybase1.SwitchUserArg{Username: c.username}); err != nil {
+			return err
+		}
+	}
 	return client.Login(context.TODO(), keybase1.LoginArg{Username: c.username, DeviceType: libkb.DeviceTypeDesktop})
 }
 
@@ -61,6 +73,10 @@ func (c *CmdLogin) ParseArgv(ctx *cli.Context) error {
 	if nargs == 1 {
 		c.username = ctx.Args()[0]
 	}
+	c.doSwitch = ctx.Bool("switch")
+	if c.doSwitch && len(c.username) == 0 {
+		return errors.New("login --switch needs a username.")
+	}
 	return nil
 }
 
@@ -4,9 +4,12 @@
 package client
 
 import (
+	"time"
+
 	"github.com/keybase/cli"
 	"github.com/keybase/client/go/libcmdline"
 	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
 	rpc "github.com/keybase/go-framed-msgpack-rpc"
 	"golang.org/x/net/context"
 )
@@ -18,10 +21,14 @@ func NewCmdPaperKey(cl *libcmdline.CommandLine) cli.Command {
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(&CmdPaperKey{}, "paperkey", c)
 		},
+		Flags: []cli.Flag{
+			expireFlag("When the paper key expires (default: never)."),
+		},
 	}
 }
 
 type CmdPaperKey struct {
+	expireIn int
 }
 
 func (c *CmdPaperKey) Run() error {
@@ -36,11 +43,12 @@ func (c *CmdPaperKey) Run() error {
 	if err := RegisterProtocols(protocols); err != nil {
 		return err
 	}
-	return cli.PaperKey(context.TODO(), 0)
+	return cli.PaperKey(context.TODO(), keybase1.PaperKeyArg{ExpireIn: c.expireIn})
 }
 
-func (c *CmdPaperKey) ParseArgv(ctx *cli.Context) error {
-	return nil
+func (c *CmdPaperKey) ParseArgv(ctx *cli.Context) (err error) {
+	c.expireIn, err = parseExpire(ctx.String("expire"), time.Now())
+	return err
 }
 
 func (c *CmdPaperKey) GetUsage() libkb.Usage {
@@ -18,6 +18,8 @@ func NewCmdPGP(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
 			NewCmdPGPGen(cl),
 			NewCmdPGPPull(cl, g),
 			NewCmdPGPUpdate(cl),
+			NewCmdPGPExtend(cl),
+			NewCmdPGPRotateSubkey(cl),
 			NewCmdPGPSelect(cl),
 			NewCmdPGPSign(cl),
 			NewCmdPGPEncrypt(cl, g),
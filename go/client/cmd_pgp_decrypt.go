@@ -51,7 +51,13 @@ func NewCmdPGPDecrypt(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Co
 		},
 		Description: `Use of this command requires at least one PGP secret key imported
    into the local Keybase keyring. It will try all secret keys in the local keyring that match the
-   given ciphertext, and will succeed so long as one such key is available.`,
+   given ciphertext, and will succeed so long as one such key is available.
+   Keys synced to the server are tried too.
+
+   If the message is signed, the signer is identified: the Keybase user who
+   owns the signing key is looked up and identified (or tracked, as with
+   "keybase track"), and the result is shown. Signatures by keys that aren't
+   on Keybase are reported, but can't be checked.`,
 	}
 }
 
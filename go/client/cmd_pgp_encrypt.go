@@ -64,10 +64,20 @@ func NewCmdPGPEncrypt(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Co
 				Name:  "o, outfile",
 				Usage: "Specify an outfile (stdout by default).",
 			},
+			cli.BoolFlag{
+				Name:  "keyserver",
+				Usage: "Look up recipients given by email address on keyservers.",
+			},
 		},
 		Description: `If encrypting with signatures, "keybase pgp encrypt" requires an
    imported PGP private key, and accesses the local Keybase keyring when producing
-   the signature.`,
+   the signature.
+
+   With --keyserver, recipients can also be email addresses of people who
+   aren't on Keybase. Their keys come from "keybase pgp pull --keyserver", or
+   else from the Web Key Directory of their domain or the HKP keyserver
+   (pgp.keyserver in the config). Nothing vouches for these keys, so check
+   their fingerprints.`,
 	}
 }
 
@@ -80,6 +90,7 @@ type CmdPGPEncrypt struct {
 	noSelf       bool
 	keyQuery     string
 	binaryOut    bool
+	keyserver    bool
 }
 
 func (c *CmdPGPEncrypt) Run() error {
@@ -100,12 +111,13 @@ func (c *CmdPGPEncrypt) Run() error {
 		return err
 	}
 	opts := keybase1.PGPEncryptOptions{
-		Recipients:   c.recipients,
-		NoSign:       !c.sign,
-		NoSelf:       c.noSelf,
-		BinaryOut:    c.binaryOut,
-		KeyQuery:     c.keyQuery,
-		TrackOptions: c.trackOptions,
+		Recipients:      c.recipients,
+		NoSign:          !c.sign,
+		NoSelf:          c.noSelf,
+		BinaryOut:       c.binaryOut,
+		KeyQuery:        c.keyQuery,
+		TrackOptions:    c.trackOptions,
+		KeyserverLookup: c.keyserver,
 	}
 	arg := keybase1.PGPEncryptArg{Source: src, Sink: snk, Opts: opts}
 	err = cli.PGPEncrypt(context.TODO(), arg)
@@ -133,6 +145,7 @@ func (c *CmdPGPEncrypt) ParseArgv(ctx *cli.Context) error {
 	c.sign = ctx.Bool("sign")
 	c.keyQuery = ctx.String("key")
 	c.binaryOut = ctx.Bool("binary")
+	c.keyserver = ctx.Bool("keyserver")
 	return nil
 }
 
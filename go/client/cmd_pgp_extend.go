@@ -0,0 +1,83 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+	"golang.org/x/net/context"
+)
+
+type CmdPGPExtend struct {
+	fingerprints []string
+	all          bool
+	lifetime     int
+}
+
+func (v *CmdPGPExtend) ParseArgv(ctx *cli.Context) (err error) {
+	v.fingerprints = ctx.Args()
+	v.all = ctx.Bool("all")
+	v.lifetime, err = parseExpire(ctx.String("expire"), time.Now())
+	return err
+}
+
+func (v *CmdPGPExtend) Run() (err error) {
+	cli, err := GetPGPClient()
+	if err != nil {
+		return err
+	}
+
+	protocols := []rpc.Protocol{
+		NewSecretUIProtocol(G),
+	}
+	if err = RegisterProtocols(protocols); err != nil {
+		return err
+	}
+
+	return cli.PGPExtend(context.TODO(), keybase1.PGPExtendArg{
+		Fingerprints: v.fingerprints,
+		All:          v.all,
+		Lifetime:     v.lifetime,
+	})
+}
+
+func NewCmdPGPExtend(cl *libcmdline.CommandLine) cli.Command {
+	return cli.Command{
+		Name:         "extend",
+		ArgumentHelp: "[fingerprints...]",
+		Usage:        "Push back the expiration of your PGP keys",
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "all",
+				Usage: "Extend all of your keys.",
+			},
+			expireFlag("When the keys should expire (default: 16y)."),
+		},
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdPGPExtend{}, "extend", c)
+		},
+		Description: `'keybase pgp extend' re-signs the self-signatures of your PGP keys and
+   of their encryption subkeys with a later expiration, and posts the updated
+   public keys to the server. The secret keys come from the local Keybase
+   keyring, or else from GPG, and the updated keys are saved back there.
+   Signing subkeys keep their old expiration.
+
+   Only keys with the specified PGP fingerprints will be extended, unless the
+   '--all' flag is specified, in which case all PGP keys will be.`,
+	}
+}
+
+func (v *CmdPGPExtend) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:     true,
+		GpgKeyring: true,
+		KbKeyring:  true,
+		API:        true,
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/keybase/client/go/engine"
 	"github.com/keybase/client/go/libcmdline"
 	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/go-crypto/openpgp/packet"
 	rpc "github.com/keybase/go-framed-msgpack-rpc"
 )
 
@@ -34,6 +35,9 @@ func (v *CmdPGPGen) ParseArgv(ctx *cli.Context) (err error) {
 		if g.NoDefPGPUid && len(g.PGPUids) == 0 {
 			err = fmt.Errorf("if you don't want the default PGP uid, you must supply a PGP uid with the --pgp-uid option")
 		}
+		if ctx.Bool("ecc") {
+			g.Algorithm = packet.PubKeyAlgoEdDSA
+		}
 		if ctx.Bool("debug") {
 			g.PrimaryBits = SmallKey
 			g.SubkeyBits = SmallKey
@@ -88,7 +92,11 @@ func NewCmdPGPGen(cl *libcmdline.CommandLine) cli.Command {
 		Flags: []cli.Flag{
 			cli.BoolFlag{
 				Name:  "d, debug",
-				Usage: "Generate small keys for debugging.",
+				Usage: "Generate small RSA keys for debugging.",
+			},
+			cli.BoolFlag{
+				Name:  "ecc",
+				Usage: "Generate an Ed25519 key with a Curve25519 subkey instead of an RSA one.",
 			},
 			cli.StringSliceFlag{
 				Name:  "pgp-uid",
@@ -109,7 +117,9 @@ func NewCmdPGPGen(cl *libcmdline.CommandLine) cli.Command {
 			},
 		},
 		Description: `"keybase pgp gen" generates a new PGP key for this account.
-   In all cases, it signs the public key with an exising device key,
+   By default, it's a 4096-bit RSA key. With --ecc, it's an Ed25519
+   (EdDSA) signing key with a Curve25519 (ECDH) encryption subkey
+   instead; these need GnuPG 2.1 or later on the other end. In all cases, it signs the public key with an exising device key,
    and pushes the signature to the server. Thus, the user will have a
    publicly-visible "PGP device" after running this operation.
 
@@ -4,6 +4,8 @@
 package client
 
 import (
+	"errors"
+
 	"github.com/keybase/cli"
 	"github.com/keybase/client/go/libcmdline"
 	"github.com/keybase/client/go/libkb"
@@ -15,10 +17,15 @@ import (
 type CmdPGPPull struct {
 	libkb.Contextified
 	userAsserts []string
+	keyserver   bool
 }
 
 func (v *CmdPGPPull) ParseArgv(ctx *cli.Context) error {
 	v.userAsserts = ctx.Args()
+	v.keyserver = ctx.Bool("keyserver")
+	if v.keyserver && len(v.userAsserts) == 0 {
+		return errors.New("pull --keyserver needs the email addresses to look up")
+	}
 	return nil
 }
 
@@ -35,7 +42,8 @@ func (v *CmdPGPPull) Run() (err error) {
 	}
 
 	return cli.PGPPull(context.TODO(), keybase1.PGPPullArg{
-		UserAsserts: v.userAsserts,
+		UserAsserts:     v.userAsserts,
+		KeyserverLookup: v.keyserver,
 	})
 }
 
@@ -44,7 +52,12 @@ func NewCmdPGPPull(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comma
 		Name:         "pull",
 		ArgumentHelp: "[<usernames...>]",
 		Usage:        "Download the latest PGP keys for people you track.",
-		Flags:        []cli.Flag{},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "keyserver",
+				Usage: "Fetch the keys of people who aren't on Keybase, by email address.",
+			},
+		},
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(&CmdPGPPull{Contextified: libkb.NewContextified(g)}, "pull", c)
 		},
@@ -55,7 +68,13 @@ func NewCmdPGPPull(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Comma
    will be a plain import.
 
    If usernames (or user assertions) are supplied, only those tracked users
-   are pulled. Without arguments, all tracked users are pulled.`,
+   are pulled. Without arguments, all tracked users are pulled.
+
+   With --keyserver, the arguments are email addresses of people who aren't
+   on Keybase. Their keys are fetched from the Web Key Directory of their
+   domain, or else from the HKP keyserver (pgp.keyserver in the config), and
+   cached for "keybase pgp encrypt --keyserver". Nothing vouches for these
+   keys, so check their fingerprints.`,
 	}
 }
 
@@ -0,0 +1,87 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+	"golang.org/x/net/context"
+)
+
+type CmdPGPRotateSubkey struct {
+	fingerprints []string
+	all          bool
+	lifetime     int
+}
+
+func (v *CmdPGPRotateSubkey) ParseArgv(ctx *cli.Context) (err error) {
+	v.fingerprints = ctx.Args()
+	v.all = ctx.Bool("all")
+	v.lifetime, err = parseExpire(ctx.String("expire"), time.Now())
+	return err
+}
+
+func (v *CmdPGPRotateSubkey) Run() (err error) {
+	cli, err := GetPGPClient()
+	if err != nil {
+		return err
+	}
+
+	protocols := []rpc.Protocol{
+		NewSecretUIProtocol(G),
+	}
+	if err = RegisterProtocols(protocols); err != nil {
+		return err
+	}
+
+	return cli.PGPRotateSubkey(context.TODO(), keybase1.PGPRotateSubkeyArg{
+		Fingerprints: v.fingerprints,
+		All:          v.all,
+		Lifetime:     v.lifetime,
+	})
+}
+
+func NewCmdPGPRotateSubkey(cl *libcmdline.CommandLine) cli.Command {
+	return cli.Command{
+		Name:         "rotate-subkey",
+		ArgumentHelp: "[fingerprints...]",
+		Usage:        "Add a fresh encryption subkey to your PGP keys",
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "all",
+				Usage: "Rotate the subkeys of all of your keys.",
+			},
+			expireFlag("When the new subkey should expire (default: 16y)."),
+		},
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdPGPRotateSubkey{}, "rotate-subkey", c)
+		},
+		Description: `'keybase pgp rotate-subkey' generates a new encryption subkey for your
+   PGP keys, signs it in, and posts the updated public keys to the server.
+   New messages to you will be encrypted for the new subkey. The old subkeys
+   are kept, so that old messages can still be decrypted.
+
+   The secret keys come from the local Keybase keyring, or else from GPG.
+   Updated keys are saved back to the Keybase keyring. GPG only gets the new
+   public keys; to give it the new secret subkey, import it from
+   'keybase pgp export --secret'.
+
+   Only keys with the specified PGP fingerprints will be changed, unless the
+   '--all' flag is specified, in which case all PGP keys will be.`,
+	}
+}
+
+func (v *CmdPGPRotateSubkey) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config:     true,
+		GpgKeyring: true,
+		KbKeyring:  true,
+		API:        true,
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"os"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/pinentry"
+)
+
+// The options that gpg-agent passes to its pinentry program on the
+// command line, which it also sets with OPTION.
+var pinentryOptionFlags = []string{"display", "ttyname", "ttytype", "lc-ctype", "lc-messages"}
+
+func NewCmdPinentry(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	flags := []cli.Flag{
+		cli.IntFlag{
+			Name:  "timeout",
+			Usage: "Ignored; for compatibility with pinentry.",
+		},
+	}
+	for _, name := range pinentryOptionFlags {
+		flags = append(flags, cli.StringFlag{
+			Name:  name,
+			Usage: "Set by gpg-agent.",
+		})
+	}
+	return cli.Command{
+		Name:  "pinentry",
+		Usage: "Act as a pinentry program for GnuPG",
+		Description: `Speaks the pinentry protocol on stdin and stdout, and asks for
+   passphrases on the terminal that gpg runs in. To have gpg-agent use it,
+   save this script somewhere, say as ~/bin/pinentry-keybase:
+
+       #!/bin/sh
+       exec keybase pinentry "$@"
+
+   make it executable, and add this line to ~/.gnupg/gpg-agent.conf:
+
+       pinentry-program /home/you/bin/pinentry-keybase
+
+   Not supported on Windows.`,
+		Flags: flags,
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdPinentry{Contextified: libkb.NewContextified(g)}, "pinentry", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+			cl.SetLogForward(libcmdline.LogForwardNone)
+		},
+	}
+}
+
+type CmdPinentry struct {
+	libkb.Contextified
+	options map[string]string
+}
+
+func (v *CmdPinentry) ParseArgv(ctx *cli.Context) error {
+	v.options = make(map[string]string)
+	for _, name := range pinentryOptionFlags {
+		if s := ctx.String(name); len(s) > 0 {
+			v.options[name] = s
+		}
+	}
+	return nil
+}
+
+func (v *CmdPinentry) Run() error {
+	server := pinentry.NewServer(pinentry.TTYPrompt, v.G().Log)
+	for name, value := range v.options {
+		server.SetOption(name, value)
+	}
+	return server.Serve(os.Stdin, os.Stdout)
+}
+
+func (v *CmdPinentry) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
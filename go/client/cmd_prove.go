@@ -4,10 +4,15 @@
 package client
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/exec"
+	"runtime"
 	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -22,6 +27,13 @@ import (
 type CmdProve struct {
 	arg    keybase1.StartProofArg
 	output string
+
+	// For --auto.
+	auto       bool
+	noWait     bool
+	postedHook string
+	interval   time.Duration
+	timeout    time.Duration
 }
 
 // ParseArgv parses arguments for the prove command.
@@ -30,6 +42,18 @@ func (p *CmdProve) ParseArgv(ctx *cli.Context) error {
 	var err error
 	p.arg.Force = ctx.Bool("force")
 	p.output = ctx.String("output")
+	p.auto = ctx.Bool("auto")
+	p.noWait = ctx.Bool("no-wait")
+	p.postedHook = ctx.String("posted-hook")
+	p.interval = ctx.Duration("interval")
+	p.timeout = ctx.Duration("timeout")
+
+	if !p.auto && (p.noWait || len(p.postedHook) > 0) {
+		return fmt.Errorf("--no-wait and --posted-hook only go with --auto")
+	}
+	if p.auto && nargs != 2 {
+		return fmt.Errorf("prove --auto takes 2 args: <service> <username>")
+	}
 
 	if nargs > 2 || nargs == 0 {
 		err = fmt.Errorf("prove takes 1 or 2 args: <service> [<username>]")
@@ -55,6 +79,10 @@ func newProveUIProtocol(ui ProveUI) rpc.Protocol {
 
 // RunClient runs the `keybase prove` subcommand in client/server mode.
 func (p *CmdProve) Run() error {
+	if p.auto {
+		return p.runAuto()
+	}
+
 	var cli keybase1.ProveClient
 
 	proveUI := ProveUI{parent: GlobUI}
@@ -81,6 +109,115 @@ func (p *CmdProve) Run() error {
 	return err
 }
 
+// autoProof is what "keybase prove --auto" outputs, for the script or
+// bot that runs it to post.
+type autoProof struct {
+	SigID        keybase1.SigID `json:"sig_id"`
+	Service      string         `json:"service"`
+	RemoteName   string         `json:"remote_name"`
+	ProofText    string         `json:"proof_text"`
+	Instructions string         `json:"instructions"`
+}
+
+// runAuto makes the proof without prompting, outputs it as JSON, and
+// then, unless told not to, waits for it to be posted.  The exit code
+// says how that went; see ProofNotPostedError and ProofFailingError.
+func (p *CmdProve) runAuto() error {
+	protocols := []rpc.Protocol{
+		NewLogUIProtocol(),
+		NewSecretUIProtocol(G),
+	}
+	cli, err := GetProveClient()
+	if err != nil {
+		return err
+	}
+	if err = RegisterProtocols(protocols); err != nil {
+		return err
+	}
+
+	proof, err := cli.MakeProof(context.TODO(), keybase1.MakeProofArg{
+		Service:  p.arg.Service,
+		Username: p.arg.Username,
+		Force:    p.arg.Force,
+	})
+	if err != nil {
+		return err
+	}
+	var instructions bytes.Buffer
+	RenderText(&instructions, proof.Instructions)
+	out, err := json.MarshalIndent(autoProof{
+		SigID:        proof.SigID,
+		Service:      proof.Service,
+		RemoteName:   proof.RemoteName,
+		ProofText:    proof.ProofText,
+		Instructions: strings.TrimSpace(instructions.String()),
+	}, "", "    ")
+	if err != nil {
+		return err
+	}
+	if len(p.output) > 0 {
+		err = p.fileOutputHook(string(out) + "\n")
+	} else {
+		err = DisplayJSON(string(out))
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(p.postedHook) > 0 {
+		if err := p.runPostedHook(out); err != nil {
+			return err
+		}
+	}
+	if p.noWait {
+		return nil
+	}
+
+	res, err := cli.WaitForProof(context.TODO(), keybase1.WaitForProofArg{
+		SigID:        proof.SigID,
+		Service:      proof.Service,
+		Username:     proof.RemoteName,
+		IntervalSecs: int(p.interval / time.Second),
+		TimeoutSecs:  int(p.timeout / time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	if res.Found {
+		G.Log.Info("Success!")
+		return nil
+	}
+	// Not found, or not there yet, is worth waiting longer for; any
+	// other hard failure needs the post fixed.
+	if s := res.Status; s == keybase1.ProofStatus_NONE || s == keybase1.ProofStatus_NOT_FOUND ||
+		libkb.ProofErrorIsSoft(libkb.NewProofError(s, "")) {
+		return ProofNotPostedError{Status: s}
+	}
+	return ProofFailingError{Status: res.Status}
+}
+
+// runPostedHook runs the --posted-hook command, with the proof's JSON on
+// its standard input, to post it.  What it prints is taken to be where
+// it posted the proof.
+func (p *CmdProve) runPostedHook(proof []byte) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", p.postedHook)
+	} else {
+		cmd = exec.Command("sh", "-c", p.postedHook)
+	}
+	cmd.Stdin = bytes.NewReader(proof)
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("posted hook failed: %s", err)
+	}
+	if where := strings.TrimSpace(string(out)); len(where) > 0 {
+		G.Log.Info("Posted at %s", where)
+	}
+	return nil
+}
+
 func (p *CmdProve) installOutputHook(ui *ProveUI) {
 	if len(p.output) > 0 {
 		ui.outputHook = func(s string) error {
@@ -97,7 +234,15 @@ func NewCmdProve(cl *libcmdline.CommandLine) cli.Command {
 		Name:         "prove",
 		ArgumentHelp: "<service> [service username]",
 		Usage:        "Generate a new proof.",
-		Description:  description,
+		Description: description + `
+
+   With --auto, for scripts and bots, nothing is asked: the proof is
+   output as JSON, with the text to post in "proof_text", and then the
+   command waits for it to be posted. The --posted-hook command, if
+   given, is run with the JSON on its standard input to post it, and
+   what it prints is logged as where it was posted. The exit code is 0
+   once the proof is found, 4 if it isn't found in time, 5 if it's found
+   but its check fails, and 2 for any other error.`,
 		Flags: []cli.Flag{
 			cli.StringFlag{
 				Name:  "output, o",
@@ -107,6 +252,28 @@ func NewCmdProve(cl *libcmdline.CommandLine) cli.Command {
 				Name:  "force, f",
 				Usage: "Don't prompt.",
 			},
+			cli.BoolFlag{
+				Name:  "auto",
+				Usage: "Don't prompt or ask to check; output the proof as JSON and wait for it to be posted.",
+			},
+			cli.BoolFlag{
+				Name:  "no-wait",
+				Usage: "With --auto, don't wait for the proof to be posted.",
+			},
+			cli.StringFlag{
+				Name:  "posted-hook",
+				Usage: "With --auto, a command to run to post the proof.",
+			},
+			cli.DurationFlag{
+				Name:  "interval",
+				Value: 10 * time.Second,
+				Usage: "With --auto, how often to check for the proof.",
+			},
+			cli.DurationFlag{
+				Name:  "timeout",
+				Value: 10 * time.Minute,
+				Usage: "With --auto, how long to wait for the proof.",
+			},
 		},
 		Action: func(c *cli.Context) {
 			cl.ChooseCommand(&CmdProve{}, "prove", c)
@@ -92,6 +92,14 @@ func (d *notificationDisplay) UserChanged(_ context.Context, uid keybase1.UID) e
 	return d.printf("User %s changed\n", uid)
 }
 
+func (d *notificationDisplay) SigchainRollback(_ context.Context, arg keybase1.SigchainRollbackArg) error {
+	return d.printf("Possible sigchain rollback for %s (%s): %s\n", arg.Username, arg.Uid, arg.Reason)
+}
+
+func (d *notificationDisplay) TrackedUserReset(_ context.Context, arg keybase1.TrackedUserResetArg) error {
+	return d.printf("Tracked user %s (%s) reset their account: eldest key %s is now %s\n", arg.Username, arg.Uid, arg.TrackedEldest, arg.Eldest)
+}
+
 func (d *notificationDisplay) FSActivity(_ context.Context, notification keybase1.FSNotification) error {
 	return d.printf("KBFS notification: %+v\n", notification)
 }
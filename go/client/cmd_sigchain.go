@@ -0,0 +1,181 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	"golang.org/x/net/context"
+)
+
+func NewCmdSigchain(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "sigchain",
+		Usage: "Inspect and verify a user's sigchain",
+		Subcommands: []cli.Command{
+			newCmdSigchainSub(cl, g, "show", "Show each link of a user's sigchain", false),
+			newCmdSigchainSub(cl, g, "verify", "Check each link of a user's sigchain again, and its tail in the Merkle tree", true),
+		},
+	}
+}
+
+type CmdSigchain struct {
+	libkb.Contextified
+	username string
+	verify   bool
+	json     bool
+}
+
+func newCmdSigchainSub(cl *libcmdline.CommandLine, g *libkb.GlobalContext, name, usage string, verify bool) cli.Command {
+	return cli.Command{
+		Name:         name,
+		ArgumentHelp: "[username]",
+		Usage:        usage,
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output as JSON (default is text).",
+			},
+		},
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdSigchain{Contextified: libkb.NewContextified(g), verify: verify}, name, c)
+		},
+	}
+}
+
+func (c *CmdSigchain) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) > 1 {
+		return fmt.Errorf("Takes at most 1 arg, a username.")
+	}
+	if len(ctx.Args()) == 1 {
+		c.username = ctx.Args()[0]
+	}
+	c.json = ctx.Bool("json")
+	return nil
+}
+
+func (c *CmdSigchain) Run() error {
+	cli, err := GetSigchainClient(c.G())
+	if err != nil {
+		return err
+	}
+	if err = RegisterProtocols(nil); err != nil {
+		return err
+	}
+	var res keybase1.SigchainRes
+	if c.verify {
+		res, err = cli.SigchainVerify(context.TODO(), keybase1.SigchainVerifyArg{Username: c.username})
+	} else {
+		res, err = cli.SigchainShow(context.TODO(), keybase1.SigchainShowArg{Username: c.username})
+	}
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		b, err := json.MarshalIndent(res, "", "    ")
+		if err != nil {
+			return err
+		}
+		if err = DisplayJSON(string(b)); err != nil {
+			return err
+		}
+	} else {
+		c.display(res)
+	}
+	if c.verify && !res.Verified {
+		return fmt.Errorf("%s's sigchain didn't verify", res.Username)
+	}
+	return nil
+}
+
+func (c *CmdSigchain) display(res keybase1.SigchainRes) {
+	GlobUI.Printf("Sigchain of %s (%s), %d links\n", res.Username, res.Uid, len(res.Links))
+	for _, l := range res.Links {
+		GlobUI.Printf("\n%s\n", c.heading(l))
+		GlobUI.Printf("  sig id:     %s\n", l.SigID)
+		GlobUI.Printf("  link id:    %s\n", l.LinkID)
+		if len(l.Prev) > 0 {
+			GlobUI.Printf("  prev:       %s\n", l.Prev)
+		}
+		GlobUI.Printf("  signed by:  %s\n", l.Kid)
+		if l.Delegates.Exists() {
+			GlobUI.Printf("  delegates:  %s\n", ColorString("yellow", l.Delegates.String()))
+		}
+		for _, kid := range l.RevokesKIDs {
+			GlobUI.Printf("  revokes key: %s\n", ColorString("red", kid.String()))
+		}
+		for _, sigID := range l.Revokes {
+			GlobUI.Printf("  revokes sig: %s\n", ColorString("red", sigID.ToDisplayString(true)))
+		}
+		if c.verify {
+			GlobUI.Printf("  check:      %s\n", linkCheckString(l))
+		}
+		var payload bytes.Buffer
+		if err := json.Indent(&payload, []byte(l.PayloadJSON), "    ", "  "); err != nil {
+			payload.WriteString(l.PayloadJSON)
+		}
+		GlobUI.Printf("  payload:\n    %s\n", payload.String())
+	}
+	if c.verify {
+		GlobUI.Printf("\nMerkle tree tail: seqno %d, %s", res.MerkleSeqno, res.MerkleLinkID)
+		if len(res.MerkleError) > 0 {
+			GlobUI.Printf(" %s\n", ColorString("red", res.MerkleError))
+		} else {
+			GlobUI.Printf(" %s\n", ColorString("green", "matches"))
+		}
+	}
+}
+
+func (c *CmdSigchain) heading(l keybase1.SigchainLink) string {
+	s := fmt.Sprintf("#%d %s, %s", l.Seqno, ColorString("bold", l.Type), keybase1.FormatTime(l.CTime))
+	if l.ETime > 0 {
+		s += ", expires " + keybase1.FormatTime(l.ETime)
+	}
+	if l.KeyFamilyChange {
+		s += " " + ColorString("yellow", "[key change]")
+	}
+	if l.Revoked {
+		s += " " + ColorString("red", "[revoked]")
+	}
+	return s
+}
+
+func linkCheckString(l keybase1.SigchainLink) string {
+	if len(l.Ignored) > 0 {
+		return ColorString("magenta", "ignored: "+l.Ignored)
+	}
+	var errs []string
+	for _, e := range []struct{ what, err string }{
+		{"hash", l.HashError},
+		{"payload", l.PayloadError},
+		{"signature", l.SigError},
+		{"chain", l.ChainError},
+	} {
+		if len(e.err) > 0 {
+			errs = append(errs, e.what+": "+e.err)
+		}
+	}
+	if len(errs) > 0 {
+		return ColorString("red", "FAILED ("+strings.Join(errs, "; ")+")")
+	}
+	if !l.Checked {
+		return ColorString("green", "ok") + " (signature not checked; from before the last account reset)"
+	}
+	return ColorString("green", "ok")
+}
+
+func (c *CmdSigchain) GetUsage() libkb.Usage {
+	return libkb.Usage{
+		Config: true,
+		API:    true,
+	}
+}
@@ -6,6 +6,7 @@ package client
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"golang.org/x/net/context"
 
@@ -87,6 +88,10 @@ func (v *CmdStatus) printExportedMe(me keybase1.User, publicKeys []keybase1.Publ
 	for _, device := range devices {
 		if device.DeviceID == G.Env.GetDeviceID() {
 			GlobUI.Printf("Device name: %s\n", device.Name)
+			GlobUI.Printf("Device expires: %s\n", formatDeviceExpiry(device.ETime))
+			if warning := deviceExpiryWarning(device, time.Now()); len(warning) > 0 {
+				G.Log.Warning(warning)
+			}
 		}
 	}
 	if len(publicKeys) == 0 {
@@ -107,6 +112,9 @@ func (v *CmdStatus) printExportedMe(me keybase1.User, publicKeys []keybase1.Publ
 		if err != nil {
 			return err
 		}
+		for _, warning := range pgpExpiryWarnings(key, time.Now()) {
+			G.Log.Warning("%s", warning)
+		}
 		for _, subkey := range subkeys {
 			subkeysShown[subkey.KID] = true
 		}
@@ -144,6 +152,12 @@ func printKey(key keybase1.PublicKey, subkeys []keybase1.PublicKey, indent int)
 			}
 			GlobUI.Printf("%s%s%s%s\n", indentSpace(indent+2), identity.Username, commentStr, emailStr)
 		}
+		if key.PGPKeyETime != 0 {
+			GlobUI.Printf("%sPGP Key Expires: %s\n", indentSpace(indent+1), keybase1.FromTime(key.PGPKeyETime).Format(expireDateLayout))
+		}
+		if key.PGPSubkeyETime != 0 {
+			GlobUI.Printf("%sPGP Encryption Subkey Expires: %s\n", indentSpace(indent+1), keybase1.FromTime(key.PGPSubkeyETime).Format(expireDateLayout))
+		}
 	}
 	if key.DeviceID != "" || key.DeviceType != "" || key.DeviceDescription != "" {
 		GlobUI.Printf("%sDevice:\n", indentSpace(indent+1))
@@ -0,0 +1,91 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libcmdline"
+	"github.com/keybase/client/go/libkb"
+	"golang.org/x/net/context"
+)
+
+func NewCmdWhoami(cl *libcmdline.CommandLine, g *libkb.GlobalContext) cli.Command {
+	return cli.Command{
+		Name:  "whoami",
+		Usage: "Show the active account and the others with sessions",
+		Action: func(c *cli.Context) {
+			cl.ChooseCommand(&CmdWhoami{Contextified: libkb.NewContextified(g)}, "whoami", c)
+			cl.SetForkCmd(libcmdline.NoFork)
+		},
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "j, json",
+				Usage: "Output as JSON (default is text).",
+			},
+		},
+	}
+}
+
+type CmdWhoami struct {
+	libkb.Contextified
+	json bool
+}
+
+func (c *CmdWhoami) ParseArgv(ctx *cli.Context) error {
+	if len(ctx.Args()) > 0 {
+		return errors.New("whoami doesn't take any arguments")
+	}
+	c.json = ctx.Bool("json")
+	return nil
+}
+
+func (c *CmdWhoami) Run() error {
+	cli, err := GetLoginClient(c.G())
+	if err != nil {
+		return err
+	}
+	accounts, err := cli.GetAccountSessions(context.TODO(), 0)
+	if err != nil {
+		return err
+	}
+
+	if c.json {
+		b, err := json.MarshalIndent(accounts, "", "    ")
+		if err != nil {
+			return err
+		}
+		return DisplayJSON(string(b))
+	}
+
+	if len(accounts) == 0 {
+		GlobUI.Printf("No accounts are configured on this device.\n")
+		return nil
+	}
+
+	i := 0
+	rowfunc := func() []string {
+		if i >= len(accounts) {
+			return nil
+		}
+		a := accounts[i]
+		i++
+		active, session := "", "logged out"
+		if a.Active {
+			active = "*"
+		}
+		if a.HasSession {
+			session = "logged in"
+		}
+		return []string{active, a.Username, session}
+	}
+	GlobUI.Tablify([]string{"", "Username", "Session"}, rowfunc)
+	return nil
+}
+
+func (c *CmdWhoami) GetUsage() libkb.Usage {
+	return libkb.Usage{}
+}
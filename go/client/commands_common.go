@@ -12,6 +12,7 @@ import (
 
 func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Command {
 	ret := []cli.Command{
+		NewCmdAuth(cl, g),
 		NewCmdBTC(cl, g),
 		NewCmdCert(cl),
 		NewCmdCompatDecrypt(cl),
@@ -22,20 +23,24 @@ func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Comma
 		NewCmdCompatVerify(cl),
 		NewCmdConfig(cl),
 		NewCmdCtl(cl, g),
+		NewCmdCurrency(cl, g),
 		NewCmdDb(cl, g),
 		NewCmdDeprovision(cl, g),
 		NewCmdDevice(cl, g),
 		NewCmdID(cl, g),
 		NewCmdListTracking(cl),
 		NewCmdListTrackers(cl),
+		NewCmdLock(cl, g),
 		NewCmdLogin(cl, g),
 		NewCmdLogout(cl, g),
 		NewCmdPaperKey(cl),
 		NewCmdPassphrase(cl),
 		NewCmdPGP(cl, g),
+		NewCmdPinentry(cl, g),
 		NewCmdPing(cl),
 		NewCmdProve(cl),
 		NewCmdSearch(cl),
+		NewCmdSigchain(cl, g),
 		NewCmdSigs(cl),
 		NewCmdSignup(cl, g),
 		NewCmdStatus(cl),
@@ -43,6 +48,7 @@ func GetCommands(cl *libcmdline.CommandLine, g *libkb.GlobalContext) []cli.Comma
 		NewCmdUnlock(cl),
 		NewCmdUntrack(cl),
 		NewCmdVersion(cl, g),
+		NewCmdWhoami(cl, g),
 	}
 	ret = append(ret, getBuildSpecificCommands(cl, g)...)
 	ret = append(ret, getPlatformSpecificCommands(cl, g)...)
@@ -7,4 +7,5 @@ package client
 
 var CHECK = "✔"
 var BADX = "✖"
+var SKIP = "⊘"
 var BTC = "฿"
@@ -5,4 +5,5 @@ package client
 
 var CHECK = "OK"
 var BADX = "BAD"
+var SKIP = "SKIP"
 var BTC = "BTC"
@@ -4,6 +4,8 @@
 package client
 
 import (
+	"fmt"
+
 	"github.com/keybase/client/go/libkb"
 	keybase1 "github.com/keybase/client/go/protocol"
 )
@@ -81,3 +83,27 @@ type ProofNotYetAvailableError struct{}
 func (e ProofNotYetAvailableError) Error() string {
 	return "Proof wasn't available; we'll keep trying"
 }
+
+// ProofNotPostedError is raised when a proof from "keybase prove --auto"
+// isn't found before the timeout.
+type ProofNotPostedError struct {
+	Status keybase1.ProofStatus
+}
+
+func (e ProofNotPostedError) Error() string {
+	return fmt.Sprintf("The proof wasn't found in time (status %d)", e.Status)
+}
+
+func (e ProofNotPostedError) ExitCode() int { return 4 }
+
+// ProofFailingError is raised when a proof from "keybase prove --auto"
+// was posted, but its check fails.
+type ProofFailingError struct {
+	Status keybase1.ProofStatus
+}
+
+func (e ProofFailingError) Error() string {
+	return fmt.Sprintf("The proof was posted, but its check fails (status %d)", e.Status)
+}
+
+func (e ProofFailingError) ExitCode() int { return 5 }
@@ -0,0 +1,115 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/keybase/cli"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+const expireDateLayout = "2006-01-02"
+
+var expireUnits = map[byte]time.Duration{
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+	'm': 30 * 24 * time.Hour,
+	'y': 365 * 24 * time.Hour,
+}
+
+func expireFlag(usage string) cli.Flag {
+	return cli.StringFlag{
+		Name:  "e, expire",
+		Usage: usage + " Either a duration (like 90d, 6m or 2y) or a date (YYYY-MM-DD).",
+	}
+}
+
+// parseExpire turns the argument of an --expire flag into a number of
+// seconds from now.  An empty string gives 0, meaning the default.
+func parseExpire(s string, now time.Time) (int, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return 0, nil
+	}
+
+	var d time.Duration
+	if t, err := time.ParseInLocation(expireDateLayout, s, time.Local); err == nil {
+		d = t.Sub(now)
+	} else if unit, ok := expireUnits[s[len(s)-1]]; ok {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("bad expiration %q", s)
+		}
+		d = time.Duration(n) * unit
+	} else {
+		return 0, fmt.Errorf("bad expiration %q: expected a duration like 90d or a date like %s", s, expireDateLayout)
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("expiration %q is in the past", s)
+	}
+	return int(d / time.Second), nil
+}
+
+// formatDeviceExpiry describes when a device's keys expire, or "-" if
+// that isn't known.
+func formatDeviceExpiry(etime keybase1.Time) string {
+	if etime == 0 {
+		return "-"
+	}
+	return keybase1.FromTime(etime).Format(expireDateLayout)
+}
+
+// deviceExpiryWarning returns a warning if a device's keys have
+// expired or are about to, and an empty string otherwise.
+func deviceExpiryWarning(dev keybase1.Device, now time.Time) string {
+	if dev.ETime == 0 {
+		return ""
+	}
+	etime := keybase1.FromTime(dev.ETime)
+	if etime.Before(now) {
+		return fmt.Sprintf("The keys of device %q expired on %s.", dev.Name, etime.Format(expireDateLayout))
+	}
+	if etime.Sub(now) < libkb.DeviceExpiryWarning {
+		return fmt.Sprintf("The keys of device %q expire on %s. Run `keybase device extend` on that device to keep using it.", dev.Name, etime.Format(expireDateLayout))
+	}
+	return ""
+}
+
+// pgpExpiryWarnings warns about a PGP key that has expired or is about
+// to, or whose encryption subkeys have.
+func pgpExpiryWarnings(key keybase1.PublicKey, now time.Time) []string {
+	if key.PGPFingerprint == "" {
+		return nil
+	}
+	fp := libkb.PGPFingerprintFromHexNoError(key.PGPFingerprint).ToKeyID()
+	check := func(etime keybase1.Time, what, fix string) string {
+		if etime == 0 {
+			return ""
+		}
+		t := keybase1.FromTime(etime)
+		if t.Before(now) {
+			return fmt.Sprintf("%s %s expired on %s. %s", what, fp, t.Format(expireDateLayout), fix)
+		}
+		if t.Sub(now) < libkb.PGPExpiryWarning {
+			return fmt.Sprintf("%s %s expires on %s. %s", what, fp, t.Format(expireDateLayout), fix)
+		}
+		return ""
+	}
+
+	var ret []string
+	if w := check(key.PGPKeyETime, "PGP key", "Run `keybase pgp extend` to keep using it."); len(w) > 0 {
+		ret = append(ret, w)
+	}
+	if w := check(key.PGPSubkeyETime, "The encryption subkey of PGP key", "Run `keybase pgp extend` or `keybase pgp rotate-subkey` to keep receiving messages."); len(w) > 0 {
+		ret = append(ret, w)
+	}
+	return ret
+}
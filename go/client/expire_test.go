@@ -0,0 +1,69 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+func TestParseExpire(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.Local)
+	day := 24 * 60 * 60
+
+	good := map[string]int{
+		"":           0,
+		"12h":        12 * 60 * 60,
+		"90d":        90 * day,
+		"2w":         14 * day,
+		"6m":         180 * day,
+		"1y":         365 * day,
+		"2016-01-31": 30 * day,
+	}
+	for s, want := range good {
+		got, err := parseExpire(s, now)
+		if err != nil {
+			t.Errorf("%q: %s", s, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%q: got %d, expected %d", s, got, want)
+		}
+	}
+
+	for _, s := range []string{"soon", "0d", "-3d", "d", "10x", "2015-12-31"} {
+		if _, err := parseExpire(s, now); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestPGPExpiryWarnings(t *testing.T) {
+	now := time.Date(2016, 1, 1, 0, 0, 0, 0, time.Local)
+	day := 24 * time.Hour
+	key := keybase1.PublicKey{PGPFingerprint: "be6bbc0ad3ca92b4af7b82ad2b9bc9bc8c7bb8a1"}
+
+	key.PGPKeyETime = keybase1.ToTime(now.Add(365 * day))
+	key.PGPSubkeyETime = keybase1.ToTime(now.Add(365 * day))
+	if w := pgpExpiryWarnings(key, now); len(w) != 0 {
+		t.Errorf("unexpected warnings: %v", w)
+	}
+
+	key.PGPSubkeyETime = keybase1.ToTime(now.Add(10 * day))
+	if w := pgpExpiryWarnings(key, now); len(w) != 1 || !strings.Contains(w[0], "subkey of PGP key 2B9BC9BC8C7BB8A1 expires on 2016-01-11") {
+		t.Errorf("bad warnings for a subkey about to expire: %v", w)
+	}
+
+	key.PGPKeyETime = keybase1.ToTime(now.Add(-day))
+	if w := pgpExpiryWarnings(key, now); len(w) != 2 || !strings.Contains(w[0], "expired on 2015-12-31") {
+		t.Errorf("bad warnings for an expired key: %v", w)
+	}
+
+	if w := pgpExpiryWarnings(keybase1.PublicKey{ETime: keybase1.ToTime(now)}, now); len(w) != 0 {
+		t.Errorf("warnings for a key that isn't PGP: %v", w)
+	}
+}
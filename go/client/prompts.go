@@ -41,4 +41,5 @@ const (
 	PromptDescriptorProvisionDeviceName
 	PromptDescriptorExportSecretKeyFromGPG
 	PromptDescriptorDeprovisionWhichUser
+	PromptDescriptorAuthSignChallenge
 )
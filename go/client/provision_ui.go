@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"sync"
 
 	"golang.org/x/net/context"
 
@@ -95,51 +96,163 @@ func (p ProvisionUI) DisplayAndPromptSecret(ctx context.Context, arg keybase1.Di
 	var resp keybase1.SecretResponse
 	if p.role == libkb.KexRoleProvisioner {
 		// This is the provisioner device (device X)
-		// For command line app, all secrets are entered on the provisioner only:
-		p.parent.Output("\nEnter the verification code from your other device here.  To get\n")
-		p.parent.Output("a verification code, run 'keybase login' on your other device.\n\n")
-
-		ret, err := PromptWithChecker(PromptDescriptorProvisionPhrase, p.parent, "Verification code", false, libkb.CheckNotEmpty)
-		if err != nil {
-			return resp, err
+		// For command line app, all secrets are entered on the provisioner,
+		// but a phone can scan this device's code instead:
+		if arg.OtherDeviceType == keybase1.DeviceType_MOBILE && len(arg.Payload) > 0 {
+			p.parent.Output("\nScan this QR Code with the keybase app on your mobile phone")
+			if len(arg.PayloadText) > 0 {
+				p.parent.Output(", or type in\nthis code there:\n\n")
+				p.parent.Output("\t" + arg.PayloadText + "\n\n")
+			} else {
+				p.parent.Output(":\n\n")
+			}
+			p.displayQRCode(arg.Payload)
+			p.parent.Output("\nOr, if your phone shows you a code, enter it here.\n\n")
+		} else {
+			p.parent.Output("\nEnter the verification code from your other device here.  To get\n")
+			p.parent.Output("a verification code, run 'keybase login' on your other device.\n\n")
+			if code := displayedCode(arg); len(code) > 0 {
+				p.parent.Output("Or, if it asks for a code, type this one there:\n\n")
+				p.parent.Output("\t" + code + "\n\n")
+			}
 		}
-		resp.Phrase = ret
-		return resp, nil
 
+		return p.promptSecret(ctx, false)
 	}
 
 	if p.role == libkb.KexRoleProvisionee {
 		// this is the provisionee device (device Y)
-		// For command line app, the provisionee displays secrets only
+		// The code can be typed on either device, so this one shows its
+		// own and prompts for the other's.
 
 		p.parent.Output("Type this verification code into your other device:\n\n")
 		p.parent.Output("\t" + arg.Phrase + "\n\n")
+		if len(arg.PayloadText) > 0 {
+			p.parent.Output("Or this shorter code, which works just as well:\n\n")
+			p.parent.Output("\t" + arg.PayloadText + "\n\n")
+		}
 		p.parent.Output("If you are using the command line client on your other device, run this command:\n\n")
 		p.parent.Output("\tkeybase device add\n\n")
 		p.parent.Output("It will then prompt you for the verification code above.\n\n")
 
 		if arg.OtherDeviceType == keybase1.DeviceType_MOBILE {
-			encodings, err := qrcode.Encode([]byte(arg.Phrase))
-			// ignoring any of these errors...phrase above will suffice.
-			if err == nil {
-				p.parent.Output("Or, scan this QR Code with the keybase app on your mobile phone:\n\n")
-				p.parent.Output(encodings.Terminal)
-				fname := path.Join(os.TempDir(), "keybase_qr.png")
-				f, ferr := os.Create(fname)
-				if ferr == nil {
-					f.Write(encodings.PNG)
-					f.Close()
-					p.parent.Printf("\nThere's also a PNG version in %s that might work better.\n\n", fname)
-				}
+			qr := arg.Payload
+			if len(qr) == 0 {
+				qr = []byte(arg.Phrase)
 			}
+			p.parent.Output("Or, scan this QR Code with the keybase app on your mobile phone:\n\n")
+			p.displayQRCode(qr)
 		}
-		return resp, nil
+		p.parent.Output("\nOr, if your other device shows you a code instead, enter it here.\n\n")
+		return p.promptSecret(ctx, true)
 	}
 
 	return resp, libkb.InvalidArgumentError{Msg: fmt.Sprintf("invalid ProvisionUI role: %d", p.role)}
 }
 
+// displayedCode is the code for the other device to type in, preferring
+// the shorter one.
+func displayedCode(arg keybase1.DisplayAndPromptSecretArg) string {
+	if len(arg.PayloadText) > 0 {
+		return arg.PayloadText
+	}
+	return arg.Phrase
+}
+
+// secretPromptMu is held while a prompt for the other device's secret
+// reads from the terminal.  A read can't be interrupted, so when the
+// other device takes this device's code instead, the prompt is only done
+// once the user presses Enter, and later prompts wait for that.
+var secretPromptMu sync.Mutex
+
+type secretPromptRes struct {
+	resp keybase1.SecretResponse
+	err  error
+}
+
+// promptSecret prompts for the other device's secret phrase or code
+// until one is entered, or ctx is canceled because the other device took
+// this one's code.  enterToContinue is whether to ask for Enter then,
+// for the prompts that follow.
+func (p ProvisionUI) promptSecret(ctx context.Context, enterToContinue bool) (keybase1.SecretResponse, error) {
+	secretPromptMu.Lock()
+	ch := make(chan secretPromptRes, 1)
+	go func() {
+		defer secretPromptMu.Unlock()
+		resp, err := p.readSecret(ctx)
+		ch <- secretPromptRes{resp, err}
+	}()
+	select {
+	case res := <-ch:
+		return res.resp, res.err
+	case <-ctx.Done():
+		if enterToContinue {
+			p.parent.Output("\n\nYour other device took this device's code.  Press Enter to continue.\n")
+		}
+		return keybase1.SecretResponse{}, ctx.Err()
+	}
+}
+
+// readSecret reads the other device's secret phrase, or its code, which
+// is checked here so that typos can be fixed.
+func (p ProvisionUI) readSecret(ctx context.Context) (keybase1.SecretResponse, error) {
+	var resp keybase1.SecretResponse
+	checker := libkb.Checker{
+		F: func(s string) bool {
+			return ctx.Err() != nil || libkb.CheckNotEmpty.F(s)
+		},
+		Hint: libkb.CheckNotEmpty.Hint,
+	}
+	for i := 0; i < 10; i++ {
+		ret, err := PromptWithChecker(PromptDescriptorProvisionPhrase, p.parent, "Verification code", false, checker)
+		if err != nil {
+			return resp, err
+		}
+		if ctx.Err() != nil {
+			return resp, ctx.Err()
+		}
+		if !libkb.IsKex2PayloadText(ret) {
+			resp.Phrase = ret
+			return resp, nil
+		}
+		payload, err := libkb.ParseKex2PayloadText(ret)
+		if err == nil {
+			err = payload.Check(G, p.role)
+		}
+		if err == nil {
+			resp.Payload, err = payload.Encode()
+		}
+		if err != nil {
+			p.parent.Printf("%s\n\n", err)
+			continue
+		}
+		return resp, nil
+	}
+	return resp, libkb.RetryExhaustedError{}
+}
+
+// displayQRCode shows data as a QR code, and writes it to a PNG file too.
+// Errors are ignored, since the codes shown as text will suffice.
+func (p ProvisionUI) displayQRCode(data []byte) {
+	encodings, err := qrcode.Encode(data)
+	if err != nil {
+		return
+	}
+	p.parent.Output(encodings.Terminal)
+	fname := path.Join(os.TempDir(), "keybase_qr.png")
+	f, ferr := os.Create(fname)
+	if ferr == nil {
+		f.Write(encodings.PNG)
+		f.Close()
+		p.parent.Printf("\nThere's also a PNG version in %s that might work better.\n\n", fname)
+	}
+}
+
 func (p ProvisionUI) PromptNewDeviceName(ctx context.Context, arg keybase1.PromptNewDeviceNameArg) (string, error) {
+	// Wait for a secret prompt to finish reading.
+	secretPromptMu.Lock()
+	secretPromptMu.Unlock()
+
 	for i := 0; i < 10; i++ {
 
 		name, err := PromptWithChecker(PromptDescriptorProvisionDeviceName, p.parent, "Enter a public name for this device", false, libkb.CheckDeviceName)
@@ -4,9 +4,12 @@
 package client
 
 import (
+	"sync"
+
 	"github.com/keybase/client/go/libkb"
 	keybase1 "github.com/keybase/client/go/protocol"
 	rpc "github.com/keybase/go-framed-msgpack-rpc"
+	"golang.org/x/net/context"
 )
 
 func GetRPCClient() (ret *rpc.Client, xp rpc.Transporter, err error) {
@@ -16,10 +19,34 @@ func GetRPCClient() (ret *rpc.Client, xp rpc.Transporter, err error) {
 func GetRPCClientWithContext(g *libkb.GlobalContext) (ret *rpc.Client, xp rpc.Transporter, err error) {
 	if _, xp, err = g.GetSocket(false); err == nil {
 		ret = rpc.NewClient(xp, libkb.ErrorUnwrapper{})
+		err = selectAccount(g, ret, xp)
 	}
 	return
 }
 
+var selectedAccountMu sync.Mutex
+var selectedAccountXp rpc.Transporter
+
+// selectAccount pins the connection to the account given with --account
+// (or KEYBASE_ACCOUNT), once per connection to the service.
+func selectAccount(g *libkb.GlobalContext, cli *rpc.Client, xp rpc.Transporter) error {
+	account := g.Env.GetAccount()
+	if len(account) == 0 {
+		return nil
+	}
+	selectedAccountMu.Lock()
+	defer selectedAccountMu.Unlock()
+	if selectedAccountXp == xp {
+		return nil
+	}
+	lcli := keybase1.LoginClient{Cli: cli}
+	if err := lcli.SelectAccount(context.TODO(), keybase1.SelectAccountArg{Username: account.String()}); err != nil {
+		return err
+	}
+	selectedAccountXp = xp
+	return nil
+}
+
 func GetRPCServer(g *libkb.GlobalContext) (ret *rpc.Server, xp rpc.Transporter, err error) {
 	if _, xp, err = g.GetSocket(false); err == nil {
 		ret = rpc.NewServer(xp, libkb.WrapError)
@@ -123,6 +150,14 @@ func GetSigsClient() (cli keybase1.SigsClient, err error) {
 	return
 }
 
+func GetSigchainClient(g *libkb.GlobalContext) (cli keybase1.SigchainClient, err error) {
+	var rcli *rpc.Client
+	if rcli, _, err = GetRPCClientWithContext(g); err == nil {
+		cli = keybase1.SigchainClient{Cli: rcli}
+	}
+	return
+}
+
 func GetPGPClient() (cli keybase1.PGPClient, err error) {
 	var rcli *rpc.Client
 	if rcli, _, err = GetRPCClient(); err == nil {
@@ -147,6 +182,14 @@ func GetBTCClient(g *libkb.GlobalContext) (cli keybase1.BTCClient, err error) {
 	return
 }
 
+func GetCryptocurrencyClient(g *libkb.GlobalContext) (cli keybase1.CryptocurrencyClient, err error) {
+	var rcli *rpc.Client
+	if rcli, _, err = GetRPCClientWithContext(g); err == nil {
+		cli = keybase1.CryptocurrencyClient{Cli: rcli}
+	}
+	return
+}
+
 func GetCtlClient(g *libkb.GlobalContext) (cli keybase1.CtlClient, err error) {
 	var rcli *rpc.Client
 	if rcli, _, err = GetRPCClientWithContext(g); err == nil {
@@ -188,3 +231,19 @@ func GetKBFSClient(g *libkb.GlobalContext) (cli keybase1.KbfsClient, err error)
 	cli = keybase1.KbfsClient{Cli: rcli}
 	return cli, nil
 }
+
+func GetCryptoClient(g *libkb.GlobalContext) (cli keybase1.CryptoClient, err error) {
+	var rcli *rpc.Client
+	if rcli, _, err = GetRPCClientWithContext(g); err == nil {
+		cli = keybase1.CryptoClient{Cli: rcli}
+	}
+	return
+}
+
+func GetSessionClient(g *libkb.GlobalContext) (cli keybase1.SessionClient, err error) {
+	var rcli *rpc.Client
+	if rcli, _, err = GetRPCClientWithContext(g); err == nil {
+		cli = keybase1.SessionClient{Cli: rcli}
+	}
+	return
+}
@@ -92,6 +92,25 @@ func (ui IdentifyTrackUI) ReportRevoked(del []keybase1.TrackDiff) {
 	}
 }
 
+// ReportReset explains that a tracked user reset their account, and
+// which of the proofs that were tracked they've made again since.
+func (ui IdentifyTrackUI) ReportReset(username string, r keybase1.AccountReset) {
+	when := "since you tracked them"
+	if r.Ctime > 0 {
+		when = "on " + keybase1.FormatTime(r.Ctime) + ", after you tracked them"
+	}
+	ui.ReportHook(ColorString("red", fmt.Sprintf("%s reset their account %s.", username, when)))
+	ui.ReportHook(fmt.Sprintf("Their eldest key was %s and is now %s, so nothing you tracked vouches for their new keys.", r.TrackedEldest, r.Eldest))
+	if len(r.CarriedOver) == 0 {
+		ui.ReportHook("None of the proofs you tracked are on the new account.")
+		return
+	}
+	ui.ReportHook("These proofs you tracked are on the new account too:")
+	for _, p := range r.CarriedOver {
+		ui.ReportHook("    " + p)
+	}
+}
+
 func (ui IdentifyTrackUI) Confirm(o *keybase1.IdentifyOutcome) (confirmed bool, err error) {
 	confirmed = false
 	var prompt string
@@ -139,6 +158,14 @@ func (ui IdentifyTrackUI) Confirm(o *keybase1.IdentifyOutcome) (confirmed bool,
 		promptDefault = libkb.PromptDefaultYes
 	}
 
+	// A reset breaks the tracking statement, but there's nothing left of
+	// it to fix; tracking them again is tracking a new account.
+	if o.Reset != nil {
+		ui.ReportReset(username, *o.Reset)
+		prompt = "Track " + username + "'s new account?"
+		promptDefault = libkb.PromptDefaultNo
+	}
+
 	// Tracking statement exists and is unchanged, nothing to do
 	if !trackChanged {
 		confirmed = true
@@ -209,6 +236,18 @@ func (w LinkCheckResultWrapper) GetTorWarning() bool {
 	return w.lcr.TorWarning
 }
 
+func (w LinkCheckResultWrapper) GetTorCheck() keybase1.TorCheck {
+	return w.lcr.TorCheck
+}
+
+// TorCheckString notes that a proof was checked over Tor.
+func (w LinkCheckResultWrapper) TorCheckString() string {
+	if w.GetTorCheck() != keybase1.TorCheck_VERIFIED {
+		return ""
+	}
+	return " " + ColorString("blue", "(checked over Tor)")
+}
+
 func (w LinkCheckResultWrapper) GetError() error {
 	return libkb.ImportProofError(w.lcr.ProofResult)
 }
@@ -268,7 +307,12 @@ func (ui BaseIdentifyUI) FinishSocialProofCheck(p keybase1.RemoteProof, l keybas
 	if err := lcr.GetError(); err == nil {
 		msg += (CHECK + " " + lcrs + `"` +
 			ColorString("green", run) + `" on ` + s.GetService() +
-			": " + lcr.GetHint().GetHumanURL())
+			": " + lcr.GetHint().GetHumanURL() + lcr.TorCheckString())
+	} else if lcr.GetTorCheck() == keybase1.TorCheck_SKIPPED {
+		msg += (SKIP + " " + lcrs +
+			ColorString("yellow", `"`+run+`" on `+s.GetService()+" "+
+				ColorString("bold", "not checked")+": "+
+				err.Error()))
 	} else {
 		msg += (BADX + " " + lcrs +
 			ColorString("red", `"`+run+`" on `+s.GetService()+" "+
@@ -285,7 +329,8 @@ func TrackDiffToColoredString(t keybase1.TrackDiff) string {
 	s := "<" + t.DisplayMarkup + ">"
 	var color string
 	switch t.Type {
-	case keybase1.TrackDiffType_ERROR, keybase1.TrackDiffType_CLASH, keybase1.TrackDiffType_REVOKED:
+	case keybase1.TrackDiffType_ERROR, keybase1.TrackDiffType_CLASH, keybase1.TrackDiffType_REVOKED,
+		keybase1.TrackDiffType_UNVERIFIED, keybase1.TrackDiffType_NEW_ELDEST:
 		color = "red"
 	case keybase1.TrackDiffType_UPGRADED:
 		color = "orange"
@@ -328,7 +373,7 @@ func (ui BaseIdentifyUI) FinishWebProofCheck(p keybase1.RemoteProof, l keybase1.
 			msg += (CHECK + " " + lcrs + "admin of " +
 				ColorString(okColor, "DNS") + " zone " +
 				ColorString(okColor, s.GetDomain()) + torWarning +
-				": found TXT entry " + lcr.GetHint().GetCheckText())
+				": found TXT entry " + lcr.GetHint().GetCheckText() + lcr.TorCheckString())
 		} else {
 			var color string
 			if s.GetProtocol() == "https" {
@@ -339,8 +384,13 @@ func (ui BaseIdentifyUI) FinishWebProofCheck(p keybase1.RemoteProof, l keybase1.
 			msg += (CHECK + " " + lcrs + "admin of " +
 				ColorString(color, s.GetHostname()) + " via " +
 				ColorString(color, strings.ToUpper(s.GetProtocol())) + torWarning +
-				": " + lcr.GetHint().GetHumanURL())
+				": " + lcr.GetHint().GetHumanURL() + lcr.TorCheckString())
 		}
+	} else if lcr.GetTorCheck() == keybase1.TorCheck_SKIPPED {
+		msg = (SKIP + " " + lcrs +
+			ColorString("yellow", "Proof for "+s.ToDisplayString()+" "+
+				ColorString("bold", "not checked")+": "+
+				lcr.GetError().Error()))
 	} else {
 		msg = (BADX + " " + lcrs +
 			ColorString("red", "Proof for "+s.ToDisplayString()+" "+
@@ -355,11 +405,26 @@ func (ui BaseIdentifyUI) FinishWebProofCheck(p keybase1.RemoteProof, l keybase1.
 }
 
 func (ui BaseIdentifyUI) DisplayCryptocurrency(l keybase1.Cryptocurrency) {
-	msg := (BTC + " bitcoin " + ColorString("green", l.Address))
+	typ := l.Type
+	if len(typ) == 0 {
+		typ = "bitcoin"
+	}
+	symbol := BTC
+	if typ != "bitcoin" {
+		symbol = typ
+		if ct := libkb.GetCurrencyType(typ); ct != nil {
+			symbol = ct.Symbol
+		}
+	}
+	msg := (symbol + " " + typ + " " + ColorString("green", l.Address))
 	ui.ReportHook(msg)
 }
 
 func (ui BaseIdentifyUI) DisplayKey(key keybase1.IdentifyKey) {
+	if key.TrackDiff != nil && key.TrackDiff.Type == keybase1.TrackDiffType_NEW_ELDEST {
+		ui.ReportHook(BADX + " " + TrackDiffToColoredString(*key.TrackDiff) + " " + ColorString("red", "new eldest key: "+key.KID.String()))
+		return
+	}
 	var ds string
 	if key.TrackDiff != nil {
 		ds = TrackDiffToColoredString(*key.TrackDiff) + " "
@@ -370,7 +435,12 @@ func (ui BaseIdentifyUI) DisplayKey(key keybase1.IdentifyKey) {
 	} else {
 		s = "<none>"
 	}
-	msg := CHECK + " " + ds + ColorString("green", "public key fingerprint: "+s)
+	mark, color := CHECK, "green"
+	if key.TrackDiff != nil && key.TrackDiff.Type == keybase1.TrackDiffType_UNVERIFIED {
+		// Nothing vouches for this key, so don't tick it off.
+		mark, color = "?", "yellow"
+	}
+	msg := mark + " " + ds + ColorString(color, "public key fingerprint: "+s)
 	ui.ReportHook(msg)
 }
 
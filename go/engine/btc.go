@@ -4,12 +4,11 @@
 package engine
 
 import (
-	"fmt"
-
 	"github.com/keybase/client/go/libkb"
 	keybase1 "github.com/keybase/client/go/protocol"
 )
 
+// BTCEngine claims a bitcoin address; see CryptocurrencyEngine.
 type BTCEngine struct {
 	libkb.Contextified
 	address string
@@ -42,65 +41,16 @@ func (e *BTCEngine) RequiredUIs() []libkb.UIKind {
 }
 
 func (e *BTCEngine) SubConsumers() []libkb.UIConsumer {
-	return []libkb.UIConsumer{}
-}
-
-func (e *BTCEngine) Run(ctx *Context) (err error) {
-	e.G().Log.Debug("+ BTCEngine Run")
-	defer func() {
-		e.G().Log.Debug("- BTCEngine Run")
-	}()
-	_, _, err = libkb.BtcAddrCheck(e.address, nil)
-	if err != nil {
-		return err
-	}
-
-	me, err := libkb.LoadMe(libkb.NewLoadUserArg(e.G()))
-	if err != nil {
-		return err
-	}
-
-	cryptocurrencyLink := me.IDTable().ActiveCryptocurrency()
-	if cryptocurrencyLink != nil && !e.force {
-		return fmt.Errorf("User already has a cryptocurrency address. To overwrite, use --force.")
-	}
-	var sigIDToRevoke keybase1.SigID
-	if cryptocurrencyLink != nil {
-		sigIDToRevoke = cryptocurrencyLink.GetSigID()
-	}
-
-	sigKey, err := e.G().Keyrings.GetSecretKeyWithPrompt(ctx.LoginContext, libkb.SecretKeyArg{
-		Me:      me,
-		KeyType: libkb.DeviceSigningKeyType,
-	}, ctx.SecretUI, "to register a cryptocurrency address")
-	if err != nil {
-		return err
-	}
-	if err = sigKey.CheckSecretKey(); err != nil {
-		return err
+	return []libkb.UIConsumer{
+		&CryptocurrencyEngine{},
 	}
+}
 
-	claim, err := me.CryptocurrencySig(sigKey, e.address, sigIDToRevoke)
-	if err != nil {
-		return err
-	}
-	sig, _, _, err := libkb.SignJSON(claim, sigKey)
-	if err != nil {
-		return err
-	}
-	kid := sigKey.GetKID()
-	_, err = e.G().API.Post(libkb.APIArg{
-		Endpoint:    "sig/post",
-		NeedSession: true,
-		Args: libkb.HTTPArgs{
-			"sig":             libkb.S{Val: sig},
-			"signing_kid":     libkb.S{Val: kid.String()},
-			"is_remote_proof": libkb.B{Val: false},
-			"type":            libkb.S{Val: "cryptocurrency"},
-		},
+func (e *BTCEngine) Run(ctx *Context) error {
+	eng := NewCryptocurrencyEngine(e.G(), keybase1.RegisterAddressArg{
+		Address: e.address,
+		Type:    "bitcoin",
+		Force:   e.force,
 	})
-	if err != nil {
-		return err
-	}
-	return nil
+	return RunEngine(eng, ctx)
 }
@@ -15,7 +15,7 @@ func getCurrentBTCAddr(tc libkb.TestContext, username string) string {
 	if err != nil {
 		tc.T.Fatal(err)
 	}
-	cryptoLink := u.IDTable().ActiveCryptocurrency()
+	cryptoLink := u.IDTable().ActiveCryptocurrency("bitcoin")
 	if cryptoLink == nil {
 		return ""
 	}
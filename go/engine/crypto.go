@@ -54,6 +54,9 @@ func SignED25519(g *libkb.GlobalContext, secretUI libkb.SecretUI,
 		err = libkb.KeyCannotSignError{}
 		return
 	}
+	if err = kp.CheckSecretKey(); err != nil {
+		return
+	}
 
 	sig := *kp.Private.Sign(arg.Msg)
 	publicKey := kp.Public
@@ -79,6 +82,9 @@ func UnboxBytes32(g *libkb.GlobalContext, secretUI libkb.SecretUI,
 		err = libkb.KeyCannotDecryptError{}
 		return
 	}
+	if err = kp.CheckSecretKey(); err != nil {
+		return
+	}
 
 	decryptedData, ok := box.Open(nil, arg.EncryptedBytes32[:],
 		(*[24]byte)(&arg.Nonce), (*[32]byte)(&arg.PeersPublicKey),
@@ -0,0 +1,173 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// CryptocurrencyEngine claims a cryptocurrency address in the user's
+// sigchain.
+type CryptocurrencyEngine struct {
+	libkb.Contextified
+	arg keybase1.RegisterAddressArg
+	res keybase1.RegisterAddressRes
+}
+
+func NewCryptocurrencyEngine(g *libkb.GlobalContext, arg keybase1.RegisterAddressArg) *CryptocurrencyEngine {
+	return &CryptocurrencyEngine{
+		Contextified: libkb.NewContextified(g),
+		arg:          arg,
+	}
+}
+
+func (e *CryptocurrencyEngine) Name() string {
+	return "Cryptocurrency"
+}
+
+func (e *CryptocurrencyEngine) Prereqs() Prereqs {
+	return Prereqs{
+		Device: true,
+	}
+}
+
+func (e *CryptocurrencyEngine) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{
+		libkb.LogUIKind,
+		libkb.SecretUIKind,
+	}
+}
+
+func (e *CryptocurrencyEngine) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{}
+}
+
+func (e *CryptocurrencyEngine) Run(ctx *Context) (err error) {
+	e.G().Log.Debug("+ CryptocurrencyEngine Run")
+	defer func() {
+		e.G().Log.Debug("- CryptocurrencyEngine Run -> %s", libkb.ErrToOk(err))
+	}()
+	typ, _, err := libkb.CryptocurrencyAddrCheck(e.arg.Address, e.arg.Type)
+	if err != nil {
+		return err
+	}
+
+	me, err := libkb.LoadMe(libkb.NewLoadUserArg(e.G()))
+	if err != nil {
+		return err
+	}
+
+	// Only an address of the same type is replaced.
+	cryptocurrencyLink := me.IDTable().ActiveCryptocurrency(typ.Name)
+	if cryptocurrencyLink != nil && !e.arg.Force {
+		return fmt.Errorf("User already has a %s address. To overwrite, use --force.", typ.Name)
+	}
+	var sigIDToRevoke keybase1.SigID
+	if cryptocurrencyLink != nil {
+		sigIDToRevoke = cryptocurrencyLink.GetSigID()
+	}
+
+	sigKey, err := e.G().Keyrings.GetSecretKeyWithPrompt(ctx.LoginContext, libkb.SecretKeyArg{
+		Me:      me,
+		KeyType: libkb.DeviceSigningKeyType,
+	}, ctx.SecretUI, "to register a cryptocurrency address")
+	if err != nil {
+		return err
+	}
+	if err = sigKey.CheckSecretKey(); err != nil {
+		return err
+	}
+
+	claim, err := me.CryptocurrencySig(sigKey, e.arg.Address, typ.Name, sigIDToRevoke)
+	if err != nil {
+		return err
+	}
+	sig, _, _, err := libkb.SignJSON(claim, sigKey)
+	if err != nil {
+		return err
+	}
+	kid := sigKey.GetKID()
+	_, err = e.G().API.Post(libkb.APIArg{
+		Endpoint:    "sig/post",
+		NeedSession: true,
+		Args: libkb.HTTPArgs{
+			"sig":             libkb.S{Val: sig},
+			"signing_kid":     libkb.S{Val: kid.String()},
+			"is_remote_proof": libkb.B{Val: false},
+			"type":            libkb.S{Val: "cryptocurrency"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	e.res.Type = typ.Name
+	return nil
+}
+
+// Result is the type of the address that was claimed.
+func (e *CryptocurrencyEngine) Result() keybase1.RegisterAddressRes {
+	return e.res
+}
+
+// CryptocurrencyRevokeEngine revokes the link that claimed an address,
+// which is given either by the address or by the link's sig ID.
+type CryptocurrencyRevokeEngine struct {
+	libkb.Contextified
+	address string
+}
+
+func NewCryptocurrencyRevokeEngine(g *libkb.GlobalContext, address string) *CryptocurrencyRevokeEngine {
+	return &CryptocurrencyRevokeEngine{
+		Contextified: libkb.NewContextified(g),
+		address:      address,
+	}
+}
+
+func (e *CryptocurrencyRevokeEngine) Name() string {
+	return "CryptocurrencyRevoke"
+}
+
+func (e *CryptocurrencyRevokeEngine) Prereqs() Prereqs {
+	return Prereqs{
+		Device: true,
+	}
+}
+
+func (e *CryptocurrencyRevokeEngine) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{
+		libkb.LogUIKind,
+		libkb.SecretUIKind,
+	}
+}
+
+func (e *CryptocurrencyRevokeEngine) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{
+		&RevokeSigsEngine{},
+	}
+}
+
+func (e *CryptocurrencyRevokeEngine) Run(ctx *Context) error {
+	me, err := libkb.LoadMe(libkb.NewLoadUserArg(e.G()))
+	if err != nil {
+		return err
+	}
+
+	var link *libkb.CryptocurrencyChainLink
+	for _, l := range me.IDTable().ActiveCryptocurrencies() {
+		if l.GetAddress() == e.address || l.GetSigID().ToString(true) == e.address || l.GetSigID().ToString(false) == e.address {
+			link = l
+			break
+		}
+	}
+	if link == nil {
+		return fmt.Errorf("No cryptocurrency address %s to revoke", e.address)
+	}
+
+	e.G().Log.Debug("| Revoking %s address %s in %s", link.GetCurrencyType(), link.GetAddress(), link.GetSigID())
+	eng := NewRevokeSigsEngine([]keybase1.SigID{link.GetSigID()}, e.G())
+	return RunEngine(eng, ctx)
+}
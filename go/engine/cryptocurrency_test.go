@@ -0,0 +1,75 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+const zcashAddress = "t1Srf64tKGepAb6ddqPJRTMujv4ms9q7BMS"
+
+func getActiveCryptocurrencies(tc libkb.TestContext, username string) map[string]string {
+	u, err := libkb.LoadUser(libkb.NewLoadUserByNameArg(tc.G, username))
+	if err != nil {
+		tc.T.Fatal(err)
+	}
+	ret := make(map[string]string)
+	for _, link := range u.IDTable().ActiveCryptocurrencies() {
+		ret[link.GetCurrencyType()] = link.GetAddress()
+	}
+	return ret
+}
+
+func TestCryptocurrency(t *testing.T) {
+	tc := SetupEngineTest(t, "currency")
+	defer tc.Cleanup()
+
+	u := CreateAndSignupFakeUser(tc, "currency")
+
+	ctx := &Context{
+		LogUI:    tc.G.UI.GetLogUI(),
+		SecretUI: u.NewSecretUI(),
+	}
+
+	// A bitcoin address given as zcash is refused.
+	e := NewCryptocurrencyEngine(tc.G, keybase1.RegisterAddressArg{Address: firstAddress, Type: "zcash"})
+	if err := RunEngine(e, ctx); err == nil {
+		t.Fatal("Registered a bitcoin address as zcash.")
+	}
+
+	// One address of each type can be claimed, and the type is worked
+	// out when it isn't given.
+	e = NewCryptocurrencyEngine(tc.G, keybase1.RegisterAddressArg{Address: firstAddress})
+	if err := RunEngine(e, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if typ := e.Result().Type; typ != "bitcoin" {
+		t.Fatalf("Registered %s as %s.", firstAddress, typ)
+	}
+	e = NewCryptocurrencyEngine(tc.G, keybase1.RegisterAddressArg{Address: zcashAddress, Type: "zcash"})
+	if err := RunEngine(e, ctx); err != nil {
+		t.Fatal(err)
+	}
+	active := getActiveCryptocurrencies(tc, u.Username)
+	if len(active) != 2 || active["bitcoin"] != firstAddress || active["zcash"] != zcashAddress {
+		t.Fatalf("Bad active addresses: %v", active)
+	}
+
+	// Revoking one leaves the other.
+	r := NewCryptocurrencyRevokeEngine(tc.G, zcashAddress)
+	if err := RunEngine(r, ctx); err != nil {
+		t.Fatal(err)
+	}
+	active = getActiveCryptocurrencies(tc, u.Username)
+	if len(active) != 1 || active["bitcoin"] != firstAddress {
+		t.Fatalf("Bad active addresses after revoking: %v", active)
+	}
+	r = NewCryptocurrencyRevokeEngine(tc.G, zcashAddress)
+	if err := RunEngine(r, ctx); err == nil {
+		t.Fatal("Revoked an address twice.")
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// DbEvict is an engine that evicts selected tables, or selected users'
+// values, from the local database, so that they're fetched again
+// without nuking everything else.
+type DbEvict struct {
+	libkb.Contextified
+	tables    []string
+	usernames []string
+	evicted   int
+}
+
+// NewDbEvict creates a DbEvict engine.  With no usernames, it evicts
+// all of tables; with no tables, all of the users' values.
+func NewDbEvict(g *libkb.GlobalContext, tables, usernames []string) *DbEvict {
+	return &DbEvict{
+		Contextified: libkb.NewContextified(g),
+		tables:       tables,
+		usernames:    usernames,
+	}
+}
+
+// Name is the unique engine name.
+func (e *DbEvict) Name() string {
+	return "DbEvict"
+}
+
+// Prereqs returns the engine prereqs.
+func (e *DbEvict) Prereqs() Prereqs {
+	return Prereqs{}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *DbEvict) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{libkb.LogUIKind}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *DbEvict) SubConsumers() []libkb.UIConsumer {
+	return nil
+}
+
+// Run starts the engine.
+func (e *DbEvict) Run(ctx *Context) error {
+	if len(e.tables) == 0 && len(e.usernames) == 0 {
+		return fmt.Errorf("nothing to evict; give tables, users, or both")
+	}
+
+	tables := make(map[libkb.ObjType]bool)
+	for _, name := range e.tables {
+		typ, err := libkb.DbTableFromName(name)
+		if err != nil {
+			return err
+		}
+		tables[typ] = true
+	}
+
+	var uids []keybase1.UID
+	if len(e.usernames) > 0 {
+		userTables := make(map[libkb.ObjType]bool)
+		for _, typ := range libkb.DbUserTables {
+			userTables[typ] = true
+		}
+		for typ := range tables {
+			if !userTables[typ] {
+				return fmt.Errorf("the %s table isn't kept per user", libkb.DbTableNames[typ])
+			}
+		}
+		if len(tables) == 0 {
+			tables = userTables
+		}
+		for _, username := range e.usernames {
+			uid, err := e.localUID(username)
+			if err != nil {
+				return err
+			}
+			uids = append(uids, uid)
+		}
+	}
+
+	selects := func(id libkb.DbKey) bool {
+		if !tables[id.Typ] {
+			return false
+		}
+		if len(uids) == 0 {
+			return true
+		}
+		for _, uid := range uids {
+			if libkb.DbKeyMentionsUID(id.Key, uid) {
+				return true
+			}
+		}
+		return false
+	}
+
+	n, err := e.G().LocalDb.Evict(selects)
+	if err != nil {
+		return err
+	}
+	e.evicted = n
+	ctx.LogUI.Info("Evicted %d values", n)
+	return nil
+}
+
+// localUID is the UID of username, as the local database knows it.  A
+// user it doesn't know can't have anything to evict, but their UID is
+// still worth a guess.
+func (e *DbEvict) localUID(username string) (keybase1.UID, error) {
+	nu := libkb.NewNormalizedUsername(username)
+	jw, err := e.G().LocalDb.Lookup(libkb.DbKey{Typ: libkb.DBLookupUsername, Key: nu.String()})
+	if err != nil {
+		return "", err
+	}
+	if jw == nil {
+		return libkb.UsernameToUID(nu.String()), nil
+	}
+	return libkb.GetUID(jw.AtKey("id"))
+}
+
+// Evicted is how many values the engine evicted.
+func (e *DbEvict) Evicted() int {
+	return e.evicted
+}
@@ -0,0 +1,70 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+	jsonw "github.com/keybase/go-jsonw"
+)
+
+func putTestDbValue(t *testing.T, tc libkb.TestContext, id libkb.DbKey, aliases []libkb.DbKey, value string) {
+	jw, err := jsonw.Unmarshal([]byte(value))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tc.G.LocalDb.Put(id, aliases, jw); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDbEvict(t *testing.T) {
+	tc := SetupEngineTest(t, "dbevict")
+	defer tc.Cleanup()
+
+	alice, bob := libkb.UsernameToUID("t_alice"), libkb.UsernameToUID("t_bob")
+	putTestDbValue(t, tc, libkb.DbKeyUID(libkb.DBUser, alice),
+		[]libkb.DbKey{{Typ: libkb.DBLookupUsername, Key: "t_alice"}}, `{"id": "`+alice.String()+`"}`)
+	putTestDbValue(t, tc, libkb.DbKeyUID(libkb.DBSigHints, alice), nil, `{}`)
+	putTestDbValue(t, tc, libkb.DbKeyUID(libkb.DBSigHints, bob), nil, `{}`)
+	putTestDbValue(t, tc, libkb.DbKey{Typ: libkb.DBProofCheck, Key: "abcd"}, nil, `{}`)
+
+	ctx := &Context{LogUI: tc.G.UI.GetLogUI()}
+	eng := NewDbEvict(tc.G, []string{"sig-hints"}, []string{"t_alice"})
+	if err := RunEngine(eng, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if eng.Evicted() != 1 {
+		t.Errorf("evicted %d values, expected alice's sig hints", eng.Evicted())
+	}
+	if jw, _ := tc.G.LocalDb.Get(libkb.DbKeyUID(libkb.DBUser, alice)); jw == nil {
+		t.Error("alice's user record was evicted with her sig hints")
+	}
+
+	eng = NewDbEvict(tc.G, []string{"proof-check"}, nil)
+	if err := RunEngine(eng, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if eng.Evicted() != 1 {
+		t.Errorf("evicted %d values, expected the proof check", eng.Evicted())
+	}
+
+	eng = NewDbEvict(tc.G, []string{"link"}, []string{"t_alice"})
+	if err := RunEngine(eng, ctx); err == nil {
+		t.Error("evicted a user's links, which aren't kept per user")
+	}
+	eng = NewDbEvict(tc.G, []string{"nonesuch"}, nil)
+	if err := RunEngine(eng, ctx); err == nil {
+		t.Error("evicted an unknown table")
+	}
+
+	eng = NewDbEvict(tc.G, nil, []string{"t_alice", "t_bob"})
+	if err := RunEngine(eng, ctx); err != nil {
+		t.Fatal(err)
+	}
+	if eng.Evicted() != 2 {
+		t.Errorf("evicted %d values, expected alice's user record and bob's sig hints", eng.Evicted())
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	jsonw "github.com/keybase/go-jsonw"
+)
+
+// DbVerify is an engine that checks that the sig chains and Merkle roots
+// in the local database still parse and verify, and, if asked, evicts
+// the ones that don't, so that they're fetched again.
+type DbVerify struct {
+	libkb.Contextified
+	evictBad bool
+	result   keybase1.DbVerifyResult
+}
+
+// NewDbVerify creates a DbVerify engine.
+func NewDbVerify(g *libkb.GlobalContext, evictBad bool) *DbVerify {
+	return &DbVerify{
+		Contextified: libkb.NewContextified(g),
+		evictBad:     evictBad,
+	}
+}
+
+// Name is the unique engine name.
+func (e *DbVerify) Name() string {
+	return "DbVerify"
+}
+
+// Prereqs returns the engine prereqs.
+func (e *DbVerify) Prereqs() Prereqs {
+	return Prereqs{}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *DbVerify) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{libkb.LogUIKind}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *DbVerify) SubConsumers() []libkb.UIConsumer {
+	return nil
+}
+
+// Run starts the engine.
+func (e *DbVerify) Run(ctx *Context) error {
+	bad := make(map[libkb.DbKey]bool)
+	problem := func(id libkb.DbKey, err error) {
+		ctx.LogUI.Warning("%s %s: %s", libkb.DbTableNames[id.Typ], id.Key, err)
+		e.result.Problems = append(e.result.Problems, keybase1.DbProblem{
+			Table:   libkb.DbTableNames[id.Typ],
+			Key:     id.Key,
+			Problem: err.Error(),
+		})
+		bad[id] = true
+	}
+
+	// Collect the keys first, and check them once the iterations are
+	// done, since checking reads the database too.
+	var uids []keybase1.UID
+	err := e.G().LocalDb.ForEach(libkb.DBUser, func(id libkb.DbKey, _ []byte) error {
+		uid, err := libkb.UIDFromHex(id.Key)
+		if err != nil {
+			problem(id, err)
+			return nil
+		}
+		uids = append(uids, uid)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	roots := make(map[libkb.DbKey][]byte)
+	err = e.G().LocalDb.ForEach(libkb.DBMerkleRoot, func(id libkb.DbKey, value []byte) error {
+		roots[id] = append([]byte{}, value...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, uid := range uids {
+		e.result.Checked++
+		if err := libkb.VerifyStoredSigChain(e.G(), uid); err != nil {
+			// The chain is loaded from the tail down, so evicting
+			// the tail and the user record has both fetched again.
+			problem(libkb.DbKeyUID(libkb.DBUser, uid), err)
+			bad[libkb.DbKeyUID(libkb.DBSigChainTailPublic, uid)] = true
+		}
+	}
+
+	if e.G().MerkleClient == nil {
+		ctx.LogUI.Warning("Not checking %d Merkle roots, since there's no Merkle client", len(roots))
+	} else {
+		for id, value := range roots {
+			e.result.Checked++
+			jw, err := jsonw.Unmarshal(value)
+			if err == nil {
+				err = e.G().MerkleClient.VerifyStoredRoot(jw)
+			}
+			if err != nil {
+				problem(id, err)
+			}
+		}
+	}
+
+	ctx.LogUI.Info("Checked %d sig chains and Merkle roots; %d had problems", e.result.Checked, len(e.result.Problems))
+
+	if !e.evictBad || len(bad) == 0 {
+		return nil
+	}
+	n, err := e.G().LocalDb.Evict(func(id libkb.DbKey) bool { return bad[id] })
+	if err != nil {
+		return err
+	}
+	e.result.Evicted = n
+	ctx.LogUI.Info("Evicted %d values", n)
+	return nil
+}
+
+// Result is what the engine found, and evicted.
+func (e *DbVerify) Result() keybase1.DbVerifyResult {
+	return e.result
+}
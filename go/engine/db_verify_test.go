@@ -0,0 +1,37 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+func TestDbVerifyEvictsCorruptUser(t *testing.T) {
+	tc := SetupEngineTest(t, "dbverify")
+	defer tc.Cleanup()
+
+	uid := libkb.UsernameToUID("t_alice")
+	putTestDbValue(t, tc, libkb.DbKeyUID(libkb.DBUser, uid), nil, `{"garbage": true}`)
+
+	ctx := &Context{LogUI: tc.G.UI.GetLogUI()}
+	eng := NewDbVerify(tc.G, true)
+	if err := RunEngine(eng, ctx); err != nil {
+		t.Fatal(err)
+	}
+	res := eng.Result()
+	if res.Checked != 1 || len(res.Problems) != 1 {
+		t.Fatalf("checked %d with problems %v, expected the corrupt user", res.Checked, res.Problems)
+	}
+	if res.Problems[0].Table != "user" || res.Problems[0].Key != uid.String() {
+		t.Errorf("problem with %s %s, expected the user record", res.Problems[0].Table, res.Problems[0].Key)
+	}
+	if res.Evicted != 1 {
+		t.Errorf("evicted %d values, expected the user record", res.Evicted)
+	}
+	if jw, _ := tc.G.LocalDb.Get(libkb.DbKeyUID(libkb.DBUser, uid)); jw != nil {
+		t.Error("the corrupt user record wasn't evicted")
+	}
+}
@@ -6,20 +6,27 @@ package engine
 import (
 	"golang.org/x/net/context"
 
-	"github.com/keybase/client/go/kex2"
 	"github.com/keybase/client/go/libkb"
-	keybase1 "github.com/keybase/client/go/protocol"
 )
 
 // DeviceAdd is an engine.
 type DeviceAdd struct {
 	libkb.Contextified
+	expireIn int
 }
 
 // NewDeviceAdd creates a DeviceAdd engine.
 func NewDeviceAdd(g *libkb.GlobalContext) *DeviceAdd {
+	return NewDeviceAddWithExpiry(g, 0)
+}
+
+// NewDeviceAddWithExpiry creates a DeviceAdd engine that provisions
+// a device whose keys expire in expireIn seconds.  Zero means the
+// default.
+func NewDeviceAddWithExpiry(g *libkb.GlobalContext, expireIn int) *DeviceAdd {
 	return &DeviceAdd{
 		Contextified: libkb.NewContextified(g),
+		expireIn:     expireIn,
 	}
 }
 
@@ -73,36 +80,24 @@ func (e *DeviceAdd) Run(ctx *Context) (err error) {
 
 	// create provisioner engine
 	provisioner := NewKex2Provisioner(e.G(), secret.Secret(), pps)
+	provisioner.expireIn = e.expireIn
 
 	var canceler func()
 
 	// display secret and prompt for secret from X in a goroutine:
 	go func() {
-		sb := secret.Secret()
-		arg := keybase1.DisplayAndPromptSecretArg{
-			Secret:          sb[:],
-			Phrase:          secret.Phrase(),
-			OtherDeviceType: provisioneeType,
-		}
+		arg := kex2SecretArg(e.G(), secret, libkb.KexRoleProvisioner, e.currentDevice(), provisioneeType)
 		var contxt context.Context
 		contxt, canceler = context.WithCancel(context.Background())
 		receivedSecret, err := ctx.ProvisionUI.DisplayAndPromptSecret(contxt, arg)
 		if err != nil {
 			// XXX ???
 			e.G().Log.Warning("DisplayAndPromptSecret error: %s", err)
-		} else if receivedSecret.Secret != nil && len(receivedSecret.Secret) > 0 {
-			e.G().Log.Debug("received secret, adding to provisioner")
-			var ks kex2.Secret
-			copy(ks[:], receivedSecret.Secret)
-			provisioner.AddSecret(ks)
-		} else if len(receivedSecret.Phrase) > 0 {
-			e.G().Log.Debug("received secret phrase, adding to provisioner")
-			ks, err := libkb.NewKex2SecretFromPhrase(receivedSecret.Phrase)
-			if err != nil {
-				e.G().Log.Warning("DisplayAndPromptSecret error: %s", err)
-			} else {
-				provisioner.AddSecret(ks.Secret())
-			}
+		} else if ks, err := receivedKex2Secret(e.G(), libkb.KexRoleProvisioner, receivedSecret); err != nil {
+			e.G().Log.Warning("DisplayAndPromptSecret error: %s", err)
+		} else if ks != nil {
+			e.G().Log.Debug("adding received secret to provisioner")
+			provisioner.AddSecret(*ks)
 		}
 	}()
 
@@ -120,3 +115,19 @@ func (e *DeviceAdd) Run(ctx *Context) (err error) {
 
 	return nil
 }
+
+// currentDevice is the device doing the adding, for the other device to
+// show, or nil if it can't be loaded.
+func (e *DeviceAdd) currentDevice() *libkb.Device {
+	me, err := libkb.LoadMe(libkb.NewLoadUserArg(e.G()))
+	if err != nil {
+		e.G().Log.Debug("| Not loading the current device: %s", err)
+		return nil
+	}
+	device, err := me.GetComputedKeyFamily().GetCurrentDevice(e.G())
+	if err != nil {
+		e.G().Log.Debug("| Not loading the current device: %s", err)
+		return nil
+	}
+	return device
+}
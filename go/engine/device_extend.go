@@ -0,0 +1,134 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// DeviceExtend is an engine that pushes back the expiration of the
+// current device's keys.  The device's sibkey and encryption subkey
+// are delegated again, with the same keys but a new expiration time,
+// so this has to happen before they expire.
+type DeviceExtend struct {
+	libkb.Contextified
+	expireIn int
+	etime    time.Time
+}
+
+// NewDeviceExtend creates a DeviceExtend engine.  The device's keys
+// will expire in expireIn seconds.
+func NewDeviceExtend(g *libkb.GlobalContext, expireIn int) *DeviceExtend {
+	return &DeviceExtend{
+		Contextified: libkb.NewContextified(g),
+		expireIn:     expireIn,
+	}
+}
+
+// Name is the unique engine name.
+func (e *DeviceExtend) Name() string {
+	return "DeviceExtend"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *DeviceExtend) Prereqs() Prereqs {
+	return Prereqs{Device: true}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *DeviceExtend) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{
+		libkb.LogUIKind,
+		libkb.SecretUIKind,
+	}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *DeviceExtend) SubConsumers() []libkb.UIConsumer {
+	return nil
+}
+
+// Run starts the engine.
+func (e *DeviceExtend) Run(ctx *Context) error {
+	if e.expireIn <= 0 {
+		return fmt.Errorf("invalid expiration: %d seconds", e.expireIn)
+	}
+
+	me, err := libkb.LoadMe(libkb.NewLoadUserForceArg(e.G()))
+	if err != nil {
+		return err
+	}
+
+	ckf := me.GetComputedKeyFamily()
+	device, err := ckf.GetCurrentDevice(e.G())
+	if err != nil {
+		return err
+	}
+	if etime, err := ckf.GetDeviceETime(device.ID); err != nil {
+		return err
+	} else if !etime.IsZero() && etime.Before(time.Now()) {
+		return fmt.Errorf("this device's keys expired at %s; it has to be provisioned again", etime)
+	}
+
+	sibkey, err := e.G().Keyrings.GetSecretKeyWithPrompt(ctx.LoginContext, libkb.SecretKeyArg{
+		Me:      me,
+		KeyType: libkb.DeviceSigningKeyType,
+	}, ctx.SecretUI, "to extend this device's keys")
+	if err != nil {
+		return err
+	}
+	if err = sibkey.CheckSecretKey(); err != nil {
+		return err
+	}
+	subkey, err := ckf.GetEncryptionSubkeyForDevice(device.ID)
+	if err != nil {
+		return err
+	}
+	if subkey == nil {
+		return libkb.NoKeyError{Msg: "no encryption key for this device"}
+	}
+
+	// The sibkey signs its own new delegation (and the reverse sig),
+	// then delegates the subkey again with the same expiration.
+	dev := *device
+	delgs := []libkb.Delegator{
+		{
+			NewKey:         sibkey,
+			ExistingKey:    sibkey,
+			DelegationType: libkb.SibkeyType,
+			Expire:         e.expireIn,
+			Me:             me,
+			Device:         &dev,
+			Contextified:   libkb.NewContextified(e.G()),
+		},
+		{
+			NewKey:         subkey,
+			ExistingKey:    sibkey,
+			DelegationType: libkb.SubkeyType,
+			Expire:         e.expireIn,
+			Me:             me,
+			Device:         &dev,
+			Contextified:   libkb.NewContextified(e.G()),
+		},
+	}
+	if err := libkb.DelegatorAggregator(ctx.LoginContext, delgs); err != nil {
+		return err
+	}
+
+	e.etime = time.Now().Add(time.Duration(e.expireIn) * time.Second)
+	name := dev.ID.String()
+	if dev.Description != nil {
+		name = *dev.Description
+	}
+	ctx.LogUI.Info("Keys for device %q now expire at %s", name, e.etime.Format("2006-01-02"))
+	return nil
+}
+
+// ETime returns the new expiration time of the device's keys.
+func (e *DeviceExtend) ETime() time.Time {
+	return e.etime
+}
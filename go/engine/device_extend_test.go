@@ -0,0 +1,47 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+func TestDeviceExtend(t *testing.T) {
+	tc := SetupEngineTest(t, "ext")
+	defer tc.Cleanup()
+
+	u := CreateAndSignupFakeUser(tc, "ext")
+
+	ctx := &Context{
+		LogUI:    tc.G.UI.GetLogUI(),
+		SecretUI: u.NewSecretUI(),
+	}
+	expireIn := 90 * 24 * 60 * 60
+	eng := NewDeviceExtend(tc.G, expireIn)
+	if err := RunEngine(eng, ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same devices and keys as before.
+	assertNumDevicesAndKeys(tc, u, 2, 4)
+
+	me, err := libkb.LoadMe(libkb.NewLoadUserForceArg(tc.G))
+	if err != nil {
+		t.Fatal(err)
+	}
+	etime, err := me.GetComputedKeyFamily().GetDeviceETime(tc.G.Env.GetDeviceID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Now().Add(time.Duration(expireIn) * time.Second)
+	if diff := want.Sub(etime); diff < 0 || diff > time.Hour {
+		t.Errorf("device expires at %s, expected about %s", etime, want)
+	}
+
+	// The device can still sign.
+	trackAlice(tc, u)
+}
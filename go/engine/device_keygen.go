@@ -16,6 +16,7 @@ type DeviceKeygenArgs struct {
 	DeviceName string
 	DeviceType string
 	Lks        *libkb.LKSec
+	ExpireIn   int // seconds; 0 for the default
 }
 
 // DeviceKeygenPushArgs determines how the push will run.  There are
@@ -37,12 +38,17 @@ type DeviceKeygenArgs struct {
 // The User argument is optional, but it is necessary if the
 // user's sigchain changes between key generation and key push.
 //
+// RevokeKIDs is also optional.  When rotating a device's keys, it holds
+// the old keys, which are revoked in the same link that delegates the new
+// signing key.
+//
 type DeviceKeygenPushArgs struct {
 	IsEldest       bool
 	SkipSignerPush bool
 	Signer         libkb.GenericKey
 	EldestKID      keybase1.KID
-	User           *libkb.User // optional
+	User           *libkb.User    // optional
+	RevokeKIDs     []keybase1.KID // optional
 }
 
 type DeviceKeygen struct {
@@ -153,7 +159,7 @@ func (e *DeviceKeygen) setup(ctx *Context) {
 			return nil, err
 		}
 		return kp, nil
-	}, libkb.NaclEdDSAExpireIn)
+	}, e.expireIn(libkb.NaclEdDSAExpireIn))
 	e.naclSignGen = libkb.NewNaclKeyGen(signArg)
 
 	encArg := e.newNaclArg(ctx, func() (libkb.NaclKeyPair, error) {
@@ -162,7 +168,7 @@ func (e *DeviceKeygen) setup(ctx *Context) {
 			return nil, err
 		}
 		return kp, nil
-	}, libkb.NaclDHExpireIn)
+	}, e.expireIn(libkb.NaclDHExpireIn))
 	e.naclEncGen = libkb.NewNaclKeyGen(encArg)
 }
 
@@ -219,6 +225,7 @@ func (e *DeviceKeygen) appendSibkey(ds []libkb.Delegator, ctx *Context, pargs *D
 	d, e.pushErr = e.naclSignGen.Push(ctx.LoginContext, true)
 	if e.pushErr == nil {
 		d.SetGlobalContext(e.G())
+		d.RevokeKIDs = pargs.RevokeKIDs
 		return append(ds, d)
 	}
 
@@ -290,6 +297,15 @@ func (e *DeviceKeygen) pushLKS(ctx *Context) {
 	}
 }
 
+// expireIn is how long the device keys last: the caller's choice if it
+// made one, otherwise the default.
+func (e *DeviceKeygen) expireIn(def int) int {
+	if e.args.ExpireIn > 0 {
+		return e.args.ExpireIn
+	}
+	return def
+}
+
 func (e *DeviceKeygen) newNaclArg(ctx *Context, gen libkb.NaclGenerator, expire int) libkb.NaclKeyGenArg {
 	return libkb.NaclKeyGenArg{
 		Generator: gen,
@@ -0,0 +1,133 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// DeviceProtect is an engine that moves the client half of the
+// device's LKSec secret to another KeyProtector.  It takes the client
+// half from the passphrase, checks that it unlocks the device's
+// signing key, and has the new protector keep it before the old one
+// forgets it.
+type DeviceProtect struct {
+	libkb.Contextified
+	protector string
+}
+
+// NewDeviceProtect creates a DeviceProtect engine that moves the
+// client half to the protector called protector.
+func NewDeviceProtect(g *libkb.GlobalContext, protector string) *DeviceProtect {
+	return &DeviceProtect{
+		Contextified: libkb.NewContextified(g),
+		protector:    protector,
+	}
+}
+
+// Name is the unique engine name.
+func (e *DeviceProtect) Name() string {
+	return "DeviceProtect"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *DeviceProtect) Prereqs() Prereqs {
+	return Prereqs{Device: true}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *DeviceProtect) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{
+		libkb.LogUIKind,
+		libkb.SecretUIKind,
+	}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *DeviceProtect) SubConsumers() []libkb.UIConsumer {
+	return nil
+}
+
+// Run starts the engine.
+func (e *DeviceProtect) Run(ctx *Context) error {
+	username := e.G().Env.GetUsername()
+	kp, err := libkb.NewKeyProtector(e.G(), e.protector, username)
+	if err != nil {
+		return err
+	}
+	old := libkb.CurrentKeyProtector(e.G())
+
+	me, err := libkb.LoadMe(libkb.NewLoadUserArg(e.G()))
+	if err != nil {
+		return err
+	}
+	pps, err := e.G().LoginState().GetPassphraseStream(ctx.SecretUI)
+	if err != nil {
+		return err
+	}
+	clientHalf := pps.LksClientHalf()
+	if err := e.check(ctx, me, clientHalf); err != nil {
+		return err
+	}
+
+	if err := kp.Protect(clientHalf); err != nil {
+		return err
+	}
+	// The passphrase protector keeps nothing, so there's nothing to
+	// get back from it.
+	if kp.Name() != libkb.KeyProtectorPassphrase {
+		kept, err := kp.Unprotect()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(kept, clientHalf) {
+			kp.Clear()
+			return errors.New("the key protector didn't give back what it was given")
+		}
+	}
+	if err := libkb.SetKeyProtector(e.G(), username, kp.Name()); err != nil {
+		return err
+	}
+
+	if old.Name() != kp.Name() {
+		if err := old.Clear(); err != nil {
+			ctx.LogUI.Warning("Couldn't clear the %s key protector: %s", old.Name(), err)
+		}
+	}
+	ctx.LogUI.Info("This device's keys are now protected by the %s key protector.", kp.Name())
+	return nil
+}
+
+// check makes sure clientHalf unlocks the device's signing key, so
+// that a protector is never left keeping something useless.
+func (e *DeviceProtect) check(ctx *Context, me *libkb.User, clientHalf []byte) error {
+	var skb *libkb.SKB
+	var err error
+	ska := libkb.SecretKeyArg{Me: me, KeyType: libkb.DeviceSigningKeyType}
+	if ctx.LoginContext != nil {
+		skb, err = ctx.LoginContext.LockedLocalSecretKey(ska)
+	} else {
+		aerr := e.G().LoginState().Account(func(a *libkb.Account) {
+			skb, err = a.LockedLocalSecretKey(ska)
+		}, "DeviceProtect - check")
+		if aerr != nil {
+			return aerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if skb == nil {
+		return libkb.NoSecretKeyError{}
+	}
+	if skb.Priv.Encryption != libkb.LKSecVersion {
+		return errors.New("this device's keys aren't protected by local key security")
+	}
+	lks := libkb.NewLKSecWithClientHalf(clientHalf, libkb.PassphraseGeneration(0), me.GetUID(), e.G())
+	_, _, err = lks.Decrypt(ctx.LoginContext, skb.Priv.Data)
+	return err
+}
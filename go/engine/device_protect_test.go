@@ -0,0 +1,54 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+func TestDeviceProtect(t *testing.T) {
+	tc := SetupEngineTest(t, "prot")
+	defer tc.Cleanup()
+
+	u := CreateAndSignupFakeUser(tc, "prot")
+
+	protectors := []string{libkb.KeyProtectorMachine}
+	if libkb.HasSecretStore() {
+		protectors = append(protectors, libkb.KeyProtectorSecretStore)
+	}
+
+	protect := func(name string) {
+		ctx := &Context{
+			LogUI:    tc.G.UI.GetLogUI(),
+			SecretUI: u.NewSecretUI(),
+		}
+		if err := RunEngine(NewDeviceProtect(tc.G, name), ctx); err != nil {
+			t.Fatalf("protecting with %s: %s", name, err)
+		}
+		if kp := libkb.CurrentKeyProtector(tc.G); kp.Name() != name {
+			t.Fatalf("key protector is %s, expected %s", kp.Name(), name)
+		}
+	}
+
+	for _, name := range protectors {
+		protect(name)
+		kp := libkb.CurrentKeyProtector(tc.G)
+		if _, err := kp.Unprotect(); err != nil {
+			t.Fatalf("%s isn't keeping the client half: %s", name, err)
+		}
+
+		// Going back to the passphrase clears what the protector kept.
+		protect(libkb.KeyProtectorPassphrase)
+		if _, err := kp.Unprotect(); err == nil {
+			t.Errorf("%s still keeps the client half after switching back to the passphrase", name)
+		} else if _, ok := err.(libkb.NoProtectedSecretError); !ok {
+			t.Errorf("%s: unexpected error %s", name, err)
+		}
+	}
+
+	// The device's keys still unlock with the passphrase.
+	trackAlice(tc, u)
+}
@@ -0,0 +1,203 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// DeviceRotate is an engine that replaces the current device's
+// signing and encryption keys with fresh ones, without
+// reprovisioning.  The new sibkey is signed into the sigchain
+// by the old one, in a link that also revokes the old keys.
+type DeviceRotate struct {
+	libkb.Contextified
+	signingKey    libkb.GenericKey
+	encryptionKey libkb.GenericKey
+	revokedKIDs   []keybase1.KID
+}
+
+// NewDeviceRotate creates a DeviceRotate engine.
+func NewDeviceRotate(g *libkb.GlobalContext) *DeviceRotate {
+	return &DeviceRotate{
+		Contextified: libkb.NewContextified(g),
+	}
+}
+
+// Name is the unique engine name.
+func (e *DeviceRotate) Name() string {
+	return "DeviceRotate"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *DeviceRotate) Prereqs() Prereqs {
+	return Prereqs{Device: true}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *DeviceRotate) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{
+		libkb.LogUIKind,
+		libkb.SecretUIKind,
+	}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *DeviceRotate) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{
+		&DeviceKeygen{},
+	}
+}
+
+// Run starts the engine.
+func (e *DeviceRotate) Run(ctx *Context) error {
+	me, err := libkb.LoadMe(libkb.NewLoadUserForceArg(e.G()))
+	if err != nil {
+		return err
+	}
+
+	deviceID := e.G().Env.GetDeviceID()
+	device, err := me.GetDevice(deviceID)
+	if err != nil {
+		return err
+	}
+	if device.Type == libkb.DeviceTypePaper {
+		return fmt.Errorf("can't rotate the keys of a paper key")
+	}
+	if device.Description == nil {
+		return fmt.Errorf("device %s has no name", deviceID)
+	}
+
+	ckf := me.GetComputedKeyFamily()
+	oldKIDs, err := ckf.GetAllActiveKeysForDevice(deviceID)
+	if err != nil {
+		return err
+	}
+
+	// The new keys expire when the old ones would have, so rotating
+	// doesn't quietly extend a device's lifetime.
+	var expireIn int
+	if etime, err := ckf.GetDeviceETime(deviceID); err == nil && !etime.IsZero() {
+		expireIn = int(etime.Sub(time.Now()) / time.Second)
+		if expireIn <= 0 {
+			return fmt.Errorf("this device's keys expired at %s", etime)
+		}
+	}
+
+	signer, err := e.G().Keyrings.GetSecretKeyWithPrompt(ctx.LoginContext, libkb.SecretKeyArg{
+		Me:      me,
+		KeyType: libkb.DeviceSigningKeyType,
+	}, ctx.SecretUI, "to rotate this device's keys")
+	if err != nil {
+		return err
+	}
+	if err = signer.CheckSecretKey(); err != nil {
+		return err
+	}
+
+	// The LKSec secret stays the same, so the server half we already
+	// have for this device still works for the new keys.
+	lks, err := libkb.NewLKSForEncrypt(ctx.SecretUI, me.GetUID(), e.G())
+	if err != nil {
+		return err
+	}
+
+	kgArgs := &DeviceKeygenArgs{
+		Me:         me,
+		DeviceID:   deviceID,
+		DeviceName: *device.Description,
+		DeviceType: device.Type,
+		Lks:        lks,
+		ExpireIn:   expireIn,
+	}
+	kgEng := NewDeviceKeygen(kgArgs, e.G())
+	if err := RunEngine(kgEng, ctx); err != nil {
+		return err
+	}
+
+	pargs := &DeviceKeygenPushArgs{
+		Signer:     signer,
+		EldestKID:  me.GetEldestKID(),
+		User:       me,
+		RevokeKIDs: oldKIDs,
+	}
+	if err := kgEng.Push(ctx, pargs); err != nil {
+		// The new keys never made it into the sigchain, so
+		// don't leave them around in the keyring.
+		e.removeLocalKeys(ctx, []keybase1.KID{kgEng.SigningKey().GetKID(), kgEng.EncryptionKey().GetKID()})
+		return err
+	}
+
+	e.signingKey = kgEng.SigningKey()
+	e.encryptionKey = kgEng.EncryptionKey()
+	e.revokedKIDs = oldKIDs
+
+	ctx.LogUI.Info("Rotated keys for device %q:", *device.Description)
+	for _, kid := range oldKIDs {
+		ctx.LogUI.Info("  revoked %s", kid)
+	}
+	ctx.LogUI.Info("  new signing key %s", e.signingKey.GetKID())
+	ctx.LogUI.Info("  new encryption key %s", e.encryptionKey.GetKID())
+
+	// The rotation is done as far as the server is concerned, so a
+	// failure to tidy up the keyring shouldn't make it look like it
+	// wasn't.
+	if err := e.removeLocalKeys(ctx, oldKIDs); err != nil {
+		e.G().Log.Warning("Rotated keys for device %q, but failed to remove the old keys from the local keyring: %s", *device.Description, err)
+	}
+	return nil
+}
+
+// removeLocalKeys drops the given secret keys from the local keyring
+// and from the cache of unlocked keys.
+func (e *DeviceRotate) removeLocalKeys(ctx *Context, kids []keybase1.KID) error {
+	var err error
+	remove := func(kr *libkb.SKBKeyringFile) {
+		if _, err = kr.RemoveKIDs(kids); err != nil {
+			return
+		}
+		err = kr.Save()
+	}
+	if ctx.LoginContext != nil {
+		kr, kerr := ctx.LoginContext.Keyring()
+		if kerr != nil {
+			return kerr
+		}
+		remove(kr)
+		ctx.LoginContext.ClearCachedSecretKeys()
+		return err
+	}
+	aerr := e.G().LoginState().Account(func(a *libkb.Account) {
+		kr, kerr := a.Keyring()
+		if kerr != nil {
+			err = kerr
+			return
+		}
+		remove(kr)
+		a.ClearCachedSecretKeys()
+	}, "DeviceRotate - removeLocalKeys")
+	if aerr != nil {
+		return aerr
+	}
+	return err
+}
+
+// SigningKey returns the device's new signing key.
+func (e *DeviceRotate) SigningKey() libkb.GenericKey {
+	return e.signingKey
+}
+
+// EncryptionKey returns the device's new encryption key.
+func (e *DeviceRotate) EncryptionKey() libkb.GenericKey {
+	return e.encryptionKey
+}
+
+// RevokedKIDs returns the device's old keys, which were revoked.
+func (e *DeviceRotate) RevokedKIDs() []keybase1.KID {
+	return e.revokedKIDs
+}
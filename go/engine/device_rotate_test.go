@@ -0,0 +1,66 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+func TestDeviceRotate(t *testing.T) {
+	tc := SetupEngineTest(t, "rot")
+	defer tc.Cleanup()
+
+	u := CreateAndSignupFakeUser(tc, "rot")
+
+	assertNumDevicesAndKeys(tc, u, 2, 4)
+
+	ctx := &Context{
+		LogUI:    tc.G.UI.GetLogUI(),
+		SecretUI: u.NewSecretUI(),
+	}
+	eng := NewDeviceRotate(tc.G)
+	if err := RunEngine(eng, ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same devices, same number of keys, but this device's keys
+	// are new.
+	assertNumDevicesAndKeys(tc, u, 2, 4)
+
+	if len(eng.RevokedKIDs()) != 2 {
+		t.Fatalf("revoked %d keys, expected 2", len(eng.RevokedKIDs()))
+	}
+	_, keys := getActiveDevicesAndKeys(tc, u)
+	for _, key := range keys {
+		for _, kid := range eng.RevokedKIDs() {
+			if key.GetKID().Equal(kid) {
+				t.Errorf("revoked key %s is still active", kid)
+			}
+		}
+	}
+
+	// The new keys should be the ones used from now on.
+	me, err := libkb.LoadMe(libkb.NewLoadUserForceArg(tc.G))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kt := range []libkb.SecretKeyType{libkb.DeviceSigningKeyType, libkb.DeviceEncryptionKeyType} {
+		key, err := tc.G.Keyrings.GetSecretKeyWithPrompt(nil, libkb.SecretKeyArg{Me: me, KeyType: kt}, u.NewSecretUI(), "test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := eng.SigningKey().GetKID()
+		if kt == libkb.DeviceEncryptionKeyType {
+			want = eng.EncryptionKey().GetKID()
+		}
+		if !key.GetKID().Equal(want) {
+			t.Errorf("%s: got key %s, expected %s", kt, key.GetKID(), want)
+		}
+	}
+
+	// Make sure the rotated keys can sign a new link.
+	trackAlice(tc, u)
+}
@@ -58,15 +58,30 @@ func (d *DevList) Run(ctx *Context) error {
 		return err
 	}
 
+	// The secret syncer doesn't know when device keys expire, so
+	// get that from the sigchain.  Not fatal if it can't be loaded.
+	var ckf *libkb.ComputedKeyFamily
+	if me, err := libkb.LoadMe(libkb.NewLoadUserArg(d.G())); err != nil {
+		d.G().Log.Debug("DevList: not showing expiration times: %s", err)
+	} else {
+		ckf = me.GetComputedKeyFamily()
+	}
+
 	var pdevs []keybase1.Device
 	for k, v := range devs {
-		pdevs = append(pdevs, keybase1.Device{
+		pdev := keybase1.Device{
 			Type:     v.Type,
 			Name:     v.Display(),
 			DeviceID: k,
 			CTime:    keybase1.TimeFromSeconds(v.CTime),
 			MTime:    keybase1.TimeFromSeconds(v.MTime),
-		})
+		}
+		if ckf != nil {
+			if etime, err := ckf.GetDeviceETime(k); err == nil && !etime.IsZero() {
+				pdev.ETime = keybase1.ToTime(etime)
+			}
+		}
+		pdevs = append(pdevs, pdev)
 	}
 	sort.Sort(dname(pdevs))
 	d.devices = pdevs
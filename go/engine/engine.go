@@ -8,6 +8,7 @@ import (
 	"net/url"
 
 	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
 )
 
 type Prereqs struct {
@@ -61,6 +62,8 @@ func RunEngine(e Engine, ctx *Context) (err error) {
 	e.G().Log.Debug("+ RunEngine(%s)", e.Name())
 	defer func() { e.G().Log.Debug("- RunEngine(%s) -> %s", e.Name(), libkb.ErrToOk(err)) }()
 
+	defer e.G().Stats.Start(keybase1.StatCategory_ENGINE, e.Name())(&err)
+
 	if err = delegateUIs(e, ctx); err != nil {
 		return err
 	}
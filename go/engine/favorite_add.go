@@ -49,6 +49,9 @@ func (e *FavoriteAdd) Run(ctx *Context) error {
 	if e.arg == nil {
 		return fmt.Errorf("FavoriteAdd arg is nil")
 	}
+	if e.G().IsOffline() {
+		return libkb.QueueOfflineFavorite(e.G(), e.arg.Folder)
+	}
 	e.G().FavoriteCache.Add(e.arg.Folder)
 	return nil
 }
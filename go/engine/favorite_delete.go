@@ -49,6 +49,9 @@ func (e *FavoriteDelete) Run(ctx *Context) error {
 	if e.arg == nil {
 		return fmt.Errorf("FavoriteDelete arg is nil")
 	}
+	if err := libkb.DropOfflineFavorite(e.G(), e.arg.Folder); err != nil {
+		return err
+	}
 	e.G().FavoriteCache.Delete(e.arg.Folder)
 	return nil
 }
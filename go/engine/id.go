@@ -61,10 +61,12 @@ func (e *IDEngine) Result() *IDRes {
 
 func (e *IDEngine) run(ctx *Context) (*IDRes, error) {
 	iarg := NewIdentifyArg(e.arg.UserAssertion, e.arg.TrackStatement, e.arg.ForceRemoteCheck)
+	iarg.CacheOnly = e.arg.CacheOnly
 	ieng := NewIdentify(iarg, e.G())
 	if err := RunEngine(ieng, ctx); err != nil {
 		return nil, err
 	}
+	ieng.reportAccountReset()
 
 	// need to tell any ui clients the track token
 	if err := ctx.IdentifyUI.ReportTrackToken(ieng.TrackToken()); err != nil {
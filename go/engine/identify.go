@@ -27,6 +27,7 @@ type IdentifyArg struct {
 	WithTracking     bool   // true if want tracking statement for logged in user on TargetUsername
 	AllowSelf        bool   // if we're allowed to id/track ourself
 	ForceRemoteCheck bool   // true: skip proof cache and perform all remote proof checks
+	CacheOnly        bool   // true: use cached proof results however old, and perform no remote proof checks
 
 	// When tracking is being performed, the identify engine is used with a tracking ui.
 	// These options are sent to the ui based on command line options.
@@ -57,6 +58,17 @@ func (ia *IdentifyArg) SelfID() bool {
 	return len(ia.TargetUsername) == 0
 }
 
+func (ia *IdentifyArg) proofCacheMode() libkb.ProofCacheMode {
+	switch {
+	case ia.ForceRemoteCheck:
+		return libkb.ProofCacheRecheck
+	case ia.CacheOnly:
+		return libkb.ProofCacheOnly
+	default:
+		return libkb.ProofCacheFresh
+	}
+}
+
 // NewIdentify creates a Identify engine.
 func NewIdentify(arg *IdentifyArg, g *libkb.GlobalContext) *Identify {
 	return &Identify{
@@ -149,6 +161,22 @@ func (e *Identify) TrackInstructions() *libkb.TrackInstructions {
 	return e.trackInst
 }
 
+// reportAccountReset warns, and notifies clients, if the user reset their
+// account since they were tracked.  Callers only do so when the outcome
+// is shown to the user, or the track is being confirmed, and not for
+// identifies that happen along the way.
+func (e *Identify) reportAccountReset() {
+	if e.outcome == nil {
+		return
+	}
+	reset := e.outcome.AccountReset()
+	if reset == nil {
+		return
+	}
+	e.G().Log.Warning("%s reset their account since you tracked them", e.user.GetName())
+	e.G().NotifyRouter.HandleTrackedUserReset(e.user.GetUID(), e.user.GetName(), reset.GetTracked(), reset.GetObserved())
+}
+
 func (e *Identify) run(ctx *Context) (*libkb.IdentifyOutcome, error) {
 	res := libkb.NewIdentifyOutcome(e.arg.WithTracking)
 	res.Username = e.user.GetName()
@@ -187,7 +215,7 @@ func (e *Identify) run(ctx *Context) (*libkb.IdentifyOutcome, error) {
 	is.ComputeRevokedProofs()
 
 	ctx.IdentifyUI.LaunchNetworkChecks(res.ExportToUncheckedIdentity(), e.user.Export())
-	e.user.IDTable().Identify(is, e.arg.ForceRemoteCheck, ctx.IdentifyUI)
+	e.user.IDTable().Identify(is, e.arg.proofCacheMode(), ctx.IdentifyUI)
 
 	base := e.user.BaseProofSet()
 	res.AddProofsToSet(base)
@@ -33,6 +33,14 @@ type Kex2Provisionee struct {
 	lks           *libkb.LKSec
 	tmpConfigFile string
 	ctx           *Context
+
+	// sibkeyETime is when the sibkey the provisioner delegates to us
+	// expires.  The encryption subkey shouldn't outlive it.
+	sibkeyETime time.Time
+
+	// secretExchanged, if set, is called once the provisioner has
+	// connected, so that a prompt for its secret can stop waiting.
+	secretExchanged func()
 }
 
 // Kex2Provisionee implements kex2.Provisionee, libkb.UserBasic,
@@ -129,6 +137,10 @@ func (e *Kex2Provisionee) HandleHello(harg keybase1.HelloArg) (res keybase1.Hell
 	e.G().Log.Debug("+ HandleHello()")
 	defer func() { e.G().Log.Debug("- HandleHello() -> %s", libkb.ErrToOk(err)) }()
 
+	if e.secretExchanged != nil {
+		e.secretExchanged()
+	}
+
 	// save parts of the hello arg for later:
 	e.uid = harg.Uid
 	e.sessionToken = harg.Token
@@ -146,6 +158,16 @@ func (e *Kex2Provisionee) HandleHello(harg keybase1.HelloArg) (res keybase1.Hell
 		return res, err
 	}
 
+	ctime, err := jw.AtKey("ctime").GetInt64()
+	if err != nil {
+		return res, err
+	}
+	expireIn, err := jw.AtKey("expire_in").GetInt64()
+	if err != nil {
+		return res, err
+	}
+	e.sibkeyETime = time.Unix(ctime+expireIn, 0)
+
 	e.eddsa, err = libkb.GenerateNaclSigningKeyPair()
 	if err != nil {
 		return res, err
@@ -420,11 +442,17 @@ func makeKeyArgs(sigID keybase1.SigID, sig []byte, delType libkb.DelegationType,
 }
 
 func (e *Kex2Provisionee) dhKeyProof(dh libkb.GenericKey, eldestKID keybase1.KID, seqno int, linkID libkb.LinkID) (sig string, sigID keybase1.SigID, err error) {
+	expire := libkb.NaclDHExpireIn
+	if !e.sibkeyETime.IsZero() {
+		if remaining := int(e.sibkeyETime.Sub(time.Now()) / time.Second); remaining < expire {
+			expire = remaining
+		}
+	}
 	delg := libkb.Delegator{
 		ExistingKey:    e.eddsa,
 		NewKey:         dh,
 		DelegationType: libkb.SubkeyType,
-		Expire:         libkb.NaclDHExpireIn,
+		Expire:         expire,
 		EldestKID:      eldestKID,
 		Device:         e.device,
 		LastSeqno:      libkb.Seqno(seqno),
@@ -25,6 +25,10 @@ type Kex2Provisioner struct {
 	provisioneeDeviceName string
 	provisioneeDeviceType string
 	ctx                   *Context
+
+	// expireIn is how long the new device's keys last, in seconds.
+	// Zero means the default.
+	expireIn int
 }
 
 // Kex2Provisioner implements kex2.Provisioner interface.
@@ -224,11 +228,15 @@ func (e *Kex2Provisioner) sessionForY() (token, csrf string, err error) {
 // skeletonProof generates a partial key proof structure that
 // device Y can fill in.
 func (e *Kex2Provisioner) skeletonProof() (string, error) {
+	expire := libkb.NaclEdDSAExpireIn
+	if e.expireIn > 0 {
+		expire = e.expireIn
+	}
 	delg := libkb.Delegator{
 		ExistingKey:    e.signingKey,
 		Me:             e.me,
 		DelegationType: libkb.SibkeyType,
-		Expire:         libkb.NaclEdDSAExpireIn,
+		Expire:         expire,
 		Contextified:   libkb.NewContextified(e.G()),
 	}
 
@@ -0,0 +1,78 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"github.com/keybase/client/go/kex2"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// kex2SecretArg is what this device shows the other one to start kex2:
+// the secret phrase, and a payload with the phrase and this device's
+// details to scan, or type in place of the phrase.
+func kex2SecretArg(g *libkb.GlobalContext, secret *libkb.Kex2Secret, role libkb.KexRole, device *libkb.Device, otherType keybase1.DeviceType) keybase1.DisplayAndPromptSecretArg {
+	sb := secret.Secret()
+	arg := keybase1.DisplayAndPromptSecretArg{
+		Secret:          sb[:],
+		Phrase:          secret.Phrase(),
+		OtherDeviceType: otherType,
+	}
+
+	deviceType := keybase1.DeviceType_DESKTOP
+	var deviceName string
+	if device != nil {
+		if device.Type == libkb.DeviceTypeMobile {
+			deviceType = keybase1.DeviceType_MOBILE
+		}
+		if device.Description != nil {
+			deviceName = *device.Description
+		}
+	}
+	payload := libkb.NewKex2Payload(g, secret, role, deviceType, deviceName)
+
+	// The phrase is enough without them.
+	var err error
+	if arg.Payload, err = payload.Encode(); err != nil {
+		g.Log.Debug("| Not showing a kex2 payload: %s", err)
+	} else if arg.PayloadText, err = payload.Text(); err != nil {
+		g.Log.Debug("| Not showing a kex2 payload text: %s", err)
+	}
+	return arg
+}
+
+// receivedKex2Secret returns the secret from the other device in a
+// DisplayAndPromptSecret response, or nil if there isn't one.  A payload
+// is checked before its secret is used.
+func receivedKex2Secret(g *libkb.GlobalContext, role libkb.KexRole, res keybase1.SecretResponse) (*kex2.Secret, error) {
+	var phrase string
+	switch {
+	case len(res.Secret) > 0:
+		g.Log.Debug("received secret")
+		var ks kex2.Secret
+		copy(ks[:], res.Secret)
+		return &ks, nil
+	case len(res.Payload) > 0:
+		payload, err := libkb.DecodeKex2Payload(res.Payload)
+		if err != nil {
+			return nil, err
+		}
+		if err = payload.Check(g, role); err != nil {
+			return nil, err
+		}
+		g.Log.Debug("received payload from device %q (type %d)", payload.DeviceName, payload.DeviceType)
+		phrase = payload.Phrase
+	case len(res.Phrase) > 0:
+		g.Log.Debug("received secret phrase")
+		phrase = res.Phrase
+	default:
+		return nil, nil
+	}
+	ks, err := libkb.NewKex2SecretFromPhrase(phrase)
+	if err != nil {
+		return nil, err
+	}
+	secret := ks.Secret()
+	return &secret, nil
+}
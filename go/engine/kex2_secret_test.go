@@ -0,0 +1,66 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+func TestReceivedKex2Secret(t *testing.T) {
+	tc := SetupEngineTest(t, "kex2secret")
+	defer tc.Cleanup()
+
+	secret, err := libkb.NewKex2Secret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := secret.Secret()
+	payload := libkb.NewKex2Payload(tc.G, secret, libkb.KexRoleProvisioner, keybase1.DeviceType_DESKTOP, "home laptop")
+	b, err := payload.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The other device's payload, phrase or secret are all taken.
+	for _, res := range []keybase1.SecretResponse{
+		{Payload: b},
+		{Phrase: secret.Phrase()},
+		{Secret: want[:]},
+	} {
+		ks, err := receivedKex2Secret(tc.G, libkb.KexRoleProvisionee, res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ks == nil || *ks != want {
+			t.Errorf("response %+v gave secret %v, expected %v", res, ks, want)
+		}
+	}
+
+	// An empty response has no secret.
+	if ks, err := receivedKex2Secret(tc.G, libkb.KexRoleProvisionee, keybase1.SecretResponse{}); err != nil || ks != nil {
+		t.Errorf("empty response gave secret %v, error %v", ks, err)
+	}
+
+	// A payload from a device in the same role is refused.
+	if _, err := receivedKex2Secret(tc.G, libkb.KexRoleProvisioner, keybase1.SecretResponse{Payload: b}); err == nil {
+		t.Error("payload from a device in the same role was taken")
+	} else if _, ok := err.(libkb.Kex2PayloadError); !ok {
+		t.Errorf("error type %T, expected libkb.Kex2PayloadError", err)
+	}
+
+	// So is an expired one.
+	payload.Expires = time.Now().Add(-time.Minute)
+	if b, err = payload.Encode(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := receivedKex2Secret(tc.G, libkb.KexRoleProvisionee, keybase1.SecretResponse{Payload: b}); err == nil {
+		t.Error("expired payload was taken")
+	} else if _, ok := err.(libkb.Kex2PayloadError); !ok {
+		t.Errorf("error type %T, expected libkb.Kex2PayloadError", err)
+	}
+}
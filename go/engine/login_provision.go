@@ -9,7 +9,6 @@ import (
 
 	"golang.org/x/net/context"
 
-	"github.com/keybase/client/go/kex2"
 	"github.com/keybase/client/go/libkb"
 	keybase1 "github.com/keybase/client/go/protocol"
 )
@@ -127,43 +126,31 @@ func (e *LoginProvision) device(ctx *Context) error {
 	// create provisionee engine
 	provisionee := NewKex2Provisionee(e.G(), device, secret.Secret())
 
-	var canceler func()
+	// The prompt for X's secret is canceled once X has connected with
+	// this device's secret instead.
+	contxt, canceler := context.WithCancel(context.Background())
+	provisionee.secretExchanged = canceler
 
 	// display secret and prompt for secret from X in a goroutine:
 	go func() {
-		sb := secret.Secret()
-		arg := keybase1.DisplayAndPromptSecretArg{
-			Secret:          sb[:],
-			Phrase:          secret.Phrase(),
-			OtherDeviceType: provisionerType,
-		}
-		var contxt context.Context
-		contxt, canceler = context.WithCancel(context.Background())
+		arg := kex2SecretArg(e.G(), secret, libkb.KexRoleProvisionee, device, provisionerType)
 		receivedSecret, err := ctx.ProvisionUI.DisplayAndPromptSecret(contxt, arg)
-		if err != nil {
+		if err != nil && contxt.Err() != nil {
+			e.G().Log.Debug("DisplayAndPromptSecret canceled: %s", err)
+		} else if err != nil {
 			// could cancel provisionee run here?
 			e.G().Log.Warning("DisplayAndPromptSecret error: %s", err)
-		} else if receivedSecret.Secret != nil && len(receivedSecret.Secret) > 0 {
-			e.G().Log.Debug("received secret, adding to provisionee")
-			var ks kex2.Secret
-			copy(ks[:], receivedSecret.Secret)
-			provisionee.AddSecret(ks)
-		} else if len(receivedSecret.Phrase) > 0 {
-			e.G().Log.Debug("received secret phrase, adding to provisionee")
-			ks, err := libkb.NewKex2SecretFromPhrase(receivedSecret.Phrase)
-			if err != nil {
-				e.G().Log.Warning("DisplayAndPromptSecret error: %s", err)
-			} else {
-				provisionee.AddSecret(ks.Secret())
-			}
+		} else if ks, err := receivedKex2Secret(e.G(), libkb.KexRoleProvisionee, receivedSecret); err != nil {
+			e.G().Log.Warning("DisplayAndPromptSecret error: %s", err)
+		} else if ks != nil {
+			e.G().Log.Debug("adding received secret to provisionee")
+			provisionee.AddSecret(*ks)
 		}
 	}()
 
 	defer func() {
-		if canceler != nil {
-			e.G().Log.Debug("canceling DisplayAndPromptSecret call")
-			canceler()
-		}
+		e.G().Log.Debug("canceling DisplayAndPromptSecret call")
+		canceler()
 	}()
 
 	f := func(lctx libkb.LoginContext) error {
@@ -705,21 +692,7 @@ func (e *LoginProvision) getPaperKey(ctx *Context) (*keypair, error) {
 }
 
 func (e *LoginProvision) loadUserByKID(kid keybase1.KID) (*libkb.User, error) {
-	arg := libkb.APIArg{
-		Endpoint:     "key/owner",
-		NeedSession:  false,
-		Contextified: libkb.NewContextified(e.G()),
-		Args:         libkb.HTTPArgs{"kid": libkb.S{Val: kid.String()}},
-	}
-	res, err := e.G().API.Get(arg)
-	if err != nil {
-		return nil, err
-	}
-	suid, err := res.Body.AtPath("uid").GetString()
-	if err != nil {
-		return nil, err
-	}
-	uid, err := keybase1.UIDFromString(suid)
+	uid, err := libkb.KeyOwner(e.G(), kid)
 	if err != nil {
 		return nil, err
 	}
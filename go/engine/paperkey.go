@@ -20,12 +20,20 @@ import (
 // PaperKey is an engine.
 type PaperKey struct {
 	passphrase libkb.PaperKeyPhrase
+	expireIn   int
 	libkb.Contextified
 }
 
 // NewPaperKey creates a PaperKey engine.
 func NewPaperKey(g *libkb.GlobalContext) *PaperKey {
+	return NewPaperKeyWithExpiry(g, 0)
+}
+
+// NewPaperKeyWithExpiry creates a PaperKey engine whose paper key
+// expires in expireIn seconds.  Zero means the default.
+func NewPaperKeyWithExpiry(g *libkb.GlobalContext, expireIn int) *PaperKey {
 	return &PaperKey{
+		expireIn:     expireIn,
 		Contextified: libkb.NewContextified(g),
 	}
 }
@@ -122,6 +130,7 @@ func (e *PaperKey) Run(ctx *Context) error {
 		Passphrase: e.passphrase,
 		Me:         me,
 		SigningKey: signingKey,
+		ExpireIn:   e.expireIn,
 	}
 	kgeng := NewPaperKeyGen(kgarg, e.G())
 	if err := RunEngine(kgeng, ctx); err != nil {
@@ -21,6 +21,7 @@ type PaperKeyGenArg struct {
 	SkipPush   bool
 	Me         *libkb.User
 	SigningKey libkb.GenericKey
+	ExpireIn   int // seconds; 0 for the default
 }
 
 // PaperKeyGen is an engine.
@@ -241,11 +242,16 @@ func (e *PaperKeyGen) push(ctx *Context) error {
 		return err
 	}
 
+	sigExpire, encExpire := libkb.NaclEdDSAExpireIn, libkb.NaclDHExpireIn
+	if e.arg.ExpireIn > 0 {
+		sigExpire, encExpire = e.arg.ExpireIn, e.arg.ExpireIn
+	}
+
 	// push the paper signing key
 	sigDel := libkb.Delegator{
 		NewKey:         e.sigKey,
 		DelegationType: libkb.SibkeyType,
-		Expire:         libkb.NaclEdDSAExpireIn,
+		Expire:         sigExpire,
 		ExistingKey:    e.arg.SigningKey,
 		Me:             e.arg.Me,
 		Device:         backupDev,
@@ -256,7 +262,7 @@ func (e *PaperKeyGen) push(ctx *Context) error {
 	sigEnc := libkb.Delegator{
 		NewKey:         e.encKey,
 		DelegationType: libkb.SubkeyType,
-		Expire:         libkb.NaclDHExpireIn,
+		Expire:         encExpire,
 		ExistingKey:    e.sigKey,
 		Me:             e.arg.Me,
 		Device:         backupDev,
@@ -18,6 +18,8 @@ type PassphraseChange struct {
 	me         *libkb.User
 	ppStream   *libkb.PassphraseStream
 	usingPaper bool
+	// the LKSec client half derived from the new passphrase
+	newClientHalf []byte
 	libkb.Contextified
 }
 
@@ -83,11 +85,25 @@ func (c *PassphraseChange) Run(ctx *Context) (err error) {
 
 	if err == nil {
 		c.G().LoginState().RunSecretSyncer(c.me.GetUID())
+		c.reprotect()
 	}
 
 	return
 }
 
+// reprotect hands the new client half to the key protector, if one is
+// keeping the old one, since the old one no longer works with the
+// server half.
+func (c *PassphraseChange) reprotect() {
+	kp := libkb.CurrentKeyProtector(c.G())
+	if kp.Name() == libkb.KeyProtectorPassphrase || c.newClientHalf == nil {
+		return
+	}
+	if err := kp.Protect(c.newClientHalf); err != nil {
+		c.G().Log.Warning("Couldn't update the %s key protector: %s", kp.Name(), err)
+	}
+}
+
 // findDeviceKeys looks for device keys and unlocks them.
 func (c *PassphraseChange) findDeviceKeys(ctx *Context) (*keypair, error) {
 	// need to be logged in to get a device key (unlocked)
@@ -313,6 +329,7 @@ func (c *PassphraseChange) commonArgs(a *libkb.Account, oldClientHalf []byte, pg
 	}
 	newPWH := newPPStream.PWHash()
 	newClientHalf := newPPStream.LksClientHalf()
+	c.newClientHalf = newClientHalf
 
 	mask := make([]byte, len(oldClientHalf))
 	libkb.XORBytes(mask, oldClientHalf, newClientHalf)
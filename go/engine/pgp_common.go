@@ -11,11 +11,18 @@ import (
 )
 
 // OutputSignatureSuccess prints the details of a successful verification.
-func OutputSignatureSuccess(ctx *Context, fingerprint libkb.PGPFingerprint, owner *libkb.User, signatureTime time.Time) {
+// signer describes who made the signature (see ScanKeys.Signer).
+func OutputSignatureSuccess(ctx *Context, fingerprint libkb.PGPFingerprint, signer string, signatureTime time.Time) {
 	if signatureTime.IsZero() {
-		ctx.LogUI.Notice("Signature verified. Signed by %s.", owner.GetName())
+		ctx.LogUI.Notice("Signature verified. Signed by %s.", signer)
 	} else {
-		ctx.LogUI.Notice("Signature verified. Signed by %s %s (%s).", owner.GetName(), humanize.Time(signatureTime), signatureTime)
+		ctx.LogUI.Notice("Signature verified. Signed by %s %s (%s).", signer, humanize.Time(signatureTime), signatureTime)
 	}
 	ctx.LogUI.Notice("PGP Fingerprint: %s.", fingerprint)
 }
+
+// OutputUnknownSigner warns that a message was signed by a key that
+// isn't on Keybase, so its signature couldn't be checked.
+func OutputUnknownSigner(ctx *Context, keyID uint64) {
+	ctx.LogUI.Warning("%s. The signature was NOT checked.", libkb.PGPUnknownSignerError{KeyID: keyID})
+}
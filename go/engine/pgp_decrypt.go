@@ -88,6 +88,13 @@ func (e *PGPDecrypt) Run(ctx *Context) (err error) {
 	if len(e.arg.SignedBy) > 0 {
 		e.arg.AssertSigned = true
 	}
+
+	// Say who signed the message whether or not a signature was asked
+	// for, so that nobody has to look up fingerprints by hand.
+	signer, err := e.reportSigner(ctx, sk)
+	if err != nil {
+		return err
+	}
 	if !e.arg.AssertSigned {
 		e.G().Log.Debug("Not checking signature status (AssertSigned == false)")
 		return nil
@@ -101,19 +108,44 @@ func (e *PGPDecrypt) Run(ctx *Context) (err error) {
 	if !e.signStatus.Verified {
 		return e.signStatus.SignatureError
 	}
+	if signer == nil {
+		return libkb.NoKeyError{Msg: fmt.Sprintf("In signature verification: no public key found for PGP ID %x", e.signStatus.KeyID)}
+	}
 
 	e.G().Log.Debug("| checkSignedBy")
-	if err = e.checkSignedBy(ctx); err != nil {
-		return err
-	}
+	return e.checkSignedBy(ctx)
+}
 
-	if e.signStatus.Entity == nil {
-		return libkb.NoKeyError{Msg: fmt.Sprintf("In signature verification: no public key found for PGP ID %x", e.signStatus.KeyID)}
+// reportSigner tells the user who signed the message, if anyone: the
+// Keybase user who owns the signing key and how their identify went, or
+// a warning that the key is unknown or the signature bad.  It returns
+// the signing key, if the signature verified.  The message is already
+// decrypted by now, so failing to check who owns the key is only an
+// error if the signature was asserted; otherwise the signer is reported
+// as unknown.
+func (e *PGPDecrypt) reportSigner(ctx *Context, sk *ScanKeys) (*libkb.PGPKeyBundle, error) {
+	if !e.signStatus.IsSigned {
+		return nil, nil
+	}
+	if _, ok := e.signStatus.SignatureError.(libkb.PGPUnknownSignerError); ok || e.signStatus.Entity == nil {
+		OutputUnknownSigner(ctx, e.signStatus.KeyID)
+		return nil, nil
 	}
-
 	bundle := libkb.NewPGPKeyBundle(e.signStatus.Entity)
-	OutputSignatureSuccess(ctx, bundle.GetFingerprint(), e.owner, e.signStatus.SignatureTime)
-	return nil
+	if !e.signStatus.Verified {
+		ctx.LogUI.Warning("BAD signature from PGP key %s: %s", bundle.GetFingerprint(), e.signStatus.SignatureError)
+		return nil, nil
+	}
+	desc, err := sk.Signer(e.signStatus.Entity)
+	if err != nil {
+		if e.arg.AssertSigned {
+			return nil, err
+		}
+		ctx.LogUI.Warning("Signed by PGP key %s, but couldn't check who owns it (%s). The signer is unknown.", bundle.GetFingerprint(), err)
+		return nil, nil
+	}
+	OutputSignatureSuccess(ctx, bundle.GetFingerprint(), desc, e.signStatus.SignatureTime)
+	return bundle, nil
 }
 
 func (e *PGPDecrypt) SignatureStatus() *libkb.SignatureStatus {
@@ -6,6 +6,7 @@ package engine
 import (
 	"bytes"
 	"crypto/rand"
+	"errors"
 	"strings"
 	"testing"
 
@@ -202,6 +203,123 @@ func TestPGPDecryptSignedOther(t *testing.T) {
 	if decmsg != msg {
 		t.Errorf("decoded: %q, expected: %q", decmsg, msg)
 	}
+	if owner := dec.Owner(); owner == nil || owner.GetName() != signer.Username {
+		t.Errorf("signer not identified: %v", owner)
+	}
+}
+
+// TestPGPDecryptUnknownSigner tests a message signed by a key that
+// isn't on Keybase: it decrypts, but the signature can't be vouched for.
+func TestPGPDecryptUnknownSigner(t *testing.T) {
+	tc := SetupEngineTest(t, "PGPDecrypt")
+	defer tc.Cleanup()
+	fu := createFakeUserWithPGPSibkey(tc)
+
+	stranger, err := tc.MakePGPKey("stranger@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	me, err := libkb.LoadMe(libkb.NewLoadUserArg(tc.G))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := "who am I?"
+	sink := libkb.NewBufferCloser()
+	if err := libkb.PGPEncrypt(strings.NewReader(msg), sink, stranger, me.GetActivePGPKeys(false)); err != nil {
+		t.Fatal(err)
+	}
+
+	decrypt := func(assertSigned bool) (*PGPDecrypt, error) {
+		decarg := &PGPDecryptArg{
+			Source:       bytes.NewReader(sink.Bytes()),
+			Sink:         libkb.NewBufferCloser(),
+			AssertSigned: assertSigned,
+		}
+		dec := NewPGPDecrypt(decarg, tc.G)
+		return dec, RunEngine(dec, decengctx(fu, tc))
+	}
+
+	dec, err := decrypt(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := dec.SignatureStatus()
+	if !status.IsSigned || status.Verified {
+		t.Errorf("bad signature status: %+v", status)
+	}
+	if _, ok := status.SignatureError.(libkb.PGPUnknownSignerError); !ok {
+		t.Errorf("signature error: %v, expected PGPUnknownSignerError", status.SignatureError)
+	}
+
+	if _, err := decrypt(true); err == nil {
+		t.Fatal("asserting a signature by an unknown key should fail")
+	} else if _, ok := err.(libkb.PGPUnknownSignerError); !ok {
+		t.Errorf("error: %v (%T), expected PGPUnknownSignerError", err, err)
+	}
+}
+
+// keyOwnerFailAPI fails to look up who owns a key, like the API does
+// when offline.
+type keyOwnerFailAPI struct {
+	libkb.API
+}
+
+func (a keyOwnerFailAPI) Get(arg libkb.APIArg) (*libkb.APIRes, error) {
+	if arg.Endpoint == "key/owner" {
+		return nil, errors.New("key/owner lookup failed")
+	}
+	return a.API.Get(arg)
+}
+
+// TestPGPDecryptSignerLookupFails tests that failing to check who owns
+// the signing key only fails the decrypt if the signature was asserted.
+func TestPGPDecryptSignerLookupFails(t *testing.T) {
+	tcRecipient := SetupEngineTest(t, "PGPDecrypt - Recipient")
+	defer tcRecipient.Cleanup()
+	recipient := createFakeUserWithPGPSibkey(tcRecipient)
+	Logout(tcRecipient)
+
+	tcSigner := SetupEngineTest(t, "PGPDecrypt - Signer")
+	defer tcSigner.Cleanup()
+	signer := createFakeUserWithPGPSibkey(tcSigner)
+
+	msg := "The owner of this key is a mystery."
+	sink := libkb.NewBufferCloser()
+	arg := &PGPEncryptArg{
+		Recips:       []string{recipient.Username},
+		Source:       strings.NewReader(msg),
+		Sink:         sink,
+		BinaryOutput: true,
+		TrackOptions: keybase1.TrackOptions{BypassConfirm: true},
+	}
+	if err := RunEngine(NewPGPEncrypt(arg, tcSigner.G), decengctx(signer, tcSigner)); err != nil {
+		t.Fatal(err)
+	}
+	Logout(tcSigner)
+	libkb.G = tcRecipient.G
+	recipient.LoginOrBust(tcRecipient)
+	tcRecipient.G.API = keyOwnerFailAPI{tcRecipient.G.API}
+
+	decrypt := func(assertSigned bool) (string, error) {
+		decoded := libkb.NewBufferCloser()
+		decarg := &PGPDecryptArg{
+			Source:       bytes.NewReader(sink.Bytes()),
+			Sink:         decoded,
+			AssertSigned: assertSigned,
+			TrackOptions: keybase1.TrackOptions{BypassConfirm: true},
+		}
+		err := RunEngine(NewPGPDecrypt(decarg, tcRecipient.G), decengctx(recipient, tcRecipient))
+		return string(decoded.Bytes()), err
+	}
+
+	if decmsg, err := decrypt(false); err != nil {
+		t.Fatalf("decrypt without asserting the signature: %s", err)
+	} else if decmsg != msg {
+		t.Errorf("decoded: %q, expected: %q", decmsg, msg)
+	}
+	if _, err := decrypt(true); err == nil {
+		t.Error("asserting the signature succeeded without knowing who signed it")
+	}
 }
 
 func TestPGPDecryptLong(t *testing.T) {
@@ -13,14 +13,15 @@ import (
 )
 
 type PGPEncryptArg struct {
-	Recips       []string // user assertions
-	Source       io.Reader
-	Sink         io.WriteCloser
-	NoSign       bool
-	NoSelf       bool
-	BinaryOutput bool
-	KeyQuery     string
-	TrackOptions keybase1.TrackOptions
+	Recips          []string // user assertions
+	Source          io.Reader
+	Sink            io.WriteCloser
+	NoSign          bool
+	NoSelf          bool
+	BinaryOutput    bool
+	KeyQuery        string
+	TrackOptions    keybase1.TrackOptions
+	KeyserverLookup bool
 }
 
 // PGPEncrypt encrypts data read from a source into a sink
@@ -112,9 +113,10 @@ func (e *PGPEncrypt) Run(ctx *Context) error {
 	}
 
 	kfarg := &PGPKeyfinderArg{
-		Users:        e.arg.Recips,
-		SkipTrack:    skipTrack,
-		TrackOptions: e.arg.TrackOptions,
+		Users:           e.arg.Recips,
+		SkipTrack:       skipTrack,
+		TrackOptions:    e.arg.TrackOptions,
+		KeyserverLookup: e.arg.KeyserverLookup,
 	}
 
 	kf := NewPGPKeyfinder(kfarg, e.G())
@@ -0,0 +1,62 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+// PGPExtendEngine pushes back the expiration of the user's PGP keys and
+// of their encryption subkeys.
+type PGPExtendEngine struct {
+	pgpKeyEditor
+	lifetime int
+}
+
+// NewPGPExtendEngine makes an engine that extends the given keys, or all
+// of them, so that they expire lifetime seconds from now.  A lifetime of
+// 0 means libkb.KeyExpireIn.
+func NewPGPExtendEngine(fingerprints []string, all bool, lifetime int, g *libkb.GlobalContext) *PGPExtendEngine {
+	if lifetime == 0 {
+		lifetime = libkb.KeyExpireIn
+	}
+	return &PGPExtendEngine{
+		pgpKeyEditor: newPGPKeyEditor(g, fingerprints, all, "extend the expiration of your PGP key"),
+		lifetime:     lifetime,
+	}
+}
+
+func (e *PGPExtendEngine) Name() string {
+	return "PGPExtend"
+}
+
+func (e *PGPExtendEngine) Prereqs() Prereqs {
+	return Prereqs{
+		Session: true,
+	}
+}
+
+func (e *PGPExtendEngine) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{
+		libkb.LogUIKind,
+		libkb.SecretUIKind,
+	}
+}
+
+func (e *PGPExtendEngine) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{}
+}
+
+func (e *PGPExtendEngine) Run(ctx *Context) error {
+	etime := time.Now().Add(time.Duration(e.lifetime) * time.Second)
+	return e.run(ctx, func(bundle *libkb.PGPKeyBundle) error {
+		if err := bundle.ExtendExpiration(etime, nil); err != nil {
+			return err
+		}
+		ctx.LogUI.Info("Key %s now expires on %s.", bundle.GetFingerprint(), etime.Format("2006-01-02"))
+		return nil
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+)
+
+func TestPGPExtend(t *testing.T) {
+	tc := SetupEngineTest(t, "pgp_extend")
+	defer tc.Cleanup()
+
+	fu := createFakeUserWithPGPSibkey(tc)
+	before, _ := getFakeUsersKeyBundleFromServer(tc, fu).Expirations()
+
+	lifetime := 2 * libkb.OneYearInSeconds
+	eng := NewPGPExtendEngine(nil, false, lifetime, tc.G)
+	ctx := Context{
+		LogUI:    tc.G.UI.GetLogUI(),
+		SecretUI: fu.NewSecretUI(),
+	}
+	if err := RunEngine(eng, &ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(eng.EditedFingerprints()) != 1 {
+		t.Fatalf("expected one key to be extended, got %d", len(eng.EditedFingerprints()))
+	}
+
+	primary, encryption := getFakeUsersKeyBundleFromServer(tc, fu).Expirations()
+	want := time.Now().Add(time.Duration(lifetime) * time.Second)
+	if primary.Equal(before) || want.Sub(primary) > time.Minute || want.Sub(encryption) > time.Minute {
+		t.Errorf("expirations on the server: %s, %s; expected about %s", primary, encryption, want)
+	}
+}
+
+func TestPGPRotateSubkey(t *testing.T) {
+	tc := SetupEngineTest(t, "pgp_rotate_subkey")
+	defer tc.Cleanup()
+
+	fu := createFakeUserWithPGPSibkey(tc)
+	if n := len(getFakeUsersKeyBundleFromServer(tc, fu).Subkeys); n != 1 {
+		t.Fatalf("expected 1 subkey, got %d", n)
+	}
+
+	eng := NewPGPRotateSubkeyEngine(nil, false, 0, tc.G)
+	ctx := Context{
+		LogUI:    tc.G.UI.GetLogUI(),
+		SecretUI: fu.NewSecretUI(),
+	}
+	if err := RunEngine(eng, &ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(getFakeUsersKeyBundleFromServer(tc, fu).Subkeys); n != 2 {
+		t.Fatalf("expected 2 subkeys on the server, got %d", n)
+	}
+
+	// The new secret subkey has to be in the local keyring, or messages
+	// for it couldn't be decrypted.
+	var skb *libkb.SKB
+	kerr := tc.G.LoginState().Keyring(func(kr *libkb.SKBKeyringFile) {
+		skb = kr.LookupByKid(getFakeUsersKeyBundleFromServer(tc, fu).GetKID())
+	}, "TestPGPRotateSubkey")
+	if kerr != nil {
+		t.Fatal(kerr)
+	}
+	if skb == nil {
+		t.Fatal("the rotated key isn't in the local keyring")
+	}
+	key, err := skb.GetPubKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(key.(*libkb.PGPKeyBundle).Subkeys); n != 2 {
+		t.Errorf("expected 2 subkeys in the local keyring, got %d", n)
+	}
+}
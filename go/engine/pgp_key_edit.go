@@ -0,0 +1,211 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// pgpKeyEditor is the part of PGPExtendEngine and PGPRotateSubkeyEngine
+// that finds the secret halves of the user's PGP keys, in the local
+// keyring or in GPG, and saves and posts the keys once they are changed.
+type pgpKeyEditor struct {
+	libkb.Contextified
+	selectedFingerprints map[string]bool
+	all                  bool
+	reason               string // for passphrase prompts
+	addsSecrets          bool   // whether edits add secret keys GPG would need too
+	edited               []libkb.PGPFingerprint
+}
+
+func newPGPKeyEditor(g *libkb.GlobalContext, fingerprints []string, all bool, reason string) pgpKeyEditor {
+	selectedFingerprints := make(map[string]bool)
+	for _, fpString := range fingerprints {
+		selectedFingerprints[strings.ToLower(fpString)] = true
+	}
+	return pgpKeyEditor{
+		Contextified:         libkb.NewContextified(g),
+		selectedFingerprints: selectedFingerprints,
+		all:                  all,
+		reason:               reason,
+	}
+}
+
+// run calls edit on the unlocked secret half of each selected key, then
+// saves the key where it was found and posts its new public half.
+func (e *pgpKeyEditor) run(ctx *Context, edit func(*libkb.PGPKeyBundle) error) error {
+	if e.all && len(e.selectedFingerprints) > 0 {
+		return fmt.Errorf("Cannot use explicit fingerprints with --all.")
+	}
+
+	me, err := libkb.LoadMe(libkb.NewLoadUserArg(e.G()))
+	if err != nil {
+		return err
+	}
+	keys := me.GetActivePGPKeys(false /* not just sibkeys */)
+	if len(keys) == 0 {
+		return libkb.NoKeyError{Msg: "You don't have any PGP keys."}
+	}
+	if len(keys) > 1 && !e.all && len(e.selectedFingerprints) == 0 {
+		return fmt.Errorf("You have more than one PGP key. To change all of them, use --all.")
+	}
+
+	del := libkb.Delegator{
+		DelegationType: libkb.PGPUpdateType,
+		Me:             me,
+		Expire:         libkb.KeyExpireIn,
+		Contextified:   libkb.NewContextified(e.G()),
+	}
+	if err := del.LoadSigningKey(ctx.LoginContext, ctx.SecretUI); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		fingerprint := key.GetFingerprint()
+		if len(e.selectedFingerprints) > 0 && !e.selectedFingerprints[fingerprint.String()] {
+			ctx.LogUI.Warning("Skipping key %s", fingerprint.String())
+			continue
+		}
+
+		bundle, inKeyring, err := e.loadSecret(ctx, me, key)
+		if err != nil {
+			if _, ok := err.(libkb.NoSecretKeyError); ok {
+				ctx.LogUI.Warning("No secret key for %s in the local Keybase keyring or the GPG keyring; skipping it.", fingerprint.String())
+				continue
+			}
+			return err
+		}
+
+		if err := edit(bundle); err != nil {
+			return err
+		}
+
+		if err := e.save(ctx, me, bundle, inKeyring); err != nil {
+			return err
+		}
+
+		del.NewKey = bundle
+		ctx.LogUI.Info("Posting update for key %s.", fingerprint.String())
+		if err := del.Run(ctx.LoginContext); err != nil {
+			return err
+		}
+		e.edited = append(e.edited, fingerprint)
+	}
+	return nil
+}
+
+// loadSecret unlocks the secret half of key, preferring the local
+// Keybase keyring to GPG.  inKeyring says which one it came from.
+func (e *pgpKeyEditor) loadSecret(ctx *Context, me *libkb.User, key *libkb.PGPKeyBundle) (bundle *libkb.PGPKeyBundle, inKeyring bool, err error) {
+	var skb *libkb.SKB
+	err = e.withKeyring(ctx, func(kr *libkb.SKBKeyringFile) error {
+		skb = kr.LookupByKid(key.GetKID())
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if skb != nil {
+		unlocked, err := skb.PromptAndUnlock(ctx.LoginContext, e.reason, "", nil, ctx.SecretUI, nil, me)
+		if err != nil {
+			return nil, false, err
+		}
+		bundle, ok := unlocked.(*libkb.PGPKeyBundle)
+		if !ok {
+			return nil, false, libkb.BadKeyError{Msg: fmt.Sprintf("key %s in the local keyring isn't a PGP key", key.GetKID())}
+		}
+		return bundle, true, nil
+	}
+
+	gpg := e.G().GetGpgClient()
+	if err := gpg.Configure(); err != nil {
+		if err == exec.ErrNotFound {
+			return nil, false, libkb.NoSecretKeyError{}
+		}
+		return nil, false, err
+	}
+	bundle, err = gpg.ImportKey(true /* secret */, key.GetFingerprint())
+	if err != nil {
+		if _, ok := err.(libkb.NoKeyError); ok {
+			return nil, false, libkb.NoSecretKeyError{}
+		}
+		return nil, false, err
+	}
+	if err := bundle.Unlock(e.reason, ctx.SecretUI); err != nil {
+		return nil, false, err
+	}
+	return bundle, false, nil
+}
+
+// save writes the changed key back to where it came from.  GPG only gets
+// the public half, which it merges into the key it has, so any secret keys
+// an edit added go into the Keybase keyring as well.
+func (e *pgpKeyEditor) save(ctx *Context, me *libkb.User, bundle *libkb.PGPKeyBundle, inKeyring bool) error {
+	if !inKeyring {
+		if err := e.G().GetGpgClient().ExportKey(*bundle); err != nil {
+			return err
+		}
+		ctx.LogUI.Info("Updated key %s in the GPG keyring.", bundle.GetFingerprint())
+		if !e.addsSecrets {
+			return nil
+		}
+	}
+
+	lks, err := libkb.NewLKSForEncrypt(ctx.SecretUI, me.GetUID(), e.G())
+	if err != nil {
+		return err
+	}
+	skb, err := bundle.ToLksSKB(lks)
+	if err != nil {
+		return err
+	}
+	err = e.withKeyring(ctx, func(kr *libkb.SKBKeyringFile) error {
+		if _, err := kr.RemoveKIDs([]keybase1.KID{bundle.GetKID()}); err != nil {
+			return err
+		}
+		return kr.PushAndSave(skb)
+	})
+	if err != nil {
+		return err
+	}
+	if !inKeyring {
+		ctx.LogUI.Warning("The new secret keys are in the Keybase keyring, but not in GPG's. To give them to GPG, run:")
+		ctx.LogUI.Warning("  keybase pgp export -s -q %s | gpg --import", bundle.GetFingerprint())
+	}
+	return nil
+}
+
+// withKeyring calls f with the local keyring.
+func (e *pgpKeyEditor) withKeyring(ctx *Context, f func(*libkb.SKBKeyringFile) error) error {
+	if ctx.LoginContext != nil {
+		kr, err := ctx.LoginContext.Keyring()
+		if err != nil {
+			return err
+		}
+		return f(kr)
+	}
+	var err error
+	aerr := e.G().LoginState().Account(func(a *libkb.Account) {
+		kr, kerr := a.Keyring()
+		if kerr != nil {
+			err = kerr
+			return
+		}
+		err = f(kr)
+	}, "pgpKeyEditor - withKeyring")
+	if aerr != nil {
+		return aerr
+	}
+	return err
+}
+
+// EditedFingerprints are the keys that were changed and posted.
+func (e *pgpKeyEditor) EditedFingerprints() []libkb.PGPFingerprint {
+	return e.edited
+}
@@ -25,6 +25,11 @@ type PGPKeyfinderArg struct {
 	Users        []string
 	SkipTrack    bool
 	TrackOptions keybase1.TrackOptions
+
+	// KeyserverLookup allows Users to include email addresses of people
+	// who aren't on Keybase.  Their keys are taken from the local cache,
+	// or else looked up in Web Key Directories and on the HKP keyserver.
+	KeyserverLookup bool
 }
 
 // NewPGPKeyfinder creates a PGPKeyfinder engine.
@@ -63,6 +68,7 @@ func (e *PGPKeyfinder) Run(ctx *Context) error {
 	e.setup(ctx)
 	e.verifyUsers(ctx)
 	e.loadKeys(ctx)
+	e.lookupExternal(ctx)
 	return e.runerr
 }
 
@@ -104,7 +110,7 @@ func (e *PGPKeyfinder) trackUsers(ctx *Context) {
 	}
 
 	// need to track any users we aren't tracking
-	for _, u := range e.arg.Users {
+	for _, u := range e.keybaseUsers() {
 		if err := e.trackUser(ctx, u); err != nil {
 			// ignore self track errors
 			if _, ok := err.(libkb.SelfTrackError); !ok {
@@ -121,7 +127,7 @@ func (e *PGPKeyfinder) identifyUsers(ctx *Context) {
 	}
 
 	// need to identify all the users
-	for _, u := range e.arg.Users {
+	for _, u := range e.keybaseUsers() {
 		if err := e.identifyUser(ctx, u); err != nil {
 			e.runerr = err
 			return
@@ -136,6 +142,9 @@ func (e *PGPKeyfinder) loadKeys(ctx *Context) {
 
 	// get the pgp keys for all the users
 	for _, x := range e.uplus {
+		if x.User == nil {
+			continue
+		}
 		keys := x.User.GetActivePGPKeys(true)
 		if len(keys) == 0 {
 			e.runerr = fmt.Errorf("User %s doesn't have a pgp key", x.User.GetName())
@@ -156,7 +165,7 @@ func (e *PGPKeyfinder) trackUser(ctx *Context, username string) error {
 	if err := RunEngine(eng, ctx); err != nil {
 		return err
 	}
-	e.addUser(eng.User(), true)
+	e.addUser(eng.User(), true, eng.Outcome())
 	return nil
 }
 
@@ -169,7 +178,12 @@ func (e *PGPKeyfinder) identifyUser(ctx *Context, user string) error {
 	if err := RunEngine(eng, ctx); err != nil {
 		return err
 	}
-	e.addUser(eng.User(), false)
+	// Without tracking them again, nothing vouches for the keys of a
+	// tracked user who reset their account.
+	if eng.Outcome().AccountReset() != nil {
+		return libkb.TrackedUserResetError{Username: eng.User().GetName()}
+	}
+	e.addUser(eng.User(), false, eng.Outcome())
 	return nil
 }
 
@@ -177,6 +191,72 @@ type UserPlusKeys struct {
 	User      *libkb.User
 	IsTracked bool
 	Keys      []*libkb.PGPKeyBundle
+	Outcome   *libkb.IdentifyOutcome // from identifying or tracking User
+
+	// External is set instead of User for keys found outside of
+	// Keybase by email address.  They're unverified.
+	External *libkb.ExternalPGPKeys
+}
+
+// keybaseUsers are the users to find on Keybase, which is all of them
+// unless some are to be looked up elsewhere.
+func (e *PGPKeyfinder) keybaseUsers() []string {
+	if !e.arg.KeyserverLookup {
+		return e.arg.Users
+	}
+	var ret []string
+	for _, u := range e.arg.Users {
+		if !libkb.IsExternalPGPRecipient(u) {
+			ret = append(ret, u)
+		}
+	}
+	return ret
+}
+
+// lookupExternal finds the keys of users given by email address, and
+// shows them in the identify UI, marked as unverified.
+func (e *PGPKeyfinder) lookupExternal(ctx *Context) {
+	if e.runerr != nil || !e.arg.KeyserverLookup {
+		return
+	}
+	for _, u := range e.arg.Users {
+		if !libkb.IsExternalPGPRecipient(u) {
+			continue
+		}
+		ext, err := libkb.LoadExternalPGPKeys(e.G(), u)
+		if err != nil {
+			e.runerr = err
+			return
+		}
+		if ext == nil {
+			if ext, err = libkb.NewPGPKeyserverLookup(e.G()).Lookup(u); err != nil {
+				e.runerr = err
+				return
+			}
+			if err := ext.Store(e.G()); err != nil {
+				e.G().Log.Warning("Failed to cache the PGP keys of %s: %s", u, err)
+			}
+		}
+		displayExternalPGPKeys(ctx, ext)
+		e.uplus = append(e.uplus, &UserPlusKeys{Keys: ext.Keys, External: ext})
+	}
+}
+
+func displayExternalPGPKeys(ctx *Context, ext *libkb.ExternalPGPKeys) {
+	if ctx.IdentifyUI == nil {
+		return
+	}
+	ctx.IdentifyUI.Start(ext.Email)
+	diff := libkb.NewTrackDiffUnverified(ext.Source)
+	for _, key := range ext.Keys {
+		fp := key.GetFingerprint()
+		ctx.IdentifyUI.DisplayKey(keybase1.IdentifyKey{
+			PGPFingerprint: fp[:],
+			KID:            key.GetKID(),
+			TrackDiff:      libkb.ExportTrackDiff(diff),
+		})
+	}
+	ctx.IdentifyUI.Finish()
 }
 
 func (e *PGPKeyfinder) loadMe() {
@@ -194,6 +274,6 @@ func (e *PGPKeyfinder) loadMe() {
 	e.me = me
 }
 
-func (e *PGPKeyfinder) addUser(user *libkb.User, tracked bool) {
-	e.uplus = append(e.uplus, &UserPlusKeys{User: user, IsTracked: tracked})
+func (e *PGPKeyfinder) addUser(user *libkb.User, tracked bool, outcome *libkb.IdentifyOutcome) {
+	e.uplus = append(e.uplus, &UserPlusKeys{User: user, IsTracked: tracked, Outcome: outcome})
 }
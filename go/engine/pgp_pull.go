@@ -4,6 +4,7 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/keybase/client/go/libkb"
@@ -11,12 +12,14 @@ import (
 )
 
 type PGPPullEngineArg struct {
-	UserAsserts []string
+	UserAsserts     []string
+	KeyserverLookup bool
 }
 
 type PGPPullEngine struct {
 	listTrackingEngine *ListTrackingEngine
 	userAsserts        []string
+	keyserverLookup    bool
 	gpgClient          *libkb.GpgCLI
 	libkb.Contextified
 }
@@ -25,6 +28,7 @@ func NewPGPPullEngine(arg *PGPPullEngineArg, g *libkb.GlobalContext) *PGPPullEng
 	return &PGPPullEngine{
 		listTrackingEngine: NewListTrackingEngine(&ListTrackingEngineArg{}, g),
 		userAsserts:        arg.UserAsserts,
+		keyserverLookup:    arg.KeyserverLookup,
 		Contextified:       libkb.NewContextified(g),
 	}
 }
@@ -150,6 +154,7 @@ func (e *PGPPullEngine) processUserWhenLoggedOut(ctx *Context, u string) error {
 	}
 
 	// prompt if the identify is correct
+	ieng.reportAccountReset()
 	outcome := ieng.Outcome().Export()
 	outcome.ForPGPPull = true
 	confirmed, err := ctx.IdentifyUI.Confirm(outcome)
@@ -179,6 +184,10 @@ func (e *PGPPullEngine) Run(ctx *Context) error {
 		return err
 	}
 
+	if e.keyserverLookup {
+		return e.runKeyserver(ctx)
+	}
+
 	if ok, _ := IsLoggedIn(e, ctx); !ok {
 		return e.runLoggedOut(ctx)
 	}
@@ -218,6 +227,36 @@ func (e *PGPPullEngine) runLoggedIn(ctx *Context, summaries []keybase1.UserSumma
 	return nil
 }
 
+// runKeyserver fetches the keys of people who aren't on Keybase, by email
+// address, caches them and imports them into GnuPG.
+func (e *PGPPullEngine) runKeyserver(ctx *Context) error {
+	if len(e.userAsserts) == 0 {
+		return errors.New("Give the email addresses to look up on keyservers")
+	}
+	for _, email := range e.userAsserts {
+		if !libkb.IsExternalPGPRecipient(email) {
+			return fmt.Errorf("Not an email address: %q", email)
+		}
+	}
+	for _, email := range e.userAsserts {
+		ext, err := libkb.NewPGPKeyserverLookup(e.G()).Lookup(email)
+		if err != nil {
+			return err
+		}
+		if err := ext.Store(e.G()); err != nil {
+			return err
+		}
+		displayExternalPGPKeys(ctx, ext)
+		for _, bundle := range ext.Keys {
+			if err := e.gpgClient.ExportKey(*bundle); err != nil {
+				return err
+			}
+		}
+		ctx.LogUI.Warning("Imported unverified key for %s from %s.", ext.Email, ext.Source)
+	}
+	return nil
+}
+
 func (e *PGPPullEngine) exportKeysToGPG(ctx *Context, user *libkb.User, tfp map[string]bool) error {
 	for _, bundle := range user.GetActivePGPKeys(false) {
 		// Check each key against the tracked set.
@@ -0,0 +1,63 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"github.com/keybase/client/go/libkb"
+)
+
+// PGPRotateSubkeyEngine adds a fresh encryption subkey to the user's PGP
+// keys.  The old subkeys are kept, so that old messages can still be
+// decrypted, but new messages are encrypted for the new one.
+type PGPRotateSubkeyEngine struct {
+	pgpKeyEditor
+	lifetime int
+}
+
+// NewPGPRotateSubkeyEngine makes an engine that rotates the encryption
+// subkeys of the given keys, or all of them.  The new subkeys expire
+// lifetime seconds from now; 0 means libkb.SubkeyExpireIn.
+func NewPGPRotateSubkeyEngine(fingerprints []string, all bool, lifetime int, g *libkb.GlobalContext) *PGPRotateSubkeyEngine {
+	if lifetime == 0 {
+		lifetime = libkb.SubkeyExpireIn
+	}
+	e := &PGPRotateSubkeyEngine{
+		pgpKeyEditor: newPGPKeyEditor(g, fingerprints, all, "add a subkey to your PGP key"),
+		lifetime:     lifetime,
+	}
+	e.addsSecrets = true
+	return e
+}
+
+func (e *PGPRotateSubkeyEngine) Name() string {
+	return "PGPRotateSubkey"
+}
+
+func (e *PGPRotateSubkeyEngine) Prereqs() Prereqs {
+	return Prereqs{
+		Session: true,
+	}
+}
+
+func (e *PGPRotateSubkeyEngine) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{
+		libkb.LogUIKind,
+		libkb.SecretUIKind,
+	}
+}
+
+func (e *PGPRotateSubkeyEngine) SubConsumers() []libkb.UIConsumer {
+	return []libkb.UIConsumer{}
+}
+
+func (e *PGPRotateSubkeyEngine) Run(ctx *Context) error {
+	return e.run(ctx, func(bundle *libkb.PGPKeyBundle) error {
+		if err := bundle.AddEncryptionSubkey(e.lifetime, nil); err != nil {
+			return err
+		}
+		subkey := bundle.Subkeys[len(bundle.Subkeys)-1].PublicKey
+		ctx.LogUI.Info("Added encryption subkey %s to key %s.", subkey.KeyIdString(), bundle.GetFingerprint())
+		return nil
+	})
+}
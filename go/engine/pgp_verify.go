@@ -15,6 +15,7 @@ import (
 	"github.com/keybase/go-crypto/openpgp"
 	"github.com/keybase/go-crypto/openpgp/armor"
 	"github.com/keybase/go-crypto/openpgp/clearsign"
+	pgpErrors "github.com/keybase/go-crypto/openpgp/errors"
 	"github.com/keybase/go-crypto/openpgp/packet"
 )
 
@@ -119,6 +120,9 @@ func (e *PGPVerify) runDetached(ctx *Context) error {
 		checkfn = openpgp.CheckArmoredDetachedSignature
 	}
 	signer, err := checkfn(sk, e.peek, bytes.NewReader(e.arg.Signature))
+	if err == pgpErrors.ErrUnknownIssuer {
+		return libkb.PGPUnknownSignerError{}
+	}
 	if err != nil {
 		return err
 	}
@@ -151,8 +155,12 @@ func (e *PGPVerify) runDetached(ctx *Context) error {
 			e.signStatus.SignatureTime = val.CreationTime
 		}
 
+		desc, err := sk.Signer(signer)
+		if err != nil {
+			return err
+		}
 		fingerprint := libkb.PGPFingerprint(signer.PrimaryKey.Fingerprint)
-		OutputSignatureSuccess(ctx, fingerprint, sk.Owner(), e.signStatus.SignatureTime)
+		OutputSignatureSuccess(ctx, fingerprint, desc, e.signStatus.SignatureTime)
 	}
 
 	return nil
@@ -182,6 +190,9 @@ func (e *PGPVerify) runClearsign(ctx *Context) error {
 	}
 
 	signer, err := openpgp.CheckDetachedSignature(sk, bytes.NewReader(b.Bytes), bytes.NewReader(sigBody))
+	if err == pgpErrors.ErrUnknownIssuer {
+		return libkb.PGPUnknownSignerError{}
+	}
 	if err != nil {
 		return fmt.Errorf("Check sig error: %s", err)
 	}
@@ -205,8 +216,12 @@ func (e *PGPVerify) runClearsign(ctx *Context) error {
 			e.signStatus.SignatureTime = val.CreationTime
 		}
 
+		desc, err := sk.Signer(signer)
+		if err != nil {
+			return err
+		}
 		fingerprint := libkb.PGPFingerprint(signer.PrimaryKey.Fingerprint)
-		OutputSignatureSuccess(ctx, fingerprint, sk.Owner(), e.signStatus.SignatureTime)
+		OutputSignatureSuccess(ctx, fingerprint, desc, e.signStatus.SignatureTime)
 	}
 
 	return nil
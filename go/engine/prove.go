@@ -4,6 +4,8 @@
 package engine
 
 import (
+	"fmt"
+
 	libkb "github.com/keybase/client/go/libkb"
 	keybase1 "github.com/keybase/client/go/protocol"
 	jsonw "github.com/keybase/go-jsonw"
@@ -26,6 +28,11 @@ type Prove struct {
 	username           string
 	usernameNormalized string
 
+	// noninteractive is for scripts and bots: the prove UI isn't used,
+	// and the proof is left in proofToPost for them to post.
+	noninteractive bool
+	proofToPost    keybase1.ProofToPost
+
 	libkb.Contextified
 }
 
@@ -37,6 +44,22 @@ func NewProve(arg *keybase1.StartProofArg, g *libkb.GlobalContext) *Prove {
 	}
 }
 
+// NewProveNoninteractive makes a new Prove Engine that doesn't use the
+// prove UI, and doesn't wait for the proof to be posted; see
+// ProofToPost.
+func NewProveNoninteractive(arg *keybase1.MakeProofArg, g *libkb.GlobalContext) *Prove {
+	return &Prove{
+		arg: &keybase1.StartProofArg{
+			SessionID: arg.SessionID,
+			Service:   arg.Service,
+			Username:  arg.Username,
+			Force:     arg.Force,
+		},
+		noninteractive: true,
+		Contextified:   libkb.NewContextified(g),
+	}
+}
+
 // Name provides the name of this engine for the engine interface contract
 func (p *Prove) Name() string {
 	return "Prove"
@@ -49,6 +72,12 @@ func (p *Prove) Prereqs() Prereqs {
 
 // RequiredUIs returns the required UIs.
 func (p *Prove) RequiredUIs() []libkb.UIKind {
+	if p.noninteractive {
+		return []libkb.UIKind{
+			libkb.LogUIKind,
+			libkb.SecretUIKind,
+		}
+	}
 	return []libkb.UIKind{
 		libkb.LogUIKind,
 		libkb.ProveUIKind,
@@ -70,6 +99,9 @@ func (p *Prove) checkExists1(ctx *Context) (err error) {
 	proofs := p.me.IDTable().GetActiveProofsFor(p.st)
 	if len(proofs) != 0 && !p.arg.Force && p.st.LastWriterWins() {
 		lst := proofs[len(proofs)-1]
+		if p.noninteractive {
+			return libkb.ProofExistsError{Account: lst.ToDisplayString()}
+		}
 		var redo bool
 		redo, err = ctx.ProveUI.PromptOverwrite(context.TODO(), keybase1.PromptOverwriteArg{
 			Account: lst.ToDisplayString(),
@@ -88,7 +120,9 @@ func (p *Prove) checkExists1(ctx *Context) (err error) {
 
 func (p *Prove) promptRemoteName(ctx *Context) (err error) {
 	p.username = p.arg.Username
-	if len(p.username) == 0 {
+	if len(p.username) == 0 && p.noninteractive {
+		err = fmt.Errorf("a username on %s is needed", p.arg.Service)
+	} else if len(p.username) == 0 {
 		var prevErr error
 		for len(p.username) == 0 && err == nil {
 			var un string
@@ -126,7 +160,13 @@ func (p *Prove) checkExists2(ctx *Context) (err error) {
 				break
 			}
 		}
-		if found != nil {
+		if found != nil && p.noninteractive {
+			if !p.arg.Force {
+				err = libkb.ProofExistsError{Account: found.ToDisplayString()}
+				return
+			}
+			p.supersede = true
+		} else if found != nil {
 			var redo bool
 			redo, err = ctx.ProveUI.PromptOverwrite(context.TODO(), keybase1.PromptOverwriteArg{
 				Account: found.ToDisplayString(),
@@ -148,7 +188,9 @@ func (p *Prove) checkExists2(ctx *Context) (err error) {
 func (p *Prove) doPrechecks(ctx *Context) (err error) {
 	var w *libkb.Markup
 	w, err = p.st.PreProofCheck(p.usernameNormalized)
-	if w != nil {
+	if w != nil && p.noninteractive {
+		p.G().Log.Debug("| Prechecks: %s", w.GetRaw())
+	} else if w != nil {
 		if uierr := ctx.ProveUI.OutputPrechecks(context.TODO(), keybase1.OutputPrechecksArg{Text: w.Export()}); uierr != nil {
 			p.G().Log.Warning("prove ui OutputPrechecks call error: %s", uierr)
 		}
@@ -157,7 +199,10 @@ func (p *Prove) doPrechecks(ctx *Context) (err error) {
 }
 
 func (p *Prove) doWarnings(ctx *Context) (err error) {
-	if mu := p.st.PreProofWarning(p.usernameNormalized); mu != nil {
+	if mu := p.st.PreProofWarning(p.usernameNormalized); mu != nil && p.noninteractive {
+		// Whoever runs a script has read the warning already.
+		p.G().Log.Debug("| Warning: %s", mu.GetRaw())
+	} else if mu != nil {
 		var ok bool
 		arg := keybase1.PreProofWarningArg{Text: mu.Export()}
 		if ok, err = ctx.ProveUI.PreProofWarning(context.TODO(), arg); err == nil && !ok {
@@ -210,6 +255,16 @@ func (p *Prove) instructAction(ctx *Context) (err error) {
 	if txt, err = p.st.FormatProofText(p.postRes); err != nil {
 		return
 	}
+	if p.noninteractive {
+		p.proofToPost = keybase1.ProofToPost{
+			SigID:        p.sigID,
+			Service:      p.arg.Service,
+			RemoteName:   p.usernameNormalized,
+			ProofText:    txt,
+			Instructions: mkp.Export(),
+		}
+		return
+	}
 	err = ctx.ProveUI.OutputInstructions(context.TODO(), keybase1.OutputInstructionsArg{
 		Instructions: mkp.Export(),
 		Proof:        txt,
@@ -273,6 +328,12 @@ func (p *Prove) SigID() keybase1.SigID {
 	return p.sigID
 }
 
+// ProofToPost returns the proof a noninteractive engine made, for the
+// caller to post.
+func (p *Prove) ProofToPost() keybase1.ProofToPost {
+	return p.proofToPost
+}
+
 // Run runs the Prove engine, performing all steps of the proof process.
 func (p *Prove) Run(ctx *Context) (err error) {
 	p.G().Log.Debug("+ ProofEngine.Run")
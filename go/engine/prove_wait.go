@@ -0,0 +1,114 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+const (
+	proveWaitInterval = 10 * time.Second
+	proveWaitTimeout  = 10 * time.Minute
+)
+
+// ProveWait is an engine that waits for a proof made by a noninteractive
+// Prove engine to be posted, so that scripts and bots don't have to
+// answer the prove UI's okToCheck.
+type ProveWait struct {
+	libkb.Contextified
+	arg    *keybase1.WaitForProofArg
+	found  bool
+	status keybase1.ProofStatus
+
+	// For tests.
+	checkPosted func(keybase1.SigID) (bool, keybase1.ProofStatus, error)
+	sleep       func(time.Duration)
+	now         func() time.Time
+}
+
+// NewProveWait creates a ProveWait engine.
+func NewProveWait(g *libkb.GlobalContext, arg *keybase1.WaitForProofArg) *ProveWait {
+	return &ProveWait{
+		Contextified: libkb.NewContextified(g),
+		arg:          arg,
+		checkPosted:  libkb.CheckPostedViaSigID,
+		sleep:        time.Sleep,
+		now:          time.Now,
+	}
+}
+
+// Name is the unique engine name.
+func (e *ProveWait) Name() string {
+	return "ProveWait"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *ProveWait) Prereqs() Prereqs {
+	return Prereqs{Session: true}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *ProveWait) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{libkb.LogUIKind}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *ProveWait) SubConsumers() []libkb.UIConsumer {
+	return nil
+}
+
+// Run starts the engine.
+func (e *ProveWait) Run(ctx *Context) error {
+	st := libkb.GetServiceType(e.arg.Service)
+	if st == nil {
+		return libkb.BadServiceError{Service: e.arg.Service}
+	}
+	interval := time.Duration(e.arg.IntervalSecs) * time.Second
+	if interval <= 0 {
+		interval = proveWaitInterval
+	}
+	timeout := time.Duration(e.arg.TimeoutSecs) * time.Second
+	if timeout <= 0 {
+		timeout = proveWaitTimeout
+	}
+	deadline := e.now().Add(timeout)
+
+	for i := 0; ; i++ {
+		found, status, err := e.checkPosted(e.arg.SigID)
+		if err != nil {
+			return err
+		}
+		e.found, e.status = found, status
+		if found {
+			ctx.LogUI.Info("Found the proof for %s", st.DisplayName(e.arg.Username))
+			return nil
+		}
+
+		// The warnings are for people; all that matters here is
+		// whether to keep trying, which DNS proofs always should.
+		warn, err := st.RecheckProofPosting(i, status, e.arg.Username)
+		if warn != nil {
+			e.G().Log.Debug("| Recheck warning: %s", warn.GetRaw())
+		}
+		if _, ok := err.(libkb.WaitForItError); err != nil && !ok {
+			return err
+		}
+
+		if !e.now().Add(interval).Before(deadline) {
+			ctx.LogUI.Warning("Didn't find the proof for %s in %s (status %d)", st.DisplayName(e.arg.Username), timeout, status)
+			return nil
+		}
+		ctx.LogUI.Info("Proof not found yet (status %d); checking again in %s", status, interval)
+		e.sleep(interval)
+	}
+}
+
+// Results are whether the proof was found, and the last status it was
+// checked with.
+func (e *ProveWait) Results() (bool, keybase1.ProofStatus) {
+	return e.found, e.status
+}
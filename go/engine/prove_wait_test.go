@@ -0,0 +1,80 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// testProveWait makes a ProveWait whose proof is found on the found-th
+// check, or never if found is 0, on a fake clock.
+func testProveWait(tc libkb.TestContext, service string, found int) (*ProveWait, *int) {
+	eng := NewProveWait(tc.G, &keybase1.WaitForProofArg{
+		SigID:        keybase1.SigID("aa"),
+		Service:      service,
+		Username:     "example.com",
+		IntervalSecs: 10,
+		TimeoutSecs:  60,
+	})
+	checks := 0
+	now := time.Unix(1440000000, 0)
+	eng.checkPosted = func(keybase1.SigID) (bool, keybase1.ProofStatus, error) {
+		checks++
+		if checks == found {
+			return true, keybase1.ProofStatus_OK, nil
+		}
+		return false, keybase1.ProofStatus_NOT_FOUND, nil
+	}
+	eng.sleep = func(d time.Duration) { now = now.Add(d) }
+	eng.now = func() time.Time { return now }
+	return eng, &checks
+}
+
+func TestProveWaitFound(t *testing.T) {
+	tc := SetupEngineTest(t, "provewait")
+	defer tc.Cleanup()
+
+	// DNS proofs are always worth waiting for.
+	eng, checks := testProveWait(tc, "dns", 3)
+	if err := eng.Run(&Context{LogUI: tc.G.UI.GetLogUI()}); err != nil {
+		t.Fatal(err)
+	}
+	if found, status := eng.Results(); !found || status != keybase1.ProofStatus_OK {
+		t.Errorf("found %v with status %d, expected the proof", found, status)
+	}
+	if *checks != 3 {
+		t.Errorf("checked %d times, expected 3", *checks)
+	}
+}
+
+func TestProveWaitTimeout(t *testing.T) {
+	tc := SetupEngineTest(t, "provewait")
+	defer tc.Cleanup()
+
+	eng, checks := testProveWait(tc, "https", 0)
+	if err := eng.Run(&Context{LogUI: tc.G.UI.GetLogUI()}); err != nil {
+		t.Fatal(err)
+	}
+	if found, status := eng.Results(); found || status != keybase1.ProofStatus_NOT_FOUND {
+		t.Errorf("found %v with status %d, expected not found", found, status)
+	}
+	// At 0s, 10s, ... 50s; another check would be past the timeout.
+	if *checks != 6 {
+		t.Errorf("checked %d times, expected 6", *checks)
+	}
+}
+
+func TestProveWaitBadService(t *testing.T) {
+	tc := SetupEngineTest(t, "provewait")
+	defer tc.Cleanup()
+
+	eng, _ := testProveWait(tc, "myspace", 1)
+	if _, ok := eng.Run(&Context{LogUI: tc.G.UI.GetLogUI()}).(libkb.BadServiceError); !ok {
+		t.Error("waited for a proof on a service that doesn't exist")
+	}
+}
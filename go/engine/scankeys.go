@@ -28,6 +28,11 @@ type ScanKeys struct {
 	opts  *keybase1.TrackOptions
 	owner *libkb.User // the owner of the found key(s).  Can be `me` or any other keybase user.
 	me    *libkb.User
+
+	ownerTracked bool
+	ownerOutcome *libkb.IdentifyOutcome
+
+	unlocked map[keybase1.KID]*libkb.PGPKeyBundle // so that each key is unlocked once
 	libkb.Contextified
 }
 
@@ -43,6 +48,7 @@ func NewScanKeys(secui libkb.SecretUI, idui libkb.IdentifyUI, opts *keybase1.Tra
 		secui:        secui,
 		idui:         idui,
 		opts:         opts,
+		unlocked:     make(map[keybase1.KID]*libkb.PGPKeyBundle),
 		Contextified: libkb.NewContextified(g),
 	}
 	var err error
@@ -67,8 +73,9 @@ func NewScanKeys(secui libkb.SecretUI, idui libkb.IdentifyUI, opts *keybase1.Tra
 		return nil, fmt.Errorf("loadme error: %s", err)
 	}
 
-	// if user provided, then load their local keys, and their synced secret key:
-	synced, err := sk.me.GetSyncedSecretKey()
+	// if user provided, then load their local keys, and all of their synced
+	// secret keys:
+	synced, err := sk.me.AllSyncedSecretKeys(nil)
 	if err != nil {
 		return nil, fmt.Errorf("getsyncedsecret err: %s", err)
 	}
@@ -178,17 +185,71 @@ func (s *ScanKeys) Owner() *libkb.User {
 	return s.owner
 }
 
-// coalesceBlocks puts the synced pgp key block and all the pgp key
-// blocks in ring into s.skbs.
-func (s *ScanKeys) coalesceBlocks(ring *libkb.SKBKeyringFile, synced *libkb.SKB) error {
+// Signer checks who owns signer, the key that made a verified signature,
+// and describes them, like "alice (tracked, proofs OK)".  Keys are found
+// by their 64-bit key IDs, which can collide, so this confirms the owner
+// by the key's full KID with the server.
+func (s *ScanKeys) Signer(signer *openpgp.Entity) (string, error) {
+	if s.owner == nil {
+		return "", libkb.ErrNilUser
+	}
+	if s.me != nil && s.owner.Equal(s.me) {
+		return "you", nil
+	}
+
+	kid := libkb.NewPGPKeyBundle(signer).GetKID()
+	uid, err := libkb.KeyOwner(s.G(), kid)
+	if err != nil {
+		return "", err
+	}
+	if !uid.Equal(s.owner.GetUID()) {
+		return "", libkb.BadSigError{
+			E: fmt.Sprintf("Signing key %s belongs to %s, not %s", kid, uid, s.owner.GetName()),
+		}
+	}
+
+	if s.ownerOutcome == nil {
+		return s.owner.GetName() + " (not identified)", nil
+	}
+	tracked := "not tracked"
+	if s.ownerTracked || s.ownerOutcome.TrackUsed != nil {
+		tracked = "tracked"
+	}
+	proofs := "proofs OK"
+	if s.ownerOutcome.NumProofSuccesses() == 0 {
+		proofs = "no proofs"
+	}
+	if n := s.ownerOutcome.NumProofFailures() + s.ownerOutcome.NumRevoked(); n == 1 {
+		proofs = "1 proof failed"
+	} else if n > 1 {
+		proofs = fmt.Sprintf("%d proofs failed", n)
+	}
+	return fmt.Sprintf("%s (%s, %s)", s.owner.GetName(), tracked, proofs), nil
+}
+
+// coalesceBlocks puts the synced pgp key blocks and all the pgp key
+// blocks in ring into s.skbs.  A key that is both synced and in the
+// local keyring is only used once; the local copy wins, since it's
+// unlocked with the device's keys rather than the passphrase.
+func (s *ScanKeys) coalesceBlocks(ring *libkb.SKBKeyringFile, synced []*libkb.SKB) error {
 	var err error
 	s.G().Log.Debug("+ ScanKeys::coalesceBlocks")
 	defer func() {
 		s.G().Log.Debug("- ScanKeys::coalesceBlocks -> %s", libkb.ErrToOk(err))
 	}()
 
-	if synced != nil {
-		s.skbs = append(s.skbs, synced)
+	seen := make(map[keybase1.KID]bool)
+	add := func(b *libkb.SKB) {
+		pub, err := b.GetPubKey()
+		if err != nil {
+			s.G().Log.Warning("error getting pub key from skb: %s", err)
+			return
+		}
+		if seen[pub.GetKID()] {
+			return
+		}
+		seen[pub.GetKID()] = true
+		s.skbs = append(s.skbs, b)
 	}
 
 	for _, b := range ring.Blocks {
@@ -197,8 +258,12 @@ func (s *ScanKeys) coalesceBlocks(ring *libkb.SKBKeyringFile, synced *libkb.SKB)
 		}
 		// make sure uid set on each block:
 		b.SetUID(s.me.GetUID())
-		s.skbs = append(s.skbs, b)
+		add(b)
 	}
+	for _, b := range synced {
+		add(b)
+	}
+	s.G().Log.Debug("| %d PGP secret keys available", len(s.skbs))
 
 	return nil
 }
@@ -237,6 +302,8 @@ func (s *ScanKeys) scan(id uint64) (openpgp.EntityList, error) {
 	// user found is the owner of the keys
 	s.G().Log.Debug("scan(%016x) => owner of key = (%s)", id, uplus[0].User.GetName())
 	s.owner = uplus[0].User
+	s.ownerTracked = uplus[0].IsTracked
+	s.ownerOutcome = uplus[0].Outcome
 
 	// convert the bundles to an openpgp entity list
 	// (which implements the openpgp.KeyRing interface)
@@ -292,17 +359,9 @@ func (s *ScanKeys) unlockByID(id uint64) openpgp.EntityList {
 		}
 
 		// some key in the bundle matched, so unlock everything:
-		unlocked, err := skb.PromptAndUnlock(nil, unlockReason, "", nil, s.secui, nil, s.me)
-		if err != nil {
-			s.G().Log.Warning("error unlocking key: %s", err)
-			continue
+		if unlocked := s.unlock(skb, bundle.GetKID()); unlocked != nil {
+			list = append(list, unlocked.Entity)
 		}
-		unlockedBundle, ok := unlocked.(*libkb.PGPKeyBundle)
-		if !ok {
-			s.G().Log.Warning("could not convert unlocked key to PGPKeyBundle")
-			continue
-		}
-		list = append(list, unlockedBundle.Entity)
 	}
 	return list
 }
@@ -310,17 +369,34 @@ func (s *ScanKeys) unlockByID(id uint64) openpgp.EntityList {
 func (s *ScanKeys) unlockAll() openpgp.EntityList {
 	var list openpgp.EntityList
 	for _, skb := range s.skbs {
-		unlocked, err := skb.PromptAndUnlock(nil, unlockReason, "", nil, s.secui, nil, s.me)
+		pubkey, err := skb.GetPubKey()
 		if err != nil {
-			s.G().Log.Warning("error unlocking key: %s", err)
+			s.G().Log.Warning("error getting pub key from skb: %s", err)
 			continue
 		}
-		unlockedBundle, ok := unlocked.(*libkb.PGPKeyBundle)
-		if !ok {
-			s.G().Log.Warning("could not convert unlocked key to PGPKeyBundle")
-			continue
+		if unlocked := s.unlock(skb, pubkey.GetKID()); unlocked != nil {
+			list = append(list, unlocked.Entity)
 		}
-		list = append(list, unlockedBundle.Entity)
 	}
 	return list
 }
+
+// unlock unlocks skb, prompting if need be, unless it was unlocked
+// before.  It returns nil if it couldn't, so that other keys can be tried.
+func (s *ScanKeys) unlock(skb *libkb.SKB, kid keybase1.KID) *libkb.PGPKeyBundle {
+	if bundle, ok := s.unlocked[kid]; ok {
+		return bundle
+	}
+	unlocked, err := skb.PromptAndUnlock(nil, unlockReason, "", nil, s.secui, nil, s.me)
+	if err != nil {
+		s.G().Log.Warning("error unlocking key: %s", err)
+		return nil
+	}
+	bundle, ok := unlocked.(*libkb.PGPKeyBundle)
+	if !ok {
+		s.G().Log.Warning("could not convert unlocked key to PGPKeyBundle")
+		return nil
+	}
+	s.unlocked[kid] = bundle
+	return bundle
+}
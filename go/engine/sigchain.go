@@ -0,0 +1,103 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package engine
+
+import (
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// SigchainArg is the user whose sigchain to load, or empty for the
+// current user, and whether to check it again.
+type SigchainArg struct {
+	Username string
+	Verify   bool
+}
+
+// Sigchain is an engine that loads a user's whole sigchain, to show each
+// link, and optionally to check each link again and look up the chain's
+// tail in the Merkle tree.
+type Sigchain struct {
+	libkb.Contextified
+	arg SigchainArg
+	res keybase1.SigchainRes
+}
+
+// NewSigchain creates a Sigchain engine.
+func NewSigchain(g *libkb.GlobalContext, arg SigchainArg) *Sigchain {
+	return &Sigchain{
+		Contextified: libkb.NewContextified(g),
+		arg:          arg,
+	}
+}
+
+// Name is the unique engine name.
+func (e *Sigchain) Name() string {
+	return "Sigchain"
+}
+
+// GetPrereqs returns the engine prereqs.
+func (e *Sigchain) Prereqs() Prereqs {
+	return Prereqs{}
+}
+
+// RequiredUIs returns the required UIs.
+func (e *Sigchain) RequiredUIs() []libkb.UIKind {
+	return []libkb.UIKind{}
+}
+
+// SubConsumers returns the other UI consumers for this engine.
+func (e *Sigchain) SubConsumers() []libkb.UIConsumer {
+	return nil
+}
+
+// Run starts the engine.
+func (e *Sigchain) Run(ctx *Context) error {
+	arg := libkb.NewLoadUserArg(e.G())
+	arg.AllKeys = true
+	arg.ForceReload = e.arg.Verify
+	if len(e.arg.Username) > 0 {
+		arg.Name = e.arg.Username
+	} else {
+		arg.Self = true
+	}
+	user, err := libkb.LoadUser(arg)
+	if err != nil {
+		return err
+	}
+
+	e.res = keybase1.SigchainRes{
+		Uid:      user.GetUID(),
+		Username: user.GetName(),
+	}
+	checks := user.RecheckSigChain()
+	e.res.Verified = e.arg.Verify
+	for _, check := range checks {
+		e.res.Links = append(e.res.Links, check.Export(e.arg.Verify))
+		if !check.OK() {
+			e.G().Log.Debug("| Link %d failed its checks", check.Link.GetSeqno())
+			e.res.Verified = false
+		}
+	}
+	if !e.arg.Verify {
+		return nil
+	}
+
+	tail, err := user.CheckMerkleTail()
+	if tail != nil {
+		e.res.MerkleSeqno = int(tail.Seqno)
+		e.res.MerkleLinkID = tail.LinkID.String()
+	}
+	if err != nil {
+		e.G().Log.Debug("| Merkle tail check failed: %s", err)
+		e.res.MerkleError = err.Error()
+		e.res.Verified = false
+	}
+	return nil
+}
+
+// Result is the sigchain, and what checking it found.
+func (e *Sigchain) Result() keybase1.SigchainRes {
+	return e.res
+}
@@ -18,8 +18,9 @@ type TrackEngineArg struct {
 }
 
 type TrackEngine struct {
-	arg  *TrackEngineArg
-	them *libkb.User
+	arg     *TrackEngineArg
+	them    *libkb.User
+	outcome *libkb.IdentifyOutcome
 	libkb.Contextified
 }
 
@@ -64,8 +65,10 @@ func (e *TrackEngine) Run(ctx *Context) error {
 
 	token := ieng.TrackToken()
 	e.them = ieng.User()
+	e.outcome = ieng.Outcome()
 
 	// prompt if the identify is correct
+	ieng.reportAccountReset()
 	outcome := ieng.Outcome().Export()
 	confirmed, err := ctx.IdentifyUI.Confirm(outcome)
 	if err != nil {
@@ -87,3 +90,8 @@ func (e *TrackEngine) Run(ctx *Context) error {
 func (e *TrackEngine) User() *libkb.User {
 	return e.them
 }
+
+// Outcome is the result of identifying the user before tracking them.
+func (e *TrackEngine) Outcome() *libkb.IdentifyOutcome {
+	return e.outcome
+}
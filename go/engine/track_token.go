@@ -175,17 +175,23 @@ func (e *TrackToken) storeRemoteTrack(ctx *Context) (err error) {
 		return err
 	}
 
+	args := libkb.HTTPArgs{
+		"sig_id_base":  libkb.S{Val: sigid.ToString(false)},
+		"sig_id_short": libkb.S{Val: sigid.ToShortID()},
+		"sig":          libkb.S{Val: sig},
+		"uid":          libkb.UIDArg(e.them.GetUID()),
+		"type":         libkb.S{Val: "track"},
+		"signing_kid":  e.signingKeyPub.GetKID(),
+	}
+
+	if e.G().IsOffline() {
+		return e.queueRemoteTrack(sigid, args)
+	}
+
 	_, err = e.G().API.Post(libkb.APIArg{
 		Endpoint:    "follow",
 		NeedSession: true,
-		Args: libkb.HTTPArgs{
-			"sig_id_base":  libkb.S{Val: sigid.ToString(false)},
-			"sig_id_short": libkb.S{Val: sigid.ToShortID()},
-			"sig":          libkb.S{Val: sig},
-			"uid":          libkb.UIDArg(e.them.GetUID()),
-			"type":         libkb.S{Val: "track"},
-			"signing_kid":  e.signingKeyPub.GetKID(),
-		},
+		Args:        args,
 	})
 
 	if err != nil {
@@ -198,3 +204,17 @@ func (e *TrackToken) storeRemoteTrack(ctx *Context) (err error) {
 
 	return err
 }
+
+// queueRemoteTrack queues the signed tracking statement to be posted
+// once we're back online.  Until then, a local track stands in for it.
+func (e *TrackToken) queueRemoteTrack(sigid keybase1.SigID, args libkb.HTTPArgs) error {
+	op, err := libkb.NewOfflineOp("track", e.them, e.trackStatementBytes, sigid, args)
+	if err != nil {
+		return err
+	}
+	op.LocalTrack = true
+	if err := e.storeLocalTrack(); err != nil {
+		return err
+	}
+	return libkb.QueueOfflineOp(e.G(), e.arg.Me, op)
+}
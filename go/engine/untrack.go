@@ -195,17 +195,28 @@ func (e *UntrackEngine) storeRemoteUntrack(them *libkb.User, ctx *Context) (err
 		return
 	}
 
+	args := libkb.HTTPArgs{
+		"sig_id_base":  libkb.S{Val: sigid.ToString(false)},
+		"sig_id_short": libkb.S{Val: sigid.ToShortID()},
+		"sig":          libkb.S{Val: sig},
+		"uid":          libkb.UIDArg(them.GetUID()),
+		"type":         libkb.S{Val: "untrack"},
+		"signing_kid":  e.signingKeyPub.GetKID(),
+	}
+
+	if e.G().IsOffline() {
+		var op libkb.OfflineOp
+		if op, err = libkb.NewOfflineOp("untrack", them, e.untrackStatementBytes, sigid, args); err != nil {
+			return
+		}
+		err = libkb.QueueOfflineOp(e.G(), e.arg.Me, op)
+		return
+	}
+
 	_, err = e.G().API.Post(libkb.APIArg{
 		Endpoint:    "follow",
 		NeedSession: true,
-		Args: libkb.HTTPArgs{
-			"sig_id_base":  libkb.S{Val: sigid.ToString(false)},
-			"sig_id_short": libkb.S{Val: sigid.ToShortID()},
-			"sig":          libkb.S{Val: sig},
-			"uid":          libkb.UIDArg(them.GetUID()),
-			"type":         libkb.S{Val: "untrack"},
-			"signing_kid":  e.signingKeyPub.GetKID(),
-		},
+		Args:        args,
 	})
 
 	return
@@ -41,6 +41,9 @@ func main() {
 	}
 	if err != nil {
 		g.Log.Error(err.Error())
+		if ec, ok := err.(libkb.ExitCoder); ok {
+			os.Exit(ec.ExitCode())
+		}
 		os.Exit(2)
 	}
 }
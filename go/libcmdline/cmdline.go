@@ -106,6 +106,12 @@ func (p CommandLine) GetRunMode() (libkb.RunMode, error) {
 func (p CommandLine) GetPinentry() string {
 	return p.GetGString("pinentry")
 }
+func (p CommandLine) GetAccount() string {
+	return p.GetGString("account")
+}
+func (p CommandLine) GetOffline() (bool, bool) {
+	return p.GetBool("offline", true)
+}
 func (p CommandLine) GetGString(s string) string {
 	return p.ctx.GlobalString(s)
 }
@@ -279,6 +285,10 @@ func (p *CommandLine) PopulateApp(addHelp bool, extraFlags []cli.Flag) {
 			Name:  "api-uri-path-prefix",
 			Usage: "Specify an alternate API URI path prefix.",
 		},
+		cli.StringFlag{
+			Name:  "account",
+			Usage: "Run as this logged-in account rather than the active one.",
+		},
 		cli.StringFlag{
 			Name:  "pinentry",
 			Usage: "Specify a path to find a pinentry program.",
@@ -303,13 +313,17 @@ func (p *CommandLine) PopulateApp(addHelp bool, extraFlags []cli.Flag) {
 			Name:  "debug, d",
 			Usage: "Enable debugging mode.",
 		},
+		cli.BoolFlag{
+			Name:  "offline",
+			Usage: "Start the service in offline mode; queue signatures until it goes back online.",
+		},
 		cli.StringFlag{
 			Name:  "run-mode",
 			Usage: "Run mode (devel, staging, prod).", // These are defined in libkb/constants.go
 		},
 		cli.StringFlag{
 			Name:  "log-format",
-			Usage: "Log format (default, plain, file, fancy).",
+			Usage: "Log format (default, plain, file, fancy, json).",
 		},
 		cli.StringFlag{
 			Name:  "pgpdir, gpgdir",
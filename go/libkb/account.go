@@ -6,6 +6,7 @@ package libkb
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	keybase1 "github.com/keybase/client/go/protocol"
 	triplesec "github.com/keybase/go-triplesec"
@@ -20,6 +21,14 @@ type Account struct {
 	skbKeyring   *SKBKeyringFile
 	secSigKey    GenericKey // cached secret signing key
 	secEncKey    GenericKey // cache secret encryption key
+
+	// When the passphrase stream or a secret key was first cached since
+	// secrets were last locked, and when one was last used.
+	secretsCached time.Time
+	secretsUsed   time.Time
+
+	// Whether resuming the session tried to unlock the local db.
+	localDbUnlockTried bool
 }
 
 func NewAccount(g *GlobalContext) *Account {
@@ -50,11 +59,46 @@ func (a *Account) LoggedInAndProvisioined() bool {
 
 // LoggedInLoad will load and check the session with the api server if necessary.
 func (a *Account) LoggedInLoad() (bool, error) {
-	return a.LocalSession().loadAndCheck()
+	ok, err := a.LocalSession().loadAndCheck()
+	if ok && err == nil {
+		a.unlockLocalDb()
+	}
+	return ok, err
 }
 
 func (a *Account) LoggedInProvisionedLoad() (bool, error) {
-	return a.LocalSession().loadAndCheckProvisioned()
+	ok, err := a.LocalSession().loadAndCheckProvisioned()
+	if ok && err == nil {
+		a.unlockLocalDb()
+	}
+	return ok, err
+}
+
+// unlockLocalDb unlocks the local db when a saved session is resumed,
+// with the LKS secret in the secret store, if there's one there.
+// Otherwise it stays locked until the LKS secret is next loaded.
+func (a *Account) unlockLocalDb() {
+	if a.localDbUnlockTried || a.G().LocalDb == nil || !a.G().LocalDb.IsLocked() {
+		return
+	}
+	a.localDbUnlockTried = true
+	nu := a.LocalSession().GetUsername()
+	uid := a.LocalSession().GetUID()
+	if nu == nil || uid.IsNil() {
+		return
+	}
+	secretStore := NewSecretStore(*nu)
+	if secretStore == nil {
+		return
+	}
+	secret, err := secretStore.RetrieveSecret()
+	if err != nil {
+		a.G().Log.Debug("| No stored secret to unlock the local db: %s", err)
+		return
+	}
+	if err := a.G().LocalDb.Unlock(uid, secret); err != nil {
+		a.G().Log.Warning("Error unlocking the local db: %s", err)
+	}
 }
 
 func (a *Account) LoadLoginSession(emailOrUsername string) error {
@@ -108,6 +152,7 @@ func (a *Account) Logout() error {
 	a.UnloadLocalSession()
 	a.loginSession = nil
 	a.skbKeyring = nil
+	a.localDbUnlockTried = false
 
 	a.secretSyncer.Clear()
 	a.secretSyncer = NewSecretSyncer(a.G())
@@ -122,6 +167,7 @@ func (a *Account) CreateStreamCache(tsec *triplesec.Cipher, pps *PassphraseStrea
 		a.G().Log.Warning("Account.CreateStreamCache overwriting exisitng StreamCache")
 	}
 	a.streamCache = NewPassphraseStreamCache(tsec, pps)
+	a.noteSecretsUse()
 }
 
 // SetStreamGeneration sets the passphrase generation on the cached stream
@@ -162,6 +208,7 @@ func (a *Account) CreateStreamCacheViaStretch(passphrase string) error {
 	}
 
 	a.streamCache = NewPassphraseStreamCache(tsec, pps)
+	a.noteSecretsUse()
 
 	return nil
 }
@@ -170,6 +217,18 @@ func (a *Account) PassphraseStreamCache() *PassphraseStreamCache {
 	return a.streamCache
 }
 
+// UsePassphraseStreamCache is PassphraseStreamCache for unlocking a secret
+// key with the cached stream, which counts as using the cached secrets as
+// far as the idle timeout is concerned.  Callers that only look at the
+// cache should use PassphraseStreamCache, so that they don't keep the
+// secrets from locking.
+func (a *Account) UsePassphraseStreamCache() *PassphraseStreamCache {
+	if a.streamCache != nil {
+		a.noteSecretsUse()
+	}
+	return a.streamCache
+}
+
 // PassphraseStream returns a copy of the currently cached passphrase stream,
 // or nil if none is there.
 func (a *Account) PassphraseStream() *PassphraseStream {
@@ -404,12 +463,14 @@ func (a *Account) Dump() {
 func (a *Account) CachedSecretKey(ska SecretKeyArg) (GenericKey, error) {
 	if ska.KeyType == DeviceSigningKeyType {
 		if a.secSigKey != nil {
+			a.noteSecretsUse()
 			return a.secSigKey, nil
 		}
 		return nil, NotFoundError{}
 	}
 	if ska.KeyType == DeviceEncryptionKeyType {
 		if a.secEncKey != nil {
+			a.noteSecretsUse()
 			return a.secEncKey, nil
 		}
 		return nil, NotFoundError{}
@@ -424,11 +485,13 @@ func (a *Account) SetCachedSecretKey(ska SecretKeyArg, key GenericKey) error {
 	if ska.KeyType == DeviceSigningKeyType {
 		a.G().Log.Debug("caching secret key for %d", ska.KeyType)
 		a.secSigKey = key
+		a.noteSecretsUse()
 		return nil
 	}
 	if ska.KeyType == DeviceEncryptionKeyType {
 		a.G().Log.Debug("caching secret key for %d", ska.KeyType)
 		a.secEncKey = key
+		a.noteSecretsUse()
 		return nil
 	}
 	return fmt.Errorf("attempt to cache invalid key type: %d", ska.KeyType)
@@ -439,3 +502,41 @@ func (a *Account) ClearCachedSecretKeys() {
 	a.secSigKey = nil
 	a.secEncKey = nil
 }
+
+func (a *Account) noteSecretsUse() {
+	now := time.Now()
+	if a.secretsCached.IsZero() {
+		a.secretsCached = now
+	}
+	a.secretsUsed = now
+}
+
+// LockSecrets zeroes the cached passphrase stream and the unlocked secret
+// keys, without logging out.  Using a key afterwards takes the passphrase
+// again.
+func (a *Account) LockSecrets() {
+	a.ClearStreamCache()
+	scrubSecretKey(a.secSigKey)
+	scrubSecretKey(a.secEncKey)
+	a.ClearCachedSecretKeys()
+	// The keyring holds the keys that were unlocked from it.
+	if a.skbKeyring != nil {
+		a.skbKeyring.scrubUnlocked()
+	}
+	a.skbKeyring = nil
+	a.secretsCached = time.Time{}
+	a.secretsUsed = time.Time{}
+}
+
+// SecretsExpired is true if secrets are cached, and either haven't been
+// used for idle, or were cached longer than maxLifetime ago.  Zero
+// durations never expire.
+func (a *Account) SecretsExpired(now time.Time, idle, maxLifetime time.Duration) bool {
+	if a.secretsCached.IsZero() {
+		return false
+	}
+	if idle > 0 && now.Sub(a.secretsUsed) >= idle {
+		return true
+	}
+	return maxLifetime > 0 && now.Sub(a.secretsCached) >= maxLifetime
+}
@@ -0,0 +1,370 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/keybase/client/go/cache/favcache"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// sessionStashFilename is where the session file of a logged-in user who
+// isn't the active user is kept, so that calls made as them (see
+// ForAccount), and switching back to them, don't need a new login, even
+// after a restart.
+func sessionStashFilename(g *GlobalContext, nu NormalizedUsername) string {
+	fn := g.Env.GetSessionFilename()
+	ext := filepath.Ext(fn)
+	return strings.TrimSuffix(fn, ext) + "." + nu.String() + ext
+}
+
+func stashExists(fn string) bool {
+	exists, _ := FileExists(fn)
+	return exists
+}
+
+// loginStateHasSession is true if there's a session token for the
+// LoginState's user, whether or not it has been checked with the server.
+func loginStateHasSession(ls *LoginState) (ret bool, err error) {
+	err = ls.LocalSession(func(s *Session) {
+		ret = s.Load() == nil && s.HasSessionToken()
+	}, "loginStateHasSession")
+	return ret, err
+}
+
+// accountConfig reads the config as it would be if nu were the active
+// user.
+type accountConfig struct {
+	ConfigReader
+	nu NormalizedUsername
+}
+
+func (c accountConfig) GetUserConfig() (*UserConfig, error) {
+	return c.GetUserConfigForUsername(c.nu)
+}
+
+func (c accountConfig) GetUsername() NormalizedUsername { return c.nu }
+
+func (c accountConfig) GetUID() (ret keybase1.UID) {
+	if uc, _ := c.GetUserConfig(); uc != nil {
+		ret = uc.GetUID()
+	}
+	return ret
+}
+
+func (c accountConfig) GetSalt() []byte {
+	if uc, _ := c.GetUserConfig(); uc != nil {
+		return uc.GetSalt()
+	}
+	return nil
+}
+
+func (c accountConfig) GetDeviceID() (ret keybase1.DeviceID) {
+	if uc, _ := c.GetUserConfig(); uc != nil {
+		ret = uc.GetDeviceID()
+	}
+	return ret
+}
+
+// accountConfigWriter writes nu's user config without making them the
+// active user.
+type accountConfigWriter struct {
+	ConfigWriter
+	config accountConfig
+}
+
+func (w accountConfigWriter) SetUserConfig(u *UserConfig, overwrite bool) error {
+	if u == nil {
+		// Only the active user is cleared from the config.
+		return nil
+	}
+	return w.SetOtherUserConfig(u, overwrite)
+}
+
+func (w accountConfigWriter) SetDeviceID(did keybase1.DeviceID) error {
+	u, err := w.config.GetUserConfig()
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return NoUserConfigError{}
+	}
+	u.SetDevice(did)
+	return w.SetOtherUserConfig(u, true)
+}
+
+// setAccountConfig makes e read and write base's config as nu.
+func (e *Env) setAccountConfig(base *Env, nu NormalizedUsername) {
+	config, writer := base.GetConfig(), base.GetConfigWriter()
+
+	e.Lock()
+	defer e.Unlock()
+	ac := accountConfig{config, nu}
+	e.config = ac
+	e.writer = nil
+	if writer != nil {
+		e.writer = accountConfigWriter{writer, ac}
+	}
+}
+
+// forAccount makes the Env of nu's account context, which keeps its
+// session in sessionFile.
+func (e *Env) forAccount(nu NormalizedUsername, sessionFile string) *Env {
+	e.RLock()
+	ret := &Env{
+		cmd:         e.cmd,
+		homeFinder:  e.homeFinder,
+		Test:        e.Test,
+		sessionFile: sessionFile,
+	}
+	e.RUnlock()
+	ret.setAccountConfig(e, nu)
+	return ret
+}
+
+// root is the context that account contexts are made from.
+func (g *GlobalContext) root() *GlobalContext {
+	if g.base != nil {
+		return g.base
+	}
+	return g
+}
+
+// ForAccount returns the context that calls made as nu run in.  That's g
+// itself if nu is empty or is the active user.  Otherwise it's nu's own
+// context, with its own LoginState, caches, API session and views of the
+// config and local database, which shares everything else with g.  The
+// active user isn't changed, so calls made as other users don't see nu.
+func (g *GlobalContext) ForAccount(nu NormalizedUsername) (*GlobalContext, error) {
+	g = g.root()
+	if len(nu) == 0 {
+		return g, nil
+	}
+
+	g.accountsMu.Lock()
+	defer g.accountsMu.Unlock()
+
+	if g.Env.GetUsername().Eq(nu) {
+		return g, nil
+	}
+	if ag, ok := g.accounts[nu]; ok {
+		return ag, nil
+	}
+
+	uc, err := g.Env.GetConfig().GetUserConfigForUsername(nu)
+	if err != nil {
+		return nil, err
+	}
+	if uc == nil {
+		return nil, UserNotFoundError{msg: nu.String()}
+	}
+
+	ag, err := g.newAccountContext(nu)
+	if err != nil {
+		return nil, err
+	}
+	if g.accounts == nil {
+		g.accounts = make(map[NormalizedUsername]*GlobalContext)
+	}
+	g.accounts[nu] = ag
+	g.Log.Debug("| ForAccount: made a context for %s", nu)
+	return ag, nil
+}
+
+func (g *GlobalContext) newAccountContext(nu NormalizedUsername) (*GlobalContext, error) {
+	ag := &GlobalContext{
+		Log:               g.Log,
+		ResolveCache:      g.ResolveCache,
+		MerkleClient:      g.MerkleClient,
+		XAPI:              g.XAPI,
+		Output:            g.Output,
+		ProofCache:        g.ProofCache,
+		GpgClient:         g.GpgClient,
+		SocketInfo:        g.SocketInfo,
+		LoopbackListener:  g.LoopbackListener,
+		XStreams:          g.XStreams,
+		Timers:            g.Timers,
+		Stats:             g.Stats,
+		UI:                g.UI,
+		Service:           g.Service,
+		ConnectionManager: g.ConnectionManager,
+		NotifyRouter:      g.NotifyRouter,
+		UIRouter:          g.UIRouter,
+		base:              g,
+	}
+	ag.Env = g.Env.forAccount(nu, sessionStashFilename(g, nu))
+
+	api, err := NewInternalAPIEngine(ag)
+	if err != nil {
+		return nil, err
+	}
+	ag.API = api
+	ag.Keyrings = NewKeyrings(ag)
+	ag.IdentifyCache = NewIdentifyCache()
+	ag.UserCache = NewUserCache(g.Env.GetUserCacheMaxAge())
+	ag.FavoriteCache = favcache.New()
+	if g.LocalDb != nil {
+		ag.LocalDb = g.LocalDb.forAccount(ag)
+	}
+	ag.loginState = NewLoginState(ag)
+	return ag, nil
+}
+
+// AccountContexts returns g's root context and the account contexts made
+// from it so far.
+func (g *GlobalContext) AccountContexts() []*GlobalContext {
+	g = g.root()
+
+	g.accountsMu.Lock()
+	defer g.accountsMu.Unlock()
+
+	ret := []*GlobalContext{g}
+	for _, ag := range g.accounts {
+		ret = append(ret, ag)
+	}
+	return ret
+}
+
+// LockSecrets locks the cached secrets of every account.
+func (g *GlobalContext) LockSecrets() error {
+	epick := FirstErrorPicker{}
+	for _, ag := range g.AccountContexts() {
+		epick.Push(ag.LoginState().LockSecrets())
+	}
+	return epick.Error()
+}
+
+// shutdownAccount shuts down an account context's own parts.
+func (g *GlobalContext) shutdownAccount() error {
+	err := g.LoginState().Shutdown()
+	g.IdentifyCache.Shutdown()
+	g.UserCache.Shutdown()
+	return err
+}
+
+// shutdownAccounts is called on shutdown.  The stashed session files stay
+// on disk, so the sessions survive a restart.
+func (g *GlobalContext) shutdownAccounts() error {
+	g.accountsMu.Lock()
+	defer g.accountsMu.Unlock()
+
+	epick := FirstErrorPicker{}
+	for nu, ag := range g.accounts {
+		epick.Push(ag.shutdownAccount())
+		delete(g.accounts, nu)
+	}
+	return epick.Error()
+}
+
+// SwitchUser makes nu the active user.  Unlike logging in as someone
+// else, the previous user's session file is stashed rather than logged
+// out, so that their session is still there for calls made as them and
+// when switching back to them.  Cached secrets are dropped.
+func (g *GlobalContext) SwitchUser(nu NormalizedUsername) error {
+	g = g.root()
+
+	g.accountsMu.Lock()
+	defer g.accountsMu.Unlock()
+	g.loginStateMu.Lock()
+	defer g.loginStateMu.Unlock()
+
+	cur := g.Env.GetUsername()
+	if cur.Eq(nu) {
+		g.Log.Debug("| SwitchUser: already active as %s", nu)
+		return nil
+	}
+
+	g.Log.Debug("+ SwitchUser: %s -> %s", cur, nu)
+
+	// Whatever happens below, leave a fresh LoginState behind.
+	defer g.createLoginStateLocked()
+
+	// nu's session moves from their own context to the active one.
+	if ag, ok := g.accounts[nu]; ok {
+		delete(g.accounts, nu)
+		if err := ag.shutdownAccount(); err != nil {
+			g.Log.Debug("| SwitchUser: shutting down %s's context: %s", nu, err)
+		}
+	}
+
+	sessionFile := g.Env.GetSessionFilename()
+	if len(cur) > 0 && stashExists(sessionFile) {
+		if err := os.Rename(sessionFile, sessionStashFilename(g, cur)); err != nil {
+			return err
+		}
+		g.Log.Debug("| Stashed session file for %s", cur)
+	}
+
+	cw := g.Env.GetConfigWriter()
+	if cw == nil {
+		return NoConfigWriterError{}
+	}
+	if err := cw.SwitchUser(nu); err != nil {
+		if _, ok := err.(UserNotFoundError); !ok {
+			return err
+		}
+		// Never logged in as nu on this machine; leave no one active so
+		// that login can provision.
+		if err := cw.SetUserConfig(nil, false); err != nil {
+			return err
+		}
+	}
+
+	if stash := sessionStashFilename(g, nu); stashExists(stash) {
+		if err := os.Rename(stash, sessionFile); err != nil {
+			return err
+		}
+		g.Log.Debug("| Restored stashed session file for %s", nu)
+	}
+
+	if g.LocalDb != nil {
+		g.LocalDb.Lock()
+	}
+	if g.IdentifyCache != nil {
+		g.IdentifyCache.Shutdown()
+	}
+	if g.UserCache != nil {
+		g.UserCache.Shutdown()
+	}
+	g.IdentifyCache = NewIdentifyCache()
+	g.UserCache = NewUserCache(g.Env.GetUserCacheMaxAge())
+	g.FavoriteCache = favcache.New()
+
+	g.Log.Debug("- SwitchUser: now active as %s", nu)
+	return nil
+}
+
+// AccountSessions lists the configured users, and for each of them whether
+// we're holding a session, either for the active user or stashed.
+func (g *GlobalContext) AccountSessions() ([]keybase1.AccountSession, error) {
+	g = g.root()
+
+	current, others, err := g.Env.GetConfig().GetAllUsernames()
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []keybase1.AccountSession
+	if len(current) > 0 {
+		hasSession, err := loginStateHasSession(g.LoginState())
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, keybase1.AccountSession{
+			Username:   current.String(),
+			Active:     true,
+			HasSession: hasSession,
+		})
+	}
+	for _, nu := range others {
+		ret = append(ret, keybase1.AccountSession{
+			Username:   nu.String(),
+			HasSession: stashExists(sessionStashFilename(g, nu)),
+		})
+	}
+	return ret, nil
+}
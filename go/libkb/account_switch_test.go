@@ -0,0 +1,184 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// fakeLogin configures nu as the current user and writes a session file
+// for them, as a successful login would.
+func fakeLogin(t *testing.T, g *GlobalContext, nu NormalizedUsername, n int) {
+	uid := keybase1.UID(fmt.Sprintf("%030x19", n))
+	did := keybase1.DeviceID(fmt.Sprintf("%030x18", n))
+	if err := g.Env.GetConfigWriter().SetUserConfig(NewUserConfig(uid, nu, nil, did), true); err != nil {
+		t.Fatal(err)
+	}
+	session := fmt.Sprintf(`{"session":"token-%s","csrf":"csrf","device_provisioned":"%s","mtime":%d}`, nu, did, time.Now().Unix())
+	if err := MakeParentDirs(g.Env.GetSessionFilename()); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(g.Env.GetSessionFilename(), []byte(session), PermFile); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func findAccountSession(t *testing.T, g *GlobalContext, nu NormalizedUsername) keybase1.AccountSession {
+	accounts, err := g.AccountSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range accounts {
+		if a.Username == nu.String() {
+			return a
+		}
+	}
+	t.Fatalf("no account session for %s in %+v", nu, accounts)
+	return keybase1.AccountSession{}
+}
+
+func TestSwitchUserKeepsSessions(t *testing.T) {
+	tc := SetupTest(t, "switch_user")
+	defer tc.Cleanup()
+	g := tc.G
+
+	alice := NewNormalizedUsername("alice")
+	bob := NewNormalizedUsername("bob")
+
+	fakeLogin(t, g, bob, 2)
+	if err := g.SwitchUser(alice); err != nil {
+		t.Fatal(err)
+	}
+	fakeLogin(t, g, alice, 1)
+	g.createLoginState()
+
+	if a := findAccountSession(t, g, alice); !a.Active || !a.HasSession {
+		t.Fatalf("alice should be active with a session: %+v", a)
+	}
+
+	if err := g.SwitchUser(bob); err != nil {
+		t.Fatal(err)
+	}
+	if !g.Env.GetUsername().Eq(bob) {
+		t.Fatalf("active user is %s, wanted bob", g.Env.GetUsername())
+	}
+	if !stashExists(sessionStashFilename(g, alice)) {
+		t.Fatal("alice's session file wasn't stashed")
+	}
+	if a := findAccountSession(t, g, alice); a.Active || !a.HasSession {
+		t.Fatalf("alice should be inactive with a session: %+v", a)
+	}
+
+	if err := g.SwitchUser(alice); err != nil {
+		t.Fatal(err)
+	}
+	if stashExists(sessionStashFilename(g, alice)) {
+		t.Fatal("alice's stashed session file wasn't restored")
+	}
+	hasSession, err := loginStateHasSession(g.LoginState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasSession {
+		t.Fatal("alice's session is gone")
+	}
+}
+
+func TestForAccount(t *testing.T) {
+	tc := SetupTest(t, "for_account")
+	defer tc.Cleanup()
+	g := tc.G
+
+	alice := NewNormalizedUsername("alice")
+	bob := NewNormalizedUsername("bob")
+
+	fakeLogin(t, g, bob, 2)
+	if err := g.SwitchUser(alice); err != nil {
+		t.Fatal(err)
+	}
+	fakeLogin(t, g, alice, 1)
+	g.createLoginState()
+
+	for _, nu := range []NormalizedUsername{"", alice} {
+		if ag, err := g.ForAccount(nu); err != nil || ag != g {
+			t.Fatalf("ForAccount(%q) = %p, %v; wanted the active context", nu, ag, err)
+		}
+	}
+	if _, err := g.ForAccount("carol"); err == nil {
+		t.Fatal("made a context for an unknown user")
+	}
+
+	ag, err := g.ForAccount(bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ag == g {
+		t.Fatal("bob's context is the active one")
+	}
+	if again, err := g.ForAccount(bob); err != nil || again != ag {
+		t.Fatalf("ForAccount(bob) made a second context: %p, %v", again, err)
+	}
+	if again, err := ag.ForAccount(bob); err != nil || again != ag {
+		t.Fatalf("ForAccount(bob) on bob's context = %p, %v", again, err)
+	}
+
+	if u := ag.Env.GetUsername(); !u.Eq(bob) {
+		t.Fatalf("bob's context is for %s", u)
+	}
+	if fn := ag.Env.GetSessionFilename(); fn != sessionStashFilename(g, bob) {
+		t.Fatalf("bob's session file is %s", fn)
+	}
+	if ag.LoginState() == g.LoginState() {
+		t.Fatal("bob's context shares the active LoginState")
+	}
+	hasSession, err := loginStateHasSession(ag.LoginState())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hasSession {
+		t.Fatal("bob's context has no session")
+	}
+
+	// Writing bob's config leaves alice active.
+	did := keybase1.DeviceID(fmt.Sprintf("%030x18", 22))
+	if err := ag.Env.GetConfigWriter().SetDeviceID(did); err != nil {
+		t.Fatal(err)
+	}
+	if got := ag.Env.GetDeviceID(); got != did {
+		t.Fatalf("bob's device ID is %s, wanted %s", got, did)
+	}
+	if u := g.Env.GetUsername(); !u.Eq(alice) {
+		t.Fatalf("active user is %s, wanted alice", u)
+	}
+	if current, _, err := g.Env.GetConfig().GetAllUsernames(); err != nil || !current.Eq(alice) {
+		t.Fatalf("current user in config is %s (%v), wanted alice", current, err)
+	}
+	if got := g.Env.GetDeviceID(); got == did {
+		t.Fatal("alice's device ID changed")
+	}
+
+	if n := len(g.AccountContexts()); n != 2 {
+		t.Fatalf("%d account contexts, wanted 2", n)
+	}
+
+	// Switching to bob drops his context; his session moves to the active
+	// one.
+	if err := g.SwitchUser(bob); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(g.AccountContexts()); n != 1 {
+		t.Fatalf("%d account contexts after switching, wanted 1", n)
+	}
+	if hasSession, err = loginStateHasSession(g.LoginState()); err != nil {
+		t.Fatal(err)
+	}
+	if !hasSession {
+		t.Fatal("bob's session is gone")
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	triplesec "github.com/keybase/go-triplesec"
+)
+
+func TestAccountSecretsExpired(t *testing.T) {
+	tc := SetupTest(t, "account")
+	defer tc.Cleanup()
+
+	a := NewAccount(tc.G)
+	now := time.Now()
+	if a.SecretsExpired(now.Add(time.Hour), time.Minute, time.Minute) {
+		t.Error("expired with nothing cached")
+	}
+
+	a.CreateStreamCache(nil, NewPassphraseStream(make([]byte, extraLen)))
+	cached := a.secretsCached
+	if cached.IsZero() {
+		t.Fatal("caching the stream wasn't noted")
+	}
+	if a.SecretsExpired(cached.Add(time.Hour), 0, 0) {
+		t.Error("expired without timeouts")
+	}
+	if a.SecretsExpired(cached.Add(30*time.Second), time.Minute, time.Hour) {
+		t.Error("expired before the idle timeout")
+	}
+	if !a.SecretsExpired(cached.Add(2*time.Minute), time.Minute, time.Hour) {
+		t.Error("didn't expire after the idle timeout")
+	}
+
+	// Use keeps it from going idle, but not past its lifetime.
+	a.secretsUsed = cached.Add(50 * time.Minute)
+	if a.SecretsExpired(cached.Add(55*time.Minute), 10*time.Minute, time.Hour) {
+		t.Error("expired while in use")
+	}
+	if !a.SecretsExpired(cached.Add(61*time.Minute), 10*time.Minute, time.Hour) {
+		t.Error("didn't expire after its lifetime")
+	}
+}
+
+func TestAccountLockSecrets(t *testing.T) {
+	tc := SetupTest(t, "account")
+	defer tc.Cleanup()
+
+	tsec, err := triplesec.NewCipher([]byte("passphrase"), make([]byte, triplesec.SaltLen))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := make([]byte, extraLen)
+	for i := range stream {
+		stream[i] = 0xaa
+	}
+	a := NewAccount(tc.G)
+	a.CreateStreamCache(tsec, NewPassphraseStream(stream))
+	cached := a.PassphraseStreamRef()
+	sigKey, err := GenerateNaclSigningKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SetCachedSecretKey(SecretKeyArg{KeyType: DeviceSigningKeyType}, sigKey); err != nil {
+		t.Fatal(err)
+	}
+	encKey, err := GenerateNaclDHKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SetCachedSecretKey(SecretKeyArg{KeyType: DeviceEncryptionKeyType}, encKey); err != nil {
+		t.Fatal(err)
+	}
+	boxed, err := encKey.Encrypt([]byte("secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a.LockSecrets()
+
+	if a.PassphraseStreamCache().Valid() {
+		t.Error("stream cache still valid after locking")
+	}
+	if !bytes.Equal(cached.stream, make([]byte, extraLen)) {
+		t.Error("cached stream wasn't zeroed")
+	}
+	if !bytes.Equal(stream, bytes.Repeat([]byte{0xaa}, extraLen)) {
+		t.Error("caller's copy of the stream was changed")
+	}
+	if _, err := a.CachedSecretKey(SecretKeyArg{KeyType: DeviceSigningKeyType}); err == nil {
+		t.Error("secret key still cached after locking")
+	}
+	if *sigKey.Private != (NaclSigningKeyPrivate{}) {
+		t.Error("cached secret key wasn't zeroed")
+	}
+
+	// Copies of the keys held elsewhere can't be used with the zeroed
+	// secret halves.
+	if _, err := sigKey.Sign([]byte("msg")); err == nil {
+		t.Error("signed with a locked key")
+	} else if _, ok := err.(SecretKeyScrubbedError); !ok {
+		t.Errorf("signing with a locked key: %T %s, expected SecretKeyScrubbedError", err, err)
+	}
+	if _, _, err := encKey.Decrypt(boxed); err == nil {
+		t.Error("decrypted with a locked key")
+	} else if _, ok := err.(SecretKeyScrubbedError); !ok {
+		t.Errorf("decrypting with a locked key: %T %s, expected SecretKeyScrubbedError", err, err)
+	}
+	if a.SecretsExpired(time.Now().Add(time.Hour), time.Minute, time.Minute) {
+		t.Error("expired after locking")
+	}
+}
+
+func TestAccountSecretsUse(t *testing.T) {
+	tc := SetupTest(t, "account")
+	defer tc.Cleanup()
+
+	a := NewAccount(tc.G)
+	a.CreateStreamCache(nil, NewPassphraseStream(make([]byte, extraLen)))
+	used := a.secretsUsed.Add(-time.Hour)
+	a.secretsUsed = used
+
+	// Only looking at the cache doesn't count as using it.
+	a.PassphraseStreamCache()
+	a.GetStreamGeneration()
+	if !a.secretsUsed.Equal(used) {
+		t.Error("looking at the stream cache reset the idle timer")
+	}
+
+	a.UsePassphraseStreamCache()
+	if !a.secretsUsed.After(used) {
+		t.Error("using the stream cache didn't reset the idle timer")
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	keybase1 "github.com/keybase/client/go/protocol"
 	jsonw "github.com/keybase/go-jsonw"
 )
 
@@ -180,6 +181,11 @@ func (api *BaseAPIEngine) PreparePost(url url.URL, arg APIArg, sendJSON bool) (*
 func doRequestShared(api Requester, arg APIArg, req *http.Request, wantJSONRes bool) (
 	resp *http.Response, jw *jsonw.Wrapper, err error) {
 
+	if arg.G().IsOffline() {
+		err = OfflineError{}
+		return
+	}
+
 	if !arg.G().Env.GetTorMode().UseSession() && arg.NeedSession {
 		err = TorSessionRequiredError{}
 		return
@@ -201,8 +207,10 @@ func doRequestShared(api Requester, arg APIArg, req *http.Request, wantJSONRes b
 	}
 
 	timer := G.Timers.Start(timerType)
+	start := time.Now()
 	resp, err = cli.cli.Do(req)
 	timer.Report(req.Method + " " + arg.Endpoint)
+	recordAPIStats(api, req, arg, time.Since(start), err)
 
 	if err != nil {
 		return nil, nil, APINetError{err: err}
@@ -242,6 +250,16 @@ func doRequestShared(api Requester, arg APIArg, req *http.Request, wantJSONRes b
 	return resp, jw, nil
 }
 
+// recordAPIStats feeds the aggregated service stats.  External requests
+// are grouped by host, since their endpoints usually embed a username.
+func recordAPIStats(api Requester, req *http.Request, arg APIArg, dur time.Duration, err error) {
+	if api.isExternal() {
+		G.Stats.Record(keybase1.StatCategory_XAPI, req.Method+" "+req.URL.Host, dur, err)
+		return
+	}
+	G.Stats.Record(keybase1.StatCategory_API, req.Method+" "+arg.Endpoint, dur, err)
+}
+
 func checkHTTPStatus(arg APIArg, resp *http.Response) error {
 	var set []int
 	if arg.HTTPStatus == nil || len(arg.HTTPStatus) == 0 {
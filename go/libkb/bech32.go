@@ -0,0 +1,135 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bech32 is the address encoding of BIP 173, and Bech32m the variant of
+// BIP 350 used for segwit versions 1 and up.
+const (
+	bech32Const  = 1
+	bech32mConst = 0x2bc830a3
+)
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		b := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := uint(0); i < 5; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	ret := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		ret = append(ret, hrp[i]>>5)
+	}
+	ret = append(ret, 0)
+	for i := 0; i < len(hrp); i++ {
+		ret = append(ret, hrp[i]&31)
+	}
+	return ret
+}
+
+// DecodeBech32 decodes s, which can't be longer than maxLen, into its
+// human-readable part and 5-bit data, and returns which of the checksum
+// constants it was made with.
+func DecodeBech32(s string, maxLen int) (hrp string, data []byte, checksum uint32, err error) {
+	if len(s) > maxLen {
+		return "", nil, 0, fmt.Errorf("bech32 string is too long")
+	}
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, 0, fmt.Errorf("bech32 string is mixed case")
+	}
+	s = strings.ToLower(s)
+	sep := strings.LastIndex(s, "1")
+	if sep < 1 || sep+7 > len(s) {
+		return "", nil, 0, fmt.Errorf("bech32 string has a bad separator")
+	}
+	hrp = s[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, 0, fmt.Errorf("bech32 string has a bad character")
+		}
+	}
+	for i := sep + 1; i < len(s); i++ {
+		d := strings.IndexByte(bech32Charset, s[i])
+		if d < 0 {
+			return "", nil, 0, fmt.Errorf("bech32 string has a bad character %q", s[i])
+		}
+		data = append(data, byte(d))
+	}
+	checksum = bech32Polymod(append(bech32HRPExpand(hrp), data...))
+	if checksum != bech32Const && checksum != bech32mConst {
+		return "", nil, 0, fmt.Errorf("bech32 string has a bad checksum")
+	}
+	return hrp, data[:len(data)-6], checksum, nil
+}
+
+// convertBits regroups data from frombits-bit to tobits-bit groups.
+func convertBits(data []byte, frombits, tobits uint, pad bool) ([]byte, error) {
+	var ret []byte
+	acc, bits := uint32(0), uint(0)
+	maxv := uint32(1)<<tobits - 1
+	for _, v := range data {
+		if uint32(v)>>frombits != 0 {
+			return nil, fmt.Errorf("bad data value %d", v)
+		}
+		acc = acc<<frombits | uint32(v)
+		bits += frombits
+		for bits >= tobits {
+			bits -= tobits
+			ret = append(ret, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			ret = append(ret, byte(acc<<(tobits-bits)&maxv))
+		}
+	} else if bits >= frombits || acc<<(tobits-bits)&maxv != 0 {
+		return nil, fmt.Errorf("bad padding")
+	}
+	return ret, nil
+}
+
+// SegwitAddrCheck checks a segwit address with the human-readable part
+// hrp, and returns its witness version and program.
+func SegwitAddrCheck(s, hrp string) (version int, program []byte, err error) {
+	gotHRP, data, checksum, err := DecodeBech32(s, 90)
+	if err != nil {
+		return 0, nil, err
+	}
+	if gotHRP != hrp {
+		return 0, nil, fmt.Errorf("Address is for %q, not %q", gotHRP, hrp)
+	}
+	if len(data) < 1 || data[0] > 16 {
+		return 0, nil, fmt.Errorf("Bad witness version")
+	}
+	version = int(data[0])
+	if program, err = convertBits(data[1:], 5, 8, false); err != nil {
+		return 0, nil, err
+	}
+	if len(program) < 2 || len(program) > 40 {
+		return 0, nil, fmt.Errorf("Bad witness program length %d", len(program))
+	}
+	if version == 0 && len(program) != 20 && len(program) != 32 {
+		return 0, nil, fmt.Errorf("Bad version 0 witness program length %d", len(program))
+	}
+	if (version == 0) != (checksum == bech32Const) {
+		return 0, nil, fmt.Errorf("Wrong checksum for witness version %d", version)
+	}
+	return version, program, nil
+}
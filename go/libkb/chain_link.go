@@ -461,12 +461,19 @@ func (c *ChainLink) VerifyHash() error {
 	if c.hashVerified {
 		return nil
 	}
+	if err := c.checkHash(); err != nil {
+		return err
+	}
+	c.hashVerified = true
+	return nil
+}
 
+// checkHash is VerifyHash without the cached result.
+func (c *ChainLink) checkHash() error {
 	h := sha256.Sum256([]byte(c.unpacked.payloadJSONStr))
 	if !FastByteArrayEq(h[:], c.id) {
 		return fmt.Errorf("hash mismatch")
 	}
-	c.hashVerified = true
 	return nil
 }
 
@@ -486,7 +493,7 @@ func (c *ChainLink) VerifyPayload() error {
 		return nil
 	}
 
-	sigid, err := SigAssertPayload(c.unpacked.sig, c.getFixedPayload())
+	sigid, err := c.checkPayload()
 	if err != nil {
 		return err
 	}
@@ -496,6 +503,12 @@ func (c *ChainLink) VerifyPayload() error {
 	return nil
 }
 
+// checkPayload is VerifyPayload without the cached result: it checks
+// that the link's signature is over its payload, and returns the sig ID.
+func (c *ChainLink) checkPayload() (keybase1.SigID, error) {
+	return SigAssertPayload(c.unpacked.sig, c.getFixedPayload())
+}
+
 func (c *ChainLink) GetSeqno() Seqno {
 	if c.unpacked != nil {
 		return c.unpacked.seqno
@@ -304,22 +304,14 @@ func (f *JSONConfigFile) setUserConfigWithLock(u *UserConfig, overwrite bool) er
 		return f.flush()
 	}
 
-	parent := f.jw.AtKey("users")
 	un := u.GetUsername()
 	f.G().Log.Debug("| SetUserConfig(%s)", un)
-	if parent.IsNil() {
-		parent = jsonw.NewDictionary()
-		f.jw.SetKey("users", parent)
-		f.dirty = true
+	written, err := f.putUserConfigWithLock(u, overwrite)
+	if err != nil {
+		return err
 	}
-	if parent.AtKey(un.String()).IsNil() || overwrite {
-		uWrapper, err := jsonw.NewObjectWrapper(*u)
-		if err != nil {
-			return err
-		}
-		parent.SetKey(un.String(), uWrapper)
+	if written {
 		f.userConfigWrapper.userConfig = u
-		f.dirty = true
 	}
 
 	if !f.getCurrentUser().Eq(un) {
@@ -331,6 +323,46 @@ func (f *JSONConfigFile) setUserConfigWithLock(u *UserConfig, overwrite bool) er
 	return f.Write()
 }
 
+// SetOtherUserConfig writes this UserConfig to the config file like
+// SetUserConfig, but leaves `current_user` as it is.
+func (f *JSONConfigFile) SetOtherUserConfig(u *UserConfig, overwrite bool) error {
+	f.userConfigWrapper.Lock()
+	defer f.userConfigWrapper.Unlock()
+
+	un := u.GetUsername()
+	f.G().Log.Debug("| SetOtherUserConfig(%s)", un)
+	written, err := f.putUserConfigWithLock(u, overwrite)
+	if err != nil {
+		return err
+	}
+	if written && f.getCurrentUser().Eq(un) {
+		f.userConfigWrapper.userConfig = u
+	}
+	return f.Write()
+}
+
+// putUserConfigWithLock writes u to users.<username>, if it isn't there
+// yet or overwrite is set, and says whether it did.
+func (f *JSONConfigFile) putUserConfigWithLock(u *UserConfig, overwrite bool) (bool, error) {
+	parent := f.jw.AtKey("users")
+	un := u.GetUsername()
+	if parent.IsNil() {
+		parent = jsonw.NewDictionary()
+		f.jw.SetKey("users", parent)
+		f.dirty = true
+	}
+	if !parent.AtKey(un.String()).IsNil() && !overwrite {
+		return false, nil
+	}
+	uWrapper, err := jsonw.NewObjectWrapper(*u)
+	if err != nil {
+		return false, err
+	}
+	parent.SetKey(un.String(), uWrapper)
+	f.dirty = true
+	return true, nil
+}
+
 func (f *JSONConfigFile) DeleteAtPath(p string) {
 	f.jw.DeleteValueAtPath(p)
 	f.flush()
@@ -376,6 +408,16 @@ func (f JSONConfigFile) GetGpg() string {
 	res, _ := f.GetStringAtPath("gpg.command")
 	return res
 }
+func (f JSONConfigFile) GetPGPKeyserver() string {
+	res, _ := f.GetStringAtPath("pgp.keyserver")
+	return res
+}
+func (f JSONConfigFile) GetLocalDbEncryption() (ret LocalDbEncryption, err error) {
+	if s, isSet := f.GetStringAtPath("db_encryption"); isSet {
+		ret, err = StringToLocalDbEncryption(s)
+	}
+	return ret, err
+}
 func (f JSONConfigFile) GetLocalRPCDebug() string {
 	return f.GetTopLevelString("local_rpc_debug")
 }
@@ -451,6 +493,10 @@ func (f JSONConfigFile) GetTorProxy() string {
 	s, _ := f.GetStringAtPath("tor.proxy")
 	return s
 }
+func (f JSONConfigFile) GetTorDNSOverHTTPSURL() string {
+	s, _ := f.GetStringAtPath("tor.doh_url")
+	return s
+}
 
 func (f JSONConfigFile) GetProxy() string {
 	return f.GetTopLevelString("proxy")
@@ -493,10 +539,42 @@ func (f JSONConfigFile) GetProofCacheMediumDur() (time.Duration, bool) {
 	return f.GetDurationAtPath("cache.medium_duration.proofs")
 }
 
+func (f JSONConfigFile) GetLogRotateMaxSize() (int, bool) {
+	return f.GetIntAtPath("logging.rotate.max_size")
+}
+
+func (f JSONConfigFile) GetLogRotateMaxAge() (time.Duration, bool) {
+	return f.GetDurationAtPath("logging.rotate.max_age")
+}
+
+func (f JSONConfigFile) GetLogRotateKeep() (int, bool) {
+	return f.GetIntAtPath("logging.rotate.keep")
+}
+
+func (f JSONConfigFile) GetLogRotateCompress() (bool, bool) {
+	return f.GetBoolAtPath("logging.rotate.compress")
+}
+
+func (f JSONConfigFile) GetSecretCacheIdleTimeout() (time.Duration, bool) {
+	return f.GetDurationAtPath("secret_cache.idle_timeout")
+}
+
+func (f JSONConfigFile) GetSecretCacheMaxLifetime() (time.Duration, bool) {
+	return f.GetDurationAtPath("secret_cache.max_lifetime")
+}
+
 func (f JSONConfigFile) GetProofCacheShortDur() (time.Duration, bool) {
 	return f.GetDurationAtPath("cache.short_duration.proofs")
 }
 
+func (f JSONConfigFile) GetProofCacheServiceDur(service, result string) (time.Duration, bool) {
+	return f.GetDurationAtPath("cache.proofs." + service + "." + result)
+}
+
+func (f JSONConfigFile) GetProofCacheMaxStored() (int, bool) {
+	return f.GetIntAtPath("cache.limits.stored_proofs")
+}
+
 func (f JSONConfigFile) GetMerkleKIDs() []string {
 	if f.jw == nil {
 		return nil
@@ -22,6 +22,10 @@ type RunMode string
 
 var TorProxy = "localhost:9050"
 
+// TorDNSOverHTTPSURL is the JSON DNS-over-HTTPS resolver that DNS proofs
+// are checked with in Tor mode, through the Tor proxy.
+var TorDNSOverHTTPSURL = "https://dns.google/resolve"
+
 const (
 	DevelRunMode      RunMode = "devel"
 	StagingRunMode            = "staging"
@@ -70,8 +74,13 @@ const (
 	ProofCacheLongDur   = 6 * time.Hour
 	ProofCacheMediumDur = 30 * time.Minute
 	ProofCacheShortDur  = 1 * time.Minute
+	ProofCacheMaxStored = 0x4000
+
+	LogRotateKeep = 5
 
 	SigShortIDBytes = 27
+
+	DefaultPGPKeyserver = "hkps://keys.openpgp.org"
 )
 
 var MerkleProdKIDs = []string{
@@ -97,6 +106,9 @@ const (
 	AuthExpireIn      = OneYearInSeconds      // 1 year
 )
 
+// Warn about devices whose keys expire sooner than this.
+const DeviceExpiryWarning = 30 * 24 * time.Hour
+
 // Status codes.  This list should match keybase/lib/constants.iced.
 const (
 	SCOk                     = 0
@@ -137,6 +149,7 @@ const (
 	SCSelfNotFound           = 1703
 	SCBadKexPhrase           = 1704
 	SCNoUIDelegation         = 1705
+	SCSigchainRollback       = 1706
 )
 
 const (
@@ -259,6 +272,7 @@ const (
 	KIDPGPDsa              = 0x11
 	KIDPGPEcdh             = 0x12
 	KIDPGPEcdsa            = 0x13
+	KIDPGPEddsa            = 0x16
 	KIDNaclEddsa           = 0x20
 	KIDNaclDH              = 0x21
 )
@@ -320,6 +334,10 @@ const (
 	Kex2ScryptR       = 8
 	Kex2ScryptP       = 1
 	Kex2ScryptKeylen  = 32
+
+	// Kex2PayloadLifetime is how long a provisioning code is good for,
+	// which is as long as the kex2 session waits for the other device.
+	Kex2PayloadLifetime = 5 * time.Minute
 )
 
 const (
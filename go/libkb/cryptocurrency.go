@@ -0,0 +1,199 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/keybase/go-triplesec/sha3"
+)
+
+// CurrencyType is a kind of cryptocurrency address that users can claim
+// in their sigchains.
+type CurrencyType struct {
+	// Name is the type as it's written in cryptocurrency links.
+	Name string
+	// Symbol is the ticker symbol shown with addresses.
+	Symbol string
+	// check validates an address, and returns what it pays to.
+	check func(address string) ([]byte, error)
+}
+
+// CheckAddress validates address, and returns the public key hash,
+// script hash, or whatever else the address pays to.
+func (t CurrencyType) CheckAddress(address string) ([]byte, error) {
+	return t.check(address)
+}
+
+// currencyTypes are in the order addresses are tried in when no type is
+// given, so that addresses valid for more than one currency go to the
+// oldest of them.
+var currencyTypes = []CurrencyType{
+	{Name: "bitcoin", Symbol: "BTC", check: bitcoinAddrCheck},
+	{Name: "litecoin", Symbol: "LTC", check: litecoinAddrCheck},
+	{Name: "zcash", Symbol: "ZEC", check: zcashAddrCheck},
+	{Name: "ethereum", Symbol: "ETH", check: ethereumAddrCheck},
+}
+
+// GetCurrencyType returns the currency type with the given name, or nil.
+func GetCurrencyType(name string) *CurrencyType {
+	for i := range currencyTypes {
+		if currencyTypes[i].Name == name {
+			return &currencyTypes[i]
+		}
+	}
+	return nil
+}
+
+// ListCurrencyTypes returns the names of all the currency types.
+func ListCurrencyTypes() []string {
+	var ret []string
+	for _, t := range currencyTypes {
+		ret = append(ret, t.Name)
+	}
+	return ret
+}
+
+// CryptocurrencyAddrCheck checks address for the currency typ, or finds
+// which currency it's for if typ is empty.
+func CryptocurrencyAddrCheck(address, typ string) (*CurrencyType, []byte, error) {
+	if len(typ) > 0 {
+		t := GetCurrencyType(typ)
+		if t == nil {
+			return nil, nil, fmt.Errorf("Unknown cryptocurrency %q; expected one of: %s", typ, strings.Join(ListCurrencyTypes(), ", "))
+		}
+		pkhash, err := t.CheckAddress(address)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Bad %s address: %s", t.Name, err)
+		}
+		return t, pkhash, nil
+	}
+	for i := range currencyTypes {
+		if pkhash, err := currencyTypes[i].CheckAddress(address); err == nil {
+			return &currencyTypes[i], pkhash, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%q isn't an address for any of: %s", address, strings.Join(ListCurrencyTypes(), ", "))
+}
+
+// base58CheckDecode decodes a base58check string, with a version prefix
+// of versionLen bytes, and returns the version and payload.
+func base58CheckDecode(s string, versionLen int) (version []byte, payload []byte, err error) {
+	buf, err := Decode58(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	l := len(buf)
+	if l < versionLen+4 {
+		return nil, nil, fmt.Errorf("Address is truncated")
+	}
+	c1 := buf[l-4:]
+	tmp := sha256.Sum256(buf[:l-4])
+	tmp2 := sha256.Sum256(tmp[:])
+	if c2 := tmp2[0:4]; !FastByteArrayEq(c1, c2) {
+		return nil, nil, fmt.Errorf("Bad checksum: %v != %v", c1, c2)
+	}
+	return buf[:versionLen], buf[versionLen : l-4], nil
+}
+
+func bitcoinAddrCheck(s string) ([]byte, error) {
+	if strings.HasPrefix(strings.ToLower(s), "bc1") {
+		_, program, err := SegwitAddrCheck(s, "bc")
+		return program, err
+	}
+	_, pkhash, err := BtcAddrCheck(s, nil)
+	return pkhash, err
+}
+
+func litecoinAddrCheck(s string) ([]byte, error) {
+	if strings.HasPrefix(strings.ToLower(s), "ltc1") {
+		_, program, err := SegwitAddrCheck(s, "ltc")
+		return program, err
+	}
+	// P2PKH, and P2SH under both its old and new versions.
+	_, pkhash, err := BtcAddrCheck(s, &BtcOpts{versions: []int{48, 5, 50}})
+	return pkhash, err
+}
+
+// Zcash transparent addresses are base58check with two version bytes;
+// these are t1 (P2PKH) and t3 (P2SH).
+var zcashTransparentVersions = [][]byte{{0x1c, 0xb8}, {0x1c, 0xbd}}
+
+const zcashSaplingLen = 43
+
+func zcashAddrCheck(s string) ([]byte, error) {
+	if strings.HasPrefix(strings.ToLower(s), "zs1") {
+		hrp, data, checksum, err := DecodeBech32(s, 90)
+		if err != nil {
+			return nil, err
+		}
+		if hrp != "zs" || checksum != bech32Const {
+			return nil, fmt.Errorf("Not a Sapling address")
+		}
+		payload, err := convertBits(data, 5, 8, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(payload) != zcashSaplingLen {
+			return nil, fmt.Errorf("Bad Sapling address length %d", len(payload))
+		}
+		return payload, nil
+	}
+	version, payload, err := base58CheckDecode(s, 2)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) != 20 {
+		return nil, fmt.Errorf("Bad transparent address length %d", len(payload))
+	}
+	for _, v := range zcashTransparentVersions {
+		if bytes.Equal(version, v) {
+			return payload, nil
+		}
+	}
+	return nil, fmt.Errorf("Bad Zcash address version %x", version)
+}
+
+// ethereumAddrCheck checks the EIP-55 checksum of mixed-case addresses;
+// addresses in all one case don't have one.
+func ethereumAddrCheck(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") || len(s) != 42 {
+		return nil, fmt.Errorf("Ethereum addresses are 0x and 40 hex digits")
+	}
+	addr := s[2:]
+	payload, err := hex.DecodeString(addr)
+	if err != nil {
+		return nil, err
+	}
+	if addr == strings.ToLower(addr) || addr == strings.ToUpper(addr) {
+		return payload, nil
+	}
+	if want := EthereumChecksumAddress(payload); want != s {
+		return nil, fmt.Errorf("Bad EIP-55 checksum; expected %s", want)
+	}
+	return payload, nil
+}
+
+// EthereumChecksumAddress writes an address with its EIP-55 checksum.
+func EthereumChecksumAddress(payload []byte) string {
+	lower := hex.EncodeToString(payload)
+	h := sha3.NewKeccak256()
+	h.Write([]byte(lower))
+	sum := h.Sum(nil)
+	ret := []byte(lower)
+	for i, c := range ret {
+		nibble := sum[i/2] >> 4
+		if i%2 == 1 {
+			nibble = sum[i/2] & 0xf
+		}
+		if c >= 'a' && nibble >= 8 {
+			ret[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(ret)
+}
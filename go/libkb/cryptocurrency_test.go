@@ -0,0 +1,69 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestCryptocurrencyAddrCheck(t *testing.T) {
+	tests := []struct {
+		address string
+		typ     string
+		want    string // empty if the address is bad
+	}{
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", "", "bitcoin"},
+		{"bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", "", "bitcoin"},
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", "bitcoin", "bitcoin"},
+		// Taproot with a Bech32 rather than a Bech32m checksum.
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd", "bitcoin", ""},
+		// Mixed case.
+		{"bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmV3", "", ""},
+		{"LVg2kJoFNg45Nbpy53h7Fe1wKyeXVRhMH9", "", "litecoin"},
+		{"ltc1qg42tkwuuxefutzxezdkdel39gfstuap288mfea", "litecoin", "litecoin"},
+		// P2SH addresses with the old version are also bitcoin's.
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", "litecoin", "litecoin"},
+		{"LVg2kJoFNg45Nbpy53h7Fe1wKyeXVRhMH9", "bitcoin", ""},
+		{"t1Srf64tKGepAb6ddqPJRTMujv4ms9q7BMS", "", "zcash"},
+		{"t3Vz22vK5z2LcKEdg16Yv4FFneEL1zg9ojd", "zcash", "zcash"},
+		{"t13X46xb2ZUMHmfVYoxy6yEdxHZWvUZpiGM", "zcash", ""},
+		{"zs1q5e8kamwt7l4ac7h5kg9hlex323mt535rnrtd3udur3pwp29fhklaevdylp7duqmda6uug0sge4", "", "zcash"},
+		{"zs1q5e8kamwt7l4ac7h5kg9hlex323mt535rnrtd3udur3pwp29fhklaevdylp7duqmda6uug0sge5", "", ""},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "", "ethereum"},
+		{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "ethereum", "ethereum"},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", "ethereum", ""},
+		{"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", "", ""},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", "dogecoin", ""},
+		{"somejunk", "", ""},
+	}
+	for _, test := range tests {
+		ct, _, err := CryptocurrencyAddrCheck(test.address, test.typ)
+		switch {
+		case len(test.want) == 0 && err == nil:
+			t.Errorf("%s (%q) was accepted as %s", test.address, test.typ, ct.Name)
+		case len(test.want) > 0 && err != nil:
+			t.Errorf("%s (%q): %s", test.address, test.typ, err)
+		case len(test.want) > 0 && ct.Name != test.want:
+			t.Errorf("%s (%q) was %s, expected %s", test.address, test.typ, ct.Name, test.want)
+		}
+	}
+}
+
+func TestEthereumChecksumAddress(t *testing.T) {
+	for _, addr := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		payload, err := hex.DecodeString(addr[2:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := EthereumChecksumAddress(payload); got != addr {
+			t.Errorf("checksummed %s as %s", addr, got)
+		}
+	}
+}
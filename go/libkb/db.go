@@ -9,6 +9,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	keybase1 "github.com/keybase/client/go/protocol"
 	jsonw "github.com/keybase/go-jsonw"
@@ -45,14 +46,30 @@ func DbKeyParse(s string) (string, *DbKey, error) {
 	return v[0], &DbKey{ObjType(b), v[2]}, nil
 }
 
+// JSONLocalDb stores JSON values in the local database, encrypting those
+// that the configured LocalDbEncryption says to once it's unlocked.
 type JSONLocalDb struct {
+	Contextified
 	engine LocalDb
+	mode   LocalDbEncryption
+
+	cryptMu sync.RWMutex
+	key     *[32]byte
+	keyID   []byte
+	macKey  *[32]byte
+}
+
+func NewJSONLocalDb(g *GlobalContext, e LocalDb, mode LocalDbEncryption) *JSONLocalDb {
+	return &JSONLocalDb{
+		Contextified: NewContextified(g),
+		engine:       e,
+		mode:         mode,
+	}
 }
 
-func NewJSONLocalDb(e LocalDb) *JSONLocalDb { return &JSONLocalDb{e} }
-func (j *JSONLocalDb) Open() error          { return j.engine.Open() }
-func (j *JSONLocalDb) ForceOpen() error     { return j.engine.ForceOpen() }
-func (j *JSONLocalDb) Close() error         { return j.engine.Close() }
+func (j *JSONLocalDb) Open() error      { return j.engine.Open() }
+func (j *JSONLocalDb) ForceOpen() error { return j.engine.ForceOpen() }
+func (j *JSONLocalDb) Close() error     { return j.engine.Close() }
 func (j *JSONLocalDb) Nuke() (string, error) {
 	fn, err := j.engine.Nuke()
 	return fn, err
@@ -61,13 +78,13 @@ func (j *JSONLocalDb) Nuke() (string, error) {
 func (j *JSONLocalDb) Put(id DbKey, aliases []DbKey, val *jsonw.Wrapper) error {
 	bytes, err := val.Marshal()
 	if err == nil {
-		err = j.engine.Put(id, aliases, bytes)
+		err = j.put(id, aliases, bytes)
 	}
 	return err
 }
 
 func (j *JSONLocalDb) Get(id DbKey) (*jsonw.Wrapper, error) {
-	bytes, found, err := j.engine.Get(id)
+	bytes, found, err := j.get(id)
 	var ret *jsonw.Wrapper
 	if found {
 		ret, err = jsonw.Unmarshal(bytes)
@@ -77,7 +94,7 @@ func (j *JSONLocalDb) Get(id DbKey) (*jsonw.Wrapper, error) {
 
 func (j *JSONLocalDb) GetInto(obj interface{}, id DbKey) (found bool, err error) {
 	var buf []byte
-	buf, found, err = j.engine.Get(id)
+	buf, found, err = j.get(id)
 	if err == nil && found {
 		err = json.Unmarshal(buf, &obj)
 	}
@@ -88,13 +105,13 @@ func (j *JSONLocalDb) PutObj(id DbKey, aliases []DbKey, obj interface{}) (err er
 	var bytes []byte
 	bytes, err = json.Marshal(obj)
 	if err == nil {
-		err = j.engine.Put(id, aliases, bytes)
+		err = j.put(id, aliases, bytes)
 	}
 	return err
 }
 
 func (j *JSONLocalDb) Lookup(id DbKey) (*jsonw.Wrapper, error) {
-	bytes, found, err := j.engine.Lookup(id)
+	bytes, found, err := j.lookup(id)
 	var ret *jsonw.Wrapper
 	if found {
 		ret, err = jsonw.Unmarshal(bytes)
@@ -102,7 +119,24 @@ func (j *JSONLocalDb) Lookup(id DbKey) (*jsonw.Wrapper, error) {
 	return ret, err
 }
 
-func (j *JSONLocalDb) Delete(id DbKey) error { return j.engine.Delete(id) }
+func (j *JSONLocalDb) Delete(id DbKey) error { return j.delete(id) }
+
+// ForEach calls f with each value stored under typ, skipping those that
+// are encrypted and can't be decrypted.
+func (j *JSONLocalDb) ForEach(typ ObjType, f func(id DbKey, value []byte) error) error {
+	return j.engine.ForEach(typ, func(stored DbKey, raw []byte) error {
+		if id, value, ok := j.recoverValue(stored, raw); ok {
+			return f(id, value)
+		}
+		return nil
+	})
+}
+
+func (j *JSONLocalDb) Stats() ([]DbTableStats, error) { return j.engine.Stats() }
+func (j *JSONLocalDb) Compact() error                 { return j.engine.Compact() }
+func (j *JSONLocalDb) Evict(selects func(id DbKey) bool) (int, error) {
+	return j.evict(selects)
+}
 
 const (
 	DBUser                    = 0x00
@@ -116,6 +150,10 @@ const (
 	DBSigChainTailPublic      = 0xe7
 	DBSigChainTailSemiprivate = 0xe8
 	DBSigChainTailEncrypted   = 0xe9
+	DBOfflineQueue            = 0xea
+	DBExternalPGPKeys         = 0xeb
+	DBSigChainHistory         = 0xec
+	DBLocalDbCrypt            = 0xed
 	DBMerkleRoot              = 0xf0
 	DBTrackers                = 0xf1
 )
@@ -125,6 +163,86 @@ const (
 	DBLookupMerkleRoot = 0x01
 )
 
+// DbTableNames are the names that `keybase db` gives the tables of the
+// local database.
+var DbTableNames = map[ObjType]string{
+	DBUser:                    "user",
+	DBSig:                     "sig",
+	DBLink:                    "link",
+	DBLocalTrack:              "local-track",
+	DBPGPKey:                  "pgp-key",
+	DBSigHints:                "sig-hints",
+	DBProofCheck:              "proof-check",
+	DBUserSecretKeys:          "user-secret-keys",
+	DBSigChainTailPublic:      "sig-chain-tail-public",
+	DBSigChainTailSemiprivate: "sig-chain-tail-semiprivate",
+	DBSigChainTailEncrypted:   "sig-chain-tail-encrypted",
+	DBOfflineQueue:            "offline-queue",
+	DBExternalPGPKeys:         "external-pgp-keys",
+	DBSigChainHistory:         "sig-chain-history",
+	DBLocalDbCrypt:            "db-crypt",
+	DBMerkleRoot:              "merkle-root",
+	DBTrackers:                "trackers",
+}
+
+// DbLookupNames are the names of the alias tables.
+var DbLookupNames = map[ObjType]string{
+	DBLookupUsername:   "username",
+	DBLookupMerkleRoot: "merkle-root",
+}
+
+// DbUserTables are the tables whose values belong to one user, and are
+// keyed by their UID.  DBSigChainHistory isn't one of them, since it's
+// there so we don't forget what we've seen of a user's sigchain.
+var DbUserTables = []ObjType{
+	DBUser, DBLocalTrack, DBSigHints, DBUserSecretKeys, DBSigChainTailPublic,
+	DBSigChainTailSemiprivate, DBSigChainTailEncrypted, DBOfflineQueue, DBTrackers,
+}
+
+// DbTableFromName is the table that DbTableNames calls name.
+func DbTableFromName(name string) (ObjType, error) {
+	for typ, n := range DbTableNames {
+		if n == name {
+			return typ, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown table %q", name)
+}
+
+// DbKeyMentionsUID is whether a key in one of the DbUserTables belongs
+// to uid.  Local tracks are keyed by both the tracker and the trackee.
+func DbKeyMentionsUID(key string, uid keybase1.UID) bool {
+	s := uid.String()
+	return key == s || strings.HasPrefix(key, s+"-") || strings.HasSuffix(key, "-"+s)
+}
+
+// DbTableStats are the number of entries of one table and their size.
+type DbTableStats struct {
+	Typ ObjType
+	// Alias is set for the alias tables, which name values in the
+	// others.
+	Alias bool
+	// Unknown is set for the keys that aren't in any table.
+	Unknown bool
+	Entries int
+	Bytes   int64
+}
+
+// Name is the table's name in DbTableNames or DbLookupNames.
+func (s DbTableStats) Name() string {
+	if s.Unknown {
+		return "unknown"
+	}
+	names, prefix := DbTableNames, ""
+	if s.Alias {
+		names, prefix = DbLookupNames, "lookup:"
+	}
+	if name, ok := names[s.Typ]; ok {
+		return prefix + name
+	}
+	return fmt.Sprintf("%s%02x", prefix, byte(s.Typ))
+}
+
 func DbKeyUID(t ObjType, uid keybase1.UID) DbKey {
 	return DbKey{Typ: t, Key: uid.String()}
 }
@@ -0,0 +1,490 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// LocalDbEncryption is which tables of the local database are encrypted
+// at rest, under a key derived from the logged-in user's LKS secret.  The
+// values in those tables are encrypted, and the keys they're stored
+// under, such as the UIDs of tracked users, are replaced with an HMAC of
+// them, as are the aliases to them.
+type LocalDbEncryption int
+
+const (
+	LocalDbEncryptNone LocalDbEncryption = iota
+	// LocalDbEncryptPrivate encrypts everything but DbPublicTables, so
+	// users can still be identified from the cache while logged out.
+	// The cached users, sigchains and sigs are left as they are, so it
+	// doesn't hide who the user looked up and tracked; only
+	// LocalDbEncryptAll does.
+	LocalDbEncryptPrivate
+	LocalDbEncryptAll
+)
+
+func (m LocalDbEncryption) String() string {
+	switch m {
+	case LocalDbEncryptPrivate:
+		return "private"
+	case LocalDbEncryptAll:
+		return "all"
+	default:
+		return "none"
+	}
+}
+
+func StringToLocalDbEncryption(s string) (ret LocalDbEncryption, err error) {
+	switch s {
+	case "none":
+		ret = LocalDbEncryptNone
+	case "private":
+		ret = LocalDbEncryptPrivate
+	case "all":
+		ret = LocalDbEncryptAll
+	default:
+		err = fmt.Errorf("Unknown local db encryption: '%s'", s)
+	}
+	return ret, err
+}
+
+// DbPublicTables are the tables that only cache public data from the
+// server, and that identifying a user needs.  LocalDbEncryptPrivate
+// leaves them as they are.  Since they hold the users, sigchains and
+// sigs that were looked up, anyone who can read the local database can
+// still tell who the user looked up and tracked; LocalDbEncryptAll is
+// for hiding that too.
+var DbPublicTables = []ObjType{
+	DBUser, DBSig, DBLink, DBPGPKey, DBSigHints, DBProofCheck,
+	DBSigChainTailPublic, DBSigChainHistory, DBMerkleRoot,
+}
+
+// dbLockedTables are the tables whose values can't be fetched again from
+// the server, so writing them while the local database is locked fails,
+// rather than the value just not being cached.
+var dbLockedTables = []ObjType{DBLocalTrack, DBOfflineQueue}
+
+// dbLookupTables are the tables that each alias table points into.
+var dbLookupTables = map[ObjType]ObjType{
+	DBLookupUsername:   DBUser,
+	DBLookupMerkleRoot: DBMerkleRoot,
+}
+
+func dbTableIn(typ ObjType, tables []ObjType) bool {
+	for _, t := range tables {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// encrypts is whether values in the table typ are encrypted.  The record
+// of the mode the database was last migrated to never is, since it's
+// read before there's a key.
+func (m LocalDbEncryption) encrypts(typ ObjType) bool {
+	switch {
+	case typ == DBLocalDbCrypt:
+		return false
+	case m == LocalDbEncryptAll:
+		return true
+	case m == LocalDbEncryptPrivate:
+		return !dbTableIn(typ, DbPublicTables)
+	default:
+		return false
+	}
+}
+
+// encryptsAlias is whether aliases of the alias table typ are hidden,
+// which they are if the values they point to are encrypted.
+func (m LocalDbEncryption) encryptsAlias(typ ObjType) bool {
+	if t, ok := dbLookupTables[typ]; ok {
+		return m.encrypts(t)
+	}
+	return m != LocalDbEncryptNone
+}
+
+// An encrypted value is:
+//
+//	magic (1) | version (1) | key ID (8) | nonce (24) |
+//	secretbox(db key | 0 | value)
+//
+// The magic byte can't start a JSON value.  The db key is sealed with the
+// value so that values can't be swapped between keys.
+const (
+	dbCryptMagic     = 0x00
+	dbCryptVersion   = 1
+	dbCryptKeyIDLen  = 8
+	dbCryptHeaderLen = 2 + dbCryptKeyIDLen + 24
+)
+
+// localDbCryptStateKey is where the mode that uid's values were last
+// migrated to is kept.  Each user migrates their own values, with their
+// own key.
+func localDbCryptStateKey(uid keybase1.UID) DbKey {
+	return DbKeyUID(DBLocalDbCrypt, uid)
+}
+
+// localDbCryptState is the mode the database was last migrated to.
+type localDbCryptState struct {
+	Mode string `json:"mode"`
+}
+
+// dbKeyOwnedBy is whether the value stored for id is uid's: it's keyed
+// by uid in one of the DbUserTables, or it's one of uid's local tracks,
+// which are keyed by the tracker and then the trackee.
+func dbKeyOwnedBy(id DbKey, uid keybase1.UID) bool {
+	if id.Typ == DBLocalTrack {
+		return strings.HasPrefix(id.Key, uid.String()+"-")
+	}
+	return dbTableIn(id.Typ, DbUserTables) && id.Key == uid.String()
+}
+
+func isDbValueSealed(val []byte) bool {
+	return len(val) > 0 && val[0] == dbCryptMagic
+}
+
+// dbCryptKey derives a key for label from the LKS secret.
+func dbCryptKey(lksSecret []byte, label string) *[32]byte {
+	mac := hmac.New(sha256.New, lksSecret)
+	fmt.Fprintf(mac, "Keybase-Local-DB-%s-%d", label, dbCryptVersion)
+	var ret [32]byte
+	copy(ret[:], mac.Sum(nil))
+	return &ret
+}
+
+// Unlock derives the local database's keys from the LKS secret of uid,
+// the logged-in user, which stays the same across passphrase changes.
+// The first time uid unlocks it after the mode changes, it encrypts or
+// decrypts uid's values already stored to match.
+func (j *JSONLocalDb) Unlock(uid keybase1.UID, lksSecret []byte) error {
+	key := dbCryptKey(lksSecret, "Encryption")
+	macKey := dbCryptKey(lksSecret, "Keys")
+	id := sha256.Sum256(key[:])
+
+	j.cryptMu.Lock()
+	same := j.key != nil && *j.key == *key
+	j.key = key
+	j.keyID = id[:dbCryptKeyIDLen]
+	j.macKey = macKey
+	j.cryptMu.Unlock()
+
+	if same {
+		return nil
+	}
+	return j.migrate(uid)
+}
+
+// forAccount makes a view of the local database for g's account
+// context, which is locked and unlocked on its own.
+func (j *JSONLocalDb) forAccount(g *GlobalContext) *JSONLocalDb {
+	return NewJSONLocalDb(g, j.engine, j.mode)
+}
+
+// Lock forgets the local database's keys, on logout.  Encrypted values
+// read as not found until it's unlocked again.
+func (j *JSONLocalDb) Lock() {
+	j.cryptMu.Lock()
+	j.key = nil
+	j.keyID = nil
+	j.macKey = nil
+	j.cryptMu.Unlock()
+}
+
+// IsLocked is whether values that are encrypted can't be read or written.
+func (j *JSONLocalDb) IsLocked() bool {
+	j.cryptMu.RLock()
+	defer j.cryptMu.RUnlock()
+	return j.key == nil
+}
+
+func (j *JSONLocalDb) getKey() (*[32]byte, []byte) {
+	j.cryptMu.RLock()
+	defer j.cryptMu.RUnlock()
+	return j.key, j.keyID
+}
+
+// storedKey is the key that id is stored under in the engine, which for
+// a table that's encrypted is an HMAC of it, so that the keys don't say
+// whose values they are.  It returns false if that needs the db to be
+// unlocked, and it isn't.  table is "kv" for a value, or "lo" for an
+// alias.
+func (j *JSONLocalDb) storedKey(id DbKey, table string) (DbKey, bool) {
+	var hidden bool
+	if table == "lo" {
+		hidden = j.mode.encryptsAlias(id.Typ)
+	} else {
+		hidden = j.mode.encrypts(id.Typ)
+	}
+	if !hidden {
+		return id, true
+	}
+	return j.hiddenKey(id, table)
+}
+
+// hiddenKey is the HMAC of id that it's stored under if its table is
+// encrypted, or false if the db is locked.
+func (j *JSONLocalDb) hiddenKey(id DbKey, table string) (DbKey, bool) {
+	j.cryptMu.RLock()
+	macKey := j.macKey
+	j.cryptMu.RUnlock()
+	if macKey == nil {
+		return DbKey{}, false
+	}
+	mac := hmac.New(sha256.New, macKey[:])
+	mac.Write(id.ToBytes(table))
+	return DbKey{Typ: id.Typ, Key: hex.EncodeToString(mac.Sum(nil))}, true
+}
+
+func (j *JSONLocalDb) seal(id DbKey, val []byte, key *[32]byte, keyID []byte) ([]byte, error) {
+	nonce, err := RandBytes(24)
+	if err != nil {
+		return nil, err
+	}
+	var fnonce [24]byte
+	copy(fnonce[:], nonce)
+
+	var plain bytes.Buffer
+	plain.WriteString(id.ToString("kv"))
+	plain.WriteByte(0)
+	plain.Write(val)
+
+	var buf bytes.Buffer
+	buf.WriteByte(dbCryptMagic)
+	buf.WriteByte(dbCryptVersion)
+	buf.Write(keyID)
+	buf.Write(nonce)
+	buf.Write(secretbox.Seal(nil, plain.Bytes(), &fnonce, key))
+	return buf.Bytes(), nil
+}
+
+// unseal decrypts the encrypted value val, returning the key it was
+// sealed with, and false if it can't be decrypted.
+func (j *JSONLocalDb) unseal(val []byte) (string, []byte, bool) {
+	key, keyID := j.getKey()
+	if key == nil || len(val) < dbCryptHeaderLen+secretbox.Overhead || val[1] != dbCryptVersion ||
+		!FastByteArrayEq(val[2:2+dbCryptKeyIDLen], keyID) {
+		return "", nil, false
+	}
+	var fnonce [24]byte
+	copy(fnonce[:], val[2+dbCryptKeyIDLen:dbCryptHeaderLen])
+	plain, ok := secretbox.Open(nil, val[dbCryptHeaderLen:], &fnonce, key)
+	if !ok {
+		j.G().Log.Warning("Local db value failed to decrypt; ignoring it")
+		return "", nil, false
+	}
+	i := bytes.IndexByte(plain, 0)
+	if i < 0 {
+		return "", nil, false
+	}
+	return string(plain[:i]), plain[i+1:], true
+}
+
+// open returns the value stored as val, and false if it's encrypted and
+// can't be decrypted, which callers treat as not found.  id is the key
+// it was stored for, or nil if it was looked up by an alias.
+func (j *JSONLocalDb) open(id *DbKey, val []byte) ([]byte, bool) {
+	if !isDbValueSealed(val) {
+		return val, true
+	}
+	sealedFor, plain, ok := j.unseal(val)
+	if !ok {
+		return nil, false
+	}
+	if id != nil && sealedFor != id.ToString("kv") {
+		j.G().Log.Warning("Local db value was stored under another key; ignoring it")
+		return nil, false
+	}
+	return plain, true
+}
+
+// recoverValue returns the key and value that were stored as raw under
+// stored, for walking the engine's tables, where the keys of encrypted
+// values are HMACs.  It returns false if raw can't be decrypted.  Until
+// a change of mode is migrated, a value can be stored under either its
+// key or the HMAC of it.
+func (j *JSONLocalDb) recoverValue(stored DbKey, raw []byte) (DbKey, []byte, bool) {
+	if !isDbValueSealed(raw) {
+		return stored, raw, true
+	}
+	sealedFor, plain, ok := j.unseal(raw)
+	if !ok {
+		return stored, nil, false
+	}
+	table, id, err := DbKeyParse(sealedFor)
+	if err != nil || table != "kv" || id.Typ != stored.Typ {
+		j.G().Log.Warning("Local db value has a bad key; ignoring it")
+		return stored, nil, false
+	}
+	if hk, _ := j.hiddenKey(*id, "kv"); *id != stored && hk != stored {
+		j.G().Log.Warning("Local db value was stored under another key; ignoring it")
+		return stored, nil, false
+	}
+	return *id, plain, true
+}
+
+// put writes val for id.  While the db is locked, a value that would be
+// encrypted isn't written, and whatever was stored for it before, and the
+// aliases to it, are left as they are; it's only for tables that are
+// just a cache, and the rest fail with a LocalDbLockedError.
+func (j *JSONLocalDb) put(id DbKey, aliases []DbKey, val []byte) error {
+	stored, ok := j.storedKey(id, "kv")
+	var storedAliases []DbKey
+	for _, alias := range aliases {
+		sa, aok := j.storedKey(alias, "lo")
+		if !aok {
+			ok = false
+			break
+		}
+		storedAliases = append(storedAliases, sa)
+	}
+	if !j.mode.encrypts(id.Typ) && ok {
+		return j.engine.Put(stored, storedAliases, val)
+	}
+	key, keyID := j.getKey()
+	if key == nil || !ok {
+		if dbTableIn(id.Typ, dbLockedTables) {
+			return LocalDbLockedError{Table: DbTableNames[id.Typ]}
+		}
+		j.G().Log.Debug("| Local db is locked; not caching %s", id.ToString("kv"))
+		return nil
+	}
+	sealed, err := j.seal(id, val, key, keyID)
+	if err != nil {
+		return err
+	}
+	return j.engine.Put(stored, storedAliases, sealed)
+}
+
+func (j *JSONLocalDb) get(id DbKey) ([]byte, bool, error) {
+	stored, ok := j.storedKey(id, "kv")
+	if !ok {
+		return nil, false, nil
+	}
+	val, found, err := j.engine.Get(stored)
+	if err != nil || !found {
+		return val, found, err
+	}
+	val, found = j.open(&id, val)
+	return val, found, nil
+}
+
+func (j *JSONLocalDb) lookup(alias DbKey) ([]byte, bool, error) {
+	stored, ok := j.storedKey(alias, "lo")
+	if !ok {
+		return nil, false, nil
+	}
+	val, found, err := j.engine.Lookup(stored)
+	if err != nil || !found {
+		return val, found, err
+	}
+	val, found = j.open(nil, val)
+	return val, found, nil
+}
+
+// delete can't find an encrypted value while the db is locked, so it
+// fails then, rather than leaving the value behind.
+func (j *JSONLocalDb) delete(id DbKey) error {
+	stored, ok := j.storedKey(id, "kv")
+	if !ok {
+		return LocalDbLockedError{Table: DbTableNames[id.Typ]}
+	}
+	return j.engine.Delete(stored)
+}
+
+// evict deletes the values whose keys selects, and the aliases to them.
+// Values that can't be decrypted are offered with the key they're
+// stored under, so that they're evicted along with their table.
+func (j *JSONLocalDb) evict(selects func(id DbKey) bool) (int, error) {
+	evicted := make(map[DbKey]bool)
+	for typ := range DbTableNames {
+		err := j.engine.ForEach(typ, func(stored DbKey, raw []byte) error {
+			if id, _, _ := j.recoverValue(stored, raw); selects(id) {
+				evicted[stored] = true
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return j.engine.Evict(func(stored DbKey) bool { return evicted[stored] })
+}
+
+// migrate encrypts uid's stored values that the mode says should be,
+// and decrypts those it says shouldn't, if the mode changed since the
+// last time uid unlocked the db, moving them to the keys that they should
+// be stored under.  The aliases to values that move are dropped, to be
+// made again as they're fetched.  Values encrypted under another user's
+// key can't be decrypted, and are left for that user, as are the values
+// of other users that are still to be encrypted; values that aren't
+// anyone's are dropped instead, since they can be fetched again.
+func (j *JSONLocalDb) migrate(uid keybase1.UID) error {
+	var state localDbCryptState
+	stateKey := localDbCryptStateKey(uid)
+	if _, err := j.GetInto(&state, stateKey); err != nil {
+		return err
+	}
+	if state.Mode == j.mode.String() || (len(state.Mode) == 0 && j.mode == LocalDbEncryptNone) {
+		return nil
+	}
+
+	j.G().Log.Debug("+ Migrating local db of %s from encryption %q to %q", uid, state.Mode, j.mode)
+	moved := make(map[DbKey]bool)
+	n := 0
+	for typ := range DbTableNames {
+		encrypt := j.mode.encrypts(typ)
+		cache := !dbTableIn(typ, dbLockedTables)
+		var ids []DbKey
+		var vals [][]byte
+		err := j.engine.ForEach(typ, func(stored DbKey, raw []byte) error {
+			id, val, ok := j.recoverValue(stored, raw)
+			if !ok {
+				return nil
+			}
+			sk, _ := j.storedKey(id, "kv")
+			if isDbValueSealed(raw) == encrypt && sk == stored {
+				return nil
+			}
+			if !isDbValueSealed(raw) && !dbKeyOwnedBy(id, uid) {
+				if cache {
+					moved[stored] = true
+				}
+				return nil
+			}
+			if sk != stored {
+				moved[stored] = true
+			}
+			ids = append(ids, id)
+			vals = append(vals, append([]byte{}, val...))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if err := j.put(id, nil, vals[i]); err != nil {
+				return err
+			}
+		}
+		n += len(ids)
+	}
+	if len(moved) > 0 {
+		if _, err := j.engine.Evict(func(stored DbKey) bool { return moved[stored] }); err != nil {
+			return err
+		}
+	}
+	j.G().Log.Debug("- Migrated %d local db values", n)
+
+	return j.PutObj(stateKey, nil, localDbCryptState{Mode: j.mode.String()})
+}
@@ -0,0 +1,345 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"os"
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+func TestLocalDbEncryption(t *testing.T) {
+	tc := SetupTest(t, "db_crypt")
+	defer tc.Cleanup()
+	db := tc.G.LocalDb
+	db.mode = LocalDbEncryptPrivate
+
+	alice := UsernameToUID("t_alice")
+	public, private := DbKeyUID(DBUser, alice), DbKeyUID(DBTrackers, alice)
+	track := LocalTrackDBKey(alice, UsernameToUID("t_bob"))
+	raw := func(id DbKey) []byte {
+		stored, ok := db.storedKey(id, "kv")
+		if !ok {
+			t.Fatalf("%s: no key while locked", id.ToString("kv"))
+		}
+		val, found, err := db.engine.Get(stored)
+		if err != nil || !found {
+			t.Fatalf("%s: found=%v, err=%v", id.ToString("kv"), found, err)
+		}
+		return val
+	}
+	get := func(id DbKey) string {
+		var s string
+		if found, err := db.GetInto(&s, id); err != nil {
+			t.Fatal(err)
+		} else if !found {
+			return ""
+		}
+		return s
+	}
+
+	// What was cached before encryption was turned on is encrypted the
+	// first time the db is unlocked.
+	for _, id := range []DbKey{public, private, track} {
+		if err := db.engine.Put(id, nil, []byte(`"`+id.ToString("kv")+`"`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	if err := db.Unlock(alice, secret); err != nil {
+		t.Fatal(err)
+	}
+	if isDbValueSealed(raw(public)) {
+		t.Error("public value was encrypted")
+	}
+	for _, id := range []DbKey{private, track} {
+		if !isDbValueSealed(raw(id)) {
+			t.Errorf("%s wasn't encrypted", id.ToString("kv"))
+		}
+		// Nor is it under a key that says whose it is.
+		if _, found, _ := db.engine.Get(id); found {
+			t.Errorf("%s is still stored under its key", id.ToString("kv"))
+		}
+		if s := get(id); s != id.ToString("kv") {
+			t.Errorf("%s read as %q", id.ToString("kv"), s)
+		}
+	}
+
+	// A value moved to another key doesn't decrypt.
+	other := DbKeyUID(DBTrackers, UsernameToUID("t_bob"))
+	otherStored, _ := db.storedKey(other, "kv")
+	if err := db.engine.Put(otherStored, nil, raw(private)); err != nil {
+		t.Fatal(err)
+	}
+	if s := get(other); len(s) > 0 {
+		t.Errorf("moved value read as %q", s)
+	}
+
+	// Locked, encrypted values read as not found.  Writes to tables that
+	// are just a cache are dropped, leaving what was there, and the rest
+	// fail, as do deletes.
+	sealed := raw(private)
+	db.Lock()
+	if s := get(private); len(s) > 0 {
+		t.Errorf("read %q while locked", s)
+	}
+	if s := get(public); len(s) == 0 {
+		t.Error("couldn't read public value while locked")
+	}
+	if err := db.PutObj(private, nil, "new"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := db.engine.Get(private); found {
+		t.Error("kept a value written while locked")
+	}
+	if _, ok := db.PutObj(track, nil, "new").(LocalDbLockedError); !ok {
+		t.Error("wrote a local track while locked")
+	}
+	if _, ok := db.Delete(track).(LocalDbLockedError); !ok {
+		t.Error("deleted a local track while locked")
+	}
+	if err := db.Unlock(alice, secret); err != nil {
+		t.Fatal(err)
+	}
+	if v := raw(private); string(v) != string(sealed) {
+		t.Error("writing while locked changed the stored value")
+	}
+	db.Lock()
+
+	// Unlocking with another user's secret doesn't decrypt either.
+	if err := db.Unlock(UsernameToUID("t_bob"), []byte("fedcba9876543210fedcba9876543210")); err != nil {
+		t.Fatal(err)
+	}
+	if s := get(track); len(s) > 0 {
+		t.Errorf("read %q with another key", s)
+	}
+
+	// Turning encryption off decrypts the values again.
+	db.Lock()
+	db.mode = LocalDbEncryptNone
+	if err := db.Unlock(alice, secret); err != nil {
+		t.Fatal(err)
+	}
+	if v := raw(track); isDbValueSealed(v) {
+		t.Error("value wasn't decrypted")
+	}
+	if _, found, _ := db.engine.Get(track); !found {
+		t.Error("value wasn't moved back to its key")
+	}
+	db.Lock()
+	if s := get(track); s != track.ToString("kv") {
+		t.Errorf("read %q after decrypting", s)
+	}
+}
+
+func TestLocalDbEncryptAll(t *testing.T) {
+	tc := SetupTest(t, "db_crypt_all")
+	defer tc.Cleanup()
+	db := tc.G.LocalDb
+	db.mode = LocalDbEncryptAll
+
+	alice := UsernameToUID("t_alice")
+	user, root := DbKeyUID(DBUser, alice), DbKey{Typ: DBMerkleRoot, Key: "5"}
+	username := DbKey{Typ: DBLookupUsername, Key: "t_alice"}
+	head := DbKey{Typ: DBLookupMerkleRoot, Key: "HEAD"}
+
+	// Nothing is written, or looked up, while locked.
+	if err := db.PutObj(user, []DbKey{username}, "old"); err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := db.engine.Get(user); found {
+		t.Error("wrote a user while locked")
+	}
+
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	if err := db.Unlock(alice, secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PutObj(user, []DbKey{username}, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PutObj(root, []DbKey{head}, "root"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Public tables are encrypted too, and neither the keys nor the
+	// aliases are stored as they are.
+	for _, id := range []DbKey{user, root} {
+		if _, found, _ := db.engine.Get(id); found {
+			t.Errorf("%s is stored under its key", id.ToString("kv"))
+		}
+		stored, _ := db.storedKey(id, "kv")
+		if val, _, _ := db.engine.Get(stored); !isDbValueSealed(val) {
+			t.Errorf("%s wasn't encrypted", id.ToString("kv"))
+		}
+	}
+	for _, alias := range []DbKey{username, head} {
+		if _, found, _ := db.engine.Lookup(alias); found {
+			t.Errorf("%s is stored as it is", alias.ToString("lo"))
+		}
+	}
+	var s string
+	if jw, err := db.Lookup(username); err != nil || jw == nil {
+		t.Fatalf("lookup by username: %v, %v", jw, err)
+	} else if s, _ = jw.GetString(); s != "alice" {
+		t.Errorf("looked up %q", s)
+	}
+
+	// ForEach and Evict see the keys, not their HMACs.
+	var ids []DbKey
+	if err := db.ForEach(DBUser, func(id DbKey, _ []byte) error {
+		ids = append(ids, id)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != user {
+		t.Errorf("ForEach saw %v", ids)
+	}
+
+	// Locked, writes to the merkle root and sigchain history leave what
+	// was there, aliases included.
+	db.Lock()
+	if err := db.PutObj(root, []DbKey{head}, "newer"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.PutObj(DbKey{Typ: DBSigChainHistory, Key: "x"}, nil, "history"); err != nil {
+		t.Fatal(err)
+	}
+	if jw, _ := db.Lookup(head); jw != nil {
+		t.Error("looked up the merkle root while locked")
+	}
+	if err := db.Unlock(alice, secret); err != nil {
+		t.Fatal(err)
+	}
+	if jw, err := db.Lookup(head); err != nil || jw == nil {
+		t.Fatalf("merkle root after unlocking: %v, %v", jw, err)
+	} else if s, _ = jw.GetString(); s != "root" {
+		t.Errorf("merkle root is %q", s)
+	}
+
+	if n, err := db.Evict(func(id DbKey) bool { return id == user }); err != nil || n != 1 {
+		t.Errorf("evicted %d: %v", n, err)
+	}
+	if jw, _ := db.Lookup(username); jw != nil {
+		t.Error("the username alias outlived its user")
+	}
+
+	// Turning encryption off puts the values back under their keys.
+	db.Lock()
+	db.mode = LocalDbEncryptNone
+	if err := db.Unlock(alice, secret); err != nil {
+		t.Fatal(err)
+	}
+	db.Lock()
+	if found, err := db.GetInto(&s, root); err != nil || !found || s != "root" {
+		t.Errorf("merkle root after decrypting: %q, %v, %v", s, found, err)
+	}
+}
+
+func TestLocalDbEncryptionMisspelled(t *testing.T) {
+	tc := SetupTest(t, "db_crypt_env")
+	defer tc.Cleanup()
+
+	defer os.Setenv("KEYBASE_DB_ENCRYPTION", os.Getenv("KEYBASE_DB_ENCRYPTION"))
+	os.Setenv("KEYBASE_DB_ENCRYPTION", "privat")
+	if _, err := tc.G.Env.GetLocalDbEncryption(); err == nil {
+		t.Error("a misspelled mode wasn't an error")
+	}
+	if err := tc.G.ConfigureCaches(); err == nil {
+		t.Error("configured the caches with a misspelled mode")
+	}
+	os.Setenv("KEYBASE_DB_ENCRYPTION", "all")
+	if m, err := tc.G.Env.GetLocalDbEncryption(); err != nil || m != LocalDbEncryptAll {
+		t.Errorf("mode %s, %v", m, err)
+	}
+}
+
+// Each user's values are encrypted with their own key, when they next
+// unlock the db, and not with the key of whoever unlocks it first.
+func TestLocalDbEncryptionTwoUsers(t *testing.T) {
+	tc := SetupTest(t, "db_crypt_two")
+	defer tc.Cleanup()
+	db := tc.G.LocalDb
+	db.mode = LocalDbEncryptPrivate
+
+	alice, bob, charlie := UsernameToUID("t_alice"), UsernameToUID("t_bob"), UsernameToUID("t_charlie")
+	aliceTrack := LocalTrackDBKey(alice, charlie)
+	bobTrack := LocalTrackDBKey(bob, charlie)
+	bobQueue := DbKeyUID(DBOfflineQueue, bob)
+	charlieTrackers := DbKeyUID(DBTrackers, charlie)
+	for _, id := range []DbKey{aliceTrack, bobTrack, bobQueue, charlieTrackers} {
+		if err := db.engine.Put(id, nil, []byte(`"`+id.ToString("kv")+`"`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plain := func(id DbKey) bool {
+		val, found, err := db.engine.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return found && !isDbValueSealed(val)
+	}
+	get := func(id DbKey) string {
+		var s string
+		if _, err := db.GetInto(&s, id); err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	aliceSecret := []byte("0123456789abcdef0123456789abcdef")
+	if err := db.Unlock(alice, aliceSecret); err != nil {
+		t.Fatal(err)
+	}
+	if plain(aliceTrack) || get(aliceTrack) != aliceTrack.ToString("kv") {
+		t.Error("alice's local track wasn't encrypted for her")
+	}
+	// Bob's values are left for him, and charlie's, which are only a
+	// cache, are dropped.
+	for _, id := range []DbKey{bobTrack, bobQueue} {
+		if !plain(id) {
+			t.Errorf("%s was encrypted with alice's key", id.ToString("kv"))
+		}
+	}
+	if _, found, _ := db.engine.Get(charlieTrackers); found {
+		t.Error("charlie's trackers were kept unencrypted")
+	}
+	db.Lock()
+
+	bobSecret := []byte("fedcba9876543210fedcba9876543210")
+	if err := db.Unlock(bob, bobSecret); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []DbKey{bobTrack, bobQueue} {
+		if plain(id) {
+			t.Errorf("%s wasn't encrypted when bob unlocked", id.ToString("kv"))
+		}
+		if s := get(id); s != id.ToString("kv") {
+			t.Errorf("bob read %s as %q", id.ToString("kv"), s)
+		}
+	}
+	if s := get(aliceTrack); len(s) > 0 {
+		t.Errorf("bob read alice's local track as %q", s)
+	}
+	db.Lock()
+
+	// Each user's migration is recorded separately.
+	for _, uid := range []keybase1.UID{alice, bob} {
+		var state localDbCryptState
+		if found, err := db.GetInto(&state, localDbCryptStateKey(uid)); err != nil || !found || state.Mode != "private" {
+			t.Errorf("%s: migration state %+v, found=%v, err=%v", uid, state, found, err)
+		}
+	}
+	if err := db.Unlock(alice, aliceSecret); err != nil {
+		t.Fatal(err)
+	}
+	if s := get(aliceTrack); s != aliceTrack.ToString("kv") {
+		t.Errorf("alice read her local track as %q", s)
+	}
+	if s := get(bobTrack); len(s) > 0 {
+		t.Errorf("alice read bob's local track as %q", s)
+	}
+}
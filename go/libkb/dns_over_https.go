@@ -0,0 +1,102 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dnsTypeTXT is the TXT record type, as resolvers' JSON answers give it.
+const dnsTypeTXT = 16
+
+// dnsRcodeNXDomain is the response code for a name that doesn't exist.
+const dnsRcodeNXDomain = 3
+
+// DNSOverHTTPSError is a failed DNS-over-HTTPS lookup.
+type DNSOverHTTPSError struct {
+	Domain string
+	Rcode  int
+}
+
+func (e DNSOverHTTPSError) Error() string {
+	if e.Rcode == dnsRcodeNXDomain {
+		return fmt.Sprintf("no such host %s", e.Domain)
+	}
+	return fmt.Sprintf("DNS lookup of %s failed with rcode %d", e.Domain, e.Rcode)
+}
+
+// LookupTXTOverHTTPS looks up the TXT records of domain with the JSON
+// DNS-over-HTTPS resolver configured for Tor mode.  The request is an
+// ordinary external API call, so in Tor mode it goes through the Tor
+// proxy, and no DNS query leaves the machine.
+func LookupTXTOverHTTPS(g *GlobalContext, domain string) ([]string, error) {
+	res, err := g.XAPI.Get(APIArg{
+		Endpoint:    g.Env.GetTorDNSOverHTTPSURL(),
+		NeedSession: false,
+		Args: HTTPArgs{
+			"name": S{Val: domain},
+			"type": S{Val: "TXT"},
+		},
+		Contextified: NewContextified(g),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rcode, err := res.Body.AtKey("Status").GetInt()
+	if err != nil {
+		return nil, err
+	}
+	if rcode != 0 {
+		return nil, DNSOverHTTPSError{Domain: domain, Rcode: rcode}
+	}
+
+	answers := res.Body.AtKey("Answer")
+	if answers.IsNil() {
+		return nil, nil
+	}
+	n, err := answers.Len()
+	if err != nil {
+		return nil, err
+	}
+	var txt []string
+	for i := 0; i < n; i++ {
+		answer := answers.AtIndex(i)
+		if typ, err := answer.AtKey("type").GetInt(); err != nil || typ != dnsTypeTXT {
+			// CNAMEs on the way to the TXT records, for instance.
+			continue
+		}
+		data, err := answer.AtKey("data").GetString()
+		if err != nil {
+			return nil, err
+		}
+		txt = append(txt, joinTXTStrings(data))
+	}
+	return txt, nil
+}
+
+// joinTXTStrings turns the data of a TXT answer into the record's value.
+// Some resolvers give the character-strings of the record in quotes,
+// as in a zone file, and some give their bare concatenation.
+func joinTXTStrings(data string) string {
+	if !strings.HasPrefix(data, `"`) {
+		return data
+	}
+	var b []byte
+	quoted := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == '\\' && quoted && i+1 < len(data):
+			i++
+			b = append(b, data[i])
+		case quoted:
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
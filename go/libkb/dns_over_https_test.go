@@ -0,0 +1,202 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// socksStandIn is a SOCKS5 proxy for tests, which stands in for Tor and
+// records where it was asked to connect.
+type socksStandIn struct {
+	listener net.Listener
+	sync.Mutex
+	targets []string
+}
+
+func newSocksStandIn(t *testing.T) *socksStandIn {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &socksStandIn{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *socksStandIn) Addr() string { return s.listener.Addr().String() }
+func (s *socksStandIn) Close()       { s.listener.Close() }
+
+func (s *socksStandIn) Targets() []string {
+	s.Lock()
+	defer s.Unlock()
+	return append([]string{}, s.targets...)
+}
+
+func (s *socksStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+
+	// Method selection: only "no authentication".
+	greeting := make([]byte, 3)
+	if _, err := io.ReadFull(conn, greeting); err != nil || greeting[0] != 5 {
+		return
+	}
+	if _, err := conn.Write([]byte{5, 0}); err != nil {
+		return
+	}
+
+	// CONNECT to a domain name.
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(conn, head); err != nil || head[1] != 1 || head[3] != 3 {
+		return
+	}
+	rest := make([]byte, int(head[4])+2)
+	if _, err := io.ReadFull(conn, rest); err != nil {
+		return
+	}
+	host := string(rest[:len(rest)-2])
+	port := binary.BigEndian.Uint16(rest[len(rest)-2:])
+	target := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+
+	s.Lock()
+	s.targets = append(s.targets, target)
+	s.Unlock()
+
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+	if _, err := conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0}); err != nil {
+		return
+	}
+	go io.Copy(upstream, conn)
+	io.Copy(conn, upstream)
+}
+
+// newTestResolver is a JSON DNS-over-HTTPS resolver that knows the TXT
+// records in zone.
+func newTestResolver(t *testing.T, zone map[string][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, typ := r.URL.Query().Get("name"), r.URL.Query().Get("type")
+		if typ != "TXT" {
+			t.Errorf("asked for a %s record", typ)
+		}
+		records, ok := zone[name]
+		if !ok {
+			fmt.Fprintf(w, `{"Status": %d}`, dnsRcodeNXDomain)
+			return
+		}
+		answers := []string{fmt.Sprintf(`{"name": %q, "type": 5, "data": "cname.example."}`, name)}
+		for _, record := range records {
+			answers = append(answers, fmt.Sprintf(`{"name": %q, "type": %d, "data": %q}`, name, dnsTypeTXT, record))
+		}
+		fmt.Fprintf(w, `{"Status": 0, "Answer": [%s]}`, strings.Join(answers, ","))
+	}))
+}
+
+func setupTorStrict(t *testing.T, tc TestContext, proxy, resolver string) {
+	w := tc.G.Env.GetConfigWriter()
+	for path, value := range map[string]string{
+		"tor.mode":    "strict",
+		"tor.proxy":   proxy,
+		"tor.doh_url": resolver,
+	} {
+		if err := w.SetStringAtPath(path, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLookupTXTOverHTTPSThroughTor(t *testing.T) {
+	tc := SetupTest(t, "doh")
+	defer tc.Cleanup()
+
+	resolver := newTestResolver(t, map[string][]string{
+		"example.com": {"plain", `"split " "in two"`, `"with \"quotes\""`},
+	})
+	defer resolver.Close()
+	proxy := newSocksStandIn(t)
+	defer proxy.Close()
+	setupTorStrict(t, tc, proxy.Addr(), resolver.URL+"/resolve")
+
+	txt, err := LookupTXTOverHTTPS(tc.G, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"plain", "split in two", `with "quotes"`}
+	if len(txt) != len(expected) {
+		t.Fatalf("got TXT records %q, expected %q", txt, expected)
+	}
+	for i := range expected {
+		if txt[i] != expected[i] {
+			t.Errorf("TXT record %d: got %q, expected %q", i, txt[i], expected[i])
+		}
+	}
+
+	targets := proxy.Targets()
+	if len(targets) == 0 || targets[0] != strings.TrimPrefix(resolver.URL, "http://") {
+		t.Errorf("the lookup went through the proxy to %v, expected the resolver at %s", targets, resolver.URL)
+	}
+
+	_, err = LookupTXTOverHTTPS(tc.G, "nowhere.example.com")
+	if e, ok := err.(DNSOverHTTPSError); !ok || e.Rcode != dnsRcodeNXDomain {
+		t.Errorf("looking up a missing domain: got %v, expected NXDOMAIN", err)
+	}
+}
+
+func TestDNSCheckerOverTor(t *testing.T) {
+	tc := SetupTest(t, "doh")
+	defer tc.Cleanup()
+
+	resolver := newTestResolver(t, map[string][]string{
+		"_keybase.example.com": {"keybase-site-verification=abc"},
+	})
+	defer resolver.Close()
+	proxy := newSocksStandIn(t)
+	defer proxy.Close()
+	setupTorStrict(t, tc, proxy.Addr(), resolver.URL+"/resolve")
+
+	rc := &DNSChecker{}
+	if pe := rc.GetTorError(); pe != nil {
+		t.Errorf("DNS proofs can't be checked over Tor: %s", pe)
+	}
+	if pe := rc.CheckDomain("keybase-site-verification=abc", "_keybase.example.com"); pe != nil {
+		t.Errorf("proof not found over Tor: %s", pe)
+	}
+	if pe := rc.CheckDomain("keybase-site-verification=abc", "example.com"); pe == nil {
+		t.Error("found a proof at a domain that doesn't exist")
+	}
+	// The lookups can share a connection, but it has to be through
+	// the proxy.
+	targets := proxy.Targets()
+	if len(targets) == 0 || targets[0] != strings.TrimPrefix(resolver.URL, "http://") {
+		t.Errorf("the lookups went through the proxy to %v, expected the resolver at %s", targets, resolver.URL)
+	}
+}
+
+func TestTorModeChecksUnreliableProofs(t *testing.T) {
+	for mode, expected := range map[TorMode]bool{TorNone: true, TorLeaky: true, TorStrict: false} {
+		if mode.CheckUnreliableProofs() != expected {
+			t.Errorf("Tor mode %d: CheckUnreliableProofs() = %v", mode, !expected)
+		}
+	}
+}
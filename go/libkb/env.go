@@ -4,6 +4,8 @@
 package libkb
 
 import (
+	"fmt"
+	"github.com/keybase/client/go/logger"
 	keybase1 "github.com/keybase/client/go/protocol"
 	"os"
 	"path/filepath"
@@ -31,11 +33,16 @@ func (n NullConfiguration) GetProofCacheSize() (int, bool)                { retu
 func (n NullConfiguration) GetProofCacheLongDur() (time.Duration, bool)   { return 0, false }
 func (n NullConfiguration) GetProofCacheMediumDur() (time.Duration, bool) { return 0, false }
 func (n NullConfiguration) GetProofCacheShortDur() (time.Duration, bool)  { return 0, false }
+func (n NullConfiguration) GetLogRotateMaxSize() (int, bool)              { return 0, false }
+func (n NullConfiguration) GetLogRotateMaxAge() (time.Duration, bool)     { return 0, false }
+func (n NullConfiguration) GetLogRotateKeep() (int, bool)                 { return 0, false }
+func (n NullConfiguration) GetLogRotateCompress() (bool, bool)            { return false, false }
 func (n NullConfiguration) GetMerkleKIDs() []string                       { return nil }
 func (n NullConfiguration) GetPinentry() string                           { return "" }
 func (n NullConfiguration) GetUID() (ret keybase1.UID)                    { return }
 func (n NullConfiguration) GetGpg() string                                { return "" }
 func (n NullConfiguration) GetGpgOptions() []string                       { return nil }
+func (n NullConfiguration) GetPGPKeyserver() string                       { return "" }
 func (n NullConfiguration) GetPGPFingerprint() *PGPFingerprint            { return nil }
 func (n NullConfiguration) GetSecretKeyringTemplate() string              { return "" }
 func (n NullConfiguration) GetSalt() []byte                               { return nil }
@@ -56,6 +63,8 @@ func (n NullConfiguration) GetAPITimeout() (time.Duration, bool)          { retu
 func (n NullConfiguration) GetTorMode() (TorMode, error)                  { return TorNone, nil }
 func (n NullConfiguration) GetTorHiddenAddress() string                   { return "" }
 func (n NullConfiguration) GetTorProxy() string                           { return "" }
+func (n NullConfiguration) GetAccount() string                            { return "" }
+func (n NullConfiguration) GetOffline() (bool, bool)                      { return false, false }
 
 func (n NullConfiguration) GetUserConfig() (*UserConfig, error) { return nil, nil }
 func (n NullConfiguration) GetUserConfigForUsername(s NormalizedUsername) (*UserConfig, error) {
@@ -81,6 +90,9 @@ func (n NullConfiguration) GetAPIDump() (bool, bool) {
 func (n NullConfiguration) GetNoPinentry() (bool, bool) {
 	return false, false
 }
+func (n NullConfiguration) GetLocalDbEncryption() (LocalDbEncryption, error) {
+	return LocalDbEncryptNone, nil
+}
 
 func (n NullConfiguration) GetStringAtPath(string) (string, bool) {
 	return "", false
@@ -102,6 +114,26 @@ func (n NullConfiguration) GetSecurityAccessGroupOverride() (bool, bool) {
 	return false, false
 }
 
+func (n NullConfiguration) GetSecretCacheIdleTimeout() (time.Duration, bool) {
+	return 0, false
+}
+
+func (n NullConfiguration) GetSecretCacheMaxLifetime() (time.Duration, bool) {
+	return 0, false
+}
+
+func (n NullConfiguration) GetTorDNSOverHTTPSURL() string {
+	return ""
+}
+
+func (n NullConfiguration) GetProofCacheServiceDur(service, result string) (time.Duration, bool) {
+	return 0, false
+}
+
+func (n NullConfiguration) GetProofCacheMaxStored() (int, bool) {
+	return 0, false
+}
+
 type TestParameters struct {
 	ConfigFilename string
 	Home           string
@@ -126,6 +158,10 @@ type Env struct {
 	homeFinder HomeFinder
 	writer     ConfigWriter
 	Test       TestParameters
+
+	// For the Env of an account context (see GlobalContext.ForAccount),
+	// where that account's session is kept
+	sessionFile string
 }
 
 func (e *Env) GetConfig() ConfigReader {
@@ -333,6 +369,7 @@ func (e *Env) GetConfigFilename() string {
 
 func (e *Env) GetSessionFilename() string {
 	return e.GetString(
+		func() string { return e.sessionFile },
 		func() string { return e.cmd.GetSessionFilename() },
 		func() string { return os.Getenv("KEYBASE_SESSION_FILE") },
 		func() string { return e.config.GetSessionFilename() },
@@ -417,6 +454,25 @@ func (e *Env) GetAPIDump() bool {
 	)
 }
 
+// GetAccount is the account a client asks the service to run its calls
+// as, if it isn't the active one.
+func (e *Env) GetAccount() NormalizedUsername {
+	return NewNormalizedUsername(e.GetString(
+		func() string { return e.cmd.GetAccount() },
+		func() string { return os.Getenv("KEYBASE_ACCOUNT") },
+	))
+}
+
+// GetOffline is whether to start in offline mode, in which the service
+// works from local state and queues signatures instead of posting them.
+func (e *Env) GetOffline() bool {
+	return e.GetBool(false,
+		func() (bool, bool) { return e.cmd.GetOffline() },
+		func() (bool, bool) { return e.getEnvBool("KEYBASE_OFFLINE") },
+		func() (bool, bool) { return e.config.GetBoolAtPath("offline") },
+	)
+}
+
 func (e *Env) GetUsername() NormalizedUsername {
 	return e.config.GetUsername()
 }
@@ -561,6 +617,36 @@ func (e *Env) GetProofCacheShortDur() time.Duration {
 	)
 }
 
+// GetProofCachePolicy is how long cached results of checking proofs on
+// service stay fresh.  A "cache.proofs.<service>.success" or ".failure"
+// duration in the config overrides the built-in policy for service;
+// services without one use the long and medium durations.
+func (e *Env) GetProofCachePolicy(service string) ProofCachePolicy {
+	p, ok := ProofCachePolicies[service]
+	if !ok {
+		p = ProofCachePolicy{
+			Success: e.GetProofCacheLongDur(),
+			Failure: e.GetProofCacheMediumDur(),
+		}
+	}
+	if d, ok := e.config.GetProofCacheServiceDur(service, "success"); ok {
+		p.Success = d
+	}
+	if d, ok := e.config.GetProofCacheServiceDur(service, "failure"); ok {
+		p.Failure = d
+	}
+	return p
+}
+
+// GetProofCacheMaxStored is how many proof check results the local
+// database keeps; the oldest go first.
+func (e *Env) GetProofCacheMaxStored() int {
+	return e.GetInt(ProofCacheMaxStored,
+		func() (int, bool) { return e.getEnvInt("KEYBASE_PROOF_CACHE_MAX_STORED") },
+		e.config.GetProofCacheMaxStored,
+	)
+}
+
 func (e *Env) GetEmailOrUsername() string {
 	un := e.GetUsername().String()
 	if len(un) > 0 {
@@ -637,6 +723,34 @@ func (e *Env) GetGpg() string {
 	)
 }
 
+// GetPGPKeyserver is the HKP keyserver to look up PGP keys of people who
+// aren't on Keybase.
+func (e *Env) GetPGPKeyserver() string {
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_PGP_KEYSERVER") },
+		func() string { return e.config.GetPGPKeyserver() },
+		func() string { return DefaultPGPKeyserver },
+	)
+}
+
+// GetLocalDbEncryption is which values in the local database are
+// encrypted at rest.  A mode that's set but misspelled is an error,
+// rather than quietly encrypting nothing.
+func (e *Env) GetLocalDbEncryption() (LocalDbEncryption, error) {
+	if s := os.Getenv("KEYBASE_DB_ENCRYPTION"); len(s) > 0 {
+		m, err := StringToLocalDbEncryption(s)
+		if err != nil {
+			return LocalDbEncryptNone, fmt.Errorf("KEYBASE_DB_ENCRYPTION: %s", err)
+		}
+		return m, nil
+	}
+	m, err := e.config.GetLocalDbEncryption()
+	if err != nil {
+		return LocalDbEncryptNone, fmt.Errorf("db_encryption in config: %s", err)
+	}
+	return m, nil
+}
+
 func (e *Env) GetGpgOptions() []string {
 	return e.GetStringList(
 		func() []string { return e.Test.GPGOptions },
@@ -690,6 +804,47 @@ func (e *Env) GetSplitLogOutput() bool {
 	)
 }
 
+// GetSecretCacheIdleTimeout is how long the service keeps the passphrase
+// stream and unlocked secret keys without using them.  Zero is forever.
+func (e *Env) GetSecretCacheIdleTimeout() time.Duration {
+	return e.GetDuration(0,
+		func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_SECRET_CACHE_IDLE_TIMEOUT") },
+		e.config.GetSecretCacheIdleTimeout,
+	)
+}
+
+// GetSecretCacheMaxLifetime is how long the service keeps the passphrase
+// stream and unlocked secret keys at all, used or not.  Zero is forever.
+func (e *Env) GetSecretCacheMaxLifetime() time.Duration {
+	return e.GetDuration(0,
+		func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_SECRET_CACHE_MAX_LIFETIME") },
+		e.config.GetSecretCacheMaxLifetime,
+	)
+}
+
+// GetLogRotateOptions says when the service rotates its log file on its
+// own.  It doesn't unless a maximum size or age is configured.
+func (e *Env) GetLogRotateOptions() logger.RotateOptions {
+	return logger.RotateOptions{
+		MaxSize: int64(e.GetInt(0,
+			func() (int, bool) { return e.getEnvInt("KEYBASE_LOG_ROTATE_MAX_SIZE") },
+			e.config.GetLogRotateMaxSize,
+		)),
+		MaxAge: e.GetDuration(0,
+			func() (time.Duration, bool) { return e.getEnvDuration("KEYBASE_LOG_ROTATE_MAX_AGE") },
+			e.config.GetLogRotateMaxAge,
+		),
+		Keep: e.GetInt(LogRotateKeep,
+			func() (int, bool) { return e.getEnvInt("KEYBASE_LOG_ROTATE_KEEP") },
+			e.config.GetLogRotateKeep,
+		),
+		Compress: e.GetBool(false,
+			func() (bool, bool) { return e.getEnvBool("KEYBASE_LOG_ROTATE_COMPRESS") },
+			e.config.GetLogRotateCompress,
+		),
+	}
+}
+
 func (e *Env) GetLogFile() string {
 	return e.GetString(
 		func() string { return e.cmd.GetLogFile() },
@@ -733,6 +888,14 @@ func (e *Env) GetTorProxy() string {
 	)
 }
 
+func (e *Env) GetTorDNSOverHTTPSURL() string {
+	return e.GetString(
+		func() string { return os.Getenv("KEYBASE_TOR_DOH_URL") },
+		func() string { return e.config.GetTorDNSOverHTTPSURL() },
+		func() string { return TorDNSOverHTTPSURL },
+	)
+}
+
 func (e *Env) GetStoredSecretAccessGroup() string {
 	var override = e.GetBool(
 		false,
@@ -59,11 +59,6 @@ type ProofAPIError struct {
 	url string
 }
 
-var ProofErrorDNSOverTor = &ProofErrorImpl{
-	Status: keybase1.ProofStatus_TOR_SKIPPED,
-	Desc:   "DNS proofs aren't reliable over Tor",
-}
-
 var ProofErrorHTTPOverTor = &ProofErrorImpl{
 	Status: keybase1.ProofStatus_TOR_SKIPPED,
 	Desc:   "HTTP proofs aren't reliable over Tor",
@@ -75,6 +70,14 @@ func (t TorSessionRequiredError) Error() string {
 	return "We can't send out PII in Tor-Strict mode; but it's needed for this operation"
 }
 
+// OfflineError is returned instead of contacting the server while in
+// offline mode.
+type OfflineError struct{}
+
+func (e OfflineError) Error() string {
+	return "This needs the Keybase server, but we're in offline mode"
+}
+
 func NewProofAPIError(s keybase1.ProofStatus, u string, d string, a ...interface{}) *ProofAPIError {
 	base := NewProofError(s, d, a...)
 	return &ProofAPIError{*base, u}
@@ -276,6 +279,16 @@ func (u NoSecretKeyError) Error() string {
 
 //=============================================================================
 
+// SecretKeyScrubbedError is for a secret key that was zeroed when secrets
+// were locked, while it was still in use.
+type SecretKeyScrubbedError struct{}
+
+func (e SecretKeyScrubbedError) Error() string {
+	return "Secret key was locked while in use; try again to unlock it"
+}
+
+//=============================================================================
+
 type NoPaperKeysError struct {
 }
 
@@ -756,6 +769,23 @@ func (e ProofNotFoundForUsernameError) Error() string {
 	return fmt.Sprintf("proof not found for %q on %q", e.Username, e.Service)
 }
 
+// ProofExistsError is raised when a proof made without prompting would
+// supersede one that's already there, and it wasn't forced.
+type ProofExistsError struct {
+	Account string
+}
+
+func (e ProofExistsError) Error() string {
+	return fmt.Sprintf("there's already a proof for %s; force it to supersede that one", e.Account)
+}
+
+// ExitCoder is an error that sets the exit code of the command that
+// fails with it.
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
 //=============================================================================
 
 type KeyGenError struct {
@@ -1024,6 +1054,21 @@ func (e PGPNoDecryptionKeyError) Error() string {
 
 //=============================================================================
 
+// PGPUnknownSignerError is for signatures by keys that don't belong to
+// any Keybase user, which can't be checked.
+type PGPUnknownSignerError struct {
+	KeyID uint64 // 0 if unknown
+}
+
+func (e PGPUnknownSignerError) Error() string {
+	if e.KeyID == 0 {
+		return "Signed by an unknown PGP key, which doesn't belong to any Keybase user"
+	}
+	return fmt.Sprintf("Signed by unknown PGP key %016X, which doesn't belong to any Keybase user", e.KeyID)
+}
+
+//=============================================================================
+
 type DecryptionError struct{}
 
 func (e DecryptionError) Error() string {
@@ -1174,3 +1219,54 @@ type UIDelegationUnavailableError struct{}
 func (e UIDelegationUnavailableError) Error() string {
 	return "This process does not support UI delegation"
 }
+
+//=============================================================================
+
+// Kex2PayloadError is for a provisioning code, scanned or typed from the
+// other device, that can't be used.
+type Kex2PayloadError struct {
+	Msg string
+}
+
+func (e Kex2PayloadError) Error() string {
+	return fmt.Sprintf("Bad code from the other device: %s", e.Msg)
+}
+
+//=============================================================================
+
+// SigchainRollbackError is for a sigchain that the server presented as
+// shorter than, or different from, one we've seen before.
+type SigchainRollbackError struct {
+	UID      keybase1.UID
+	Username string
+	Msg      string
+}
+
+func (e SigchainRollbackError) Error() string {
+	return fmt.Sprintf("Possible sigchain rollback for %s: %s", e.Username, e.Msg)
+}
+
+//=============================================================================
+
+// TrackedUserResetError is for a tracked user who reset their account
+// since they were tracked, and who has to be tracked again before their
+// new keys are used.
+type TrackedUserResetError struct {
+	Username string
+}
+
+func (e TrackedUserResetError) Error() string {
+	return fmt.Sprintf("%s reset their account since you tracked them; run `keybase track %s` to check and track the new account first", e.Username, e.Username)
+}
+
+//=============================================================================
+
+// LocalDbLockedError is for a value that has to be encrypted in the local
+// database, and can't be fetched again, written while no one is logged in.
+type LocalDbLockedError struct {
+	Table string
+}
+
+func (e LocalDbLockedError) Error() string {
+	return fmt.Sprintf("Can't write %s to the local db while it's locked; log in first", e.Table)
+}
@@ -31,32 +31,46 @@ import (
 type ShutdownHook func() error
 
 type GlobalContext struct {
-	Log               logger.Logger   // Handles all logging
-	Env               *Env            // Env variables, cmdline args & config
-	Keyrings          *Keyrings       // Gpg Keychains holding keys
-	API               API             // How to make a REST call to the server
-	ResolveCache      *ResolveCache   // cache of resolve results
-	LocalDb           *JSONLocalDb    // Local DB for cache
-	MerkleClient      *MerkleClient   // client for querying server's merkle sig tree
-	XAPI              ExternalAPI     // for contacting Twitter, Github, etc.
-	Output            io.Writer       // where 'Stdout'-style output goes
-	ProofCache        *ProofCache     // where to cache proof results
-	FavoriteCache     *favcache.Cache // where to cache favorite folders
-	GpgClient         *GpgCLI         // A standard GPG-client (optional)
-	ShutdownHooks     []ShutdownHook  // on shutdown, fire these...
-	SocketInfo        Socket          // which socket to bind/connect to
-	socketWrapperMu   sync.RWMutex
-	SocketWrapper     *SocketWrapper     // only need one connection per
-	LoopbackListener  *LoopbackListener  // If we're in loopback mode, we'll connect through here
-	XStreams          *ExportedStreams   // a table of streams we've exported to the daemon (or vice-versa)
-	Timers            *TimerSet          // Which timers are currently configured on
-	IdentifyCache     *IdentifyCache     // cache of IdentifyOutcomes
-	UserCache         *UserCache         // cache of Users
-	UI                UI                 // Interact with the UI
-	Service           bool               // whether we're in server mode
-	shutdownOnce      sync.Once          // whether we've shut down or not
-	loginStateMu      sync.RWMutex       // protects loginState pointer, which gets destroyed on logout
-	loginState        *LoginState        // What phase of login the user's in
+	Log              logger.Logger   // Handles all logging
+	Env              *Env            // Env variables, cmdline args & config
+	Keyrings         *Keyrings       // Gpg Keychains holding keys
+	API              API             // How to make a REST call to the server
+	ResolveCache     *ResolveCache   // cache of resolve results
+	LocalDb          *JSONLocalDb    // Local DB for cache
+	MerkleClient     *MerkleClient   // client for querying server's merkle sig tree
+	XAPI             ExternalAPI     // for contacting Twitter, Github, etc.
+	Output           io.Writer       // where 'Stdout'-style output goes
+	ProofCache       *ProofCache     // where to cache proof results
+	FavoriteCache    *favcache.Cache // where to cache favorite folders
+	GpgClient        *GpgCLI         // A standard GPG-client (optional)
+	ShutdownHooks    []ShutdownHook  // on shutdown, fire these...
+	SocketInfo       Socket          // which socket to bind/connect to
+	socketWrapperMu  sync.RWMutex
+	SocketWrapper    *SocketWrapper    // only need one connection per
+	LoopbackListener *LoopbackListener // If we're in loopback mode, we'll connect through here
+	XStreams         *ExportedStreams  // a table of streams we've exported to the daemon (or vice-versa)
+	Timers           *TimerSet         // Which timers are currently configured on
+	Stats            *StatsRegistry    // Aggregated timings of API calls, engines, RPCs, etc.
+	IdentifyCache    *IdentifyCache    // cache of IdentifyOutcomes
+	UserCache        *UserCache        // cache of Users
+	UI               UI                // Interact with the UI
+	Service          bool              // whether we're in server mode
+	shutdownOnce     sync.Once         // whether we've shut down or not
+	loginStateMu     sync.RWMutex      // protects loginState pointer, which gets destroyed on logout
+	loginState       *LoginState       // What phase of login the user's in
+
+	// The contexts that calls made as users other than the active one run
+	// in, and for one of those, the context it was made from; see
+	// ForAccount
+	accountsMu sync.Mutex
+	accounts   map[NormalizedUsername]*GlobalContext
+	base       *GlobalContext
+
+	// Offline mode, if it was switched at runtime; see SetOffline
+	offlineMu      sync.RWMutex
+	offline        *bool
+	offlineQueueMu sync.Mutex // serializes changes to the queue of offline sigs
+
 	ConnectionManager *ConnectionManager // keep tabs on all active client connections
 	NotifyRouter      *NotifyRouter      // How to route notifications
 	UIRouter          UIRouter           // How to route UIs
@@ -64,7 +78,8 @@ type GlobalContext struct {
 
 func NewGlobalContext() *GlobalContext {
 	return &GlobalContext{
-		Log: logger.New("keybase"),
+		Log:   logger.New("keybase"),
+		Stats: NewStatsRegistry(),
 	}
 }
 
@@ -123,6 +138,10 @@ func (g *GlobalContext) Logout() error {
 		return err
 	}
 
+	if g.LocalDb != nil {
+		g.LocalDb.Lock()
+	}
+
 	if g.IdentifyCache != nil {
 		g.IdentifyCache.Shutdown()
 	}
@@ -161,6 +180,12 @@ func (g *GlobalContext) ConfigureConfig() error {
 	}
 	g.Env.SetConfig(*c)
 	g.Env.SetConfigWriter(c)
+
+	g.accountsMu.Lock()
+	defer g.accountsMu.Unlock()
+	for nu, ag := range g.accounts {
+		ag.Env.setAccountConfig(g.Env, nu)
+	}
 	return nil
 }
 
@@ -208,7 +233,11 @@ func (g *GlobalContext) ConfigureCaches() error {
 	// We consider the local DB as a cache; it's caching our
 	// fetches from the server after all (and also our cryptographic
 	// checking).
-	g.LocalDb = NewJSONLocalDb(NewLevelDb(g))
+	mode, err := g.Env.GetLocalDbEncryption()
+	if err != nil {
+		return err
+	}
+	g.LocalDb = NewJSONLocalDb(g, NewLevelDb(g), mode)
 	return g.LocalDb.Open()
 }
 
@@ -257,6 +286,7 @@ func (g *GlobalContext) Shutdown() error {
 		if g.LoginState() != nil {
 			epick.Push(g.LoginState().Shutdown())
 		}
+		epick.Push(g.shutdownAccounts())
 
 		if g.IdentifyCache != nil {
 			g.IdentifyCache.Shutdown()
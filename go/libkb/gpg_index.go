@@ -87,6 +87,8 @@ func (k GpgBaseKey) AlgoString() string {
 		return "R"
 	case packet.PubKeyAlgoECDSA:
 		return "E"
+	case packet.PubKeyAlgoEdDSA:
+		return "Ed"
 	default:
 		return "?"
 	}
@@ -480,6 +480,12 @@ func (l *TrackChainLink) GetTrackedUsername() (string, error) {
 	return l.payloadJSON.AtPath("body.track.basics.username").GetString()
 }
 
+// GetTrackedEldestKID is the tracked user's eldest key when they were
+// tracked.
+func (l *TrackChainLink) GetTrackedEldestKID() (keybase1.KID, error) {
+	return GetKID(l.payloadJSON.AtPath("body.track.key.kid"))
+}
+
 func (l *TrackChainLink) IsRevoked() bool {
 	return l.revoked || l.untrack != nil
 }
@@ -752,12 +758,19 @@ type CryptocurrencyChainLink struct {
 	GenericChainLink
 	pkhash  []byte
 	address string
+	typ     string
 }
 
 func (c CryptocurrencyChainLink) GetAddress() string {
 	return c.address
 }
 
+// GetCurrencyType is the name of the currency the address is for, e.g.
+// "bitcoin".
+func (c CryptocurrencyChainLink) GetCurrencyType() string {
+	return c.typ
+}
+
 func ParseCryptocurrencyChainLink(b GenericChainLink) (
 	cl *CryptocurrencyChainLink, err error) {
 
@@ -772,17 +785,17 @@ func ParseCryptocurrencyChainLink(b GenericChainLink) (
 		return
 	}
 
-	if typ != "bitcoin" {
-		err = fmt.Errorf("Can only handle 'bitcoin' addresses for now; got %s", typ)
+	if GetCurrencyType(typ) == nil {
+		err = fmt.Errorf("Unknown cryptocurrency %q", typ)
 		return
 	}
 
-	_, pkhash, err = BtcAddrCheck(addr, nil)
+	_, pkhash, err = CryptocurrencyAddrCheck(addr, typ)
 	if err != nil {
 		err = fmt.Errorf("At signature %s: %s", b.ToDebugString(), err)
 		return
 	}
-	cl = &CryptocurrencyChainLink{b, pkhash, addr}
+	cl = &CryptocurrencyChainLink{b, pkhash, addr, typ}
 	return
 }
 
@@ -1092,13 +1105,28 @@ func (idt *IdentityTable) TrackChainLinkFor(username string, uid keybase1.UID) (
 	return nil, nil
 }
 
-func (idt *IdentityTable) ActiveCryptocurrency() *CryptocurrencyChainLink {
+// ActiveCryptocurrency returns the address of the given type, which is
+// the last one claimed, unless it's been revoked.
+func (idt *IdentityTable) ActiveCryptocurrency(typ string) *CryptocurrencyChainLink {
 	var ret *CryptocurrencyChainLink
-	tab := idt.cryptocurrency
-	if len(tab) > 0 {
-		last := tab[len(tab)-1]
-		if !last.IsRevoked() {
-			ret = last
+	for _, link := range idt.cryptocurrency {
+		if link.typ == typ {
+			ret = link
+		}
+	}
+	if ret != nil && ret.IsRevoked() {
+		ret = nil
+	}
+	return ret
+}
+
+// ActiveCryptocurrencies returns the active address of each type, in the
+// order they were claimed in.
+func (idt *IdentityTable) ActiveCryptocurrencies() []*CryptocurrencyChainLink {
+	var ret []*CryptocurrencyChainLink
+	for _, link := range idt.cryptocurrency {
+		if idt.ActiveCryptocurrency(link.typ) == link {
+			ret = append(ret, link)
 		}
 	}
 	return ret
@@ -1118,28 +1146,28 @@ func (idt *IdentityTable) Len() int {
 	return len(idt.Order)
 }
 
-func (idt *IdentityTable) Identify(is IdentifyState, forceRemoteCheck bool, ui IdentifyUI) {
+func (idt *IdentityTable) Identify(is IdentifyState, cacheMode ProofCacheMode, ui IdentifyUI) {
 	var wg sync.WaitGroup
 	for _, lcr := range is.res.ProofChecks {
 		wg.Add(1)
 		go func(l *LinkCheckResult) {
 			defer wg.Done()
-			idt.identifyActiveProof(l, is, forceRemoteCheck, ui)
+			idt.identifyActiveProof(l, is, cacheMode, ui)
 		}(lcr)
 	}
 
 	// wait for all goroutines to complete before exiting
 	wg.Wait()
 
-	if acc := idt.ActiveCryptocurrency(); acc != nil {
+	for _, acc := range idt.ActiveCryptocurrencies() {
 		acc.Display(ui)
 	}
 }
 
 //=========================================================================
 
-func (idt *IdentityTable) identifyActiveProof(lcr *LinkCheckResult, is IdentifyState, forceRemoteCheck bool, ui IdentifyUI) {
-	idt.proofRemoteCheck(is.HasPreviousTrack(), forceRemoteCheck, lcr)
+func (idt *IdentityTable) identifyActiveProof(lcr *LinkCheckResult, is IdentifyState, cacheMode ProofCacheMode, ui IdentifyUI) {
+	idt.proofRemoteCheck(is.HasPreviousTrack(), cacheMode, lcr)
 	lcr.link.DisplayCheck(ui, *lcr)
 }
 
@@ -1153,6 +1181,7 @@ type LinkCheckResult struct {
 	trackedProofState keybase1.ProofState
 	position          int
 	torWarning        bool
+	torCheck          keybase1.TorCheck
 }
 
 func (l LinkCheckResult) GetDiff() TrackDiff      { return l.diff }
@@ -1162,6 +1191,10 @@ func (l LinkCheckResult) GetCached() *CheckResult { return l.cached }
 func (l LinkCheckResult) GetPosition() int        { return l.position }
 func (l LinkCheckResult) GetTorWarning() bool     { return l.torWarning }
 
+// TorSkipped is whether the proof wasn't checked, since it can't be
+// checked reliably over Tor in Tor-strict mode.
+func (l LinkCheckResult) TorSkipped() bool { return l.torCheck == keybase1.TorCheck_SKIPPED }
+
 func ComputeRemoteDiff(tracked, observed keybase1.ProofState) TrackDiff {
 	if observed == tracked {
 		return TrackDiffNone{}
@@ -1173,23 +1206,24 @@ func ComputeRemoteDiff(tracked, observed keybase1.ProofState) TrackDiff {
 	return TrackDiffRemoteChanged{tracked, observed}
 }
 
-func (idt *IdentityTable) proofRemoteCheck(hasPreviousTrack, forceRemoteCheck bool, res *LinkCheckResult) {
+func (idt *IdentityTable) proofRemoteCheck(hasPreviousTrack bool, cacheMode ProofCacheMode, res *LinkCheckResult) {
 	p := res.link
 
 	idt.G().Log.Debug("+ RemoteCheckProof %s", p.ToDebugString())
 	doCache := false
+	unchecked := false
 	sid := p.GetSigID()
 
 	defer func() {
 
-		if hasPreviousTrack {
+		if hasPreviousTrack && !unchecked {
 			observedProofState := ProofErrorToState(res.err)
 			res.remoteDiff = ComputeRemoteDiff(res.trackedProofState, observedProofState)
 		}
 
 		if doCache {
 			idt.G().Log.Debug("| Caching results under key=%s", sid)
-			if cacheErr := idt.G().ProofCache.Put(sid, res.err); cacheErr != nil {
+			if cacheErr := idt.G().ProofCache.Put(sid, p.TableKey(), res.err); cacheErr != nil {
 				idt.G().Log.Warning("proof cache put error: %s", cacheErr)
 			}
 		}
@@ -1210,23 +1244,67 @@ func (idt *IdentityTable) proofRemoteCheck(hasPreviousTrack, forceRemoteCheck bo
 		return
 	}
 
-	if idt.G().Env.GetTorMode().Enabled() {
+	torMode := idt.G().Env.GetTorMode()
+	if torMode.Enabled() {
 		if e := pc.GetTorError(); e != nil {
+			// In Tor-strict mode, don't check what Tor can't
+			// check reliably, and don't let it count against
+			// the tracked state either.
+			if !torMode.CheckUnreliableProofs() {
+				unchecked = true
+				res.torCheck = keybase1.TorCheck_SKIPPED
+				res.err = e
+				return
+			}
 			res.torWarning = true
 		}
 	}
 
-	if !forceRemoteCheck {
+	switch cacheMode {
+	case ProofCacheFresh:
 		if res.cached = idt.G().ProofCache.Get(sid); res.cached != nil {
+			idt.G().Log.Debug("| Using the result cached at %s", res.cached.Time)
+			res.err = res.cached.Status
+			return
+		}
+	case ProofCacheOnly:
+		if res.cached = idt.G().ProofCache.GetAny(sid); res.cached != nil {
+			idt.G().Log.Debug("| Using the result cached at %s (stale: %v)", res.cached.Time, res.cached.Stale)
 			res.err = res.cached.Status
 			return
 		}
+		// As offline, not having checked it shouldn't look like
+		// the proof broke.
+		unchecked = true
+		res.err = NewProofError(keybase1.ProofStatus_HOST_UNREACHABLE, "not checked; no cached result")
+		return
+	}
+
+	// Offline, we can't check it, which shouldn't look like the proof
+	// broke since we tracked it.
+	if idt.G().IsOffline() {
+		unchecked = true
+		res.err = NewProofError(keybase1.ProofStatus_HOST_UNREACHABLE, "not checked; offline")
+		return
 	}
 
 	// From this point on in the function, we'll be putting our results into
 	// cache (in the defer above).
 	doCache = true
 
+	start := time.Now()
+	defer func() {
+		var err error
+		if res.err != nil {
+			err = res.err
+		}
+		idt.G().Stats.Record(keybase1.StatCategory_PROOF, p.TableKey(), time.Since(start), err)
+	}()
+
+	if torMode.Enabled() {
+		res.torCheck = keybase1.TorCheck_VERIFIED
+	}
+
 	if res.err = pc.CheckHint(*res.hint); res.err != nil {
 		idt.G().Log.Debug("| Hint failed with error: %s", res.err.Error())
 		return
@@ -80,13 +80,25 @@ func (i IdentifyOutcome) NumRevoked() int {
 func (i IdentifyOutcome) NumProofFailures() int {
 	nfails := 0
 	for _, c := range i.ProofChecks {
-		if c.err != nil {
+		if c.err != nil && !c.TorSkipped() {
 			nfails++
 		}
 	}
 	return nfails
 }
 
+// The number of proofs that weren't checked, since they can't be
+// checked reliably over Tor.
+func (i IdentifyOutcome) NumTorSkipped() int {
+	nskipped := 0
+	for _, c := range i.ProofChecks {
+		if c.TorSkipped() {
+			nskipped++
+		}
+	}
+	return nskipped
+}
+
 // The number of proofs that actually worked
 func (i IdentifyOutcome) NumProofSuccesses() int {
 	nsucc := 0
@@ -141,6 +153,29 @@ func (i IdentifyOutcome) NumTrackChanges() int {
 	return ntc
 }
 
+// AccountReset is the user's account having been reset since they were
+// tracked, or nil if it wasn't.
+func (i IdentifyOutcome) AccountReset() *TrackDiffNewEldest {
+	for _, k := range i.KeyDiffs {
+		if d, ok := k.(TrackDiffNewEldest); ok {
+			return &d
+		}
+	}
+	return nil
+}
+
+// CarriedOverProofs are the proofs that are as they were tracked.  After
+// an account reset, they're the ones the user has made again.
+func (i IdentifyOutcome) CarriedOverProofs() []string {
+	var ret []string
+	for _, c := range i.ProofChecksSorted() {
+		if c.diff != nil && c.diff.IsSameAsTracked() {
+			ret = append(ret, c.link.ToDisplayString())
+		}
+	}
+	return ret
+}
+
 func (i IdentifyOutcome) TrackStatus() keybase1.TrackStatus {
 	if i.NumTrackFailures() > 0 || i.NumRevoked() > 0 {
 		return keybase1.TrackStatus_UPDATE_BROKEN
@@ -192,6 +227,11 @@ func (i IdentifyOutcome) GetErrorAndWarnings(strict bool) (warnings Warnings, er
 		softErr(p)
 	}
 
+	if nskipped := i.NumTorSkipped(); nskipped > 0 {
+		warnings.Push(StringWarning(fmt.Sprintf("%d proof%s skipped in Tor-strict mode, since Tor can't check them reliably",
+			nskipped, GiveMeAnS(nskipped))))
+	}
+
 	if ntf := i.NumTrackFailures(); ntf > 0 {
 		probs = append(probs,
 			fmt.Sprintf("%d track component%s failed",
@@ -91,6 +91,13 @@ func (s *IdentifyState) ComputeKeyDiffs(dhook func(keybase1.IdentifyKey)) {
 		dhook(k)
 	}
 
+	if s.track != nil {
+		if diff := s.eldestDiff(); diff != nil {
+			s.res.KeyDiffs = append(s.res.KeyDiffs, *diff)
+			display(diff.observed, *diff)
+		}
+	}
+
 	found := s.u.GetActivePGPKIDs(true)
 	foundMap := mapify(found)
 	var tracked []keybase1.KID
@@ -121,3 +128,20 @@ func (s *IdentifyState) ComputeKeyDiffs(dhook func(keybase1.IdentifyKey)) {
 		}
 	}
 }
+
+// eldestDiff is the user's account having been reset, with a new eldest
+// key, since the tracking statement, if it was.
+func (s *IdentifyState) eldestDiff() *TrackDiffNewEldest {
+	tracked := s.track.GetTrackedEldestKID(s.u.G())
+	observed := s.u.GetEldestKID()
+	if tracked.IsNil() || observed.IsNil() || tracked.Equal(observed) {
+		return nil
+	}
+	ret := &TrackDiffNewEldest{tracked: tracked, observed: observed}
+	if sc := s.u.sigChain(); sc != nil {
+		if links, err := sc.GetCurrentSubchain(observed); err == nil && len(links) > 0 {
+			ret.ctime = links[0].GetCTime()
+		}
+	}
+	return ret
+}
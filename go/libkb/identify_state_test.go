@@ -0,0 +1,60 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+	jsonw "github.com/keybase/go-jsonw"
+)
+
+func testTrackLink(t *testing.T, username string, eldest keybase1.KID) *TrackChainLink {
+	track := jsonw.NewDictionary()
+	track.SetValueAtPath("basics.username", jsonw.NewString(username))
+	track.SetValueAtPath("key.kid", jsonw.NewString(eldest.String()))
+	payload := jsonw.NewDictionary()
+	payload.SetValueAtPath("body.track", track)
+	link, err := ParseTrackChainLink(GenericChainLink{&ChainLink{payloadJSON: payload}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return link
+}
+
+func TestIdentifyStateAccountReset(t *testing.T) {
+	tc := SetupTest(t, "identify_state")
+	defer tc.Cleanup()
+
+	sc, kids := loadRalphChain(t, tc)
+	u := &User{name: "ralph", id: sc.uid, sigChainMem: sc}
+	u.leaf.eldest = kids["second_eldest"]
+
+	res := NewIdentifyOutcome(true)
+	is := NewIdentifyState(res, u)
+	is.CreateTrackLookup(testTrackLink(t, "ralph", kids["second_eldest"]))
+	if diff := is.eldestDiff(); diff != nil {
+		t.Fatalf("same eldest key, but got %+v", diff)
+	}
+
+	is.CreateTrackLookup(testTrackLink(t, "ralph", kids["first_eldest"]))
+	diff := is.eldestDiff()
+	if diff == nil {
+		t.Fatal("reset wasn't noticed")
+	}
+	if !diff.GetTracked().Equal(kids["first_eldest"]) || !diff.GetObserved().Equal(kids["second_eldest"]) {
+		t.Errorf("wrong keys in %+v", diff)
+	}
+	if !diff.GetCTime().Equal(sc.chainLinks[2].GetCTime()) {
+		t.Errorf("reset at %s, expected the first link of the new subchain at %s", diff.GetCTime(), sc.chainLinks[2].GetCTime())
+	}
+	if !diff.BreaksTracking() {
+		t.Error("a reset didn't break tracking")
+	}
+
+	res.KeyDiffs = append(res.KeyDiffs, *diff)
+	if res.AccountReset() == nil || res.TrackStatus() != keybase1.TrackStatus_UPDATE_BROKEN {
+		t.Errorf("outcome didn't show the reset: %+v", res.Export())
+	}
+}
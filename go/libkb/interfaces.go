@@ -62,6 +62,9 @@ type CommandLine interface {
 	GetTorHiddenAddress() string
 	GetTorProxy() string
 
+	GetAccount() string
+	GetOffline() (bool, bool)
+
 	// Lower-level functions
 	GetGString(string) string
 	GetString(string) string
@@ -87,6 +90,10 @@ type LocalDb interface {
 	Delete(id DbKey) error
 	Get(id DbKey) ([]byte, bool, error)
 	Lookup(alias DbKey) ([]byte, bool, error)
+	ForEach(typ ObjType, f func(id DbKey, value []byte) error) error
+	Stats() ([]DbTableStats, error)
+	Compact() error
+	Evict(selects func(id DbKey) bool) (int, error)
 }
 
 type ConfigReader interface {
@@ -112,10 +119,20 @@ type ConfigReader interface {
 	GetProofCacheLongDur() (time.Duration, bool)
 	GetProofCacheMediumDur() (time.Duration, bool)
 	GetProofCacheShortDur() (time.Duration, bool)
+	GetProofCacheServiceDur(service, result string) (time.Duration, bool)
+	GetProofCacheMaxStored() (int, bool)
+	GetLogRotateMaxSize() (int, bool)
+	GetLogRotateMaxAge() (time.Duration, bool)
+	GetLogRotateKeep() (int, bool)
+	GetLogRotateCompress() (bool, bool)
+	GetSecretCacheIdleTimeout() (time.Duration, bool)
+	GetSecretCacheMaxLifetime() (time.Duration, bool)
 	GetMerkleKIDs() []string
 	GetPinentry() string
 	GetNoPinentry() (bool, bool)
 	GetGpg() string
+	GetPGPKeyserver() string
+	GetLocalDbEncryption() (LocalDbEncryption, error)
 	GetGpgOptions() []string
 	GetSecretKeyringTemplate() string
 	GetSalt() []byte
@@ -139,10 +156,12 @@ type ConfigReader interface {
 	GetTorMode() (TorMode, error)
 	GetTorHiddenAddress() string
 	GetTorProxy() string
+	GetTorDNSOverHTTPSURL() string
 }
 
 type ConfigWriter interface {
 	SetUserConfig(cfg *UserConfig, overwrite bool) error
+	SetOtherUserConfig(cfg *UserConfig, overwrite bool) error
 	SwitchUser(un NormalizedUsername) error
 	NukeUser(un NormalizedUsername) error
 	SetDeviceID(keybase1.DeviceID) error
@@ -392,6 +392,12 @@ func KeyProof(arg Delegator) (ret *jsonw.Wrapper, err error) {
 		body.SetKey(string(arg.DelegationType), kp)
 	}
 
+	if len(arg.RevokeKIDs) > 0 {
+		revokeSection := jsonw.NewDictionary()
+		revokeSection.SetKey("kids", jsonw.NewWrapper(arg.RevokeKIDs))
+		body.SetKey("revoke", revokeSection)
+	}
+
 	return
 }
 
@@ -491,7 +497,7 @@ func (u *User) RevokeSigsProof(key GenericKey, sigIDsToRevoke []keybase1.SigID)
 	return ret, nil
 }
 
-func (u *User) CryptocurrencySig(key GenericKey, address string, sigToRevoke keybase1.SigID) (*jsonw.Wrapper, error) {
+func (u *User) CryptocurrencySig(key GenericKey, address string, typ string, sigToRevoke keybase1.SigID) (*jsonw.Wrapper, error) {
 	ret, err := ProofMetadata{
 		Me:         u,
 		LinkType:   CryptocurrencyType,
@@ -503,7 +509,7 @@ func (u *User) CryptocurrencySig(key GenericKey, address string, sigToRevoke key
 	body := ret.AtKey("body")
 	currencySection := jsonw.NewDictionary()
 	currencySection.SetKey("address", jsonw.NewString(address))
-	currencySection.SetKey("type", jsonw.NewString("bitcoin"))
+	currencySection.SetKey("type", jsonw.NewString(typ))
 	body.SetKey("cryptocurrency", currencySection)
 	if len(sigToRevoke) > 0 {
 		revokeSection := jsonw.NewDictionary()
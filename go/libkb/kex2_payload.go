@@ -0,0 +1,260 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/keybase/client/go/encoding/basex"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// Kex2Payload is what one device shows the other to start provisioning,
+// either as a QR code or as a short code to type in place of the secret
+// phrase. It has the phrase, packed as the indices of its words, and
+// enough about the device showing it for the other to check that the two
+// can provision each other at all.
+//
+// Encoded, it's:
+//
+//	version (1) | role and device type (1) | router hint (1) |
+//	expiry, Unix seconds (4) | phrase (11) |
+//	device name length (1) | device name | checksum (4)
+//
+// where the checksum is the start of the SHA256 of everything before it.
+// The short text form is base58 and leaves out the device name.
+type Kex2Payload struct {
+	Phrase     string
+	Role       KexRole
+	DeviceType keybase1.DeviceType
+	// DeviceName is empty in the text form, and for new devices.
+	DeviceName string
+	// RouterHint is the run mode, since devices can't meet through
+	// different servers' routers.
+	RouterHint RunMode
+	Expires    time.Time
+}
+
+const (
+	kex2PayloadVersion     = 1
+	kex2PayloadChecksumLen = 4
+	kex2PayloadTextGroup   = 4
+)
+
+// secwordBits is how many bits each word from secwords carries.
+const secwordBits = 11
+
+// kex2PhraseWords is how many words are in a kex2 secret phrase, as
+// SecWordList picks them, and kex2PhraseLen how many bytes they pack into.
+var (
+	kex2PhraseWords = (Kex2PhraseEntropy + secwordBits - 1) / secwordBits
+	kex2PhraseLen   = (kex2PhraseWords*secwordBits + 7) / 8
+)
+
+// NewKex2Payload makes the payload for this device to show the other one.
+func NewKex2Payload(g *GlobalContext, secret *Kex2Secret, role KexRole, deviceType keybase1.DeviceType, deviceName string) *Kex2Payload {
+	return &Kex2Payload{
+		Phrase:     secret.Phrase(),
+		Role:       role,
+		DeviceType: deviceType,
+		DeviceName: deviceName,
+		RouterHint: g.Env.GetRunMode(),
+		Expires:    time.Now().Add(Kex2PayloadLifetime),
+	}
+}
+
+func (p *Kex2Payload) encode(withName bool) ([]byte, error) {
+	phrase, err := packKex2Phrase(p.Phrase)
+	if err != nil {
+		return nil, err
+	}
+	name := []byte(p.DeviceName)
+	if !withName {
+		name = nil
+	}
+	if len(name) > 0xff {
+		return nil, fmt.Errorf("device name is too long")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(kex2PayloadVersion)
+	buf.WriteByte(byte(p.Role)<<4 | byte(p.DeviceType))
+	buf.WriteByte(runModeHint(p.RouterHint))
+	binary.Write(&buf, binary.BigEndian, uint32(p.Expires.Unix()))
+	buf.Write(phrase)
+	buf.WriteByte(byte(len(name)))
+	buf.Write(name)
+	sum := sha256.Sum256(buf.Bytes())
+	buf.Write(sum[:kex2PayloadChecksumLen])
+	return buf.Bytes(), nil
+}
+
+// Encode encodes the payload for a QR code.
+func (p *Kex2Payload) Encode() ([]byte, error) {
+	return p.encode(true)
+}
+
+// Text encodes the payload, without the device name, in dash-separated
+// groups of base58 to be typed in.
+func (p *Kex2Payload) Text() (string, error) {
+	b, err := p.encode(false)
+	if err != nil {
+		return "", err
+	}
+	s := basex.Base58StdEncoding.EncodeToString(b)
+	var groups []string
+	for len(s) > kex2PayloadTextGroup {
+		groups = append(groups, s[:kex2PayloadTextGroup])
+		s = s[kex2PayloadTextGroup:]
+	}
+	groups = append(groups, s)
+	return strings.Join(groups, "-"), nil
+}
+
+// DecodeKex2Payload decodes a payload, and checks its checksum.
+func DecodeKex2Payload(b []byte) (*Kex2Payload, error) {
+	fixed := 7 + kex2PhraseLen + 1
+	if len(b) < fixed+kex2PayloadChecksumLen {
+		return nil, Kex2PayloadError{"it's too short"}
+	}
+	body, checksum := b[:len(b)-kex2PayloadChecksumLen], b[len(b)-kex2PayloadChecksumLen:]
+	if sum := sha256.Sum256(body); !FastByteArrayEq(sum[:kex2PayloadChecksumLen], checksum) {
+		return nil, Kex2PayloadError{"the checksum doesn't match; check for typos"}
+	}
+	if body[0] != kex2PayloadVersion {
+		return nil, Kex2PayloadError{fmt.Sprintf("unknown version %d; is your other device up to date?", body[0])}
+	}
+	if nameLen := int(body[fixed-1]); len(body) != fixed+nameLen {
+		return nil, Kex2PayloadError{"the device name is truncated"}
+	}
+
+	phrase, err := unpackKex2Phrase(body[7 : 7+kex2PhraseLen])
+	if err != nil {
+		return nil, Kex2PayloadError{err.Error()}
+	}
+	return &Kex2Payload{
+		Phrase:     phrase,
+		Role:       KexRole(body[1] >> 4),
+		DeviceType: keybase1.DeviceType(body[1] & 0xf),
+		DeviceName: string(body[fixed:]),
+		RouterHint: hintRunMode(body[2]),
+		Expires:    time.Unix(int64(binary.BigEndian.Uint32(body[3:7])), 0),
+	}, nil
+}
+
+// ParseKex2PayloadText decodes a payload from its text form.
+func ParseKex2PayloadText(s string) (*Kex2Payload, error) {
+	b, err := basex.Base58StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, Kex2PayloadError{"it has a character that can't be in a code; check for typos"}
+	}
+	return DecodeKex2Payload(b)
+}
+
+// IsKex2PayloadText is whether s was meant as a payload's text form,
+// which is anything but a secret phrase: exactly kex2PhraseWords secret
+// phrase words.  Codes can be typed with spaces in place of dashes.
+func IsKex2PayloadText(s string) bool {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return false
+	}
+	if len(words) != kex2PhraseWords {
+		return true
+	}
+	for _, w := range words {
+		if secwordIndex(w) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Check checks that a payload from the other device is still good, and
+// for a device that can provision, or be provisioned by, one with role.
+func (p *Kex2Payload) Check(g *GlobalContext, role KexRole) error {
+	if p.Role == role {
+		if role == KexRoleProvisioner {
+			return Kex2PayloadError{"it's from another device that's adding a device, not a new one"}
+		}
+		return Kex2PayloadError{"it's from another new device, not one that's adding it"}
+	}
+	if mode := g.Env.GetRunMode(); p.RouterHint != NoRunMode && p.RouterHint != mode {
+		return Kex2PayloadError{fmt.Sprintf("it's for the %s servers, not %s", p.RouterHint, mode)}
+	}
+	if time.Now().After(p.Expires) {
+		return Kex2PayloadError{"it's expired; start again on both devices"}
+	}
+	return nil
+}
+
+func runModeHint(mode RunMode) byte {
+	for i, m := range RunModes {
+		if m == mode {
+			return byte(i + 1)
+		}
+	}
+	return 0
+}
+
+func hintRunMode(b byte) RunMode {
+	if b == 0 || int(b) > len(RunModes) {
+		return NoRunMode
+	}
+	return RunModes[b-1]
+}
+
+// packKex2Phrase packs the words of a phrase as their indices in secwords.
+func packKex2Phrase(phrase string) ([]byte, error) {
+	words := strings.Fields(phrase)
+	if len(words) != kex2PhraseWords {
+		return nil, fmt.Errorf("phrase has %d words, not %d", len(words), kex2PhraseWords)
+	}
+	x := new(big.Int)
+	for _, w := range words {
+		i := secwordIndex(w)
+		if i < 0 {
+			return nil, fmt.Errorf("%q isn't a secret phrase word", w)
+		}
+		x.Lsh(x, secwordBits)
+		x.Or(x, big.NewInt(int64(i)))
+	}
+	ret := make([]byte, kex2PhraseLen)
+	b := x.Bytes()
+	copy(ret[len(ret)-len(b):], b)
+	return ret, nil
+}
+
+func unpackKex2Phrase(b []byte) (string, error) {
+	x := new(big.Int).SetBytes(b)
+	mask := big.NewInt(1<<secwordBits - 1)
+	words := make([]string, kex2PhraseWords)
+	for i := len(words) - 1; i >= 0; i-- {
+		idx := new(big.Int).And(x, mask).Int64()
+		if int(idx) >= len(secwords) {
+			return "", fmt.Errorf("bad phrase word %d", idx)
+		}
+		words[i] = secwords[idx]
+		x.Rsh(x, secwordBits)
+	}
+	if x.Sign() != 0 {
+		return "", fmt.Errorf("phrase has extra bits")
+	}
+	return strings.Join(words, " "), nil
+}
+
+func secwordIndex(w string) int {
+	for i, s := range secwords {
+		if s == w {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,120 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+func testKex2Payload(t *testing.T, tc TestContext) (*Kex2Secret, *Kex2Payload) {
+	secret, err := NewKex2Secret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return secret, NewKex2Payload(tc.G, secret, KexRoleProvisioner, keybase1.DeviceType_DESKTOP, "home laptop")
+}
+
+func TestKex2Payload(t *testing.T) {
+	tc := SetupTest(t, "kex2_payload")
+	defer tc.Cleanup()
+
+	secret, payload := testKex2Payload(t, tc)
+	b, err := payload.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	qr, err := DecodeKex2Payload(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qr.Phrase != secret.Phrase() || qr.DeviceName != "home laptop" || qr.Role != KexRoleProvisioner || qr.RouterHint != tc.G.Env.GetRunMode() {
+		t.Errorf("decoded %+v, expected %+v", qr, payload)
+	}
+	if err := qr.Check(tc.G, KexRoleProvisionee); err != nil {
+		t.Error(err)
+	}
+
+	// The text form leaves out the device name, and is much shorter
+	// than the phrase.
+	text, err := payload.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsKex2PayloadText(text) || IsKex2PayloadText(secret.Phrase()) {
+		t.Errorf("text %q and phrase %q weren't told apart", text, secret.Phrase())
+	}
+	if spaced := strings.Replace(text, "-", " ", -1); !IsKex2PayloadText(spaced) {
+		t.Errorf("text %q typed with spaces was taken for a phrase", spaced)
+	}
+	if len(text) >= len(secret.Phrase()) {
+		t.Errorf("text %q is longer than the phrase", text)
+	}
+	typed, err := ParseKex2PayloadText(strings.Replace(text, "-", " ", -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if typed.Phrase != secret.Phrase() || len(typed.DeviceName) > 0 || !typed.Expires.Equal(qr.Expires) {
+		t.Errorf("parsed %+v from text", typed)
+	}
+}
+
+func TestKex2PayloadTypos(t *testing.T) {
+	tc := SetupTest(t, "kex2_payload")
+	defer tc.Cleanup()
+
+	_, payload := testKex2Payload(t, tc)
+	text, err := payload.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Swap two different neighboring characters, and change one.
+	for i := 0; i+1 < len(text); i++ {
+		if text[i] != text[i+1] && text[i] != '-' && text[i+1] != '-' {
+			swapped := text[:i] + text[i+1:i+2] + text[i:i+1] + text[i+2:]
+			if _, err := ParseKex2PayloadText(swapped); err == nil {
+				t.Errorf("swapped text %q was accepted", swapped)
+			}
+			break
+		}
+	}
+	changed := "2" + text[1:]
+	if text[0] == '2' {
+		changed = "3" + text[1:]
+	}
+	if _, err := ParseKex2PayloadText(changed); err == nil {
+		t.Errorf("changed text %q was accepted", changed)
+	}
+	if _, err := ParseKex2PayloadText(text[:len(text)-3]); err == nil {
+		t.Error("truncated text was accepted")
+	}
+}
+
+func TestKex2PayloadCheck(t *testing.T) {
+	tc := SetupTest(t, "kex2_payload")
+	defer tc.Cleanup()
+
+	_, payload := testKex2Payload(t, tc)
+	if _, ok := payload.Check(tc.G, KexRoleProvisioner).(Kex2PayloadError); !ok {
+		t.Error("a provisioner accepted another provisioner's code")
+	}
+
+	other := *payload
+	other.RouterHint = StagingRunMode
+	if tc.G.Env.GetRunMode() == StagingRunMode {
+		other.RouterHint = ProductionRunMode
+	}
+	if _, ok := other.Check(tc.G, KexRoleProvisionee).(Kex2PayloadError); !ok {
+		t.Error("accepted a code for other servers")
+	}
+
+	expired := *payload
+	expired.Expires = time.Now().Add(-time.Second)
+	if _, ok := expired.Check(tc.G, KexRoleProvisionee).(Kex2PayloadError); !ok {
+		t.Error("accepted an expired code")
+	}
+}
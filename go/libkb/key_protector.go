@@ -0,0 +1,271 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/crypto/nacl/secretbox"
+)
+
+// KeyProtector keeps the client half of this device's LKSec secret, the
+// half that's otherwise derived from the passphrase, so that the
+// device's secret keys can be unlocked without asking for it.  Each
+// protector keeps it differently; the one in use is picked per user with
+// `keybase device protect`.
+type KeyProtector interface {
+	// Name is the name the protector is configured by.
+	Name() string
+	// Protect keeps clientHalf, replacing anything kept before.
+	Protect(clientHalf []byte) error
+	// Unprotect returns the kept client half, or a
+	// NoProtectedSecretError if nothing is kept.
+	Unprotect() ([]byte, error)
+	// Clear forgets the kept client half.
+	Clear() error
+}
+
+const (
+	KeyProtectorPassphrase  = "passphrase"
+	KeyProtectorSecretStore = "secret-store"
+	KeyProtectorMachine     = "machine"
+)
+
+// KeyProtectorNames are the protectors NewKeyProtector knows.
+var KeyProtectorNames = []string{KeyProtectorPassphrase, KeyProtectorSecretStore, KeyProtectorMachine}
+
+// NoProtectedSecretError is returned by KeyProtector.Unprotect when the
+// protector isn't keeping a client half.
+type NoProtectedSecretError struct {
+	Protector string
+}
+
+func (e NoProtectedSecretError) Error() string {
+	return fmt.Sprintf("the %s key protector isn't keeping a secret", e.Protector)
+}
+
+// NewKeyProtector makes the protector called name for username.
+func NewKeyProtector(g *GlobalContext, name string, username NormalizedUsername) (KeyProtector, error) {
+	switch name {
+	case "", KeyProtectorPassphrase:
+		return PassphraseProtector{}, nil
+	case KeyProtectorSecretStore:
+		// Kept apart from the full LKSec secret that "store secret"
+		// puts in the secret store under the plain username.
+		store := NewSecretStore(NormalizedUsername(username.String() + ".lks-client-half"))
+		if store == nil {
+			return nil, errors.New("there's no secret store on this platform")
+		}
+		return NewSecretStoreProtector(store), nil
+	case KeyProtectorMachine:
+		return NewMachineProtector(filepath.Join(g.Env.GetDataDir(), username.String()+".sealed"), username), nil
+	default:
+		return nil, fmt.Errorf("unknown key protector %q; expected one of %s", name, strings.Join(KeyProtectorNames, ", "))
+	}
+}
+
+// CurrentKeyProtector is the protector configured for the current user,
+// or PassphraseProtector if there's none.
+func CurrentKeyProtector(g *GlobalContext) KeyProtector {
+	username := g.Env.GetUsername()
+	if username.IsNil() {
+		return PassphraseProtector{}
+	}
+	uc, err := g.Env.GetConfig().GetUserConfigForUsername(username)
+	if err != nil || uc == nil {
+		return PassphraseProtector{}
+	}
+	kp, err := NewKeyProtector(g, uc.KeyProtector, username)
+	if err != nil {
+		g.Log.Warning("Using the passphrase to unlock keys: %s", err)
+		return PassphraseProtector{}
+	}
+	return kp
+}
+
+// SetKeyProtector records that username's client half is kept by the
+// protector called name.
+func SetKeyProtector(g *GlobalContext, username NormalizedUsername, name string) error {
+	uc, err := g.Env.GetConfig().GetUserConfigForUsername(username)
+	if err != nil {
+		return err
+	}
+	if uc == nil {
+		return NoUserConfigError{}
+	}
+	if name == KeyProtectorPassphrase {
+		name = ""
+	}
+	uc.KeyProtector = name
+	return g.Env.GetConfigWriter().SetUserConfig(uc, true)
+}
+
+//=============================================================================
+
+// PassphraseProtector is the protector of old: it keeps nothing, and the
+// client half is derived from the passphrase when it's entered.
+type PassphraseProtector struct{}
+
+func (p PassphraseProtector) Name() string                    { return KeyProtectorPassphrase }
+func (p PassphraseProtector) Protect(clientHalf []byte) error { return nil }
+func (p PassphraseProtector) Clear() error                    { return nil }
+
+func (p PassphraseProtector) Unprotect() ([]byte, error) {
+	return nil, NoProtectedSecretError{Protector: KeyProtectorPassphrase}
+}
+
+//=============================================================================
+
+// SecretStoreProtector keeps the client half in the OS's secret store,
+// such as the OS X keychain.
+type SecretStoreProtector struct {
+	store SecretStore
+}
+
+func NewSecretStoreProtector(store SecretStore) SecretStoreProtector {
+	return SecretStoreProtector{store: store}
+}
+
+func (p SecretStoreProtector) Name() string { return KeyProtectorSecretStore }
+
+func (p SecretStoreProtector) Protect(clientHalf []byte) error {
+	return p.store.StoreSecret(clientHalf)
+}
+
+func (p SecretStoreProtector) Unprotect() ([]byte, error) {
+	clientHalf, err := p.store.RetrieveSecret()
+	if err != nil || len(clientHalf) == 0 {
+		return nil, NoProtectedSecretError{Protector: KeyProtectorSecretStore}
+	}
+	return clientHalf, nil
+}
+
+func (p SecretStoreProtector) Clear() error {
+	return p.store.ClearSecret()
+}
+
+//=============================================================================
+
+// MachineProtector seals the client half to this machine in software: it
+// encrypts it, in a file, under a key derived from the machine's ID.  A
+// copy of the file is no use on another machine, but anyone who can
+// read both the file and the machine ID on this one can open it, so it's
+// no better than the file's permissions.  It's the stand-in for sealing
+// with a TPM.
+type MachineProtector struct {
+	filename  string
+	username  NormalizedUsername
+	machineID func() ([]byte, error)
+}
+
+const machineSealVersion = 1
+
+func NewMachineProtector(filename string, username NormalizedUsername) MachineProtector {
+	return MachineProtector{
+		filename:  filename,
+		username:  username,
+		machineID: readMachineID,
+	}
+}
+
+func (p MachineProtector) Name() string { return KeyProtectorMachine }
+
+// sealingKey derives the key that seals the client half from the
+// machine ID, a salt kept with the sealed client half, and the username.
+func (p MachineProtector) sealingKey(salt []byte) (*[32]byte, error) {
+	id, err := p.machineID()
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, id)
+	fmt.Fprintf(mac, "Keybase-LKS-machine-seal-%d", machineSealVersion)
+	mac.Write(salt)
+	mac.Write([]byte(p.username))
+	var key [32]byte
+	copy(key[:], mac.Sum(nil))
+	return &key, nil
+}
+
+func (p MachineProtector) Protect(clientHalf []byte) error {
+	salt, err := RandBytes(32)
+	if err != nil {
+		return err
+	}
+	key, err := p.sealingKey(salt)
+	if err != nil {
+		return err
+	}
+	nonce, err := RandBytes(24)
+	if err != nil {
+		return err
+	}
+	var fnonce [24]byte
+	copy(fnonce[:], nonce)
+
+	var buf bytes.Buffer
+	buf.WriteByte(machineSealVersion)
+	buf.Write(salt)
+	buf.Write(nonce)
+	buf.Write(secretbox.Seal(nil, clientHalf, &fnonce, key))
+
+	if err := MakeParentDirs(p.filename); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(p.filename, buf.Bytes(), PermFile)
+}
+
+func (p MachineProtector) Unprotect() ([]byte, error) {
+	sealed, err := ioutil.ReadFile(p.filename)
+	if os.IsNotExist(err) {
+		return nil, NoProtectedSecretError{Protector: KeyProtectorMachine}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < 1+32+24+secretbox.Overhead || sealed[0] != machineSealVersion {
+		return nil, fmt.Errorf("%s isn't a client half sealed to this machine", p.filename)
+	}
+	salt, nonce, box := sealed[1:33], sealed[33:57], sealed[57:]
+	key, err := p.sealingKey(salt)
+	if err != nil {
+		return nil, err
+	}
+	var fnonce [24]byte
+	copy(fnonce[:], nonce)
+	clientHalf, ok := secretbox.Open(nil, box, &fnonce, key)
+	if !ok {
+		return nil, fmt.Errorf("%s was sealed to another machine", p.filename)
+	}
+	return clientHalf, nil
+}
+
+func (p MachineProtector) Clear() error {
+	err := os.Remove(p.filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// machineIDFiles hold the machine ID that systemd and D-Bus give each
+// installation.
+var machineIDFiles = []string{"/etc/machine-id", "/var/lib/dbus/machine-id"}
+
+func readMachineID() ([]byte, error) {
+	for _, name := range machineIDFiles {
+		id, err := ioutil.ReadFile(name)
+		if err == nil && len(bytes.TrimSpace(id)) > 0 {
+			return bytes.TrimSpace(id), nil
+		}
+	}
+	return nil, errors.New("can't find this machine's ID")
+}
@@ -0,0 +1,134 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func fakeMachineID(id string) func() ([]byte, error) {
+	return func() ([]byte, error) { return []byte(id), nil }
+}
+
+func testKeyProtectorRoundTrip(t *testing.T, kp KeyProtector) {
+	if _, err := kp.Unprotect(); err == nil {
+		t.Fatalf("%s: unprotected a client half before protecting one", kp.Name())
+	} else if _, ok := err.(NoProtectedSecretError); !ok {
+		t.Fatalf("%s: unexpected error %s", kp.Name(), err)
+	}
+	clientHalf, err := RandBytes(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := kp.Protect(clientHalf); err != nil {
+		t.Fatal(err)
+	}
+	kept, err := kp.Unprotect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(kept, clientHalf) {
+		t.Errorf("%s: got back %x, expected %x", kp.Name(), kept, clientHalf)
+	}
+	if err := kp.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kp.Unprotect(); err == nil {
+		t.Errorf("%s: unprotected a client half after clearing it", kp.Name())
+	}
+}
+
+func TestSecretStoreProtector(t *testing.T) {
+	tc := SetupTest(t, "key_protector")
+	defer tc.Cleanup()
+	testKeyProtectorRoundTrip(t, NewSecretStoreProtector(&TestSecretStore{}))
+}
+
+func TestMachineProtector(t *testing.T) {
+	tc := SetupTest(t, "key_protector")
+	defer tc.Cleanup()
+
+	filename := filepath.Join(tc.Tp.Home, "alice.sealed")
+	kp := NewMachineProtector(filename, "alice")
+	kp.machineID = fakeMachineID("0123456789abcdef")
+	testKeyProtectorRoundTrip(t, kp)
+
+	// The sealed client half is no use on another machine.
+	if err := kp.Protect([]byte("client half")); err != nil {
+		t.Fatal(err)
+	}
+	elsewhere := kp
+	elsewhere.machineID = fakeMachineID("fedcba9876543210")
+	if _, err := elsewhere.Unprotect(); err == nil {
+		t.Error("unsealed the client half with another machine's ID")
+	}
+}
+
+func TestUnlockWithKeyProtector(t *testing.T) {
+	tc := SetupTest(t, "key_protector")
+	defer tc.Cleanup()
+
+	clientHalf, err := RandBytes(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverHalf, err := RandBytes(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lks := NewLKSecWithClientHalf(clientHalf, 1, "", tc.G)
+	lks.SetServerHalf(serverHalf)
+	key, err := GenerateNaclSigningKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	skb, err := key.ToLksSKB(lks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preload := NewLKSecWithClientHalf(nil, 1, "", tc.G)
+	preload.SetServerHalf(serverHalf)
+
+	kp := NewSecretStoreProtector(&TestSecretStore{})
+	if _, err := skb.unlockWithKeyProtector(nil, kp, preload); err == nil {
+		t.Fatal("unlocked with an empty key protector")
+	}
+	if err := kp.Protect(clientHalf); err != nil {
+		t.Fatal(err)
+	}
+	unlocked, err := skb.unlockWithKeyProtector(nil, kp, preload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unlocked.GetKID() != key.GetKID() {
+		t.Errorf("unlocked %s, expected %s", unlocked.GetKID(), key.GetKID())
+	}
+}
+
+func TestSetKeyProtector(t *testing.T) {
+	tc := SetupTest(t, "key_protector")
+	defer tc.Cleanup()
+
+	if name := CurrentKeyProtector(tc.G).Name(); name != KeyProtectorPassphrase {
+		t.Errorf("protector with no user: %s", name)
+	}
+	fakeLogin(t, tc.G, "alice", 1)
+	if err := SetKeyProtector(tc.G, "alice", KeyProtectorMachine); err != nil {
+		t.Fatal(err)
+	}
+	if name := CurrentKeyProtector(tc.G).Name(); name != KeyProtectorMachine {
+		t.Errorf("protector after setting it: %s", name)
+	}
+	if err := SetKeyProtector(tc.G, "alice", KeyProtectorPassphrase); err != nil {
+		t.Fatal(err)
+	}
+	if name := CurrentKeyProtector(tc.G).Name(); name != KeyProtectorPassphrase {
+		t.Errorf("protector after resetting it: %s", name)
+	}
+	if _, err := NewKeyProtector(tc.G, "tpm", "alice"); err == nil {
+		t.Error("made an unknown key protector")
+	}
+}
@@ -793,6 +793,26 @@ func (ckf *ComputedKeyFamily) GetSibkeyForDevice(did keybase1.DeviceID) (key Gen
 	return
 }
 
+// GetDeviceETime returns when the given device's keys expire, which is
+// the earlier of the expiration times of its sibkey and encryption subkey.
+func (ckf *ComputedKeyFamily) GetDeviceETime(did keybase1.DeviceID) (ret time.Time, err error) {
+	kid, err := ckf.getSibkeyKidForDevice(did)
+	if err != nil {
+		return ret, err
+	}
+	cki, found := ckf.cki.Infos[kid]
+	if !found {
+		return ret, NoKeyError{fmt.Sprintf("The key '%s' wasn't found", kid)}
+	}
+	ret = cki.GetETime()
+	if sub, found := ckf.cki.Infos[cki.Subkey]; found && sub.ETime > 0 {
+		if subETime := sub.GetETime(); ret.IsZero() || subETime.Before(ret) {
+			ret = subETime
+		}
+	}
+	return ret, nil
+}
+
 // GetCurrentDevice returns the current device.
 func (ckf *ComputedKeyFamily) GetCurrentDevice(g *GlobalContext) (*Device, error) {
 	if g == nil {
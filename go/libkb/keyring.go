@@ -411,10 +411,11 @@ func (k *Keyrings) GetSecretKeyWithPassphrase(lctx LoginContext, me *User, passp
 	var tsec *triplesec.Cipher
 	var pps *PassphraseStream
 	if lctx != nil {
-		tsec = lctx.PassphraseStreamCache().Triplesec()
-		pps = lctx.PassphraseStreamCache().PassphraseStream()
+		sc := lctx.UsePassphraseStreamCache()
+		tsec = sc.Triplesec()
+		pps = sc.PassphraseStream()
 	} else {
-		k.G().LoginState().PassphraseStreamCache(func(sc *PassphraseStreamCache) {
+		k.G().LoginState().UsePassphraseStreamCache(func(sc *PassphraseStreamCache) {
 			tsec = sc.Triplesec()
 			pps = sc.PassphraseStream()
 		}, "StreamCache - tsec, pps")
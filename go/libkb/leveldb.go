@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
 )
 
 type LevelDb struct {
@@ -158,3 +159,105 @@ func (l *LevelDb) Delete(id DbKey) error {
 	err := l.db.Delete(id.ToBytes("kv"), nil)
 	return err
 }
+
+// ForEach calls f with each value stored under typ.
+func (l *LevelDb) ForEach(typ ObjType, f func(id DbKey, value []byte) error) error {
+	// Lazy Open
+	if err := l.open(); err != nil {
+		return err
+	}
+
+	prefix := DbKey{Typ: typ}.ToBytes("kv")
+	iter := l.db.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		_, id, err := DbKeyParse(string(iter.Key()))
+		if err != nil {
+			return err
+		}
+		if err := f(*id, iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// Stats counts the entries in each table of the database, and their
+// size.
+func (l *LevelDb) Stats() ([]DbTableStats, error) {
+	// Lazy Open
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+
+	var ret []DbTableStats
+	index := make(map[string]int)
+	iter := l.db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		table, id, err := DbKeyParse(string(iter.Key()))
+		if err != nil {
+			// Not one of ours; count it anyway.
+			table, id = "", &DbKey{}
+		}
+		k := DbKey{Typ: id.Typ}.ToString(table)
+		i, ok := index[k]
+		if !ok {
+			i = len(ret)
+			index[k] = i
+			ret = append(ret, DbTableStats{Typ: id.Typ, Alias: table == "lo", Unknown: table == ""})
+		}
+		ret[i].Entries++
+		ret[i].Bytes += int64(len(iter.Key()) + len(iter.Value()))
+	}
+	return ret, iter.Error()
+}
+
+// Compact rewrites the whole database, dropping deleted and overwritten
+// values from the files.
+func (l *LevelDb) Compact() error {
+	// Lazy Open
+	if err := l.open(); err != nil {
+		return err
+	}
+
+	return l.db.CompactRange(util.Range{})
+}
+
+// Evict deletes the values that match selects, and the aliases to them.
+// It returns how many values it deleted.
+func (l *LevelDb) Evict(selects func(id DbKey) bool) (int, error) {
+	// Lazy Open
+	if err := l.open(); err != nil {
+		return 0, err
+	}
+
+	batch := new(leveldb.Batch)
+	evicted := make(map[string]bool)
+	iter := l.db.NewIterator(util.BytesPrefix([]byte("kv:")), nil)
+	for iter.Next() {
+		_, id, err := DbKeyParse(string(iter.Key()))
+		if err != nil || !selects(*id) {
+			continue
+		}
+		evicted[string(iter.Key())] = true
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+
+	iter = l.db.NewIterator(util.BytesPrefix([]byte("lo:")), nil)
+	for iter.Next() {
+		if evicted[string(iter.Value())] {
+			batch.Delete(append([]byte{}, iter.Key()...))
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+
+	return len(evicted), l.db.Write(batch, nil)
+}
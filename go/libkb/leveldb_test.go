@@ -0,0 +1,115 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"testing"
+)
+
+func TestLevelDbMaintenance(t *testing.T) {
+	tc := SetupTest(t, "leveldb")
+	defer tc.Cleanup()
+	db := tc.G.LocalDb
+
+	alice, bob := UsernameToUID("t_alice"), UsernameToUID("t_bob")
+	puts := []struct {
+		id      DbKey
+		aliases []DbKey
+	}{
+		{DbKeyUID(DBUser, alice), []DbKey{{Typ: DBLookupUsername, Key: "t_alice"}}},
+		{DbKeyUID(DBUser, bob), []DbKey{{Typ: DBLookupUsername, Key: "t_bob"}}},
+		{DbKeyUID(DBSigHints, alice), nil},
+		{DbKeyUID(DBSigHints, bob), nil},
+		{DbKey{Typ: DBProofCheck, Key: "abcd"}, nil},
+		{DbKey{Typ: DBProofCheck, Key: "ef01"}, nil},
+	}
+	for _, p := range puts {
+		if err := db.engine.Put(p.id, p.aliases, []byte(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := db.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, s := range stats {
+		counts[s.Name()] = s.Entries
+	}
+	for name, n := range map[string]int{"user": 2, "sig-hints": 2, "proof-check": 2, "lookup:username": 2} {
+		if counts[name] != n {
+			t.Errorf("%s has %d entries, expected %d", name, counts[name], n)
+		}
+	}
+
+	var checks []string
+	err = db.ForEach(DBProofCheck, func(id DbKey, _ []byte) error {
+		checks = append(checks, id.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) != 2 || checks[0] != "abcd" || checks[1] != "ef01" {
+		t.Errorf("proof checks: %v", checks)
+	}
+
+	// Evict one table, and one user's values.
+	n, err := db.Evict(func(id DbKey) bool { return id.Typ == DBProofCheck })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("evicted %d proof checks, expected 2", n)
+	}
+	n, err = db.Evict(func(id DbKey) bool { return DbKeyMentionsUID(id.Key, alice) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("evicted %d of alice's values, expected 2", n)
+	}
+
+	if jw, err := db.Lookup(DbKey{Typ: DBLookupUsername, Key: "t_alice"}); err != nil || jw != nil {
+		t.Errorf("alice's username still resolves: %v, %v", jw, err)
+	}
+	if jw, err := db.Lookup(DbKey{Typ: DBLookupUsername, Key: "t_bob"}); err != nil || jw == nil {
+		t.Errorf("bob's username doesn't resolve: %v", err)
+	}
+	if jw, err := db.Get(DbKeyUID(DBSigHints, bob)); err != nil || jw == nil {
+		t.Errorf("bob's sig hints were evicted: %v", err)
+	}
+
+	if err := db.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	stats, err = db.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, s := range stats {
+		total += s.Entries
+	}
+	if total != 3 {
+		t.Errorf("%d entries left, expected bob's 3", total)
+	}
+}
+
+func TestDbKeyMentionsUID(t *testing.T) {
+	uid := UsernameToUID("t_alice")
+	other := UsernameToUID("t_bob")
+	for key, expected := range map[string]bool{
+		uid.String():                        true,
+		uid.String() + "-" + other.String(): true,
+		other.String() + "-" + uid.String(): true,
+		other.String():                      false,
+		"x" + uid.String():                  false,
+	} {
+		if DbKeyMentionsUID(key, uid) != expected {
+			t.Errorf("DbKeyMentionsUID(%q) != %v", key, expected)
+		}
+	}
+}
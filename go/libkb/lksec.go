@@ -96,6 +96,7 @@ func (s *LKSec) Load(lctx LoginContext) (err error) {
 
 	if s.secret != nil {
 		s.G().Log.Debug("| Short-circuit; we already know the full secret")
+		s.unlockLocalDb()
 		return nil
 	}
 
@@ -132,10 +133,22 @@ func (s *LKSec) Load(lctx LoginContext) (err error) {
 	s.secret = make([]byte, len(s.serverHalf))
 	XORBytes(s.secret, s.serverHalf, s.clientHalf)
 	s.G().Log.Debug("| Making XOR'ed secret key for Local Key Security (LKS): ServerHalf=%x; clientHalf=%x", s.serverHalf, s.clientHalf)
+	s.unlockLocalDb()
 
 	return nil
 }
 
+// unlockLocalDb unlocks the local database with the secret, which only
+// the logged-in user's device can have.
+func (s *LKSec) unlockLocalDb() {
+	if s.uid.IsNil() || s.G().LocalDb == nil {
+		return
+	}
+	if err := s.G().LocalDb.Unlock(s.uid, s.secret); err != nil {
+		s.G().Log.Warning("Error unlocking the local db: %s", err)
+	}
+}
+
 func (s *LKSec) GetSecret(lctx LoginContext) (secret []byte, err error) {
 	s.G().Log.Debug("+ LKsec:GetSecret()")
 	defer func() {
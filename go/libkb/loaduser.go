@@ -159,6 +159,18 @@ func LoadUser(arg LoadUserArg) (ret *User, err error) {
 		return
 	}
 
+	// Our own signatures that are waiting to be posted come after
+	// the stored chain.
+	if arg.Self && arg.G().IsOffline() {
+		var q *OfflineQueue
+		if q, err = LoadOfflineQueue(arg.G(), ret.GetUID()); err != nil {
+			return
+		}
+		if err = q.applyTo(ret); err != nil {
+			return
+		}
+	}
+
 	if ret.sigHints, err = LoadAndRefreshSigHints(ret.id, arg.G()); err != nil {
 		return
 	}
@@ -202,7 +214,15 @@ func loadUser(g *GlobalContext, uid keybase1.UID, rres ResolveResult, force bool
 		g.Log.Warning("Failed to load %s from storage: %s", uid, err)
 	}
 
-	leaf, err := lookupMerkleLeaf(g, uid, local)
+	// Offline, all we have is what we stored, so the leaf vouches for
+	// that and we never load from the server.
+	offline := g.IsOffline()
+	var leaf *MerkleUserLeaf
+	if offline {
+		leaf, err = offlineMerkleLeaf(g, local)
+	} else {
+		leaf, err = lookupMerkleLeaf(g, uid, local)
+	}
 	if err != nil {
 		return nil, refresh, err
 	}
@@ -222,7 +242,7 @@ func loadUser(g *GlobalContext, uid keybase1.UID, rres ResolveResult, force bool
 	g.Log.Debug("| Freshness: basics=%v; for %s", f1, uid)
 
 	var ret *User
-	if !loadRemote && !force {
+	if !loadRemote && (!force || offline) {
 		ret = local
 	} else if ret, err = loadUserFromServer(g, uid, rres.body); err != nil {
 		return nil, refresh, err
@@ -46,6 +46,7 @@ type LoginContext interface {
 	CreateStreamCache(tsec *triplesec.Cipher, pps *PassphraseStream)
 	CreateStreamCacheViaStretch(passphrase string) error
 	PassphraseStreamCache() *PassphraseStreamCache
+	UsePassphraseStreamCache() *PassphraseStreamCache
 	ClearStreamCache()
 	SetStreamGeneration(gen PassphraseGeneration, nilPPStreamOK bool)
 	GetStreamGeneration() PassphraseGeneration
@@ -68,6 +69,7 @@ type LoginContext interface {
 
 	CachedSecretKey(ska SecretKeyArg) (GenericKey, error)
 	SetCachedSecretKey(ska SecretKeyArg, key GenericKey) error
+	ClearCachedSecretKeys()
 }
 
 type loginHandler func(LoginContext) error
@@ -875,12 +877,47 @@ func (s *LoginState) Account(h acctHandler, name string) error {
 	return s.acctHandle(h, name)
 }
 
+// LockSecrets zeroes the cached passphrase stream and forgets the
+// unlocked secret keys, so that the passphrase is needed again to use
+// them.  The user stays logged in.
+func (s *LoginState) LockSecrets() error {
+	return s.Account(func(a *Account) {
+		a.LockSecrets()
+	}, "LockSecrets")
+}
+
+// ExpireSecrets locks secrets if they've gone unused for longer than the
+// configured idle timeout, or have been cached for longer than the
+// configured maximum lifetime.  It says whether it did.
+func (s *LoginState) ExpireSecrets() (locked bool, err error) {
+	idle := s.G().Env.GetSecretCacheIdleTimeout()
+	maxLifetime := s.G().Env.GetSecretCacheMaxLifetime()
+	if idle == 0 && maxLifetime == 0 {
+		return false, nil
+	}
+	err = s.acctHandle(func(a *Account) {
+		if a.SecretsExpired(time.Now(), idle, maxLifetime) {
+			a.LockSecrets()
+			locked = true
+		}
+	}, "ExpireSecrets")
+	return locked, err
+}
+
 func (s *LoginState) PassphraseStreamCache(h func(*PassphraseStreamCache), name string) error {
 	return s.Account(func(a *Account) {
 		h(a.PassphraseStreamCache())
 	}, name)
 }
 
+// UsePassphraseStreamCache is PassphraseStreamCache for unlocking a secret
+// key with the cached stream; see Account.UsePassphraseStreamCache.
+func (s *LoginState) UsePassphraseStreamCache(h func(*PassphraseStreamCache), name string) error {
+	return s.Account(func(a *Account) {
+		h(a.UsePassphraseStreamCache())
+	}, name)
+}
+
 func (s *LoginState) LocalSession(h func(*Session), name string) error {
 	return s.Account(func(a *Account) {
 		h(a.LocalSession())
@@ -400,6 +400,21 @@ func (mc *MerkleClient) VerifyRoot(root *MerkleRoot) error {
 		return nil
 	}
 
+	if err := mc.verifyRootSig(root); err != nil {
+		return err
+	}
+
+	if e2 := root.Store(); e2 != nil {
+		mc.G().Log.Errorf("Cannot commit Merkle root to local DB: %s", e2)
+	}
+
+	mc.verified[root.seqno] = true
+
+	return nil
+}
+
+// verifyRootSig checks the server's signature of root.
+func (mc *MerkleClient) verifyRootSig(root *MerkleRoot) error {
 	kid, sig, err := mc.findValidKIDAndSig(root)
 	if err != nil {
 		return err
@@ -419,17 +434,17 @@ func (mc *MerkleClient) VerifyRoot(root *MerkleRoot) error {
 
 	// Actually run the PGP verification over the signature
 	_, err = key.VerifyString(sig, []byte(root.payloadJSONString))
+	return err
+}
+
+// VerifyStoredRoot checks that a root from the local database still
+// parses, and that the server's signature of it still verifies.
+func (mc *MerkleClient) VerifyStoredRoot(jw *jsonw.Wrapper) error {
+	root, err := NewMerkleRootFromJSON(jw, mc.G())
 	if err != nil {
 		return err
 	}
-
-	if e2 := root.Store(); e2 != nil {
-		mc.G().Log.Errorf("Cannot commit Merkle root to local DB: %s", e2)
-	}
-
-	mc.verified[root.seqno] = true
-
-	return nil
+	return mc.verifyRootSig(root)
 }
 
 func parseTriple(jw *jsonw.Wrapper) (*MerkleTriple, error) {
@@ -692,6 +707,7 @@ func (path PathSteps) VerifyPath(curr NodeHash, uidS string) (juser *jsonw.Wrapp
 func (mc *MerkleClient) LookupUser(q HTTPArgs) (u *MerkleUserLeaf, err error) {
 
 	mc.G().Log.Debug("+ MerkleClient.LookupUser(%v)", q)
+	defer mc.G().Stats.Start(keybase1.StatCategory_MERKLE, "LookupUser")(&err)
 
 	var path *VerificationPath
 
@@ -240,6 +240,9 @@ func (k NaclSigningKeyPair) CheckSecretKey() error {
 	if k.Private == nil {
 		return NoSecretKeyError{}
 	}
+	if *k.Private == (NaclSigningKeyPrivate{}) {
+		return SecretKeyScrubbedError{}
+	}
 	return nil
 }
 
@@ -259,6 +262,9 @@ func (k NaclDHKeyPair) CheckSecretKey() error {
 	if k.Private == nil {
 		return NoSecretKeyError{}
 	}
+	if *k.Private == (NaclDHKeyPrivate{}) {
+		return SecretKeyScrubbedError{}
+	}
 	return nil
 }
 
@@ -269,9 +275,26 @@ func (k NaclDHKeyPair) HasSecretKey() bool {
 func (k NaclSigningKeyPair) CanSign() bool { return k.Private != nil }
 func (k NaclDHKeyPair) CanSign() bool      { return false }
 
+// scrubSecretKey zeroes the secret half of k, if it's a NaCl key.  Every
+// copy of k shares it, so an engine still holding one gets a
+// SecretKeyScrubbedError from CheckSecretKey, which guards every use of
+// the secret half, rather than signing or decrypting with a zero key.
+// No real key is all zero.
+func scrubSecretKey(k GenericKey) {
+	switch k := k.(type) {
+	case NaclSigningKeyPair:
+		if k.Private != nil {
+			*k.Private = NaclSigningKeyPrivate{}
+		}
+	case NaclDHKeyPair:
+		if k.Private != nil {
+			*k.Private = NaclDHKeyPrivate{}
+		}
+	}
+}
+
 func (k NaclSigningKeyPair) Sign(msg []byte) (ret *NaclSigInfo, err error) {
-	if k.Private == nil {
-		err = NoSecretKeyError{}
+	if err = k.CheckSecretKey(); err != nil {
 		return
 	}
 	ret = &NaclSigInfo{
@@ -420,6 +443,9 @@ func (k NaclDHKeyPair) ToServerSKB(gc *GlobalContext, t *triplesec.Cipher, gen P
 }
 
 func (k NaclSigningKeyPair) ToLksSKB(lks *LKSec) (*SKB, error) {
+	if err := k.CheckSecretKey(); err != nil {
+		return nil, err
+	}
 	data, err := lks.Encrypt(k.Private[:])
 	if err != nil {
 		return nil, err
@@ -434,6 +460,9 @@ func (k NaclSigningKeyPair) ToLksSKB(lks *LKSec) (*SKB, error) {
 }
 
 func (k NaclDHKeyPair) ToLksSKB(lks *LKSec) (*SKB, error) {
+	if err := k.CheckSecretKey(); err != nil {
+		return nil, err
+	}
 	data, err := lks.Encrypt(k.Private[:])
 	if err != nil {
 		return nil, err
@@ -574,8 +603,8 @@ func (k NaclDHKeyPair) Encrypt(msg []byte, sender *NaclDHKeyPair) (*NaclEncrypti
 		} else {
 			return nil, err
 		}
-	} else if sender.Private == nil {
-		return nil, NoSecretKeyError{}
+	} else if err := sender.CheckSecretKey(); err != nil {
+		return nil, err
 	}
 
 	var nonce [NaclDHNonceSize]byte
@@ -649,8 +678,7 @@ func (k NaclDHKeyPair) DecryptFromString(ciphertext string) (msg []byte, sender
 // Decrypt a NaclEncryptionInfo packet, and on success return the plaintext
 // and the KID of the sender (which might be an ephemeral key).
 func (k NaclDHKeyPair) Decrypt(nei *NaclEncryptionInfo) (plaintext []byte, sender keybase1.KID, err error) {
-	if k.Private == nil {
-		err = NoSecretKeyError{}
+	if err = k.CheckSecretKey(); err != nil {
 		return
 	}
 	if nei.EncryptionType != KIDNaclDH {
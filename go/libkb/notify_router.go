@@ -137,6 +137,61 @@ func (n *NotifyRouter) HandleUserChanged(uid keybase1.UID) {
 	})
 }
 
+// HandleSigchainRollback is called whenever a user's sigchain looks
+// like it was rolled back. It will broadcast the messages to all curious
+// listeners.
+func (n *NotifyRouter) HandleSigchainRollback(uid keybase1.UID, username, reason string) {
+	if n == nil {
+		return
+	}
+	// For all connections we currently have open...
+	n.cm.ApplyAll(func(id ConnectionID, xp rpc.Transporter) bool {
+		// If the connection wants the `Users` notification type
+		if n.getNotificationChannels(id).Users {
+			// In the background do...
+			go func() {
+				// A send of a `SigchainRollback` RPC
+				(keybase1.NotifyUsersClient{
+					Cli: rpc.NewClient(xp, ErrorUnwrapper{}),
+				}).SigchainRollback(context.TODO(), keybase1.SigchainRollbackArg{
+					Uid:      uid,
+					Username: username,
+					Reason:   reason,
+				})
+			}()
+		}
+		return true
+	})
+}
+
+// HandleTrackedUserReset is called whenever an identify finds that a
+// tracked user reset their account since they were tracked. It will
+// broadcast the messages to all curious listeners.
+func (n *NotifyRouter) HandleTrackedUserReset(uid keybase1.UID, username string, trackedEldest, eldest keybase1.KID) {
+	if n == nil {
+		return
+	}
+	// For all connections we currently have open...
+	n.cm.ApplyAll(func(id ConnectionID, xp rpc.Transporter) bool {
+		// If the connection wants the `Users` notification type
+		if n.getNotificationChannels(id).Users {
+			// In the background do...
+			go func() {
+				// A send of a `TrackedUserReset` RPC
+				(keybase1.NotifyUsersClient{
+					Cli: rpc.NewClient(xp, ErrorUnwrapper{}),
+				}).TrackedUserReset(context.TODO(), keybase1.TrackedUserResetArg{
+					Uid:           uid,
+					Username:      username,
+					TrackedEldest: trackedEldest,
+					Eldest:        eldest,
+				})
+			}()
+		}
+		return true
+	})
+}
+
 // HandleFSActivity is called for any KBFS notification. It will broadcast the messages
 // to all curious listeners.
 func (n *NotifyRouter) HandleFSActivity(activity keybase1.FSNotification) {
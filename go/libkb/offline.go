@@ -0,0 +1,487 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+	jsonw "github.com/keybase/go-jsonw"
+)
+
+// IsOffline is true if we're in offline mode, in which we never contact
+// the Keybase server: users and their sig chains are loaded from local
+// storage, and new signatures are queued until we're back online.
+func (g *GlobalContext) IsOffline() bool {
+	g = g.root()
+	g.offlineMu.RLock()
+	defer g.offlineMu.RUnlock()
+	if g.offline != nil {
+		return *g.offline
+	}
+	return g.Env != nil && g.Env.GetOffline()
+}
+
+// SetOffline switches offline mode on or off, overriding the config.  The
+// caller should flush the queue of offline signatures (with
+// FlushOfflineQueue) after going back online.
+func (g *GlobalContext) SetOffline(offline bool) {
+	g = g.root()
+	g.offlineMu.Lock()
+	defer g.offlineMu.Unlock()
+	g.offline = &offline
+}
+
+// offlineMerkleLeaf stands in for the server's Merkle leaf when we're
+// offline.  It vouches for exactly what we have stored, so the sig chain
+// loader takes the stored chain as current.
+func offlineMerkleLeaf(g *GlobalContext, local *User) (*MerkleUserLeaf, error) {
+	if local == nil {
+		return nil, OfflineError{}
+	}
+	idVersion, err := local.GetIDVersion()
+	if err != nil {
+		return nil, err
+	}
+	leaf := &MerkleUserLeaf{
+		idVersion: idVersion,
+		username:  local.GetName(),
+		uid:       local.GetUID(),
+	}
+
+	var tail MerkleTriple
+	found, err := g.LocalDb.GetInto(&tail, DbKeyUID(DBSigChainTailPublic, local.GetUID()))
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return leaf, nil
+	}
+	leaf.public = &tail
+	link, err := ImportLinkFromStorage(tail.LinkID, keybase1.UID(""), g)
+	if err != nil {
+		return nil, err
+	}
+	if link != nil {
+		leaf.eldest = link.ToEldestKID()
+	}
+	return leaf, nil
+}
+
+// resolveUsernameLocally finds the UID of a Keybase user we have stored,
+// for when we can't ask the server.  Other assertions can't be resolved
+// offline.
+func resolveUsernameLocally(au AssertionURL) (res ResolveResult) {
+	if !au.IsKeybase() {
+		res.err = OfflineError{}
+		return
+	}
+	res.kbUsername = au.GetValue()
+	jw, err := G.LocalDb.Lookup(DbKey{Typ: DBLookupUsername, Key: res.kbUsername})
+	if err != nil {
+		res.err = err
+		return
+	}
+	if jw == nil {
+		res.err = NotFoundError{msg: fmt.Sprintf("%s isn't stored locally, and we're offline", au)}
+		return
+	}
+	res.uid, res.err = GetUID(jw.AtKey("id"))
+	return
+}
+
+// OfflineOp is a signature made while offline, kept in the local db until
+// it can be posted.  It records the chain tail it was signed on top of, so
+// that we can tell if the chain moved on in the meantime.
+type OfflineOp struct {
+	Type       string            `json:"type"`
+	Username   string            `json:"username"`
+	UID        keybase1.UID      `json:"uid"`
+	Args       map[string]string `json:"args"`
+	Seqno      Seqno             `json:"seqno"`
+	Prev       string            `json:"prev"`
+	LinkID     string            `json:"link_id"`
+	SigID      keybase1.SigID    `json:"sig_id"`
+	CTime      int64             `json:"ctime"`
+	LocalTrack bool              `json:"local_track,omitempty"` // a local track stands in until it's posted
+	Conflict   string            `json:"conflict,omitempty"`
+}
+
+// NewOfflineOp makes an OfflineOp for a signature of the given statement,
+// which will be posted to the follow endpoint with the given args.
+func NewOfflineOp(typ string, them *User, statement []byte, sigID keybase1.SigID, args HTTPArgs) (op OfflineOp, err error) {
+	jw, err := jsonw.Unmarshal(statement)
+	if err != nil {
+		return op, err
+	}
+	seqno, err := jw.AtKey("seqno").GetInt64()
+	if err != nil {
+		return op, err
+	}
+	if prev := jw.AtKey("prev"); !prev.IsNil() {
+		if op.Prev, err = prev.GetString(); err != nil {
+			return op, err
+		}
+	}
+	op.Type = typ
+	op.Username = them.GetName()
+	op.UID = them.GetUID()
+	op.Seqno = Seqno(seqno)
+	op.LinkID = ComputeLinkID(statement).String()
+	op.SigID = sigID
+	op.CTime = time.Now().Unix()
+	op.Args = make(map[string]string)
+	for k, v := range args {
+		op.Args[k] = v.String()
+	}
+	return op, nil
+}
+
+// offlineFavoriteAdd is the type of a queued favorite folder, which
+// isn't a signature, so it isn't tied to the sig chain and can't conflict.
+const offlineFavoriteAdd = "favorite_add"
+
+// NewOfflineFavoriteOp makes an OfflineOp that adds folder to the
+// favorites once we're back online.
+func NewOfflineFavoriteOp(folder keybase1.Folder) OfflineOp {
+	return OfflineOp{
+		Type:     offlineFavoriteAdd,
+		Username: folder.Name,
+		Args: map[string]string{
+			"name":          folder.Name,
+			"private":       fmt.Sprintf("%t", folder.Private),
+			"notifications": fmt.Sprintf("%t", folder.NotificationsOn),
+		},
+		CTime: time.Now().Unix(),
+	}
+}
+
+func (op OfflineOp) pending() bool { return len(op.Conflict) == 0 }
+
+func (op OfflineOp) isSig() bool { return op.Type != offlineFavoriteAdd }
+
+func (op OfflineOp) folder() keybase1.Folder {
+	return keybase1.Folder{
+		Name:            op.Args["name"],
+		Private:         op.Args["private"] == "true",
+		NotificationsOn: op.Args["notifications"] == "true",
+	}
+}
+
+func (op OfflineOp) merkleTriple() (MerkleTriple, error) {
+	linkID, err := LinkIDFromHex(op.LinkID)
+	if err != nil {
+		return MerkleTriple{}, err
+	}
+	return MerkleTriple{Seqno: op.Seqno, LinkID: linkID, SigID: op.SigID}, nil
+}
+
+func (op OfflineOp) Export() keybase1.OfflineOp {
+	return keybase1.OfflineOp{
+		Type:     op.Type,
+		Username: op.Username,
+		Seqno:    int(op.Seqno),
+		SigID:    op.SigID,
+		Ctime:    keybase1.TimeFromSeconds(op.CTime),
+		Conflict: op.Conflict,
+	}
+}
+
+// OfflineQueue is the list of offline signatures of one user, oldest
+// first.
+type OfflineQueue struct {
+	Contextified
+	uid keybase1.UID
+	Ops []OfflineOp `json:"ops"`
+}
+
+func offlineQueueDbKey(uid keybase1.UID) DbKey {
+	return DbKeyUID(DBOfflineQueue, uid)
+}
+
+// LoadOfflineQueue loads uid's queue of offline signatures.  It's empty
+// if there isn't one.
+func LoadOfflineQueue(g *GlobalContext, uid keybase1.UID) (*OfflineQueue, error) {
+	q := &OfflineQueue{Contextified: NewContextified(g), uid: uid}
+	if _, err := g.LocalDb.GetInto(q, offlineQueueDbKey(uid)); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (q *OfflineQueue) store() error {
+	if len(q.Ops) == 0 {
+		return q.G().LocalDb.Delete(offlineQueueDbKey(q.uid))
+	}
+	return q.G().LocalDb.PutObj(offlineQueueDbKey(q.uid), nil, q)
+}
+
+// applyTo bumps u's sig chain past the pending signatures, so that the
+// next signature is made on top of them.
+func (q *OfflineQueue) applyTo(u *User) error {
+	for _, op := range q.Ops {
+		if !op.pending() || !op.isSig() {
+			continue
+		}
+		mt, err := op.merkleTriple()
+		if err != nil {
+			return err
+		}
+		u.SigChainBumpMT(mt)
+	}
+	return nil
+}
+
+// QueueOfflineOp adds op to me's queue of offline signatures, and bumps
+// me's sig chain past it.
+func QueueOfflineOp(g *GlobalContext, me *User, op OfflineOp) error {
+	g.root().offlineQueueMu.Lock()
+	defer g.root().offlineQueueMu.Unlock()
+
+	q, err := LoadOfflineQueue(g, me.GetUID())
+	if err != nil {
+		return err
+	}
+	mt, err := op.merkleTriple()
+	if err != nil {
+		return err
+	}
+	q.Ops = append(q.Ops, op)
+	if err := q.store(); err != nil {
+		return err
+	}
+	me.SigChainBumpMT(mt)
+	g.Log.Info("Offline: queued %s of %s; it will be posted when we're back online", op.Type, op.Username)
+	return nil
+}
+
+// QueueOfflineFavorite adds folder to the current user's queue, to be
+// added to the favorites once we're back online.  Until then it's in the
+// favorites cache, so it's listed already.
+func QueueOfflineFavorite(g *GlobalContext, folder keybase1.Folder) error {
+	uid := g.GetMyUID()
+	if uid.IsNil() {
+		return LoginRequiredError{}
+	}
+
+	g.root().offlineQueueMu.Lock()
+	defer g.root().offlineQueueMu.Unlock()
+
+	q, err := LoadOfflineQueue(g, uid)
+	if err != nil {
+		return err
+	}
+	q.Ops = append(q.Ops, NewOfflineFavoriteOp(folder))
+	if err := q.store(); err != nil {
+		return err
+	}
+	g.FavoriteCache.Add(folder)
+	g.Log.Info("Offline: queued favorite %s; it will be added when we're back online", folder.Name)
+	return nil
+}
+
+// DropOfflineFavorite removes folder from the current user's queue, so
+// that a favorite deleted while offline isn't added back on reconnect.
+func DropOfflineFavorite(g *GlobalContext, folder keybase1.Folder) error {
+	uid := g.GetMyUID()
+	if uid.IsNil() {
+		return nil
+	}
+
+	g.root().offlineQueueMu.Lock()
+	defer g.root().offlineQueueMu.Unlock()
+
+	q, err := LoadOfflineQueue(g, uid)
+	if err != nil {
+		return err
+	}
+	var kept []OfflineOp
+	for _, op := range q.Ops {
+		if op.Type == offlineFavoriteAdd && op.folder() == folder {
+			continue
+		}
+		kept = append(kept, op)
+	}
+	if len(kept) == len(q.Ops) {
+		return nil
+	}
+	q.Ops = kept
+	return q.store()
+}
+
+// FlushOfflineQueue posts the current user's queued signatures, oldest
+// first, and adds the queued favorites.  If the server's sig chain tail isn't the one a signature was
+// made on top of (say, because another device signed something in the
+// meantime), that signature and the ones after it are marked as
+// conflicting and left in the queue, since they have to be done again.
+func FlushOfflineQueue(g *GlobalContext) (err error) {
+	if g.IsOffline() {
+		return OfflineError{}
+	}
+	uid := g.GetMyUID()
+	if uid.IsNil() {
+		return nil
+	}
+
+	g.root().offlineQueueMu.Lock()
+	defer g.root().offlineQueueMu.Unlock()
+
+	q, err := LoadOfflineQueue(g, uid)
+	if err != nil {
+		return err
+	}
+
+	// Favorites don't depend on the sig chain, so they go first.
+	var sigs, pending []OfflineOp
+	for _, op := range q.Ops {
+		if op.isSig() {
+			sigs = append(sigs, op)
+			if op.pending() {
+				pending = append(pending, op)
+			}
+			continue
+		}
+		g.FavoriteCache.Add(op.folder())
+		g.Log.Info("Added favorite %s, queued while offline", op.Username)
+	}
+	if len(sigs) < len(q.Ops) {
+		q.Ops = sigs
+		if err := q.store(); err != nil {
+			return err
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	g.Log.Debug("+ FlushOfflineQueue: %d queued", len(pending))
+	defer func() { g.Log.Debug("- FlushOfflineQueue -> %s", ErrToOk(err)) }()
+
+	me, err := LoadMe(NewLoadUserForceArg(g))
+	if err != nil {
+		return err
+	}
+
+	var kept []OfflineOp
+	conflict := ""
+	for _, op := range q.Ops {
+		if !op.pending() {
+			kept = append(kept, op)
+			continue
+		}
+		if len(conflict) > 0 || err != nil {
+			if len(conflict) > 0 {
+				op.Conflict = conflict
+			}
+			kept = append(kept, op)
+			continue
+		}
+
+		seqno := me.sigChain().GetLastKnownSeqno()
+		var prev string
+		if last := me.sigChain().GetLastKnownID(); last != nil {
+			prev = last.String()
+		}
+		if op.Seqno != seqno+1 || op.Prev != prev {
+			conflict = fmt.Sprintf("the sig chain moved on while offline (signed at seqno %d, the server is at %d)", op.Seqno-1, seqno)
+			g.Log.Warning("Offline %s of %s conflicts: %s", op.Type, op.Username, conflict)
+			op.Conflict = conflict
+			kept = append(kept, op)
+			continue
+		}
+
+		args := NewHTTPArgs()
+		for k, v := range op.Args {
+			args[k] = S{Val: v}
+		}
+		_, perr := g.API.Post(APIArg{
+			Endpoint:     "follow",
+			NeedSession:  true,
+			Args:         args,
+			Contextified: NewContextified(g),
+		})
+		if perr != nil {
+			if _, ok := perr.(APINetError); ok {
+				// Still can't reach the server; try again later.
+				err = perr
+			} else {
+				conflict = perr.Error()
+				g.Log.Warning("Offline %s of %s was rejected: %s", op.Type, op.Username, conflict)
+				op.Conflict = conflict
+			}
+			kept = append(kept, op)
+			continue
+		}
+
+		mt, merr := op.merkleTriple()
+		if merr != nil {
+			return merr
+		}
+		me.SigChainBumpMT(mt)
+		if op.LocalTrack {
+			if lerr := RemoveLocalTrack(uid, op.UID, g); lerr != nil {
+				g.Log.Warning("Failed to remove local track of %s: %s", op.Username, lerr)
+			}
+		}
+		g.Log.Info("Posted %s of %s, signed while offline", op.Type, op.Username)
+	}
+
+	q.Ops = kept
+	if serr := q.store(); serr != nil && err == nil {
+		err = serr
+	}
+	g.NotifyRouter.HandleUserChanged(uid)
+	return err
+}
+
+// DiscardOfflineConflicts drops the current user's queued signatures
+// that conflicted with the server, along with the local tracks that
+// stood in for them.
+func DiscardOfflineConflicts(g *GlobalContext) error {
+	uid := g.GetMyUID()
+	if uid.IsNil() {
+		return LoginRequiredError{}
+	}
+
+	g.root().offlineQueueMu.Lock()
+	defer g.root().offlineQueueMu.Unlock()
+
+	q, err := LoadOfflineQueue(g, uid)
+	if err != nil {
+		return err
+	}
+	var kept []OfflineOp
+	for _, op := range q.Ops {
+		if op.pending() {
+			kept = append(kept, op)
+			continue
+		}
+		if op.LocalTrack {
+			if err := RemoveLocalTrack(uid, op.UID, g); err != nil {
+				return err
+			}
+		}
+	}
+	q.Ops = kept
+	return q.store()
+}
+
+// OfflineStatus exports whether we're offline and the current user's
+// queue of offline signatures.
+func OfflineStatus(g *GlobalContext) (keybase1.OfflineStatus, error) {
+	ret := keybase1.OfflineStatus{Offline: g.IsOffline()}
+	uid := g.GetMyUID()
+	if uid.IsNil() {
+		return ret, nil
+	}
+	q, err := LoadOfflineQueue(g, uid)
+	if err != nil {
+		return ret, err
+	}
+	for _, op := range q.Ops {
+		ret.Queue = append(ret.Queue, op.Export())
+	}
+	return ret, nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+func TestOfflineNoServer(t *testing.T) {
+	tc := SetupTest(t, "offline")
+	defer tc.Cleanup()
+	g := tc.G
+
+	g.SetOffline(true)
+	if !g.IsOffline() {
+		t.Fatal("expected to be offline")
+	}
+	_, err := g.API.Get(APIArg{Endpoint: "merkle/root", Contextified: NewContextified(g)})
+	if _, ok := err.(OfflineError); !ok {
+		t.Fatalf("API call while offline: got %v, expected an OfflineError", err)
+	}
+	if _, ok := FlushOfflineQueue(g).(OfflineError); !ok {
+		t.Fatal("flushing the queue while offline should fail")
+	}
+
+	g.SetOffline(false)
+	if g.IsOffline() {
+		t.Fatal("expected to be online")
+	}
+}
+
+func TestOfflineQueue(t *testing.T) {
+	tc := SetupTest(t, "offline")
+	defer tc.Cleanup()
+	g := tc.G
+
+	fakeLogin(t, g, NewNormalizedUsername("t_offline"), 1)
+	uid := g.GetMyUID()
+
+	statement := []byte(`{"seqno":7,"prev":"aabbcc","body":{"type":"track"}}`)
+	args := HTTPArgs{"type": S{Val: "track"}, "sig": S{Val: "sig"}}
+	them := NewUserThin("t_alice", keybase1.UID("295a7eea607af32040647123732bc819"))
+	pending, err := NewOfflineOp("track", them, statement, keybase1.SigID("aa"), args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Seqno != 7 || pending.Prev != "aabbcc" || pending.Args["sig"] != "sig" {
+		t.Fatalf("bad op: %+v", pending)
+	}
+	conflict := pending
+	conflict.Type = "untrack"
+	conflict.LinkID = "ddeeff"
+	conflict.Conflict = "the sig chain moved on"
+
+	q, err := LoadOfflineQueue(g, uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Ops = []OfflineOp{pending, conflict}
+	if err := q.store(); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := OfflineStatus(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Queue) != 2 || status.Queue[1].Conflict != conflict.Conflict {
+		t.Fatalf("bad status: %+v", status)
+	}
+
+	if err := DiscardOfflineConflicts(g); err != nil {
+		t.Fatal(err)
+	}
+	q, err = LoadOfflineQueue(g, uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Ops) != 1 || q.Ops[0].LinkID != pending.LinkID {
+		t.Fatalf("after discarding conflicts: %+v", q.Ops)
+	}
+}
+
+func TestOfflineFavorite(t *testing.T) {
+	tc := SetupTest(t, "offline")
+	defer tc.Cleanup()
+	g := tc.G
+
+	fakeLogin(t, g, NewNormalizedUsername("t_offline"), 1)
+	g.SetOffline(true)
+
+	bob := keybase1.Folder{Name: "t_offline,t_bob", Private: true}
+	charlie := keybase1.Folder{Name: "t_offline,t_charlie"}
+	for _, f := range []keybase1.Folder{bob, charlie} {
+		if err := QueueOfflineFavorite(g, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := len(g.FavoriteCache.List()); n != 2 {
+		t.Errorf("cache len: %d, expected 2", n)
+	}
+	status, err := OfflineStatus(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status.Queue) != 2 || status.Queue[0].Type != offlineFavoriteAdd || status.Queue[0].Username != bob.Name {
+		t.Fatalf("bad status: %+v", status)
+	}
+
+	// A favorite deleted while offline isn't added on reconnect.
+	if err := DropOfflineFavorite(g, charlie); err != nil {
+		t.Fatal(err)
+	}
+	g.FavoriteCache.Delete(charlie)
+
+	// Simulate a restart, which loses the cache but not the queue.
+	g.FavoriteCache.Delete(bob)
+	g.SetOffline(false)
+	if err := FlushOfflineQueue(g); err != nil {
+		t.Fatal(err)
+	}
+	list := g.FavoriteCache.List()
+	if len(list) != 1 || list[0] != bob {
+		t.Errorf("favorites after flushing: %+v, expected only %+v", list, bob)
+	}
+	q, err := LoadOfflineQueue(g, g.GetMyUID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Ops) != 0 {
+		t.Errorf("queue after flushing: %+v, expected it empty", q.Ops)
+	}
+}
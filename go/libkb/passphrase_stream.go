@@ -95,6 +95,16 @@ func (ps *PassphraseStream) Clone() *PassphraseStream {
 	}
 }
 
+// Scrub zeroes the stream, for when it's no longer needed.
+func (ps *PassphraseStream) Scrub() {
+	if ps == nil {
+		return
+	}
+	for i := range ps.stream {
+		ps.stream[i] = 0
+	}
+}
+
 func (ps PassphraseStream) Export() keybase1.PassphraseStream {
 	return keybase1.PassphraseStream{
 		PassphraseStream: ps.stream,
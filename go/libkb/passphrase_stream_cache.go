@@ -20,10 +20,11 @@ type PassphraseStreamCacheReader interface {
 	Valid() bool
 }
 
+// NewPassphraseStreamCache caches a copy of ps, which Clear zeroes.
 func NewPassphraseStreamCache(tsec *triplesec.Cipher, ps *PassphraseStream) *PassphraseStreamCache {
 	return &PassphraseStreamCache{
 		tsec:             tsec,
-		passphraseStream: ps,
+		passphraseStream: ps.Clone(),
 	}
 }
 
@@ -65,6 +66,7 @@ func (s *PassphraseStreamCache) Clear() {
 	}
 	s.tsec.Scrub()
 	s.tsec = nil
+	s.passphraseStream.Scrub()
 	s.passphraseStream = nil
 }
 
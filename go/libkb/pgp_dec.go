@@ -82,7 +82,11 @@ func PGPDecrypt(source io.Reader, sink io.Writer, kr openpgp.KeyRing) (*Signatur
 		if md.SignedBy != nil {
 			status.Entity = md.SignedBy.Entity
 		}
-		if md.SignatureError != nil {
+		if md.SignedBy == nil {
+			// openpgp doesn't check signatures by keys it can't find, and
+			// says nothing about them.
+			status.SignatureError = PGPUnknownSignerError{KeyID: md.SignedByKeyId}
+		} else if md.SignatureError != nil {
 			status.SignatureError = md.SignatureError
 		} else {
 			status.Verified = true
@@ -107,6 +111,9 @@ func pgpDecryptClearsign(source io.Reader, sink io.Writer, kr openpgp.KeyRing) (
 	}
 
 	signer, err := openpgp.CheckDetachedSignature(kr, bytes.NewReader(b.Bytes), b.ArmoredSignature.Body)
+	if err == errors.ErrUnknownIssuer {
+		return nil, PGPUnknownSignerError{}
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Check sig error: %s", err)
 	}
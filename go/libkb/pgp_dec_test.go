@@ -37,3 +37,37 @@ func TestPGPDecryptBasic(t *testing.T) {
 		t.Errorf("decoded: %q, expected %q", dec, msg)
 	}
 }
+
+func TestPGPDecryptUnknownSigner(t *testing.T) {
+	tc := SetupTest(t, "pgp_decrypt")
+	defer tc.Cleanup()
+	signer, err := tc.MakePGPKey("signer@keybase.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := tc.MakePGPKey("recipient@keybase.io")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mid := NewBufferCloser()
+	if err := PGPEncrypt(strings.NewReader("who sent this?"), mid, signer, []*PGPKeyBundle{recipient}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Decrypt without the signer's key.
+	status, err := PGPDecryptWithBundles(mid, NewBufferCloser(), []*PGPKeyBundle{recipient})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.IsSigned || status.Verified || status.Entity != nil {
+		t.Fatalf("bad signature status: %+v", status)
+	}
+	serr, ok := status.SignatureError.(PGPUnknownSignerError)
+	if !ok {
+		t.Fatalf("signature error: %v (%T), expected PGPUnknownSignerError", status.SignatureError, status.SignatureError)
+	}
+	if serr.KeyID != signer.PrimaryKey.KeyId {
+		t.Errorf("unknown signer key ID: %016X, expected %016X", serr.KeyID, signer.PrimaryKey.KeyId)
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"time"
+
+	"github.com/keybase/go-crypto/openpgp"
+	"github.com/keybase/go-crypto/openpgp/packet"
+)
+
+// Warn about PGP keys that expire sooner than this.
+const PGPExpiryWarning = 30 * 24 * time.Hour
+
+// pgpKeyExpiration is when pub expires according to sig, or the zero
+// time if it doesn't.  Key lifetimes count from the key's creation, not
+// the signature's.
+func pgpKeyExpiration(pub *packet.PublicKey, sig *packet.Signature) time.Time {
+	if sig == nil || sig.KeyLifetimeSecs == nil || *sig.KeyLifetimeSecs == 0 {
+		return time.Time{}
+	}
+	return pub.CreationTime.Add(time.Duration(*sig.KeyLifetimeSecs) * time.Second)
+}
+
+// primaryIdentity picks the identity whose self-signature governs the
+// primary key, the way GPG does.
+func (k *PGPKeyBundle) primaryIdentity() *openpgp.Identity {
+	var first *openpgp.Identity
+	for _, id := range k.Identities {
+		if id.SelfSignature == nil {
+			continue
+		}
+		if id.SelfSignature.IsPrimaryId != nil && *id.SelfSignature.IsPrimaryId {
+			return id
+		}
+		if first == nil || id.SelfSignature.CreationTime.After(first.SelfSignature.CreationTime) {
+			first = id
+		}
+	}
+	return first
+}
+
+func isEncryptionSubkey(subkey openpgp.Subkey) bool {
+	return subkey.Sig != nil &&
+		subkey.Sig.SigType == packet.SigTypeSubkeyBinding &&
+		subkey.Sig.FlagsValid &&
+		(subkey.Sig.FlagEncryptCommunications || subkey.Sig.FlagEncryptStorage) &&
+		subkey.PublicKey.PubKeyAlgo.CanEncrypt()
+}
+
+// Expirations returns when the primary key expires, and when the last
+// of its encryption subkeys does.  Either is the zero time if it never
+// expires, or if there are no encryption subkeys.
+func (k *PGPKeyBundle) Expirations() (primary, encryption time.Time) {
+	if id := k.primaryIdentity(); id != nil {
+		primary = pgpKeyExpiration(k.PrimaryKey, id.SelfSignature)
+	}
+	for _, subkey := range k.Subkeys {
+		if !isEncryptionSubkey(subkey) {
+			continue
+		}
+		etime := pgpKeyExpiration(subkey.PublicKey, subkey.Sig)
+		if etime.IsZero() {
+			return primary, time.Time{}
+		}
+		if etime.After(encryption) {
+			encryption = etime
+		}
+	}
+	return primary, encryption
+}
+
+func (k *PGPKeyBundle) checkUnlockedPrimary() error {
+	if k.PrivateKey == nil {
+		return NoSecretKeyError{}
+	}
+	if k.PrivateKey.Encrypted {
+		return fmt.Errorf("PGP key %s must be unlocked to change it", k.GetFingerprint().ToKeyID())
+	}
+	return nil
+}
+
+// lifetimeUntil is the lifetime that makes pub expire at etime.
+func lifetimeUntil(pub *packet.PublicKey, etime time.Time) (*uint32, error) {
+	d := etime.Sub(pub.CreationTime) / time.Second
+	if d <= 0 || d > 0xffffffff {
+		return nil, fmt.Errorf("can't make a key created on %s expire on %s", pub.CreationTime.Format("2006-01-02"), etime.Format("2006-01-02"))
+	}
+	secs := uint32(d)
+	return &secs, nil
+}
+
+// ExtendExpiration re-signs the key's self-signatures and the bindings
+// of its encryption subkeys so that they all expire at etime.  Signing
+// subkeys are left alone, since their bindings need a signature by the
+// subkey too.  The primary secret key must be unlocked.
+func (k *PGPKeyBundle) ExtendExpiration(etime time.Time, config *packet.Config) error {
+	if err := k.checkUnlockedPrimary(); err != nil {
+		return err
+	}
+	now := config.Now()
+
+	lifetime, err := lifetimeUntil(k.PrimaryKey, etime)
+	if err != nil {
+		return err
+	}
+	for _, id := range k.Identities {
+		if id.SelfSignature == nil {
+			continue
+		}
+		sig := *id.SelfSignature
+		sig.CreationTime = now
+		sig.KeyLifetimeSecs = lifetime
+		if err := sig.SignUserId(id.UserId.Id, k.PrimaryKey, k.PrivateKey, config); err != nil {
+			return err
+		}
+		id.SelfSignature = &sig
+	}
+
+	for i, subkey := range k.Subkeys {
+		if !isEncryptionSubkey(subkey) {
+			continue
+		}
+		if lifetime, err = lifetimeUntil(subkey.PublicKey, etime); err != nil {
+			return err
+		}
+		sig := *subkey.Sig
+		sig.CreationTime = now
+		sig.KeyLifetimeSecs = lifetime
+		if err := sig.SignKey(subkey.PublicKey, k.PrivateKey, config); err != nil {
+			return err
+		}
+		k.Subkeys[i].Sig = &sig
+	}
+
+	k.ArmoredPublicKey = ""
+	return nil
+}
+
+// AddEncryptionSubkey generates a new encryption subkey that expires
+// lifetime seconds from now, of the same kind as the key's existing
+// ones, and binds it to the key.  The older subkeys stay, so that
+// messages sent to them can still be decrypted, but PGP implementations
+// encrypt to the newest one.  The primary secret key must be unlocked.
+func (k *PGPKeyBundle) AddEncryptionSubkey(lifetime int, config *packet.Config) error {
+	if err := k.checkUnlockedPrimary(); err != nil {
+		return err
+	}
+	now := config.Now()
+
+	var priv *packet.PrivateKey
+	switch k.PrimaryKey.PubKeyAlgo {
+	case packet.PubKeyAlgoEdDSA:
+		cvPriv, err := packet.GenerateCurve25519Key(config.Random())
+		if err != nil {
+			return err
+		}
+		priv = packet.NewECDHPrivateKey(now, cvPriv)
+	case packet.PubKeyAlgoRSA, packet.PubKeyAlgoRSASignOnly:
+		bits := 4096
+		for _, subkey := range k.Subkeys {
+			if isEncryptionSubkey(subkey) && subkey.PublicKey.PubKeyAlgo == packet.PubKeyAlgoRSA {
+				if n, err := subkey.PublicKey.BitLength(); err == nil && int(n) > bits {
+					bits = int(n)
+				}
+			}
+		}
+		rsaPriv, err := rsa.GenerateKey(config.Random(), bits)
+		if err != nil {
+			return err
+		}
+		priv = packet.NewRSAPrivateKey(now, rsaPriv)
+	default:
+		return fmt.Errorf("can't make encryption subkeys for PGP keys of type %d", k.PrimaryKey.PubKeyAlgo)
+	}
+	priv.IsSubkey = true
+	priv.PublicKey.IsSubkey = true
+
+	sig := &packet.Signature{
+		CreationTime:              now,
+		SigType:                   packet.SigTypeSubkeyBinding,
+		PubKeyAlgo:                k.PrimaryKey.PubKeyAlgo,
+		Hash:                      config.Hash(),
+		FlagsValid:                true,
+		FlagEncryptStorage:        true,
+		FlagEncryptCommunications: true,
+		IssuerKeyId:               &k.PrimaryKey.KeyId,
+		KeyLifetimeSecs:           ui32p(lifetime),
+	}
+	if err := sig.SignKey(&priv.PublicKey, k.PrivateKey, config); err != nil {
+		return err
+	}
+	k.Subkeys = append(k.Subkeys, openpgp.Subkey{
+		PublicKey:  &priv.PublicKey,
+		PrivateKey: priv,
+		Sig:        sig,
+	})
+
+	k.ArmoredPublicKey = ""
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/keybase/go-crypto/openpgp"
+	"github.com/keybase/go-crypto/openpgp/packet"
+)
+
+func TestPGPExpirations(t *testing.T) {
+	tc := SetupTest(t, "pgp_expire")
+	defer tc.Cleanup()
+
+	key := makeReimportedPGPKey(t, tc, "expire@keybase.io", packet.PubKeyAlgoEdDSA)
+	primary, encryption := key.Expirations()
+	want := key.PrimaryKey.CreationTime.Add(KeyExpireIn * time.Second)
+	if !primary.Equal(want) {
+		t.Errorf("primary key expires %s, expected %s", primary, want)
+	}
+	want = key.Subkeys[0].PublicKey.CreationTime.Add(SubkeyExpireIn * time.Second)
+	if !encryption.Equal(want) {
+		t.Errorf("encryption subkey expires %s, expected %s", encryption, want)
+	}
+}
+
+func TestPGPExtendExpiration(t *testing.T) {
+	tc := SetupTest(t, "pgp_expire")
+	defer tc.Cleanup()
+
+	key := makeReimportedPGPKey(t, tc, "extend@keybase.io", packet.PubKeyAlgoEdDSA)
+	etime := time.Now().Add(2 * 365 * 24 * time.Hour).Truncate(time.Second)
+	if err := key.ExtendExpiration(etime, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The new self-signatures have to check out after a round trip.
+	armored, err := key.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ReadOneKeyFromString(armored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primary, encryption := pub.Expirations()
+	if !primary.Equal(etime) || !encryption.Equal(etime) {
+		t.Errorf("expirations after extending: %s, %s; expected %s", primary, encryption, etime)
+	}
+
+	if err := key.ExtendExpiration(key.PrimaryKey.CreationTime.Add(-time.Hour), nil); err == nil {
+		t.Error("expected an error for an expiration before the key's creation")
+	}
+}
+
+func TestPGPExtendExpirationLocked(t *testing.T) {
+	tc := SetupTest(t, "pgp_expire")
+	defer tc.Cleanup()
+
+	key := makeReimportedPGPKey(t, tc, "locked@keybase.io", packet.PubKeyAlgoEdDSA)
+	armored, err := key.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ReadOneKeyFromString(armored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pub.ExtendExpiration(time.Now().Add(time.Hour), nil); err == nil {
+		t.Error("extended a key without its secret half")
+	}
+}
+
+func TestPGPAddEncryptionSubkey(t *testing.T) {
+	tc := SetupTest(t, "pgp_expire")
+	defer tc.Cleanup()
+
+	key := makeReimportedPGPKey(t, tc, "rotate@keybase.io", packet.PubKeyAlgoEdDSA)
+	oldID := key.Subkeys[0].PublicKey.KeyId
+	if err := key.AddEncryptionSubkey(OneYearInSeconds, &packet.Config{Time: func() time.Time {
+		// Later than the original subkey, so that it's preferred.
+		return time.Now().Add(time.Minute)
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := key.Entity.SerializePrivate(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	armored, err := PGPKeyRawToArmored(buf.Bytes(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := ReadPrivateKeyFromString(armored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotated.Subkeys) != 2 {
+		t.Fatalf("expected 2 subkeys, got %d", len(rotated.Subkeys))
+	}
+	newID := rotated.Subkeys[1].PublicKey.KeyId
+	if _, encryption := rotated.Expirations(); !encryption.Equal(rotated.Subkeys[0].PublicKey.CreationTime.Add(SubkeyExpireIn * time.Second)) {
+		t.Errorf("the old subkey should still expire last: %s", encryption)
+	}
+
+	// New messages go to the new subkey, and can be decrypted.
+	msg := "rotated"
+	sink := NewBufferCloser()
+	if err := PGPEncrypt(strings.NewReader(msg), sink, nil, []*PGPKeyBundle{rotated}); err != nil {
+		t.Fatal(err)
+	}
+	md, err := openpgp.ReadMessage(bytes.NewReader(sink.Bytes()), rotated.toList(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(md.EncryptedToKeyIds) != 1 || md.EncryptedToKeyIds[0] != newID || newID == oldID {
+		t.Errorf("encrypted to %x, expected the new subkey %x", md.EncryptedToKeyIds, newID)
+	}
+	var out bytes.Buffer
+	if _, err := out.ReadFrom(md.UnverifiedBody); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != msg {
+		t.Errorf("decrypted %q, expected %q", out.String(), msg)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/rsa"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/keybase/go-crypto/openpgp"
 	"github.com/keybase/go-crypto/openpgp/errors"
@@ -16,6 +17,7 @@ import (
 )
 
 type PGPGenArg struct {
+	Algorithm       packet.PublicKeyAlgorithm // of the primary key; RSA or EdDSA
 	PrimaryBits     int
 	SubkeyBits      int
 	Ids             Identities
@@ -34,9 +36,10 @@ func ui32p(i int) *uint32 {
 	return nil
 }
 
-// NewEntity returns an Entity that contains a fresh RSA/RSA keypair with a
-// single identity composed of the given full name, comment and email, any of
-// which may be empty but must not contain any of "()<>\x00".
+// NewEntity returns an Entity that contains a fresh RSA/RSA or
+// EdDSA/ECDH keypair with a single identity composed of the given full
+// name, comment and email, any of which may be empty but must not contain
+// any of "()<>\x00".
 // If config is nil, sensible defaults will be used.
 //
 // Modification of: https://code.google.com/p/go/source/browse/openpgp/keys.go?repo=crypto&r=8fec09c61d5d66f460d227fd1df3473d7e015bc6#456
@@ -61,25 +64,14 @@ func GeneratePGPKeyBundle(arg PGPGenArg, logUI LogUI) (*PGPKeyBundle, error) {
 		}
 	}
 
-	if logUI != nil {
-		logUI.Info("Generating primary key (%d bits)", arg.PrimaryBits)
-	}
-	masterPriv, err := rsa.GenerateKey(arg.Config.Random(), arg.PrimaryBits)
-	if err != nil {
-		return nil, err
-	}
-
-	if logUI != nil {
-		logUI.Info("Generating encryption subkey (%d bits)", arg.SubkeyBits)
-	}
-	encryptingPriv, err := rsa.GenerateKey(arg.Config.Random(), arg.SubkeyBits)
+	masterPriv, encryptingPriv, err := arg.generateKeys(currentTime, logUI)
 	if err != nil {
 		return nil, err
 	}
 
 	e := &openpgp.Entity{
-		PrimaryKey: packet.NewRSAPublicKey(currentTime, &masterPriv.PublicKey),
-		PrivateKey: packet.NewRSAPrivateKey(currentTime, masterPriv),
+		PrimaryKey: &masterPriv.PublicKey,
+		PrivateKey: masterPriv,
 		Identities: make(map[string]*openpgp.Identity),
 	}
 
@@ -94,7 +86,7 @@ func GeneratePGPKeyBundle(arg PGPGenArg, logUI LogUI) (*PGPKeyBundle, error) {
 			SelfSignature: &packet.Signature{
 				CreationTime:         currentTime,
 				SigType:              packet.SigTypePositiveCert,
-				PubKeyAlgo:           packet.PubKeyAlgoRSA,
+				PubKeyAlgo:           masterPriv.PubKeyAlgo,
 				Hash:                 arg.Config.Hash(),
 				IsPrimaryId:          &isPrimaryID,
 				FlagsValid:           true,
@@ -112,12 +104,12 @@ func GeneratePGPKeyBundle(arg PGPGenArg, logUI LogUI) (*PGPKeyBundle, error) {
 
 	e.Subkeys = make([]openpgp.Subkey, 1)
 	e.Subkeys[0] = openpgp.Subkey{
-		PublicKey:  packet.NewRSAPublicKey(currentTime, &encryptingPriv.PublicKey),
-		PrivateKey: packet.NewRSAPrivateKey(currentTime, encryptingPriv),
+		PublicKey:  &encryptingPriv.PublicKey,
+		PrivateKey: encryptingPriv,
 		Sig: &packet.Signature{
 			CreationTime:              currentTime,
 			SigType:                   packet.SigTypeSubkeyBinding,
-			PubKeyAlgo:                packet.PubKeyAlgoRSA,
+			PubKeyAlgo:                masterPriv.PubKeyAlgo,
 			Hash:                      arg.Config.Hash(),
 			FlagsValid:                true,
 			FlagEncryptStorage:        true,
@@ -135,6 +127,44 @@ func GeneratePGPKeyBundle(arg PGPGenArg, logUI LogUI) (*PGPKeyBundle, error) {
 	return NewPGPKeyBundle(e), nil
 }
 
+// generateKeys makes the primary (signing) key and the encryption subkey.
+func (a *PGPGenArg) generateKeys(currentTime time.Time, logUI LogUI) (primary, subkey *packet.PrivateKey, err error) {
+	info := func(format string, args ...interface{}) {
+		if logUI != nil {
+			logUI.Info(format, args...)
+		}
+	}
+
+	switch a.Algorithm {
+	case packet.PubKeyAlgoEdDSA:
+		info("Generating Ed25519 primary key")
+		edPriv, err := packet.GenerateEdDSAKey(a.Config.Random())
+		if err != nil {
+			return nil, nil, err
+		}
+		info("Generating Curve25519 encryption subkey")
+		cvPriv, err := packet.GenerateCurve25519Key(a.Config.Random())
+		if err != nil {
+			return nil, nil, err
+		}
+		return packet.NewEdDSAPrivateKey(currentTime, edPriv), packet.NewECDHPrivateKey(currentTime, cvPriv), nil
+	case packet.PubKeyAlgoRSA:
+		info("Generating primary key (%d bits)", a.PrimaryBits)
+		masterPriv, err := rsa.GenerateKey(a.Config.Random(), a.PrimaryBits)
+		if err != nil {
+			return nil, nil, err
+		}
+		info("Generating encryption subkey (%d bits)", a.SubkeyBits)
+		encryptingPriv, err := rsa.GenerateKey(a.Config.Random(), a.SubkeyBits)
+		if err != nil {
+			return nil, nil, err
+		}
+		return packet.NewRSAPrivateKey(currentTime, masterPriv), packet.NewRSAPrivateKey(currentTime, encryptingPriv), nil
+	default:
+		return nil, nil, fmt.Errorf("can't generate PGP keys of type %d", a.Algorithm)
+	}
+}
+
 // CreateIDs creates identities for KeyGenArg.Ids if none exist.
 // It uses PGPUids to determine the set of Ids.  It does not set the
 // default keybase.io uid.  AddDefaultUid() does that.
@@ -183,6 +213,9 @@ func (a *PGPGenArg) PGPUserIDs() ([]*packet.UserId, error) {
 }
 
 func (a *PGPGenArg) Init() (err error) {
+	if a.Algorithm == 0 {
+		a.Algorithm = packet.PubKeyAlgoRSA
+	}
 	defBits := 4096
 	if a.PrimaryBits == 0 {
 		a.PrimaryBits = defBits
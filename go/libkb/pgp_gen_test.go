@@ -0,0 +1,122 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/keybase/go-crypto/openpgp/packet"
+)
+
+// makeReimportedPGPKey generates a key and round-trips its secret half
+// through the armored format, which checks its self-signatures.
+func makeReimportedPGPKey(t *testing.T, tc TestContext, id string, algo packet.PublicKeyAlgorithm) *PGPKeyBundle {
+	arg := PGPGenArg{
+		Algorithm:   algo,
+		PrimaryBits: 1024,
+		SubkeyBits:  1024,
+		PGPUids:     []string{id},
+	}
+	arg.Init()
+	arg.CreatePGPIDs()
+	bundle, err := GeneratePGPKeyBundle(arg, tc.G.UI.GetLogUI())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := bundle.Entity.SerializePrivate(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	armored, err := PGPKeyRawToArmored(buf.Bytes(), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ReadPrivateKeyFromString(armored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !imported.GetFingerprint().Eq(bundle.GetFingerprint()) {
+		t.Fatalf("fingerprint changed on import: %s != %s", imported.GetFingerprint(), bundle.GetFingerprint())
+	}
+	return imported
+}
+
+func TestPGPGenDefaultsToRSA(t *testing.T) {
+	var arg PGPGenArg
+	arg.Init()
+	if arg.Algorithm != packet.PubKeyAlgoRSA {
+		t.Errorf("default algorithm: %d, expected RSA", arg.Algorithm)
+	}
+}
+
+func TestPGPGenECC(t *testing.T) {
+	tc := SetupTest(t, "pgp_ecc")
+	defer tc.Cleanup()
+
+	src := makeReimportedPGPKey(t, tc, "src@keybase.io", packet.PubKeyAlgoEdDSA)
+	dst := makeReimportedPGPKey(t, tc, "dst@keybase.io", packet.PubKeyAlgoEdDSA)
+
+	if src.PrimaryKey.PubKeyAlgo != packet.PubKeyAlgoEdDSA {
+		t.Errorf("primary key algorithm: %d", src.PrimaryKey.PubKeyAlgo)
+	}
+	if len(src.Subkeys) != 1 || src.Subkeys[0].PublicKey.PubKeyAlgo != packet.PubKeyAlgoECDH {
+		t.Errorf("expected one ECDH subkey")
+	}
+	if desc := src.KeyDescription(); !strings.HasPrefix(desc, "256-bit EdDSA key") {
+		t.Errorf("key description: %q", desc)
+	}
+	if src.GetAlgoType() != KIDPGPEddsa || !IsPGPAlgo(src.GetAlgoType()) {
+		t.Errorf("algo type: %d", src.GetAlgoType())
+	}
+
+	testPGPSignAndEncrypt(t, src, dst)
+}
+
+func TestPGPGenRSA(t *testing.T) {
+	tc := SetupTest(t, "pgp_rsa")
+	defer tc.Cleanup()
+
+	src := makeReimportedPGPKey(t, tc, "src@keybase.io", packet.PubKeyAlgoRSA)
+	dst := makeReimportedPGPKey(t, tc, "dst@keybase.io", packet.PubKeyAlgoEdDSA)
+	if desc := src.KeyDescription(); !strings.HasPrefix(desc, "1024-bit RSA key") {
+		t.Errorf("key description: %q", desc)
+	}
+
+	// RSA and ECC keys can talk to each other.
+	testPGPSignAndEncrypt(t, src, dst)
+	testPGPSignAndEncrypt(t, dst, src)
+}
+
+func testPGPSignAndEncrypt(t *testing.T, src, dst *PGPKeyBundle) {
+	msg := "the eagle has landed"
+	sig, _, err := src.SignToString([]byte(msg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := src.VerifyString(sig, []byte(msg)); err != nil {
+		t.Fatalf("verify: %s", err)
+	}
+	if _, err := src.VerifyString(sig, []byte("something else")); err == nil {
+		t.Fatal("signature verified for the wrong message")
+	}
+
+	sink := NewBufferCloser()
+	if err := PGPEncrypt(strings.NewReader(msg), sink, src, []*PGPKeyBundle{dst}); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	status, err := PGPDecryptWithBundles(bytes.NewReader(sink.Bytes()), &out, []*PGPKeyBundle{dst, src})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != msg {
+		t.Errorf("decrypted %q, expected %q", out.String(), msg)
+	}
+	if !status.IsSigned || !status.Verified || status.SignatureError != nil {
+		t.Errorf("bad signature status: %+v", status)
+	}
+}
@@ -484,6 +484,10 @@ func (k PGPKeyBundle) KeyInfo() (algorithm, kid, creation string) {
 		typ = "DSA"
 	case packet.PubKeyAlgoECDSA:
 		typ = "ECDSA"
+	case packet.PubKeyAlgoEdDSA:
+		typ = "EdDSA"
+	case packet.PubKeyAlgoECDH:
+		typ = "ECDH"
 	default:
 		typ = "<UNKONWN TYPE>"
 	}
@@ -581,7 +585,7 @@ func (k PGPKeyBundle) VerifyString(sig string, msg []byte) (id keybase1.SigID, e
 
 func IsPGPAlgo(algo AlgoType) bool {
 	switch algo {
-	case KIDPGPRsa, KIDPGPElgamal, KIDPGPDsa, KIDPGPEcdh, KIDPGPEcdsa, KIDPGPBase:
+	case KIDPGPRsa, KIDPGPElgamal, KIDPGPDsa, KIDPGPEcdh, KIDPGPEcdsa, KIDPGPEddsa, KIDPGPBase:
 		return true
 	}
 	return false
@@ -0,0 +1,289 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/base32"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/keybase/go-crypto/openpgp"
+	"github.com/keybase/go-crypto/openpgp/packet"
+)
+
+// Cached keyserver and WKD results are looked up again after this long,
+// so that revocations and new keys are noticed.
+const ExternalPGPKeysCacheTTL = 24 * time.Hour
+
+// zbase32 is the encoding of WKD's hashed local parts.
+var zbase32 = base32.NewEncoding("ybndrfg8ejkmcpqxot1uwisza345h769")
+
+// IsExternalPGPRecipient is true if s is an email address rather than a
+// Keybase user assertion, so its PGP key can only be found outside of
+// Keybase.
+func IsExternalPGPRecipient(s string) bool {
+	if !CheckEmail.F(s) {
+		return false
+	}
+	_, err := ParseAssertionURL(s, false)
+	return err != nil
+}
+
+// ExternalPGPKeys are the PGP keys for an email address found outside of
+// Keybase, on a keyserver or in the domain's Web Key Directory.  Nothing
+// vouches for them but the place they came from.
+type ExternalPGPKeys struct {
+	Email  string
+	Source string
+	CTime  time.Time
+	Keys   []*PGPKeyBundle
+}
+
+type externalPGPKeysStored struct {
+	Source string   `json:"source"`
+	CTime  int64    `json:"ctime"`
+	Keys   []string `json:"keys"`
+}
+
+func externalPGPKeysDbKey(email string) DbKey {
+	return DbKey{Typ: DBExternalPGPKeys, Key: strings.ToLower(email)}
+}
+
+// LoadExternalPGPKeys loads the keys for email that were cached by an
+// earlier lookup.  It returns nil if there aren't any, if they were cached
+// longer than ExternalPGPKeysCacheTTL ago, or if all of them have expired
+// since.
+func LoadExternalPGPKeys(g *GlobalContext, email string) (*ExternalPGPKeys, error) {
+	var stored externalPGPKeysStored
+	found, err := g.LocalDb.GetInto(&stored, externalPGPKeysDbKey(email))
+	if err != nil || !found {
+		return nil, err
+	}
+	ret := &ExternalPGPKeys{
+		Email:  strings.ToLower(email),
+		Source: stored.Source,
+		CTime:  time.Unix(stored.CTime, 0),
+	}
+	if age := time.Since(ret.CTime); age > ExternalPGPKeysCacheTTL {
+		g.Log.Debug("| Cached PGP keys for %s are stale (%s old)", ret.Email, age)
+		return nil, nil
+	}
+	now := time.Now()
+	for _, armored := range stored.Keys {
+		key, err := ReadOneKeyFromString(armored)
+		if err != nil {
+			return nil, err
+		}
+		if pruneExternalPGPKey(key, now) {
+			ret.Keys = append(ret.Keys, key)
+		}
+	}
+	if len(ret.Keys) == 0 {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+// pruneExternalPGPKey drops the revoked and expired subkeys of k, and
+// reports whether k itself is still usable, i.e. its primary key isn't
+// revoked or expired.
+func pruneExternalPGPKey(k *PGPKeyBundle, now time.Time) bool {
+	if len(k.Revocations) > 0 {
+		return false
+	}
+	id := k.primaryIdentity()
+	if id == nil || id.SelfSignature.RevocationReason != nil {
+		return false
+	}
+	if etime := pgpKeyExpiration(k.PrimaryKey, id.SelfSignature); !etime.IsZero() && now.After(etime) {
+		return false
+	}
+	var subkeys []openpgp.Subkey
+	for _, subkey := range k.Subkeys {
+		if subkey.Sig == nil || subkey.Sig.SigType == packet.SigTypeSubkeyRevocation || subkey.Sig.RevocationReason != nil {
+			continue
+		}
+		if etime := pgpKeyExpiration(subkey.PublicKey, subkey.Sig); !etime.IsZero() && now.After(etime) {
+			continue
+		}
+		subkeys = append(subkeys, subkey)
+	}
+	k.Subkeys = subkeys
+	return true
+}
+
+// Store caches the keys in the local db, replacing whatever was there for
+// the same email address.
+func (k *ExternalPGPKeys) Store(g *GlobalContext) error {
+	stored := externalPGPKeysStored{Source: k.Source, CTime: k.CTime.Unix()}
+	for _, key := range k.Keys {
+		armored, err := key.Encode()
+		if err != nil {
+			return err
+		}
+		stored.Keys = append(stored.Keys, armored)
+	}
+	return g.LocalDb.PutObj(externalPGPKeysDbKey(k.Email), nil, stored)
+}
+
+// PGPKeyserverLookup finds PGP keys by email address, first in the Web Key
+// Directory of the address's domain, then on an HKP keyserver.
+type PGPKeyserverLookup struct {
+	Contextified
+	keyserver string
+	wkdBase   string // if set, WKD requests go here instead of to the domain
+}
+
+func NewPGPKeyserverLookup(g *GlobalContext) *PGPKeyserverLookup {
+	return &PGPKeyserverLookup{
+		Contextified: NewContextified(g),
+		keyserver:    g.Env.GetPGPKeyserver(),
+	}
+}
+
+// Lookup finds the keys for email.  Only keys with a user ID for email are
+// returned; if there aren't any, it's a NoKeyError.
+func (l *PGPKeyserverLookup) Lookup(email string) (ret *ExternalPGPKeys, err error) {
+	email = strings.ToLower(email)
+	l.G().Log.Debug("+ PGPKeyserverLookup(%s)", email)
+	defer func() { l.G().Log.Debug("- PGPKeyserverLookup(%s) -> %s", email, ErrToOk(err)) }()
+
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || !CheckEmail.F(email) {
+		return nil, fmt.Errorf("Not an email address: %q", email)
+	}
+
+	keys, source, werr := l.lookupWKD(email[:at], email[at+1:])
+	if werr != nil {
+		l.G().Log.Debug("| WKD lookup failed: %s", werr)
+	}
+	if len(keys) == 0 {
+		var herr error
+		keys, source, herr = l.lookupHKP(email)
+		if herr != nil {
+			l.G().Log.Debug("| HKP lookup failed: %s", herr)
+			if werr == nil {
+				werr = herr
+			}
+		}
+	}
+	if len(keys) == 0 {
+		if werr != nil {
+			return nil, werr
+		}
+		return nil, NoKeyError{Msg: fmt.Sprintf("No PGP key found for %s in its Web Key Directory or on %s", email, l.keyserver)}
+	}
+	return &ExternalPGPKeys{Email: email, Source: source, CTime: time.Now(), Keys: keys}, nil
+}
+
+// lookupWKD tries WKD's advanced method, then its direct one.
+func (l *PGPKeyserverLookup) lookupWKD(local, domain string) (keys []*PGPKeyBundle, source string, err error) {
+	sum := sha1.Sum([]byte(local))
+	hash := zbase32.EncodeToString(sum[:])
+
+	var urls []string
+	if len(l.wkdBase) > 0 {
+		urls = []string{l.wkdBase + "/.well-known/openpgpkey/hu/" + hash}
+	} else {
+		urls = []string{
+			"https://openpgpkey." + domain + "/.well-known/openpgpkey/" + domain + "/hu/" + hash,
+			"https://" + domain + "/.well-known/openpgpkey/hu/" + hash,
+		}
+	}
+	source = "WKD (" + domain + ")"
+	for _, u := range urls {
+		keys, err = l.fetch(u, HTTPArgs{"l": S{Val: local}}, local+"@"+domain)
+		if len(keys) > 0 {
+			return keys, source, nil
+		}
+	}
+	return nil, source, err
+}
+
+func (l *PGPKeyserverLookup) lookupHKP(email string) (keys []*PGPKeyBundle, source string, err error) {
+	u, err := hkpURL(l.keyserver)
+	if err != nil {
+		return nil, "", err
+	}
+	args := HTTPArgs{
+		"op":      S{Val: "get"},
+		"options": S{Val: "mr"},
+		"search":  S{Val: email},
+	}
+	keys, err = l.fetch(u.String()+"/pks/lookup", args, email)
+	return keys, u.Host, err
+}
+
+// hkpURL makes an http(s) URL out of a keyserver URI, which may use the
+// hkp or hkps schemes.
+func hkpURL(keyserver string) (*url.URL, error) {
+	if !strings.Contains(keyserver, "://") {
+		keyserver = "hkps://" + keyserver
+	}
+	u, err := url.Parse(keyserver)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "hkps", "https":
+		u.Scheme = "https"
+	case "hkp", "http":
+		u.Scheme = "http"
+		if !strings.Contains(u.Host, ":") {
+			u.Host += ":11371"
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported keyserver scheme: %q", u.Scheme)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
+
+// fetch gets keys from u, which may be binary or armored, and keeps those
+// that have a user ID for email and aren't revoked or expired.  A 404
+// means there are none.
+func (l *PGPKeyserverLookup) fetch(u string, args HTTPArgs, email string) ([]*PGPKeyBundle, error) {
+	res, err := l.G().XAPI.GetText(APIArg{
+		Endpoint:     u,
+		Args:         args,
+		HTTPStatus:   []int{200, 404},
+		Contextified: l.Contextified,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if res.HTTPStatus == 404 || len(res.Body) == 0 {
+		return nil, nil
+	}
+
+	var entities openpgp.EntityList
+	if strings.HasPrefix(strings.TrimSpace(res.Body), "-----BEGIN") {
+		entities, err = openpgp.ReadArmoredKeyRing(strings.NewReader(res.Body))
+	} else {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader([]byte(res.Body)))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*PGPKeyBundle
+	now := time.Now()
+	for _, entity := range entities {
+		key := &PGPKeyBundle{Entity: entity}
+		if !pruneExternalPGPKey(key, now) {
+			continue
+		}
+		for _, id := range entity.Identities {
+			if strings.ToLower(id.UserId.Email) == email {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	return keys, nil
+}
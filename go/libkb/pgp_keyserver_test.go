@@ -0,0 +1,195 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/keybase/go-crypto/openpgp/packet"
+)
+
+func TestIsExternalPGPRecipient(t *testing.T) {
+	cases := map[string]bool{
+		"vendor@example.com": true,
+		"max":                false,
+		"max@twitter":        false,
+		"max@github":         false,
+		"example.com@dns":    false,
+		"not an email":       false,
+	}
+	for s, expected := range cases {
+		if IsExternalPGPRecipient(s) != expected {
+			t.Errorf("IsExternalPGPRecipient(%q) != %v", s, expected)
+		}
+	}
+}
+
+func TestHKPURL(t *testing.T) {
+	cases := map[string]string{
+		"hkps://keys.openpgp.org":      "https://keys.openpgp.org",
+		"keys.openpgp.org":             "https://keys.openpgp.org",
+		"hkp://pgp.mit.edu":            "http://pgp.mit.edu:11371",
+		"hkp://localhost:8080/":        "http://localhost:8080",
+		"https://keyserver.ubuntu.com": "https://keyserver.ubuntu.com",
+	}
+	for in, expected := range cases {
+		u, err := hkpURL(in)
+		if err != nil {
+			t.Errorf("%s: %s", in, err)
+			continue
+		}
+		if u.String() != expected {
+			t.Errorf("hkpURL(%q) = %q, expected %q", in, u, expected)
+		}
+	}
+	if _, err := hkpURL("ldap://keys.example.com"); err == nil {
+		t.Error("expected an error for an ldap keyserver")
+	}
+}
+
+func TestPGPKeyserverLookup(t *testing.T) {
+	tc := SetupTest(t, "pgp_keyserver")
+	defer tc.Cleanup()
+
+	wkdKey := makeReimportedPGPKey(t, tc, "alice@example.com", packet.PubKeyAlgoEdDSA)
+	hkpKey := makeReimportedPGPKey(t, tc, "bob@example.com", packet.PubKeyAlgoEdDSA)
+	other := makeReimportedPGPKey(t, tc, "mallory@example.com", packet.PubKeyAlgoEdDSA)
+
+	var wkdBody bytes.Buffer
+	if err := wkdKey.Entity.Serialize(&wkdBody); err != nil {
+		t.Fatal(err)
+	}
+	hkpBody, err := hkpKey.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherBody, err := other.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hkpSearches []string
+	mux := http.NewServeMux()
+	// The WKD hash of "alice".
+	mux.HandleFunc("/.well-known/openpgpkey/hu/kei1q4tipxxu1yj79k9kfukdhfy631xe", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("l") != "alice" {
+			t.Errorf("bad WKD local part: %q", r.URL.Query().Get("l"))
+		}
+		w.Write(wkdBody.Bytes())
+	})
+	mux.HandleFunc("/pks/lookup", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("op") != "get" || q.Get("options") != "mr" {
+			t.Errorf("bad HKP query: %s", r.URL.RawQuery)
+		}
+		hkpSearches = append(hkpSearches, q.Get("search"))
+		switch q.Get("search") {
+		case "bob@example.com":
+			w.Write([]byte(hkpBody))
+		case "carol@example.com":
+			// A key for someone else altogether.
+			w.Write([]byte(otherBody))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	lookup := NewPGPKeyserverLookup(tc.G)
+	lookup.keyserver = "hkp://" + srv.Listener.Addr().String()
+	lookup.wkdBase = srv.URL
+
+	res, err := lookup.Lookup("Alice@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Source != "WKD (example.com)" || len(res.Keys) != 1 || !res.Keys[0].GetFingerprint().Eq(wkdKey.GetFingerprint()) {
+		t.Fatalf("bad WKD result: %+v", res)
+	}
+	if len(hkpSearches) != 0 {
+		t.Errorf("the keyserver was searched even though WKD had a key")
+	}
+
+	res, err = lookup.Lookup("bob@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Source != srv.Listener.Addr().String() || len(res.Keys) != 1 || !res.Keys[0].GetFingerprint().Eq(hkpKey.GetFingerprint()) {
+		t.Fatalf("bad HKP result: %+v", res)
+	}
+
+	if _, err := lookup.Lookup("carol@example.com"); err == nil {
+		t.Fatal("a key without carol's user ID was accepted")
+	} else if _, ok := err.(NoKeyError); !ok {
+		t.Fatalf("expected a NoKeyError, got %T: %s", err, err)
+	}
+	if _, err := lookup.Lookup("dave@example.com"); err == nil {
+		t.Fatal("expected an error for an unknown address")
+	}
+
+	// Round trip through the local cache.
+	if err := res.Store(tc.G); err != nil {
+		t.Fatal(err)
+	}
+	cached, err := LoadExternalPGPKeys(tc.G, "BOB@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached == nil || cached.Source != res.Source || len(cached.Keys) != 1 || !cached.Keys[0].GetFingerprint().Eq(hkpKey.GetFingerprint()) {
+		t.Fatalf("bad cached keys: %+v", cached)
+	}
+	if cached, err = LoadExternalPGPKeys(tc.G, "alice@example.com"); err != nil || cached != nil {
+		t.Fatalf("expected nothing cached for alice: %+v, %v", cached, err)
+	}
+
+	// Stale results aren't used.
+	res.CTime = time.Now().Add(-ExternalPGPKeysCacheTTL - time.Minute)
+	if err := res.Store(tc.G); err != nil {
+		t.Fatal(err)
+	}
+	if cached, err = LoadExternalPGPKeys(tc.G, "bob@example.com"); err != nil || cached != nil {
+		t.Fatalf("expected stale keys to be ignored: %+v, %v", cached, err)
+	}
+}
+
+func TestPruneExternalPGPKey(t *testing.T) {
+	tc := SetupTest(t, "pgp_keyserver_prune")
+	defer tc.Cleanup()
+
+	lifetime := func(d time.Duration) *uint32 {
+		secs := uint32(d / time.Second)
+		return &secs
+	}
+	later := time.Now().Add(time.Hour)
+
+	key := makeReimportedPGPKey(t, tc, "alice@example.com", packet.PubKeyAlgoEdDSA)
+	if !pruneExternalPGPKey(key, later) || len(key.Subkeys) != 1 {
+		t.Fatalf("a good key was pruned: %d subkeys", len(key.Subkeys))
+	}
+
+	key.Subkeys[0].Sig.KeyLifetimeSecs = lifetime(time.Minute)
+	if !pruneExternalPGPKey(key, later) {
+		t.Fatal("a key with an expired subkey was dropped")
+	}
+	if len(key.Subkeys) != 0 {
+		t.Errorf("an expired subkey was kept")
+	}
+
+	key = makeReimportedPGPKey(t, tc, "bob@example.com", packet.PubKeyAlgoEdDSA)
+	key.Subkeys[0].Sig.SigType = packet.SigTypeSubkeyRevocation
+	if !pruneExternalPGPKey(key, later) || len(key.Subkeys) != 0 {
+		t.Errorf("a revoked subkey was kept")
+	}
+
+	key = makeReimportedPGPKey(t, tc, "carol@example.com", packet.PubKeyAlgoEdDSA)
+	key.primaryIdentity().SelfSignature.KeyLifetimeSecs = lifetime(time.Minute)
+	if pruneExternalPGPKey(key, later) {
+		t.Errorf("an expired key was kept")
+	}
+}
@@ -3,6 +3,8 @@
 
 package libkb
 
+import keybase1 "github.com/keybase/client/go/protocol"
+
 func PGPLookup(g *GlobalContext, id uint64) (username, uid string, err error) {
 	return pgpLookup(g, pgpLookupArg{uintID: id})
 }
@@ -51,3 +53,22 @@ func pgpLookup(g *GlobalContext, arg pgpLookupArg) (username, uid string, err er
 	return data.Username, data.UID, nil
 
 }
+
+// KeyOwner asks the server who owns the key with the given KID.
+func KeyOwner(g *GlobalContext, kid keybase1.KID) (keybase1.UID, error) {
+	arg := APIArg{
+		Endpoint:     "key/owner",
+		NeedSession:  false,
+		Contextified: NewContextified(g),
+		Args:         HTTPArgs{"kid": S{Val: kid.String()}},
+	}
+	res, err := g.API.Get(arg)
+	if err != nil {
+		return "", err
+	}
+	suid, err := res.Body.AtPath("uid").GetString()
+	if err != nil {
+		return "", err
+	}
+	return keybase1.UIDFromString(suid)
+}
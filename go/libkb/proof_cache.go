@@ -4,6 +4,7 @@
 package libkb
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -12,10 +13,44 @@ import (
 	jsonw "github.com/keybase/go-jsonw"
 )
 
+// ProofCachePolicy is how long the results of checking proofs on a
+// service stay fresh, by whether the check worked.  Soft failures, such
+// as timeouts, are never cached.
+type ProofCachePolicy struct {
+	Success time.Duration
+	Failure time.Duration
+}
+
+// ProofCachePolicies are the built-in policies, by proof table key.  DNS
+// records change more than posts do, and failures are rechecked soon in
+// case they were fixed.
+var ProofCachePolicies = map[string]ProofCachePolicy{
+	"dns":        {Success: time.Hour, Failure: 5 * time.Minute},
+	"github":     {Success: 24 * time.Hour, Failure: 5 * time.Minute},
+	"twitter":    {Success: 24 * time.Hour, Failure: 5 * time.Minute},
+	"reddit":     {Success: 24 * time.Hour, Failure: 5 * time.Minute},
+	"hackernews": {Success: 24 * time.Hour, Failure: 5 * time.Minute},
+	"coinbase":   {Success: 24 * time.Hour, Failure: 5 * time.Minute},
+}
+
+// ProofCacheMode is how an identify uses the proof cache.
+type ProofCacheMode int
+
+const (
+	// ProofCacheFresh uses fresh cached results, and checks the rest.
+	ProofCacheFresh ProofCacheMode = iota
+	// ProofCacheRecheck checks every proof.
+	ProofCacheRecheck
+	// ProofCacheOnly uses cached results however old, and checks nothing.
+	ProofCacheOnly
+)
+
 type CheckResult struct {
 	Contextified
-	Status ProofError // Or nil if it was a success
-	Time   time.Time  // When the last check was
+	Status  ProofError // Or nil if it was a success
+	Time    time.Time  // When the last check was
+	Service string     // The proof's table key, for its cache policy
+	Stale   bool       // Used past its freshness
 }
 
 func (cr CheckResult) Pack() *jsonw.Wrapper {
@@ -27,25 +62,33 @@ func (cr CheckResult) Pack() *jsonw.Wrapper {
 		p.SetKey("status", s)
 	}
 	p.SetKey("time", jsonw.NewInt64(cr.Time.Unix()))
+	p.SetKey("service", jsonw.NewString(cr.Service))
 	return p
 }
 
 func (cr CheckResult) ToDisplayString() string {
+	if cr.Stale {
+		return "[cached " + FormatTime(cr.Time) + ", stale]"
+	}
 	return "[cached " + FormatTime(cr.Time) + "]"
 }
 
 func (cr CheckResult) IsFresh() bool {
-	// XXX  Might also want two separate timeouts for no error and hard failures.
+	// Offline, an old result is better than none at all.
+	if cr.G().IsOffline() {
+		return true
+	}
 
+	policy := cr.G().Env.GetProofCachePolicy(cr.Service)
 	var interval time.Duration
 	if cr.Status == nil {
-		interval = cr.G().Env.GetProofCacheLongDur()
+		interval = policy.Success
 	} else if ProofErrorIsSoft(cr.Status) {
 		// don't use cache results for "soft" errors (500s, timeouts)
 		// see issue #140
 		return false
 	} else {
-		interval = cr.G().Env.GetProofCacheMediumDur()
+		interval = policy.Failure
 	}
 	return (time.Since(cr.Time) < interval)
 }
@@ -61,9 +104,11 @@ func NewNowCheckResult(g *GlobalContext, pe ProofError) *CheckResult {
 func NewCheckResult(g *GlobalContext, jw *jsonw.Wrapper) (res *CheckResult, err error) {
 	var t int64
 	var code int
-	var desc string
+	var desc, service string
 
 	jw.AtKey("time").GetInt64Void(&t, &err)
+	// Results cached before there were policies have no service.
+	service, _ = jw.AtKey("service").GetString()
 	status := jw.AtKey("status")
 	var pe ProofError
 
@@ -77,6 +122,7 @@ func NewCheckResult(g *GlobalContext, jw *jsonw.Wrapper) (res *CheckResult, err
 			Contextified: NewContextified(g),
 			Status:       pe,
 			Time:         time.Unix(t, 0),
+			Service:      service,
 		}
 	}
 	return
@@ -87,10 +133,15 @@ type ProofCache struct {
 	capac int
 	lru   *lru.Cache
 	sync.RWMutex
+
+	// stored is about how many results are in the local database, or
+	// -1 until they're counted.
+	storedMu sync.Mutex
+	stored   int
 }
 
 func NewProofCache(g *GlobalContext, capac int) *ProofCache {
-	return &ProofCache{Contextified: NewContextified(g), capac: capac}
+	return &ProofCache{Contextified: NewContextified(g), capac: capac, stored: -1}
 }
 
 func (pc *ProofCache) setup() error {
@@ -124,10 +175,6 @@ func (pc *ProofCache) memGet(sid keybase1.SigID) *CheckResult {
 		pc.G().Log.Errorf("Bad type assertion in ProofCache.Get")
 		return nil
 	}
-	if !cr.IsFresh() {
-		pc.lru.Remove(sid)
-		return nil
-	}
 	return &cr
 }
 
@@ -142,7 +189,29 @@ func (pc *ProofCache) memPut(sid keybase1.SigID, cr CheckResult) {
 	pc.lru.Add(sid, cr)
 }
 
+// Get is the fresh cached result of checking the proof with sid, if
+// there is one.
 func (pc *ProofCache) Get(sid keybase1.SigID) *CheckResult {
+	cr := pc.get(sid)
+	if cr == nil || !cr.IsFresh() {
+		return nil
+	}
+	return cr
+}
+
+// GetAny is the cached result of checking the proof with sid, fresh or
+// not, if there is one.
+func (pc *ProofCache) GetAny(sid keybase1.SigID) *CheckResult {
+	cr := pc.get(sid)
+	if cr != nil && !cr.IsFresh() {
+		cr.Stale = true
+	}
+	return cr
+}
+
+// get looks in memory, then in the local database.  Results that aren't
+// fresh any more are kept, for GetAny, until the database is pruned.
+func (pc *ProofCache) get(sid keybase1.SigID) *CheckResult {
 	if pc == nil {
 		return nil
 	}
@@ -150,6 +219,9 @@ func (pc *ProofCache) Get(sid keybase1.SigID) *CheckResult {
 	cr := pc.memGet(sid)
 	if cr == nil {
 		cr = pc.dbGet(sid)
+		if cr != nil {
+			pc.memPut(sid, *cr)
+		}
 	}
 	return cr
 }
@@ -181,25 +253,95 @@ func (pc *ProofCache) dbGet(sid keybase1.SigID) (cr *CheckResult) {
 		pc.G().Log.Errorf("Bad cached CheckResult for %s", sidstr)
 		return nil
 	}
+	return cr
+}
+
+func (pc *ProofCache) dbPut(sid keybase1.SigID, cr CheckResult) error {
+	dbkey, _ := pc.dbKey(sid)
+	jw := cr.Pack()
+	if err := pc.G().LocalDb.Put(dbkey, []DbKey{}, jw); err != nil {
+		return err
+	}
+	return pc.bound()
+}
+
+// bound prunes the oldest results from the local database once there
+// are more than the configured maximum, down to three quarters of it,
+// so that it isn't pruned on every put.
+func (pc *ProofCache) bound() error {
+	pc.storedMu.Lock()
+	defer pc.storedMu.Unlock()
+
+	max := pc.G().Env.GetProofCacheMaxStored()
+	if pc.stored < 0 {
+		n, err := pc.countStored()
+		if err != nil {
+			return err
+		}
+		pc.stored = n
+	} else {
+		// Overwriting a result counts too, so the count only
+		// errs high, and pruning counts properly.
+		pc.stored++
+	}
+	if pc.stored <= max {
+		return nil
+	}
 
-	if !cr.IsFresh() {
-		if err := pc.G().LocalDb.Delete(dbkey); err != nil {
-			pc.G().Log.Errorf("Delete error: %s", err)
+	var all storedCheckResults
+	err := pc.G().LocalDb.ForEach(DBProofCheck, func(id DbKey, value []byte) error {
+		var t int64
+		if jw, err := jsonw.Unmarshal(value); err == nil {
+			t, _ = jw.AtKey("time").GetInt64()
 		}
-		pc.G().Log.Debug("| Cached CheckResult for %s wasn't fresh", sidstr)
+		all = append(all, storedCheckResult{id, t})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	keep := max * 3 / 4
+	if len(all) <= keep {
+		pc.stored = len(all)
 		return nil
 	}
+	sort.Sort(all)
+	prune := make(map[DbKey]bool)
+	for _, s := range all[:len(all)-keep] {
+		prune[s.key] = true
+	}
+	n, err := pc.G().LocalDb.Evict(func(id DbKey) bool { return prune[id] })
+	if err != nil {
+		return err
+	}
+	pc.G().Log.Debug("| Pruned %d old proof check results", n)
+	pc.stored = len(all) - n
+	return nil
+}
 
-	return cr
+type storedCheckResult struct {
+	key  DbKey
+	time int64
 }
 
-func (pc *ProofCache) dbPut(sid keybase1.SigID, cr CheckResult) error {
-	dbkey, _ := pc.dbKey(sid)
-	jw := cr.Pack()
-	return pc.G().LocalDb.Put(dbkey, []DbKey{}, jw)
+// storedCheckResults sort oldest first.
+type storedCheckResults []storedCheckResult
+
+func (s storedCheckResults) Len() int           { return len(s) }
+func (s storedCheckResults) Less(i, j int) bool { return s[i].time < s[j].time }
+func (s storedCheckResults) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (pc *ProofCache) countStored() (int, error) {
+	n := 0
+	err := pc.G().LocalDb.ForEach(DBProofCheck, func(DbKey, []byte) error {
+		n++
+		return nil
+	})
+	return n, err
 }
 
-func (pc *ProofCache) Put(sid keybase1.SigID, pe ProofError) error {
+// Put caches the result of checking the proof with sid, on service.
+func (pc *ProofCache) Put(sid keybase1.SigID, service string, pe ProofError) error {
 	if pc == nil {
 		return nil
 	}
@@ -207,6 +349,7 @@ func (pc *ProofCache) Put(sid keybase1.SigID, pe ProofError) error {
 		Contextified: pc.Contextified,
 		Status:       pe,
 		Time:         time.Now(),
+		Service:      service,
 	}
 	pc.memPut(sid, cr)
 	return pc.dbPut(sid, cr)
@@ -0,0 +1,117 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+func testCheckResult(tc TestContext, service string, pe ProofError, age time.Duration) CheckResult {
+	return CheckResult{
+		Contextified: NewContextified(tc.G),
+		Status:       pe,
+		Time:         time.Now().Add(-age),
+		Service:      service,
+	}
+}
+
+func TestProofCachePolicies(t *testing.T) {
+	tc := SetupTest(t, "proof_cache")
+	defer tc.Cleanup()
+
+	hard := NewProofError(keybase1.ProofStatus_NOT_FOUND, "gone")
+	soft := NewProofError(keybase1.ProofStatus_HOST_UNREACHABLE, "timed out")
+	tests := []struct {
+		service string
+		pe      ProofError
+		age     time.Duration
+		fresh   bool
+	}{
+		{"dns", nil, 30 * time.Minute, true},
+		{"dns", nil, 2 * time.Hour, false},
+		{"github", nil, 2 * time.Hour, true},
+		{"github", hard, 2 * time.Minute, true},
+		{"github", hard, 10 * time.Minute, false},
+		{"github", soft, 0, false},
+		// Without a policy of its own, the long and medium durations.
+		{"", nil, 5 * time.Hour, true},
+		{"", nil, 7 * time.Hour, false},
+		{"", hard, 20 * time.Minute, true},
+	}
+	for _, test := range tests {
+		cr := testCheckResult(tc, test.service, test.pe, test.age)
+		if fresh := cr.IsFresh(); fresh != test.fresh {
+			t.Errorf("%q result %v old, error %v: fresh = %v", test.service, test.age, test.pe, fresh)
+		}
+	}
+
+	if err := tc.G.Env.GetConfigWriter().SetStringAtPath("cache.proofs.dns.success", "3h"); err != nil {
+		t.Fatal(err)
+	}
+	if cr := testCheckResult(tc, "dns", nil, 2*time.Hour); !cr.IsFresh() {
+		t.Error("the configured DNS policy wasn't used")
+	}
+}
+
+func TestProofCacheGetAny(t *testing.T) {
+	tc := SetupTest(t, "proof_cache")
+	defer tc.Cleanup()
+
+	pc := NewProofCache(tc.G, 10)
+	sid := keybase1.SigID("aa" + fmt.Sprintf("%062d", 1))
+	if err := pc.dbPut(sid, testCheckResult(tc, "dns", nil, 2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if cr := pc.Get(sid); cr != nil {
+		t.Errorf("got a stale result: %+v", cr)
+	}
+	cr := pc.GetAny(sid)
+	if cr == nil {
+		t.Fatal("the stale result is gone")
+	}
+	if !cr.Stale || cr.Service != "dns" {
+		t.Errorf("bad stale result: %+v", cr)
+	}
+	if s := cr.ToDisplayString(); s != "[cached "+FormatTime(cr.Time)+", stale]" {
+		t.Errorf("stale result displays as %q", s)
+	}
+}
+
+func TestProofCacheBound(t *testing.T) {
+	tc := SetupTest(t, "proof_cache")
+	defer tc.Cleanup()
+
+	if err := tc.G.Env.GetConfigWriter().SetIntAtPath("cache.limits.stored_proofs", 4); err != nil {
+		t.Fatal(err)
+	}
+
+	pc := NewProofCache(tc.G, 10)
+	var sids []keybase1.SigID
+	for i := 0; i < 5; i++ {
+		sid := keybase1.SigID("aa" + fmt.Sprintf("%062d", i))
+		sids = append(sids, sid)
+		// The first are the oldest.
+		if err := pc.dbPut(sid, testCheckResult(tc, "github", nil, time.Duration(10-i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := pc.countStored()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("%d results stored, expected 3", n)
+	}
+	for i, sid := range sids {
+		if found := pc.dbGet(sid) != nil; found != (i >= 2) {
+			t.Errorf("result %d stored: %v", i, found)
+		}
+	}
+}
@@ -23,7 +23,9 @@ func NewDNSChecker(p RemoteProofChainLink) (*DNSChecker, ProofError) {
 	return &DNSChecker{p}, nil
 }
 
-func (rc *DNSChecker) GetTorError() ProofError { return ProofErrorDNSOverTor }
+// GetTorError is nil, since over Tor the TXT records are looked up with
+// DNS-over-HTTPS, through the Tor proxy.
+func (rc *DNSChecker) GetTorError() ProofError { return nil }
 
 func (rc *DNSChecker) CheckHint(h SigHint) ProofError {
 	_, sigID, err := OpenSig(rc.proof.GetArmoredSig())
@@ -44,7 +46,7 @@ func (rc *DNSChecker) CheckHint(h SigHint) ProofError {
 }
 
 func (rc *DNSChecker) CheckDomain(sig string, domain string) ProofError {
-	txt, err := net.LookupTXT(domain)
+	txt, err := rc.lookupTXT(domain)
 	if err != nil {
 		return NewProofError(keybase1.ProofStatus_DNS_ERROR,
 			"DNS failure for %s: %s", domain, err)
@@ -61,6 +63,16 @@ func (rc *DNSChecker) CheckDomain(sig string, domain string) ProofError {
 		len(txt), domain, sig)
 }
 
+// lookupTXT looks up the TXT records of domain with the system resolver,
+// or, in Tor mode, where that would leak the lookup, with DNS-over-HTTPS.
+func (rc *DNSChecker) lookupTXT(domain string) ([]string, error) {
+	if G.Env.GetTorMode().Enabled() {
+		G.Log.Debug("| Looking up TXT records of %s over HTTPS", domain)
+		return LookupTXTOverHTTPS(G, domain)
+	}
+	return net.LookupTXT(domain)
+}
+
 func (rc *DNSChecker) CheckStatus(h SigHint) ProofError {
 
 	wanted := h.checkText
@@ -66,6 +66,11 @@ func resolveUID(au AssertionURL) ResolveResult {
 		return *p
 	}
 
+	if G.IsOffline() {
+		// Not cached, so that we ask the server once we're online.
+		return resolveUsernameLocally(au)
+	}
+
 	r := resolveUsername(au)
 
 	if r.err != nil {
@@ -10,10 +10,12 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"time"
 
 	keybase1 "github.com/keybase/client/go/protocol"
 	"github.com/keybase/go-crypto/openpgp"
 	pgpErrors "github.com/keybase/go-crypto/openpgp/errors"
+	"github.com/keybase/go-crypto/openpgp/packet"
 )
 
 func (sh SigHint) Export() *keybase1.SigHint {
@@ -38,6 +40,7 @@ func (l LinkCheckResult) Export() keybase1.LinkCheckResult {
 		ProofId:     l.position,
 		ProofResult: ExportProofError(l.err),
 		TorWarning:  l.torWarning,
+		TorCheck:    l.torCheck,
 	}
 	if l.cached != nil {
 		ret.Cached = l.cached.Export()
@@ -58,6 +61,7 @@ func (cr CheckResult) Export() *keybase1.CheckResult {
 	return &keybase1.CheckResult{
 		ProofResult:   ExportProofError(cr.Status),
 		Time:          keybase1.ToTime(cr.Time),
+		Stale:         cr.Stale,
 		DisplayMarkup: cr.ToDisplayString(),
 	}
 }
@@ -234,6 +238,19 @@ func ImportStatusAsError(s *keybase1.Status) error {
 		return SibkeyAlreadyExistsError{}
 	case SCNoUIDelegation:
 		return UIDelegationUnavailableError{}
+	case SCSigchainRollback:
+		ret := SigchainRollbackError{Msg: s.Desc}
+		for _, f := range s.Fields {
+			switch f.Key {
+			case "uid":
+				ret.UID = keybase1.UID(f.Value)
+			case "username":
+				ret.Username = f.Value
+			case "msg":
+				ret.Msg = f.Value
+			}
+		}
+		return ret
 	default:
 		ase := AppStatusError{
 			Code:   s.Code,
@@ -347,6 +364,16 @@ func (ir *IdentifyOutcome) Export() *keybase1.IdentifyOutcome {
 		TrackOptions:      ir.TrackOptions,
 		Reason:            ir.Reason,
 	}
+	if reset := ir.AccountReset(); reset != nil {
+		ret.Reset = &keybase1.AccountReset{
+			TrackedEldest: reset.GetTracked(),
+			Eldest:        reset.GetObserved(),
+			CarriedOver:   ir.CarriedOverProofs(),
+		}
+		if t := reset.GetCTime(); !t.IsZero() {
+			ret.Reset.Ctime = keybase1.ToTime(t)
+		}
+	}
 	return ret
 }
 
@@ -374,6 +401,7 @@ func ImportWarnings(v []string) Warnings {
 func (c CryptocurrencyChainLink) Export() (ret keybase1.Cryptocurrency) {
 	ret.Pkhash = c.pkhash
 	ret.Address = c.address
+	ret.Type = c.typ
 	return
 }
 
@@ -541,11 +569,22 @@ func (bundle *PGPKeyBundle) Export() keybase1.PublicKey {
 	for _, identity := range bundle.Identities {
 		identities = append(identities, ExportPGPIdentity(identity))
 	}
+	primary, encryption := bundle.Expirations()
 	return keybase1.PublicKey{
 		KID:            kid,
 		PGPFingerprint: fingerprintStr,
 		PGPIdentities:  identities,
+		PGPKeyETime:    exportExpiration(primary),
+		PGPSubkeyETime: exportExpiration(encryption),
+	}
+}
+
+// exportExpiration is 0 for keys that never expire.
+func exportExpiration(t time.Time) keybase1.Time {
+	if t.IsZero() {
+		return 0
 	}
+	return keybase1.ToTime(t)
 }
 
 func (ckf ComputedKeyFamily) Export() []keybase1.PublicKey {
@@ -554,11 +593,13 @@ func (ckf ComputedKeyFamily) Export() []keybase1.PublicKey {
 		kid := key.GetKID()
 		fingerprintStr := ""
 		identities := []keybase1.PGPIdentity{}
+		var pgpETime, pgpSubkeyETime time.Time
 		if pgpBundle, isPGP := key.(*PGPKeyBundle); isPGP {
 			fingerprintStr = pgpBundle.GetFingerprint().String()
 			for _, identity := range pgpBundle.Identities {
 				identities = append(identities, ExportPGPIdentity(identity))
 			}
+			pgpETime, pgpSubkeyETime = pgpBundle.Expirations()
 		}
 		cki := ckf.cki.Infos[kid]
 		deviceID := ckf.cki.KIDToDeviceID[kid]
@@ -589,6 +630,8 @@ func (ckf ComputedKeyFamily) Export() []keybase1.PublicKey {
 			DeviceDescription: deviceDescription,
 			CTime:             keybase1.TimeFromSeconds(cki.CTime),
 			ETime:             keybase1.TimeFromSeconds(cki.ETime),
+			PGPKeyETime:       exportExpiration(pgpETime),
+			PGPSubkeyETime:    exportExpiration(pgpSubkeyETime),
 		})
 	}
 	for _, sibkey := range ckf.GetAllActiveSibkeys() {
@@ -657,6 +700,7 @@ func (u *User) Export() *keybase1.User {
 //=============================================================================
 
 func (a PGPGenArg) ExportTo(ret *keybase1.PGPKeyGenArg) {
+	ret.Algorithm = keybase1.PGPKeyAlgorithm(a.Algorithm)
 	ret.PrimaryBits = a.PrimaryBits
 	ret.SubkeyBits = a.SubkeyBits
 	ret.CreateUids = keybase1.PGPCreateUids{UseDefault: !a.NoDefPGPUid, Ids: a.Ids.Export()}
@@ -666,6 +710,7 @@ func (a PGPGenArg) ExportTo(ret *keybase1.PGPKeyGenArg) {
 //=============================================================================
 
 func ImportKeyGenArg(a keybase1.PGPKeyGenArg) (ret PGPGenArg) {
+	ret.Algorithm = packet.PublicKeyAlgorithm(a.Algorithm)
 	ret.PrimaryBits = a.PrimaryBits
 	ret.SubkeyBits = a.SubkeyBits
 	ret.NoDefPGPUid = !a.CreateUids.UseDefault
@@ -830,3 +875,66 @@ func (e UIDelegationUnavailableError) ToStatus() keybase1.Status {
 		Desc: e.Error(),
 	}
 }
+
+func (e SigchainRollbackError) ToStatus() keybase1.Status {
+	return keybase1.Status{
+		Code: SCSigchainRollback,
+		Name: "SC_SIGCHAIN_ROLLBACK",
+		Desc: e.Error(),
+		Fields: []keybase1.StringKVPair{
+			{Key: "uid", Value: e.UID.String()},
+			{Key: "username", Value: e.Username},
+			{Key: "msg", Value: e.Msg},
+		},
+	}
+}
+
+//=============================================================================
+
+// Export exports a link, and its check if verified.
+func (c ChainLinkCheck) Export(verified bool) keybase1.SigchainLink {
+	l := c.Link
+	ret := keybase1.SigchainLink{
+		Seqno:       int(l.GetSeqno()),
+		SigID:       l.GetSigID(),
+		LinkID:      l.id.String(),
+		Prev:        l.GetPrev().String(),
+		Type:        l.unpacked.typ,
+		CTime:       keybase1.ToTime(l.GetCTime()),
+		ETime:       keybase1.ToTime(l.GetETime()),
+		Kid:         l.GetKID(),
+		PayloadJSON: l.unpacked.payloadJSONStr,
+		Revoked:     l.revoked,
+		Revokes:     l.GetRevocations(),
+		RevokesKIDs: l.GetRevokeKids(),
+		Ignored:     c.Ignored,
+	}
+	if tcl := l.Typed(); tcl != nil {
+		ret.Revoked = tcl.IsRevoked()
+		if tcl.GetRole() != DLGNone {
+			ret.Delegates = tcl.GetDelegatedKid()
+		}
+	}
+	switch ret.Type {
+	case string(EldestType), string(SibkeyType), string(SubkeyType), string(PGPUpdateType):
+		ret.KeyFamilyChange = true
+	default:
+		ret.KeyFamilyChange = len(ret.RevokesKIDs) > 0 || ret.Delegates.Exists()
+	}
+	if verified {
+		ret.Checked = c.Checked
+		ret.HashError = exportErr(c.HashErr)
+		ret.PayloadError = exportErr(c.PayloadErr)
+		ret.SigError = exportErr(c.SigErr)
+		ret.ChainError = exportErr(c.ChainErr)
+	}
+	return ret
+}
+
+// exportErr is an error's message, or empty for no error.
+func exportErr(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -252,6 +252,21 @@ func (s *Session) check() error {
 		return nil
 	}
 
+	if s.G().IsOffline() {
+		// We can't ask the server, so trust the config.  Don't mark the
+		// session as checked, so that it's checked once we're online.
+		if !s.isConfigLoggedIn() {
+			return nil
+		}
+		s.G().Log.Debug("- offline; trusting the config")
+		cr := s.G().Env.GetConfig()
+		s.valid = true
+		s.uid = cr.GetUID()
+		nu := cr.GetUsername()
+		s.username = &nu
+		return nil
+	}
+
 	res, err := s.G().API.Get(APIArg{
 		SessionR:    s,
 		Endpoint:    "sesscheck",
@@ -575,6 +575,10 @@ type SigChainLoader struct {
 	// loaded, and here's the existing sigchain.
 	preload *SigChain
 
+	// What we've seen of the chain before, which the loaded one has to
+	// be consistent with.
+	history *SigChainHistory
+
 	Contextified
 }
 
@@ -817,6 +821,39 @@ func (l *SigChainLoader) StoreTail() (err error) {
 	return
 }
 
+func (l *SigChainLoader) eldest() keybase1.KID {
+	if l.leaf != nil {
+		return l.leaf.eldest
+	}
+	return l.user.GetEldestKID()
+}
+
+// CheckHistory checks the loaded chain against every tail of it we've
+// seen before.  If it looks like the server rolled the chain back, that's
+// an error, and clients listening for user notifications hear about it.
+func (l *SigChainLoader) CheckHistory() (err error) {
+	if l.history == nil {
+		if l.history, err = LoadSigChainHistory(l.G(), l.user.GetUID()); err != nil {
+			return err
+		}
+	}
+	err = l.history.Check(l.chain, l.eldest())
+	if rerr, ok := err.(SigchainRollbackError); ok {
+		l.G().Log.Warning("%s", rerr)
+		l.G().NotifyRouter.HandleSigchainRollback(rerr.UID, rerr.Username, rerr.Msg)
+	}
+	return err
+}
+
+// StoreHistory records the loaded chain's tail in its history.
+func (l *SigChainLoader) StoreHistory() error {
+	if l.history == nil {
+		return nil
+	}
+	l.history.Observe(l.chain.GetCurrentTailTriple(), l.eldest())
+	return l.history.Store()
+}
+
 // Store a SigChain to local storage as a result of having loaded it.
 // We eagerly write loaded chain links to storage if they verify properly.
 func (l *SigChainLoader) Store() (err error) {
@@ -824,9 +861,43 @@ func (l *SigChainLoader) Store() (err error) {
 	if err == nil {
 		err = l.chain.Store()
 	}
+	if err == nil {
+		err = l.StoreHistory()
+	}
 	return
 }
 
+// VerifyStoredSigChain checks that uid's user record and public sig chain
+// in the local database still parse, and that the links of the chain
+// still hash, link up and verify, without going to the server.
+func VerifyStoredSigChain(g *GlobalContext, uid keybase1.UID) error {
+	u, err := loadUserFromLocalStorage(g, uid)
+	if err != nil {
+		return err
+	}
+	if u == nil {
+		return NotFoundError{msg: fmt.Sprintf("no stored user record for %s", uid)}
+	}
+
+	l := SigChainLoader{
+		user:         u,
+		self:         uid.Equal(g.Env.GetUID()),
+		chainType:    PublicChain,
+		Contextified: NewContextified(g),
+	}
+	if err = l.GetKeyFamily(); err != nil {
+		return err
+	}
+	if err = l.LoadLinksFromStorage(); err != nil {
+		return err
+	}
+	if err = l.MakeSigChain(); err != nil {
+		return err
+	}
+	_, err = l.chain.VerifySigsAndComputeKeys(u.GetEldestKID(), &l.ckf)
+	return err
+}
+
 // Load is the main entry point into the SigChain loader.  It runs through
 // all of the steps to load a chain in from storage, to refresh it against
 // the server, and to verify its integrity.
@@ -886,6 +957,11 @@ func (l *SigChainLoader) Load() (ret *SigChain, err error) {
 	} else if l.chain.GetComputedKeyInfos() == nil {
 		l.G().Log.Debug("| Need to reverify chain since we don't have ComputedKeyInfos")
 	} else {
+		stage("CheckHistory")
+		if err = l.CheckHistory(); err != nil {
+			return
+		}
+		err = l.StoreHistory()
 		return
 	}
 
@@ -893,6 +969,10 @@ func (l *SigChainLoader) Load() (ret *SigChain, err error) {
 	if err = l.chain.VerifyChain(); err != nil {
 		return
 	}
+	stage("CheckHistory")
+	if err = l.CheckHistory(); err != nil {
+		return
+	}
 	stage("Store")
 	if err = l.chain.Store(); err != nil {
 		return
@@ -0,0 +1,134 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+)
+
+// ChainLinkCheck is what checking a link of a sigchain again, from
+// scratch, found.  Unlike the checks done when loading a user, these
+// don't use or update the cached results on the link.
+type ChainLinkCheck struct {
+	Link *ChainLink
+	// Checked is false for links from before the last account reset,
+	// whose keys aren't in the current key family.
+	Checked bool
+	// Ignored is why a known bad link is skipped, if it is.
+	Ignored    string
+	HashErr    error
+	PayloadErr error
+	SigErr     error
+	ChainErr   error
+}
+
+// OK is whether the link passed all of its checks.
+func (c ChainLinkCheck) OK() bool {
+	return c.HashErr == nil && c.PayloadErr == nil && c.SigErr == nil && c.ChainErr == nil
+}
+
+// SigChainLinks returns the user's public sigchain links, oldest first.
+func (u *User) SigChainLinks() []*ChainLink {
+	if u.sigChain() == nil {
+		return nil
+	}
+	return u.sigChain().chainLinks
+}
+
+// RecheckSigChain checks each link of the user's sigchain again: its hash,
+// that its signature is over its payload, that it's signed by a key that
+// was active at the time, and that it follows the link before it.  The
+// checks are the ones done when the chain is loaded, minus their cached
+// results.
+func (u *User) RecheckSigChain() []ChainLinkCheck {
+	links := u.SigChainLinks()
+	current := make(map[*ChainLink]bool)
+	if sc := u.sigChain(); sc != nil {
+		sub, err := sc.GetCurrentSubchain(u.GetEldestKID())
+		if err == nil {
+			for _, l := range sub {
+				current[l] = true
+			}
+		}
+	}
+	ckf := u.GetComputedKeyFamily()
+
+	ret := make([]ChainLinkCheck, len(links))
+	for i, link := range links {
+		res := ChainLinkCheck{Link: link}
+		if bad, reason := link.IsBad(); bad {
+			res.Ignored = reason
+		}
+
+		res.HashErr = link.checkHash()
+		if sigID, err := link.checkPayload(); err != nil {
+			res.PayloadErr = err
+		} else if sigID != link.unpacked.sigID {
+			res.PayloadErr = fmt.Errorf("sig ID %s doesn't match %s", sigID, link.unpacked.sigID)
+		}
+
+		if i > 0 {
+			prev := links[i-1]
+			if !prev.id.Eq(link.GetPrev()) {
+				res.ChainErr = ChainLinkPrevHashMismatchError{fmt.Sprintf("Chain mismatch at seqno=%d", link.GetSeqno())}
+			} else if prev.GetSeqno()+1 != link.GetSeqno() {
+				res.ChainErr = ChainLinkWrongSeqnoError{fmt.Sprintf("Chain seqno mismatch at seqno=%d (previous=%d)", link.GetSeqno(), prev.GetSeqno())}
+			}
+		}
+		if res.ChainErr == nil {
+			res.ChainErr = link.CheckNameAndID(u.GetNormalizedName(), u.GetUID())
+		}
+
+		if current[link] && ckf != nil && len(res.Ignored) == 0 {
+			res.Checked = true
+			res.SigErr = link.recheckSig(*ckf)
+		}
+		ret[i] = res
+	}
+	return ret
+}
+
+// recheckSig is VerifySigWithKeyFamily without its cached checks.
+func (c *ChainLink) recheckSig(ckf ComputedKeyFamily) error {
+	if err := c.checkServerSignatureMetadata(ckf); err != nil {
+		return err
+	}
+	key, _, err := ckf.FindActiveSibkeyAtTime(c.GetKID(), c.GetCTime())
+	if err != nil {
+		return err
+	}
+	if _, err = key.VerifyString(c.unpacked.sig, c.getFixedPayload()); err != nil {
+		return BadSigError{err.Error()}
+	}
+	return nil
+}
+
+// CheckMerkleTail looks the user up in the server's Merkle tree, and checks
+// that the tail of the loaded sigchain is the one in the tree.
+func (u *User) CheckMerkleTail() (*MerkleTriple, error) {
+	leaf, err := lookupMerkleLeaf(u.G(), u.GetUID(), u)
+	if err != nil {
+		return nil, err
+	}
+	return u.checkMerkleTail(leaf)
+}
+
+// checkMerkleTail checks the loaded sigchain's tail against leaf.
+func (u *User) checkMerkleTail(leaf *MerkleUserLeaf) (*MerkleTriple, error) {
+	if leaf == nil || leaf.public == nil {
+		return nil, MerkleNotFoundError{u.GetUID().String(), "no public sigchain in the tree"}
+	}
+	var tail *MerkleTriple
+	if sc := u.sigChain(); sc != nil {
+		tail = sc.GetCurrentTailTriple()
+	}
+	if tail == nil {
+		return leaf.public, NewServerChainError("The tree has seqno=%d, but no sigchain was loaded", leaf.public.Seqno)
+	}
+	if tail.Seqno != leaf.public.Seqno || !tail.LinkID.Eq(leaf.public.LinkID) {
+		return leaf.public, NewServerChainError("The tree has seqno=%d (%s), but the sigchain ends at seqno=%d (%s)",
+			leaf.public.Seqno, leaf.public.LinkID, tail.Seqno, tail.LinkID)
+	}
+	return leaf.public, nil
+}
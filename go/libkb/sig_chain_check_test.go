@@ -0,0 +1,119 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"strings"
+	"testing"
+)
+
+func loadRalph(t *testing.T, tc TestContext) *User {
+	sc, _ := loadRalphChain(t, tc)
+	return &User{name: "ralph", id: sc.uid, sigChainMem: sc, Contextified: NewContextified(tc.G)}
+}
+
+func TestRecheckSigChain(t *testing.T) {
+	tc := SetupTest(t, "sig_chain_check")
+	defer tc.Cleanup()
+
+	u := loadRalph(t, tc)
+	checks := u.RecheckSigChain()
+	if len(checks) != len(u.SigChainLinks()) {
+		t.Fatalf("%d checks for %d links", len(checks), len(u.SigChainLinks()))
+	}
+	for _, c := range checks {
+		if !c.OK() {
+			t.Errorf("link %d: hash %v, payload %v, sig %v, chain %v", c.Link.GetSeqno(), c.HashErr, c.PayloadErr, c.SigErr, c.ChainErr)
+		}
+	}
+}
+
+func TestRecheckSigChainTamperedPayload(t *testing.T) {
+	tc := SetupTest(t, "sig_chain_check")
+	defer tc.Cleanup()
+
+	u := loadRalph(t, tc)
+	links := u.SigChainLinks()
+	link := links[1]
+	// Checked once, so that the result is cached, which the recheck
+	// mustn't rely on.
+	if err := link.VerifyLink(); err != nil {
+		t.Fatal(err)
+	}
+	// Pretend the server rewrote the payload, and rehashed it so that
+	// the hash still checks out.
+	link.unpacked.payloadJSONStr = strings.Replace(link.unpacked.payloadJSONStr, `"seqno"`, `"seqno" `, 1)
+	link.id = ComputeLinkID([]byte(link.unpacked.payloadJSONStr))
+
+	checks := u.RecheckSigChain()
+	if checks[1].HashErr != nil {
+		t.Errorf("hash error for a rehashed link: %s", checks[1].HashErr)
+	}
+	if checks[1].PayloadErr == nil {
+		t.Error("no payload error for a tampered payload")
+	}
+	// The next link doesn't follow the rehashed one.
+	if len(links) > 2 && checks[2].ChainErr == nil {
+		t.Error("no chain error after a rehashed link")
+	}
+	if !checks[0].OK() {
+		t.Error("the link before the tampered one failed")
+	}
+}
+
+func TestRecheckSigChainTamperedHash(t *testing.T) {
+	tc := SetupTest(t, "sig_chain_check")
+	defer tc.Cleanup()
+
+	u := loadRalph(t, tc)
+	last := u.SigChainLinks()[len(u.SigChainLinks())-1]
+	if err := last.VerifyLink(); err != nil {
+		t.Fatal(err)
+	}
+	id := make(LinkID, len(last.id))
+	copy(id, last.id)
+	id[0] ^= 0xff
+	last.id = id
+
+	checks := u.RecheckSigChain()
+	c := checks[len(checks)-1]
+	if c.HashErr == nil {
+		t.Error("no hash error for a tampered hash")
+	}
+	if c.PayloadErr != nil {
+		t.Errorf("payload error for an untouched payload: %s", c.PayloadErr)
+	}
+	if c.OK() {
+		t.Error("a link with a tampered hash passed")
+	}
+}
+
+func TestCheckMerkleTail(t *testing.T) {
+	tc := SetupTest(t, "sig_chain_check")
+	defer tc.Cleanup()
+
+	u := loadRalph(t, tc)
+	tail := u.sigChain().GetCurrentTailTriple()
+
+	if _, err := u.checkMerkleTail(&MerkleUserLeaf{public: tail}); err != nil {
+		t.Fatalf("matching tail: %s", err)
+	}
+
+	ahead := &MerkleTriple{Seqno: tail.Seqno + 1, LinkID: tail.LinkID}
+	if _, err := u.checkMerkleTail(&MerkleUserLeaf{public: ahead}); err == nil {
+		t.Error("no error for a tree that's ahead of the chain")
+	}
+
+	otherID := make(LinkID, len(tail.LinkID))
+	copy(otherID, tail.LinkID)
+	otherID[0] ^= 0xff
+	forked := &MerkleTriple{Seqno: tail.Seqno, LinkID: otherID}
+	if _, err := u.checkMerkleTail(&MerkleUserLeaf{public: forked}); err == nil {
+		t.Error("no error for a tree with another tail")
+	}
+
+	if _, err := u.checkMerkleTail(&MerkleUserLeaf{}); err == nil {
+		t.Error("no error for a user who isn't in the tree")
+	}
+}
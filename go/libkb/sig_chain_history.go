@@ -0,0 +1,134 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"fmt"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// sigChainHistoryMax is how many observations of a chain we keep. Links
+// hash their way back to the start of the chain, so checking the newest
+// observations also checks the links of the ones dropped before them.
+const sigChainHistoryMax = 256
+
+// SigChainObservation is a tail of a user's public sigchain that we've
+// seen, and the eldest key the Merkle tree had for them then.
+type SigChainObservation struct {
+	Seqno  Seqno        `json:"seqno"`
+	LinkID LinkID       `json:"id"`
+	Eldest keybase1.KID `json:"eldest,omitempty"`
+	Time   int64        `json:"time"`
+}
+
+// SigChainHistory is every tail of a user's public sigchain that we've
+// seen. Unlike the stored tail, which just says where to load the chain
+// from, it isn't evicted with the rest of a user's cached data, so the
+// server can't roll a chain back by waiting for us to forget it.
+type SigChainHistory struct {
+	Contextified
+	uid      keybase1.UID
+	Observed []SigChainObservation `json:"observed"`
+	dirty    bool
+}
+
+func sigChainHistoryDbKey(uid keybase1.UID) DbKey {
+	return DbKeyUID(DBSigChainHistory, uid)
+}
+
+// LoadSigChainHistory loads what we've seen of uid's sigchain, which is
+// nothing if we've never loaded it.
+func LoadSigChainHistory(g *GlobalContext, uid keybase1.UID) (*SigChainHistory, error) {
+	h := &SigChainHistory{Contextified: NewContextified(g), uid: uid}
+	if _, err := g.LocalDb.GetInto(h, sigChainHistoryDbKey(uid)); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Check checks a newly loaded chain, whose eldest key in the Merkle tree
+// is eldest, against what we've seen of it before.  The chain can't be
+// shorter than it was, can't have a different link at a seqno we've
+// seen, and if the eldest key changed, a link we hadn't seen yet has to
+// start a subchain for the new one.
+func (h *SigChainHistory) Check(sc *SigChain, eldest keybase1.KID) error {
+	if len(h.Observed) == 0 {
+		return nil
+	}
+	var seqno Seqno
+	if tail := sc.GetCurrentTailTriple(); tail != nil {
+		seqno = tail.Seqno
+	}
+	for _, o := range h.Observed {
+		if o.Seqno > seqno {
+			return h.rollbackError(sc, "the chain ends at seqno=%d, but we've seen it at seqno=%d", seqno, o.Seqno)
+		}
+		// Links before the ones loaded were checked when they were.
+		link := sc.GetLinkFromSeqno(int(o.Seqno))
+		if link != nil && !link.id.Eq(o.LinkID) {
+			return h.rollbackError(sc, "seqno=%d is %s, but we've seen %s there", o.Seqno, link.id, o.LinkID)
+		}
+	}
+
+	// An account reset that hasn't added a new key yet leaves no eldest
+	// key, and so no keys to trust that a rollback could bring back.
+	last := h.Observed[len(h.Observed)-1]
+	if last.Eldest.IsNil() || eldest.IsNil() || last.Eldest.Equal(eldest) {
+		return nil
+	}
+	sub, err := sc.GetCurrentSubchain(eldest)
+	if err != nil {
+		return err
+	}
+	if len(sub) == 0 || sub[0].GetSeqno() <= last.Seqno {
+		return h.rollbackError(sc, "the eldest key changed from %s to %s without a new link for it after seqno=%d", last.Eldest, eldest, last.Seqno)
+	}
+	return nil
+}
+
+func (h *SigChainHistory) rollbackError(sc *SigChain, format string, args ...interface{}) SigchainRollbackError {
+	return SigchainRollbackError{
+		UID:      h.uid,
+		Username: sc.username.String(),
+		Msg:      fmt.Sprintf(format, args...),
+	}
+}
+
+// Observe records tail, with the eldest key the Merkle tree had for it,
+// if it isn't the last tail we saw.
+func (h *SigChainHistory) Observe(tail *MerkleTriple, eldest keybase1.KID) {
+	if tail == nil {
+		return
+	}
+	if n := len(h.Observed); n > 0 {
+		last := h.Observed[n-1]
+		if last.Seqno == tail.Seqno && last.LinkID.Eq(tail.LinkID) && last.Eldest.Equal(eldest) {
+			return
+		}
+	}
+	h.Observed = append(h.Observed, SigChainObservation{
+		Seqno:  tail.Seqno,
+		LinkID: tail.LinkID,
+		Eldest: eldest,
+		Time:   time.Now().Unix(),
+	})
+	if n := len(h.Observed); n > sigChainHistoryMax {
+		h.Observed = h.Observed[n-sigChainHistoryMax:]
+	}
+	h.dirty = true
+}
+
+// Store writes the history back to the local database, if it changed.
+func (h *SigChainHistory) Store() error {
+	if !h.dirty {
+		return nil
+	}
+	if err := h.G().LocalDb.PutObj(sigChainHistoryDbKey(h.uid), nil, h); err != nil {
+		return err
+	}
+	h.dirty = false
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"encoding/json"
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+	jsonw "github.com/keybase/go-jsonw"
+	testvectors "github.com/keybase/keybase-test-vectors/go"
+)
+
+// loadRalphChain loads ralph's chain, whose first two links are under his
+// first eldest key and the rest under his second.
+func loadRalphChain(t *testing.T, tc TestContext) (*SigChain, map[string]keybase1.KID) {
+	inputJSON := testvectors.ChainTestInputs["ralph_chain.json"]
+	var input TestInput
+	if err := json.Unmarshal([]byte(inputJSON), &input); err != nil {
+		t.Fatal(err)
+	}
+	blob, err := jsonw.Unmarshal([]byte(inputJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid, err := UIDFromHex(input.UID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := blob.AtKey("chain").Len()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := &SigChain{username: NewNormalizedUsername(input.Username), uid: uid, Contextified: NewContextified(tc.G)}
+	for i := 0; i < n; i++ {
+		link, err := ImportLinkFromServer(sc, blob.AtKey("chain").AtIndex(i), uid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sc.chainLinks = append(sc.chainLinks, link)
+	}
+	kids := make(map[string]keybase1.KID)
+	for label, kid := range input.LabelKids {
+		kids[label] = keybase1.KIDFromString(kid)
+	}
+	return sc, kids
+}
+
+func TestSigChainHistory(t *testing.T) {
+	tc := SetupTest(t, "sig_chain_history")
+	defer tc.Cleanup()
+
+	sc, kids := loadRalphChain(t, tc)
+	eldest := kids["second_eldest"]
+
+	h, err := LoadSigChainHistory(tc.G, sc.uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Check(sc, eldest); err != nil {
+		t.Fatalf("nothing seen yet, but: %s", err)
+	}
+	h.Observe(sc.GetCurrentTailTriple(), eldest)
+	if err := h.Store(); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err = LoadSigChainHistory(tc.G, sc.uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Observed) != 1 {
+		t.Fatalf("loaded %d observations, expected 1", len(h.Observed))
+	}
+	if err := h.Check(sc, eldest); err != nil {
+		t.Fatal(err)
+	}
+
+	// The revocation-free chain the server would like us to see instead.
+	truncated := *sc
+	truncated.chainLinks = sc.chainLinks[:len(sc.chainLinks)-1]
+	if _, ok := h.Check(&truncated, eldest).(SigchainRollbackError); !ok {
+		t.Error("a shorter chain was accepted")
+	}
+
+	forked := &SigChainHistory{Contextified: NewContextified(tc.G), uid: sc.uid}
+	forked.Observe(&MerkleTriple{Seqno: 5, LinkID: sc.chainLinks[3].id}, eldest)
+	if _, ok := forked.Check(sc, eldest).(SigchainRollbackError); !ok {
+		t.Error("a different link at a seen seqno was accepted")
+	}
+}
+
+func TestSigChainHistoryReset(t *testing.T) {
+	tc := SetupTest(t, "sig_chain_history")
+	defer tc.Cleanup()
+
+	sc, kids := loadRalphChain(t, tc)
+
+	// Seen before the reset: the new eldest key's links come after.
+	h := &SigChainHistory{Contextified: NewContextified(tc.G), uid: sc.uid}
+	h.Observe(sc.chainLinks[1].ToMerkleTriple(), kids["first_eldest"])
+	if err := h.Check(sc, kids["second_eldest"]); err != nil {
+		t.Error(err)
+	}
+
+	// Seen after it, but under the old eldest key.
+	h = &SigChainHistory{Contextified: NewContextified(tc.G), uid: sc.uid}
+	h.Observe(sc.chainLinks[3].ToMerkleTriple(), kids["first_eldest"])
+	if _, ok := h.Check(sc, kids["second_eldest"]).(SigchainRollbackError); !ok {
+		t.Error("an eldest key change without a new link was accepted")
+	}
+
+	// A key that none of the links are for.
+	h = &SigChainHistory{Contextified: NewContextified(tc.G), uid: sc.uid}
+	h.Observe(sc.GetCurrentTailTriple(), kids["second_eldest"])
+	if _, ok := h.Check(sc, kids["new_eldest"]).(SigchainRollbackError); !ok {
+		t.Error("an eldest key with no links was accepted")
+	}
+}
@@ -166,7 +166,7 @@ func (sh *SigHints) Refresh() error {
 
 func LoadAndRefreshSigHints(uid keybase1.UID, g *GlobalContext) (sh *SigHints, err error) {
 	sh, err = LoadSigHints(uid, g)
-	if err == nil {
+	if err == nil && !g.IsOffline() {
 		err = sh.Refresh()
 	}
 	return
@@ -216,6 +216,17 @@ func (s *SKB) RawUnlockedKey() []byte {
 	return s.decryptedRaw
 }
 
+// scrubUnlocked zeroes and forgets the unlocked secret key, so that it
+// has to be unlocked again.
+func (s *SKB) scrubUnlocked() {
+	for i := range s.decryptedRaw {
+		s.decryptedRaw[i] = 0
+	}
+	s.decryptedRaw = nil
+	scrubSecretKey(s.decryptedSecret)
+	s.decryptedSecret = nil
+}
+
 func (s *SKB) unlockSecretKeyFromSecretRetriever(secretRetriever SecretRetriever) (key GenericKey, err error) {
 	if key = s.decryptedSecret; key != nil {
 		return
@@ -406,6 +417,27 @@ func (s *SKB) lksUnlockWithSecretRetriever(secretRetriever SecretRetriever) (unl
 	return
 }
 
+// unlockWithKeyProtector unlocks an LKSec-protected key with the client
+// half that kp keeps.
+func (s *SKB) unlockWithKeyProtector(lctx LoginContext, kp KeyProtector, lksPreload *LKSec) (GenericKey, error) {
+	clientHalf, err := kp.Unprotect()
+	if err != nil {
+		return nil, err
+	}
+	s.Lock()
+	uid := s.uid
+	s.Unlock()
+	lks := NewLKSecWithClientHalf(clientHalf, PassphraseGeneration(0), uid, s.G())
+	if lksPreload != nil {
+		lks.SetServerHalf(lksPreload.GetServerHalf())
+	}
+	unlocked, _, err := lks.Decrypt(lctx, s.Priv.Data)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseUnlocked(unlocked)
+}
+
 func (s *SKB) SetUID(uid keybase1.UID) {
 	G.Log.Debug("| Setting UID on SKB to %s", uid)
 	s.Lock()
@@ -430,6 +462,14 @@ func NewSKBKeyringFile(n string) *SKBKeyringFile {
 	}
 }
 
+// scrubUnlocked zeroes the secret keys that were unlocked from the
+// keyring.
+func (k *SKBKeyringFile) scrubUnlocked() {
+	for _, s := range k.Blocks {
+		s.scrubUnlocked()
+	}
+}
+
 func (k *SKBKeyringFile) Load() (err error) {
 	G.Log.Debug("+ Loading SKB keyring: %s", k.filename)
 	var packets KeybasePackets
@@ -577,6 +617,36 @@ func (k *SKBKeyringFile) Push(skb *SKB) error {
 	return nil
 }
 
+// RemoveKIDs drops the secret keys with the given KIDs from the keyring,
+// and returns how many were found.  Call Save to write the change out.
+func (k *SKBKeyringFile) RemoveKIDs(kids []keybase1.KID) (int, error) {
+	drop := make(map[keybase1.KID]bool)
+	for _, kid := range kids {
+		drop[kid] = true
+	}
+
+	var keep []*SKB
+	for _, b := range k.Blocks {
+		key, err := b.GetPubKey()
+		if err != nil {
+			return 0, err
+		}
+		if key == nil || !drop[key.GetKID()] {
+			keep = append(keep, b)
+		}
+	}
+	n := len(k.Blocks) - len(keep)
+	if n == 0 {
+		return 0, nil
+	}
+
+	k.Blocks = keep
+	k.fpIndex = make(map[PGPFingerprint]*SKB)
+	k.kidIndex = make(map[keybase1.KID]*SKB)
+	k.dirty = true
+	return n, k.Index()
+}
+
 func (k SKBKeyringFile) GetFilename() string { return k.filename }
 
 func (k SKBKeyringFile) WriteTo(w io.Writer) (int64, error) {
@@ -653,14 +723,24 @@ func (s *SKB) UnlockNoPrompt(lctx LoginContext, secretStore SecretStore, lksPrel
 		// fall through if we failed to unlock with retrieved secret...
 	}
 
+	// try the client half kept by the key protector:
+	if s.Priv.Encryption == LKSecVersion {
+		key, err := s.unlockWithKeyProtector(lctx, CurrentKeyProtector(s.G()), lksPreload)
+		s.G().Log.Debug("| unlockWithKeyProtector -> %s", ErrToOk(err))
+		if err == nil {
+			return key, nil
+		}
+	}
+
 	// try using the passphrase stream cache
 	var tsec *triplesec.Cipher
 	var pps *PassphraseStream
 	if lctx != nil {
-		tsec = lctx.PassphraseStreamCache().Triplesec()
-		pps = lctx.PassphraseStreamCache().PassphraseStream()
+		sc := lctx.UsePassphraseStreamCache()
+		tsec = sc.Triplesec()
+		pps = sc.PassphraseStream()
 	} else {
-		s.G().LoginState().PassphraseStreamCache(func(sc *PassphraseStreamCache) {
+		s.G().LoginState().UsePassphraseStreamCache(func(sc *PassphraseStreamCache) {
 			tsec = sc.Triplesec()
 			pps = sc.PassphraseStream()
 		}, "skb - PromptAndUnlock - tsec, pps")
@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"testing"
 
+	keybase1 "github.com/keybase/client/go/protocol"
 	"github.com/keybase/go-crypto/openpgp"
 	triplesec "github.com/keybase/go-triplesec"
 	"github.com/ugorji/go/codec"
@@ -201,3 +202,47 @@ func TestUnusedSecretStore(t *testing.T) {
 		t.Errorf("secret unexpectedly non-empty")
 	}
 }
+
+func TestSKBKeyringRemoveKIDs(t *testing.T) {
+	tc := SetupTest(t, "skb_keyring_remove_kids")
+	defer tc.Cleanup()
+
+	lks := makeTestLKSec(t, tc.G)
+	kr := NewSKBKeyringFile(tc.G.SKBFilenameForUser("alice"))
+
+	var kids []keybase1.KID
+	for i := 0; i < 3; i++ {
+		key, err := GenerateNaclSigningKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		skb, err := key.ToLksSKB(lks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := kr.Push(skb); err != nil {
+			t.Fatal(err)
+		}
+		kids = append(kids, key.GetKID())
+	}
+
+	n, err := kr.RemoveKIDs([]keybase1.KID{kids[0], kids[2], "0120deadbeef"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("removed %d keys, wanted 2", n)
+	}
+	if len(kr.Blocks) != 1 {
+		t.Fatalf("%d keys left, wanted 1", len(kr.Blocks))
+	}
+	if kr.LookupByKid(kids[0]) != nil || kr.LookupByKid(kids[2]) != nil {
+		t.Error("removed keys are still indexed")
+	}
+	if kr.LookupByKid(kids[1]) == nil {
+		t.Error("kept key isn't indexed")
+	}
+	if !kr.dirty {
+		t.Error("keyring should need saving")
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// statBucketBounds are the upper bounds (inclusive) of the latency
+// histogram buckets.  Anything slower falls into a final overflow bucket.
+var statBucketBounds = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+type statKey struct {
+	category keybase1.StatCategory
+	name     string
+}
+
+type statEntry struct {
+	count   int
+	errors  int
+	total   time.Duration
+	max     time.Duration
+	buckets []int
+}
+
+func newStatEntry() *statEntry {
+	return &statEntry{buckets: make([]int, len(statBucketBounds)+1)}
+}
+
+func (e *statEntry) record(d time.Duration, failed bool) {
+	e.count++
+	if failed {
+		e.errors++
+	}
+	e.total += d
+	if d > e.max {
+		e.max = d
+	}
+	i := sort.Search(len(statBucketBounds), func(i int) bool { return d <= statBucketBounds[i] })
+	e.buckets[i]++
+}
+
+func (e *statEntry) export(k statKey) keybase1.StatEntry {
+	ret := keybase1.StatEntry{
+		Category: k.category,
+		Name:     k.name,
+		Count:    e.count,
+		Errors:   e.errors,
+		TotalMs:  int64(e.total / time.Millisecond),
+		MaxMs:    int64(e.max / time.Millisecond),
+	}
+	for i, n := range e.buckets {
+		var bound int64
+		if i < len(statBucketBounds) {
+			bound = int64(statBucketBounds[i] / time.Millisecond)
+		}
+		ret.Histogram = append(ret.Histogram, keybase1.LatencyBucket{UpperBoundMs: bound, Count: n})
+	}
+	return ret
+}
+
+// StatsRegistry aggregates timings of API calls, engines, RPCs, proof
+// checks and merkle lookups for the lifetime of the process.  Unlike
+// the TimerSet, which only logs individual measurements when asked to,
+// the registry is always on, and is safe to use from many goroutines.
+type StatsRegistry struct {
+	sync.Mutex
+	since   time.Time
+	entries map[statKey]*statEntry
+}
+
+func NewStatsRegistry() *StatsRegistry {
+	return &StatsRegistry{
+		since:   time.Now(),
+		entries: make(map[statKey]*statEntry),
+	}
+}
+
+// Record adds one measurement of duration d to the entry for the given
+// category and name.  A non-nil err counts the call as a failure.
+func (s *StatsRegistry) Record(cat keybase1.StatCategory, name string, d time.Duration, err error) {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	k := statKey{category: cat, name: name}
+	e, ok := s.entries[k]
+	if !ok {
+		e = newStatEntry()
+		s.entries[k] = e
+	}
+	e.record(d, err != nil)
+}
+
+// Start returns a function that records the time since Start was
+// called when it is invoked.  Meant to be deferred with a pointer to the
+// caller's named error return, so that failures are counted too.
+func (s *StatsRegistry) Start(cat keybase1.StatCategory, name string) func(*error) {
+	start := time.Now()
+	return func(errp *error) {
+		var err error
+		if errp != nil {
+			err = *errp
+		}
+		s.Record(cat, name, time.Since(start), err)
+	}
+}
+
+// Export returns a snapshot of all entries, sorted by category and then
+// by total time spent, slowest first.
+func (s *StatsRegistry) Export() keybase1.ServiceStats {
+	if s == nil {
+		return keybase1.ServiceStats{}
+	}
+	s.Lock()
+	defer s.Unlock()
+	ret := keybase1.ServiceStats{Since: keybase1.ToTime(s.since)}
+	for k, e := range s.entries {
+		ret.Entries = append(ret.Entries, e.export(k))
+	}
+	sort.Sort(statEntries(ret.Entries))
+	return ret
+}
+
+// Reset drops all recorded measurements.
+func (s *StatsRegistry) Reset() {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.since = time.Now()
+	s.entries = make(map[statKey]*statEntry)
+}
+
+type statEntries []keybase1.StatEntry
+
+func (s statEntries) Len() int      { return len(s) }
+func (s statEntries) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s statEntries) Less(i, j int) bool {
+	if s[i].Category != s[j].Category {
+		return s[i].Category < s[j].Category
+	}
+	if s[i].TotalMs != s[j].TotalMs {
+		return s[i].TotalMs > s[j].TotalMs
+	}
+	return s[i].Name < s[j].Name
+}
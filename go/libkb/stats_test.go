@@ -0,0 +1,71 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package libkb
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+func TestStatsRegistry(t *testing.T) {
+	s := NewStatsRegistry()
+	s.Record(keybase1.StatCategory_API, "GET user/lookup", 3*time.Millisecond, nil)
+	s.Record(keybase1.StatCategory_API, "GET user/lookup", 700*time.Millisecond, errors.New("timeout"))
+	s.Record(keybase1.StatCategory_API, "GET merkle/path", 20*time.Millisecond, nil)
+	s.Record(keybase1.StatCategory_PROOF, "twitter", time.Minute, nil)
+
+	stats := s.Export()
+	if len(stats.Entries) != 3 {
+		t.Fatalf("wanted 3 entries, got %d", len(stats.Entries))
+	}
+
+	lookup := stats.Entries[0]
+	if lookup.Name != "GET user/lookup" {
+		t.Fatalf("wanted slowest API entry first, got %q", lookup.Name)
+	}
+	if lookup.Count != 2 || lookup.Errors != 1 {
+		t.Errorf("count/errors: %d/%d, wanted 2/1", lookup.Count, lookup.Errors)
+	}
+	if lookup.MaxMs != 700 || lookup.TotalMs != 703 {
+		t.Errorf("max/total: %d/%d, wanted 700/703", lookup.MaxMs, lookup.TotalMs)
+	}
+	if len(lookup.Histogram) != len(statBucketBounds)+1 {
+		t.Fatalf("wrong number of buckets: %d", len(lookup.Histogram))
+	}
+	if lookup.Histogram[0].Count != 1 || lookup.Histogram[0].UpperBoundMs != 5 {
+		t.Errorf("bad first bucket: %+v", lookup.Histogram[0])
+	}
+
+	proof := stats.Entries[2]
+	if proof.Category != keybase1.StatCategory_PROOF {
+		t.Fatalf("wanted proof entry last, got %+v", proof)
+	}
+	last := proof.Histogram[len(proof.Histogram)-1]
+	if last.UpperBoundMs != 0 || last.Count != 1 {
+		t.Errorf("slow proof should land in overflow bucket: %+v", last)
+	}
+
+	s.Reset()
+	if n := len(s.Export().Entries); n != 0 {
+		t.Errorf("wanted no entries after reset, got %d", n)
+	}
+}
+
+func TestStatsRegistryStart(t *testing.T) {
+	s := NewStatsRegistry()
+	err := errors.New("failed")
+	s.Start(keybase1.StatCategory_ENGINE, "Identify")(&err)
+	s.Start(keybase1.StatCategory_ENGINE, "Identify")(nil)
+
+	stats := s.Export()
+	if len(stats.Entries) != 1 {
+		t.Fatalf("wanted 1 entry, got %d", len(stats.Entries))
+	}
+	if e := stats.Entries[0]; e.Count != 2 || e.Errors != 1 {
+		t.Errorf("count/errors: %d/%d, wanted 2/1", e.Count, e.Errors)
+	}
+}
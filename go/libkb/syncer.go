@@ -39,6 +39,10 @@ func RunSyncer(s Syncer, uid keybase1.UID, loggedIn bool, sr SessionReader) (err
 		s.G().Log.Debug("| Won't sync with server since we're not logged in")
 		return
 	}
+	if s.G().IsOffline() {
+		s.G().Log.Debug("| Won't sync with server since we're offline")
+		return
+	}
 	if err = s.syncFromServer(uid, sr); err != nil {
 		return
 	}
@@ -36,6 +36,13 @@ func (m TorMode) UseHeaders() bool {
 	return m != TorStrict
 }
 
+// CheckUnreliableProofs is whether to check proofs that can't be
+// checked reliably over Tor, such as plain HTTP ones, rather than skip
+// them.
+func (m TorMode) CheckUnreliableProofs() bool {
+	return m != TorStrict
+}
+
 func StringToTorMode(s string) (ret TorMode, err error) {
 	switch s {
 	case "strict":
@@ -122,6 +122,17 @@ func (l TrackLookup) GetTrackedKeys() []TrackedKey {
 	return ret
 }
 
+// GetTrackedEldestKID is the tracked user's eldest key when they were
+// tracked, or nil if the tracking statement doesn't say.
+func (l TrackLookup) GetTrackedEldestKID(g *GlobalContext) keybase1.KID {
+	ret, err := l.link.GetTrackedEldestKID()
+	if err != nil {
+		g.Log.Debug("No eldest KID in tracking statement: %s", err)
+		return ""
+	}
+	return ret
+}
+
 func (l TrackLookup) IsRemote() bool {
 	return l.link.IsRemote()
 }
@@ -296,6 +307,63 @@ func (t TrackDiffRemoteChanged) IsSameAsTracked() bool {
 	return false
 }
 
+// TrackDiffUnverified marks a key found outside of Keybase, on a keyserver
+// or in a Web Key Directory.  There are no proofs behind it.
+type TrackDiffUnverified struct {
+	source string
+}
+
+func NewTrackDiffUnverified(source string) TrackDiffUnverified {
+	return TrackDiffUnverified{source: source}
+}
+
+func (t TrackDiffUnverified) BreaksTracking() bool {
+	return false
+}
+func (t TrackDiffUnverified) ToDisplayString() string {
+	return "UNVERIFIED; from " + t.source
+}
+func (t TrackDiffUnverified) ToDisplayMarkup() *Markup {
+	return NewMarkup(t.ToDisplayString())
+}
+func (t TrackDiffUnverified) GetTrackDiffType() keybase1.TrackDiffType {
+	return keybase1.TrackDiffType_UNVERIFIED
+}
+func (t TrackDiffUnverified) IsSameAsTracked() bool {
+	return false
+}
+
+// TrackDiffNewEldest marks a tracked user whose account was reset, with
+// a new eldest key, since they were tracked.  Nothing that was tracked
+// vouches for keys under the new one.
+type TrackDiffNewEldest struct {
+	tracked  keybase1.KID
+	observed keybase1.KID
+	ctime    time.Time
+}
+
+func (t TrackDiffNewEldest) BreaksTracking() bool {
+	return true
+}
+func (t TrackDiffNewEldest) ToDisplayString() string {
+	return "Account reset! Eldest key was " + t.tracked.String()
+}
+func (t TrackDiffNewEldest) ToDisplayMarkup() *Markup {
+	return NewMarkup(t.ToDisplayString())
+}
+func (t TrackDiffNewEldest) GetTrackDiffType() keybase1.TrackDiffType {
+	return keybase1.TrackDiffType_NEW_ELDEST
+}
+func (t TrackDiffNewEldest) IsSameAsTracked() bool {
+	return false
+}
+func (t TrackDiffNewEldest) GetTracked() keybase1.KID  { return t.tracked }
+func (t TrackDiffNewEldest) GetObserved() keybase1.KID { return t.observed }
+
+// GetCTime is when the new eldest key's subchain started, or zero if the
+// key has no links.
+func (t TrackDiffNewEldest) GetCTime() time.Time { return t.ctime }
+
 func NewTrackLookup(link *TrackChainLink) *TrackLookup {
 	sbs := link.ToServiceBlocks()
 	set := NewTrackSet()
@@ -42,6 +42,10 @@ type UserConfig struct {
 	Salt   string             `json:"salt"`
 	Device *string            `json:"device"`
 
+	// KeyProtector names the KeyProtector that keeps this device's LKSec
+	// client half; empty for the passphrase.
+	KeyProtector string `json:"key_protector,omitempty"`
+
 	importedID       keybase1.UID
 	importedSalt     []byte
 	importedDeviceID keybase1.DeviceID
@@ -0,0 +1,90 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	logging "github.com/keybase/go-logging"
+)
+
+// jsonOutput is nonzero while the "json" log format is configured.
+var jsonOutput int32
+
+func isJSONOutput() bool {
+	return atomic.LoadInt32(&jsonOutput) != 0
+}
+
+// tagsSeparator sets the context log tags of a message apart from its
+// text when logging JSON, so that jsonFormatter can give them fields of
+// their own.  It's the ASCII record separator, which log messages don't
+// otherwise contain.
+const tagsSeparator = "\x1e"
+
+// jsonTags encodes tags to append to a format string after
+// tagsSeparator.  Percent signs are doubled, since the result goes
+// through Sprintf.
+func jsonTags(tags map[string]string) string {
+	b, err := json.Marshal(tags)
+	if err != nil {
+		return ""
+	}
+	return tagsSeparator + strings.Replace(string(b), "%", "%%", -1)
+}
+
+// splitTags undoes jsonTags on a formatted message.
+func splitTags(msg string) (string, map[string]string) {
+	i := strings.LastIndex(msg, tagsSeparator)
+	if i < 0 {
+		return msg, nil
+	}
+	var tags map[string]string
+	if err := json.Unmarshal([]byte(msg[i+len(tagsSeparator):]), &tags); err != nil {
+		return msg, nil
+	}
+	return msg[:i], tags
+}
+
+// jsonEntry is one line of JSON log output.
+type jsonEntry struct {
+	Time    string            `json:"time"`
+	Level   string            `json:"level"`
+	Module  string            `json:"module"`
+	File    string            `json:"file,omitempty"`
+	ID      uint64            `json:"id"`
+	Message string            `json:"msg"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+// jsonFormatter writes each log record as a line of JSON, for log
+// pipelines, with the context log tags as fields.
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(calldepth int, r *logging.Record, w io.Writer) error {
+	msg, tags := splitTags(r.Message())
+	entry := jsonEntry{
+		Time:    r.Time.UTC().Format(time.RFC3339Nano),
+		Level:   r.Level.String(),
+		Module:  r.Module,
+		ID:      r.Id,
+		Message: msg,
+		Tags:    tags,
+	}
+	if _, file, line, ok := runtime.Caller(calldepth + 1); ok {
+		entry.File = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	}
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
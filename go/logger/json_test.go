@@ -0,0 +1,44 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSplitTags(t *testing.T) {
+	cases := []struct {
+		name     string
+		format   string
+		args     []interface{}
+		tags     map[string]string
+		expected string
+	}{
+		{"no tags", "hello %s", []interface{}{"world"}, nil, "hello world"},
+		{"one tag", "hello %s", []interface{}{"world"}, map[string]string{"rpc": "123"}, "hello world"},
+		{"several tags", "%d things", []interface{}{3}, map[string]string{"rpc": "1", "id": "x"}, "3 things"},
+		{"percent in tag", "done", nil, map[string]string{"pct": "100%"}, "done"},
+		{"separator in message", "a\x1eb", nil, map[string]string{"k": "v"}, "a\x1eb"},
+	}
+	for _, c := range cases {
+		format := c.format
+		if c.tags != nil {
+			format += jsonTags(c.tags)
+		}
+		msg, tags := splitTags(fmt.Sprintf(format, c.args...))
+		if msg != c.expected {
+			t.Errorf("%s: message %q, expected %q", c.name, msg, c.expected)
+		}
+		if !reflect.DeepEqual(tags, c.tags) {
+			t.Errorf("%s: tags %v, expected %v", c.name, tags, c.tags)
+		}
+	}
+
+	// A separator that isn't followed by tags leaves the message alone.
+	if msg, tags := splitTags("a\x1enot json"); msg != "a\x1enot json" || tags != nil {
+		t.Errorf("bad split of an untagged message: %q, %v", msg, tags)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+	logging "github.com/keybase/go-logging"
+)
+
+// AllModules stands for every module in SetLogLevel.
+const AllModules = "*"
+
+var modulesMutex sync.Mutex
+var modules = make(map[string]bool)
+
+func registerModule(module string) {
+	modulesMutex.Lock()
+	defer modulesMutex.Unlock()
+	modules[module] = true
+}
+
+// Modules lists the modules that loggers have been made for.
+func Modules() []string {
+	modulesMutex.Lock()
+	defer modulesMutex.Unlock()
+	var ret []string
+	for m := range modules {
+		ret = append(ret, m)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func toLoggingLevel(level keybase1.LogLevel) (logging.Level, error) {
+	switch level {
+	case keybase1.LogLevel_DEBUG:
+		return logging.DEBUG, nil
+	case keybase1.LogLevel_INFO:
+		return logging.INFO, nil
+	case keybase1.LogLevel_NOTICE:
+		return logging.NOTICE, nil
+	case keybase1.LogLevel_WARN:
+		return logging.WARNING, nil
+	case keybase1.LogLevel_ERROR:
+		return logging.ERROR, nil
+	case keybase1.LogLevel_CRITICAL, keybase1.LogLevel_FATAL:
+		return logging.CRITICAL, nil
+	default:
+		return 0, fmt.Errorf("Unknown log level %d", level)
+	}
+}
+
+func isModule(module string) bool {
+	modulesMutex.Lock()
+	defer modulesMutex.Unlock()
+	return modules[module]
+}
+
+// SetLogLevel sets the level of the messages logged for module, which
+// has to be one of Modules(), or for every module if it's AllModules.
+// Messages less severe than level are dropped.  It can be called at any
+// time.
+func SetLogLevel(module string, level keybase1.LogLevel) error {
+	l, err := toLoggingLevel(level)
+	if err != nil {
+		return err
+	}
+	if module != AllModules {
+		if !isModule(module) {
+			return fmt.Errorf("Unknown log module %q; expected one of %s", module, strings.Join(Modules(), ", "))
+		}
+		logging.SetLevel(l, module)
+		return nil
+	}
+	logging.SetLevel(l, "")
+	for _, m := range Modules() {
+		logging.SetLevel(l, m)
+	}
+	return nil
+}
+
+// lockedLeveledBackend guards the per-module levels of a backend, so
+// that they can be changed while other goroutines log.
+type lockedLeveledBackend struct {
+	sync.RWMutex
+	inner logging.LeveledBackend
+}
+
+func newLockedLeveledBackend(b logging.Backend) *lockedLeveledBackend {
+	return &lockedLeveledBackend{inner: logging.AddModuleLevel(b)}
+}
+
+func (b *lockedLeveledBackend) Log(level logging.Level, calldepth int, rec *logging.Record) error {
+	b.RLock()
+	defer b.RUnlock()
+	return b.inner.Log(level, calldepth+1, rec)
+}
+
+func (b *lockedLeveledBackend) GetLevel(module string) logging.Level {
+	b.RLock()
+	defer b.RUnlock()
+	return b.inner.GetLevel(module)
+}
+
+func (b *lockedLeveledBackend) SetLevel(level logging.Level, module string) {
+	b.Lock()
+	defer b.Unlock()
+	b.inner.SetLevel(level, module)
+}
+
+func (b *lockedLeveledBackend) IsEnabledFor(level logging.Level, module string) bool {
+	b.RLock()
+	defer b.RUnlock()
+	return b.inner.IsEnabledFor(level, module)
+}
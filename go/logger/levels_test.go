@@ -0,0 +1,53 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package logger
+
+import (
+	"testing"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+	logging "github.com/keybase/go-logging"
+)
+
+func TestSetLogLevel(t *testing.T) {
+	New("levels_test_a")
+	New("levels_test_b")
+
+	if err := SetLogLevel(AllModules, keybase1.LogLevel_INFO); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLogLevel("levels_test_a", keybase1.LogLevel_WARN); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]logging.Level{
+		"levels_test_a": logging.WARNING,
+		"levels_test_b": logging.INFO,
+	}
+	for module, expected := range cases {
+		if level := logging.GetLevel(module); level != expected {
+			t.Errorf("%s: level %s, expected %s", module, level, expected)
+		}
+	}
+
+	// Setting every module's level overrides the per-module one.
+	if err := SetLogLevel(AllModules, keybase1.LogLevel_ERROR); err != nil {
+		t.Fatal(err)
+	}
+	for module := range cases {
+		if level := logging.GetLevel(module); level != logging.ERROR {
+			t.Errorf("%s: level %s, expected ERROR", module, level)
+		}
+	}
+
+	if err := SetLogLevel(AllModules, keybase1.LogLevel(100)); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+	if err := SetLogLevel("levels_test_c", keybase1.LogLevel_DEBUG); err == nil {
+		t.Error("expected an error for an unknown module")
+	}
+	if level := logging.GetLevel("levels_test_c"); level != logging.ERROR {
+		t.Errorf("unknown module got level %s", level)
+	}
+}
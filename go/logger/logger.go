@@ -53,6 +53,9 @@ type Logger interface {
 	// RotateLogFile rotates the log file, if the underlying logger is
 	// writing to a file.
 	RotateLogFile() error
+	// AutoRotate rotates the log file on its own once it gets too
+	// big or too old, as set out in opts.
+	AutoRotate(opts RotateOptions)
 
 	// External loggers are a hack to allow the calls to G.Log.* in the daemon
 	// to be forwarded to the client. Loggers are registered here with
@@ -35,6 +35,7 @@ func (l *Null) CErrorf(ctx context.Context, fmt string, arg ...interface{})    {
 func (l *Null) Error(fmt string, arg ...interface{})                           {}
 func (l *Null) Configure(style string, debug bool, filename string)            {}
 func (l *Null) RotateLogFile() error                                           { return nil }
+func (l *Null) AutoRotate(opts RotateOptions)                                  {}
 func (l *Null) AddExternalLogger(ExternalLogger) uint64                        { return 0 }
 func (l *Null) RemoveExternalLogger(uint64)                                    {}
 func (l *Null) SetExternalLogLevel(level keybase1.LogLevel)                    {}
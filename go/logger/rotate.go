@@ -0,0 +1,245 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RotateOptions say when a log file is rotated, and what happens to the
+// old ones.
+type RotateOptions struct {
+	MaxSize  int64         // rotate once the file is this big; 0 for no limit
+	MaxAge   time.Duration // rotate once the file is this old; 0 for no limit
+	Keep     int           // how many rotated files to keep; 0 keeps them all
+	Compress bool          // gzip rotated files
+}
+
+func (o RotateOptions) enabled() bool {
+	return o.MaxSize > 0 || o.MaxAge > 0
+}
+
+// How often to check whether the log file is due for rotation.
+const rotateCheckInterval = time.Minute
+
+// Rotated files are named after the log file, with the time of their
+// rotation appended in this layout, which sorts by time.
+const rotateTimeLayout = "20060102T150405"
+
+// AutoRotate starts rotating the log file on its own, as set out in
+// opts, instead of waiting for RotateLogFile to be called after an
+// external logrotate.  It only does so if stderr goes to the log file,
+// since that's where log messages are written.  Calling it again
+// replaces the options; zero options stop the rotation.
+func (log *Standard) AutoRotate(opts RotateOptions) {
+	log.configureMutex.Lock()
+	defer log.configureMutex.Unlock()
+
+	if log.stopRotate != nil {
+		close(log.stopRotate)
+		log.stopRotate = nil
+	}
+	if !opts.enabled() || len(log.filename) == 0 {
+		return
+	}
+	if !canReopenLogFile || !stderrIsFile(log.filename) {
+		log.internal.Debug("Not rotating %s on our own, since stderr doesn't go there", log.filename)
+		return
+	}
+
+	stop := make(chan struct{})
+	log.stopRotate = stop
+	filename := log.filename
+	go func() {
+		lastRotation := lastRotationTime(filename, time.Now())
+		ticker := time.NewTicker(rotateCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				if !rotationDue(filename, opts, lastRotation, now) {
+					continue
+				}
+				if err := log.rotate(filename, opts, now); err != nil {
+					log.internal.Warning("Couldn't rotate %s: %s", filename, err)
+					continue
+				}
+				lastRotation = now
+			}
+		}
+	}()
+}
+
+// rotate moves the log file aside and starts a new one.
+func (log *Standard) rotate(filename string, opts RotateOptions, now time.Time) error {
+	logRotateMutex.Lock()
+	defer logRotateMutex.Unlock()
+	return rotateFile(filename, opts, now, func() error {
+		return log.reopenLogFile()
+	})
+}
+
+// stderrIsFile is true if stderr is filename.
+func stderrIsFile(filename string) bool {
+	a, err := os.Stderr.Stat()
+	if err != nil {
+		return false
+	}
+	b, err := os.Stat(filename)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(a, b)
+}
+
+// rotationDue is true if filename has outgrown opts, or the last
+// rotation was longer ago than opts allows.
+func rotationDue(filename string, opts RotateOptions, lastRotation, now time.Time) bool {
+	fi, err := os.Stat(filename)
+	if err != nil || fi.Size() == 0 {
+		return false
+	}
+	if opts.MaxSize > 0 && fi.Size() >= opts.MaxSize {
+		return true
+	}
+	return opts.MaxAge > 0 && now.Sub(lastRotation) >= opts.MaxAge
+}
+
+// rotateFile renames filename after the current time, calls reopen to
+// start writing to a new one, and then compresses and prunes the old
+// ones as opts say.
+func rotateFile(filename string, opts RotateOptions, now time.Time, reopen func() error) error {
+	archive := filename + "." + now.Format(rotateTimeLayout)
+	for i := 1; fileExistsAny(archive); i++ {
+		archive = fmt.Sprintf("%s.%s-%d", filename, now.Format(rotateTimeLayout), i)
+	}
+	if err := os.Rename(filename, archive); err != nil {
+		return err
+	}
+	if err := reopen(); err != nil {
+		return err
+	}
+	if opts.Compress {
+		if err := gzipFile(archive); err != nil {
+			return err
+		}
+	}
+	return pruneRotated(filename, opts.Keep)
+}
+
+// fileExistsAny is true if name exists, compressed or not.
+func fileExistsAny(name string) bool {
+	for _, n := range []string{name, name + ".gz"} {
+		if exists, _ := FileExists(n); exists {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipFile replaces name with name.gz.
+func gzipFile(name string) (err error) {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(name+".gz", os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(name + ".gz")
+		}
+	}()
+
+	zw := gzip.NewWriter(out)
+	if _, err = io.Copy(zw, in); err != nil {
+		return err
+	}
+	if err = zw.Close(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	in.Close()
+	return os.Remove(name)
+}
+
+// rotatedFiles lists the rotated files of filename, oldest first.
+func rotatedFiles(filename string) ([]string, error) {
+	matches, err := filepath.Glob(filename + ".*")
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, m := range matches {
+		suffix := strings.TrimSuffix(strings.TrimPrefix(m, filename+"."), ".gz")
+		if len(suffix) < len(rotateTimeLayout) {
+			continue
+		}
+		if _, err := time.Parse(rotateTimeLayout, suffix[:len(rotateTimeLayout)]); err != nil {
+			continue
+		}
+		ret = append(ret, m)
+	}
+	sort.Sort(byRotation(ret))
+	return ret, nil
+}
+
+// byRotation sorts rotated files by name, ignoring the .gz, so that a
+// compressed file sorts before one rotated later in the same second.
+type byRotation []string
+
+func (b byRotation) Len() int      { return len(b) }
+func (b byRotation) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byRotation) Less(i, j int) bool {
+	return strings.TrimSuffix(b[i], ".gz") < strings.TrimSuffix(b[j], ".gz")
+}
+
+// pruneRotated removes all but the keep newest rotated files.
+func pruneRotated(filename string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	files, err := rotatedFiles(filename)
+	if err != nil {
+		return err
+	}
+	for len(files) > keep {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+// lastRotationTime is when filename was last rotated, judging by the
+// names of its rotated files, or else now.
+func lastRotationTime(filename string, now time.Time) time.Time {
+	files, err := rotatedFiles(filename)
+	if err != nil || len(files) == 0 {
+		return now
+	}
+	suffix := strings.TrimPrefix(files[len(files)-1], filename+".")
+	t, err := time.ParseInLocation(rotateTimeLayout, suffix[:len(rotateTimeLayout)], time.Local)
+	if err != nil {
+		return now
+	}
+	return t
+}
@@ -0,0 +1,216 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package logger
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setupRotateTest(t *testing.T) (dir, filename string) {
+	dir, err := ioutil.TempDir("", "logger_rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, filepath.Join(dir, "keybase.service.log")
+}
+
+func writeLogFile(t *testing.T, filename, data string) {
+	if err := ioutil.WriteFile(filename, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// reopenLogFile stands in for Standard.reopenLogFile, which would point
+// stderr at the new file.
+func reopenLogFile(filename string) func() error {
+	return func() error {
+		return ioutil.WriteFile(filename, nil, 0600)
+	}
+}
+
+func TestRotationDue(t *testing.T) {
+	dir, filename := setupRotateTest(t)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	writeLogFile(t, filename, strings.Repeat("x", 100))
+
+	cases := []struct {
+		opts         RotateOptions
+		lastRotation time.Time
+		due          bool
+	}{
+		{RotateOptions{}, now.Add(-time.Hour), false},
+		{RotateOptions{MaxSize: 100}, now, true},
+		{RotateOptions{MaxSize: 50}, now, true},
+		{RotateOptions{MaxSize: 101}, now, false},
+		{RotateOptions{MaxAge: time.Hour}, now.Add(-2 * time.Hour), true},
+		{RotateOptions{MaxAge: time.Hour}, now.Add(-time.Minute), false},
+		{RotateOptions{MaxSize: 1000, MaxAge: time.Hour}, now.Add(-2 * time.Hour), true},
+	}
+	for i, c := range cases {
+		if due := rotationDue(filename, c.opts, c.lastRotation, now); due != c.due {
+			t.Errorf("case %d: rotationDue(%+v) = %v, expected %v", i, c.opts, due, c.due)
+		}
+	}
+
+	// An empty log is never due, however old.
+	writeLogFile(t, filename, "")
+	if rotationDue(filename, RotateOptions{MaxSize: 1, MaxAge: time.Second}, now.Add(-time.Hour), now) {
+		t.Errorf("an empty log file was due for rotation")
+	}
+}
+
+func TestRotateFile(t *testing.T) {
+	cases := []struct {
+		name     string
+		compress bool
+		keep     int
+		rotates  int
+		expected int
+	}{
+		{"plain", false, 0, 3, 3},
+		{"compressed", true, 0, 3, 3},
+		{"pruned", false, 2, 5, 2},
+		{"compressed and pruned", true, 1, 3, 1},
+	}
+	for _, c := range cases {
+		dir, filename := setupRotateTest(t)
+		opts := RotateOptions{MaxSize: 10, Keep: c.keep, Compress: c.compress}
+		start := time.Date(2015, 11, 20, 10, 0, 0, 0, time.Local)
+
+		for i := 0; i < c.rotates; i++ {
+			writeLogFile(t, filename, strings.Repeat(string('a'+rune(i)), 20))
+			if err := rotateFile(filename, opts, start.Add(time.Duration(i)*time.Hour), reopenLogFile(filename)); err != nil {
+				t.Fatalf("%s: rotation %d: %s", c.name, i, err)
+			}
+		}
+
+		fi, err := os.Stat(filename)
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		if fi.Size() != 0 {
+			t.Errorf("%s: the new log file isn't empty", c.name)
+		}
+
+		files, err := rotatedFiles(filename)
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+		if len(files) != c.expected {
+			t.Fatalf("%s: %d rotated files, expected %d: %v", c.name, len(files), c.expected, files)
+		}
+
+		// The newest ones are kept, and have the right contents.
+		for i, f := range files {
+			n := c.rotates - c.expected + i
+			if strings.HasSuffix(f, ".gz") != c.compress {
+				t.Errorf("%s: %s compressed = %v", c.name, f, !c.compress)
+			}
+			if got := readRotated(t, f); got != strings.Repeat(string('a'+rune(n)), 20) {
+				t.Errorf("%s: %s holds %q", c.name, f, got)
+			}
+		}
+
+		last := lastRotationTime(filename, time.Now())
+		if expected := start.Add(time.Duration(c.rotates-1) * time.Hour); !last.Equal(expected) {
+			t.Errorf("%s: last rotation %s, expected %s", c.name, last, expected)
+		}
+		os.RemoveAll(dir)
+	}
+}
+
+func readRotated(t *testing.T, name string) string {
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if !strings.HasSuffix(name, ".gz") {
+		b, err := ioutil.ReadAll(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestRotateFileSameSecond(t *testing.T) {
+	dir, filename := setupRotateTest(t)
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		writeLogFile(t, filename, "log")
+		if err := rotateFile(filename, RotateOptions{MaxSize: 1}, now, reopenLogFile(filename)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := rotatedFiles(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Errorf("rotations within a second overwrote each other: %v", files)
+	}
+}
+
+func TestRotatedFiles(t *testing.T) {
+	dir, filename := setupRotateTest(t)
+	defer os.RemoveAll(dir)
+
+	names := []string{
+		filename,
+		filename + ".20151120T100000.gz",
+		filename + ".20151119T100000",
+		filename + ".20151120T100000-1",
+		filename + ".old",
+		filename + ".2015",
+		filepath.Join(dir, "keybase.kbfs.log.20151120T100000"),
+	}
+	for _, n := range names {
+		writeLogFile(t, n, "")
+	}
+
+	files, err := rotatedFiles(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		filename + ".20151119T100000",
+		filename + ".20151120T100000.gz",
+		filename + ".20151120T100000-1",
+	}
+	if strings.Join(files, ",") != strings.Join(expected, ",") {
+		t.Errorf("rotated files: %v, expected %v", files, expected)
+	}
+
+	if err := pruneRotated(filename, 1); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ = rotatedFiles(filename); len(files) != 1 || files[0] != expected[2] {
+		t.Errorf("after pruning: %v", files)
+	}
+	for _, n := range []string{filename, filename + ".old", filename + ".2015"} {
+		if exists, _ := FileExists(n); !exists {
+			t.Errorf("%s was pruned", n)
+		}
+	}
+}
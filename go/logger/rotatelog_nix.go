@@ -9,9 +9,17 @@ import (
 	"syscall"
 )
 
+const canReopenLogFile = true
+
 func (log *Standard) RotateLogFile() error {
 	logRotateMutex.Lock()
 	defer logRotateMutex.Unlock()
+	return log.reopenLogFile()
+}
+
+// reopenLogFile points stdout and stderr at a freshly opened log file,
+// for when the old one was moved away.  logRotateMutex must be held.
+func (log *Standard) reopenLogFile() error {
 	log.internal.Info("Rotating log file; closing down old file")
 	_, file, err := OpenLogFile(log.filename)
 	if err != nil {
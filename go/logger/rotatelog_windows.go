@@ -5,8 +5,16 @@
 
 package logger
 
+import "errors"
+
+const canReopenLogFile = false
+
 func (log *Standard) RotateLogFile() error {
 	// This seems to mean copying a file descriptor to log.filename
 	// on top of stdout and stderr, which is TBI on Windows
 	return nil
 }
+
+func (log *Standard) reopenLogFile() error {
+	return errors.New("reopening the log file is not implemented on Windows")
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	keybase1 "github.com/keybase/client/go/protocol"
 	logging "github.com/keybase/go-logging"
@@ -68,6 +69,8 @@ type Standard struct {
 	externalLoggersCount uint64
 	externalLogLevel     keybase1.LogLevel
 	externalLoggersMutex sync.RWMutex
+
+	stopRotate chan struct{} // closed to stop AutoRotate
 }
 
 // New creates a new Standard logger for module.
@@ -92,6 +95,7 @@ func NewWithCallDepth(module string, extraCallDepth int) *Standard {
 		externalLogLevel:     keybase1.LogLevel_INFO,
 	}
 	ret.initLogging()
+	registerModule(module)
 	return ret
 }
 
@@ -101,7 +105,7 @@ func (log *Standard) initLogging() {
 	// to point to the appropriate log file.
 	initLoggingBackendOnce.Do(func() {
 		logBackend := logging.NewLogBackend(os.Stderr, "", 0)
-		logging.SetBackend(logBackend)
+		logging.SetBackend(newLockedLeveledBackend(logBackend))
 		logging.SetLevel(logging.INFO, log.module)
 	})
 }
@@ -115,6 +119,15 @@ func (log *Standard) prepareString(
 	if !ok || len(logTags) == 0 {
 		return fmts
 	}
+	if isJSONOutput() {
+		tags := make(map[string]string)
+		for key, tag := range logTags {
+			if v := ctx.Value(key); v != nil {
+				tags[tag] = fmt.Sprintf("%s", v)
+			}
+		}
+		return fmts + jsonTags(tags)
+	}
 	var tags []string
 	for key, tag := range logTags {
 		if v := ctx.Value(key); v != nil {
@@ -243,6 +256,13 @@ func (log *Standard) Configure(style string, debug bool, filename string) {
 		logging.SetLevel(logging.DEBUG, log.module)
 	}
 
+	// JSON, one object per line, for log pipelines.
+	if style == "json" {
+		atomic.StoreInt32(&jsonOutput, 1)
+		logging.SetFormatter(jsonFormatter{})
+		return
+	}
+	atomic.StoreInt32(&jsonOutput, 0)
 	logging.SetFormatter(logging.MustStringFormatter(logfmt))
 }
 
@@ -127,6 +127,10 @@ func (log *TestLogger) RotateLogFile() error {
 	return nil
 }
 
+func (log *TestLogger) AutoRotate(opts RotateOptions) {
+	// no-op
+}
+
 // no-op stubs to fulfill the Logger interface
 func (log *TestLogger) AddExternalLogger(externalLogger ExternalLogger) uint64 { return 0 }
 func (log *TestLogger) RemoveExternalLogger(handle uint64)                     {}
@@ -0,0 +1,202 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package pinentry
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/keybase/client/go/logger"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// Assuan error codes, as GnuPG makes them: the error source pinentry
+// (5) in the top byte, and a gpg-error code below.
+const (
+	assuanErrGeneral        = 83886081 // GPG_ERR_GENERAL
+	assuanErrNotImplemented = 83886149 // GPG_ERR_NOT_IMPLEMENTED
+	assuanErrNotConfirmed   = 83886194 // GPG_ERR_NOT_CONFIRMED
+	assuanErrCanceled       = 83886179 // GPG_ERR_CANCELED
+	assuanErrUnknownCommand = 83886355 // GPG_ERR_ASS_UNKNOWN_CMD
+)
+
+// assuanEncode escapes s for an Assuan command argument or data line.
+func assuanEncode(s string) string {
+	s = strings.Replace(s, "%", "%25", -1)
+	s = strings.Replace(s, "\r", "%0D", -1)
+	s = strings.Replace(s, "\n", "%0A", -1)
+	return s
+}
+
+// assuanDecode undoes the %XX escapes in an Assuan command argument or
+// data line.  Malformed escapes are left alone.
+func assuanDecode(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b = append(b, byte(c))
+				i += 2
+				continue
+			}
+		}
+		b = append(b, s[i])
+	}
+	return string(b)
+}
+
+// PromptFunc asks the user for a secret, on behalf of a GETPIN command.
+// options are the ones set with OPTION, such as "ttyname".  A nil result
+// with a nil error means the user entered nothing.
+type PromptFunc func(arg keybase1.SecretEntryArg, options map[string]string) (*keybase1.SecretEntryRes, error)
+
+// Server speaks the pinentry side of the Assuan protocol, so that
+// Keybase can be GnuPG's pinentry program, or stand in for one in tests.
+// It keeps the strings that the SET commands give it, and calls prompt
+// for GETPIN.  CONFIRM and MESSAGE aren't supported.
+type Server struct {
+	prompt  PromptFunc
+	log     logger.Logger
+	arg     keybase1.SecretEntryArg
+	options map[string]string
+}
+
+func NewServer(prompt PromptFunc, log logger.Logger) *Server {
+	return &Server{
+		prompt:  prompt,
+		log:     log,
+		options: make(map[string]string),
+	}
+}
+
+// SetOption sets an option as OPTION would, such as a default ttyname
+// given on the command line.
+func (s *Server) SetOption(name, value string) {
+	s.options[name] = value
+}
+
+// Serve answers commands from r on w until BYE or the end of r.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	send := func(format string, args ...interface{}) error {
+		fmt.Fprintf(bw, format+"\n", args...)
+		return bw.Flush()
+	}
+	if err := send("OK Pleased to meet you"); err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err == io.EOF && len(line) == 0 {
+			return nil
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cmd, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			cmd, arg = line[:i], line[i+1:]
+		}
+		cmd = strings.ToUpper(cmd)
+
+		done, err := s.handle(cmd, assuanDecode(arg), send)
+		if err != nil || done {
+			return err
+		}
+	}
+}
+
+// handle answers one command.  done is set after BYE.
+func (s *Server) handle(cmd, arg string, send func(string, ...interface{}) error) (done bool, err error) {
+	switch cmd {
+	case "SETDESC":
+		s.arg.Desc = arg
+	case "SETPROMPT":
+		s.arg.Prompt = arg
+	case "SETOK":
+		s.arg.Ok = arg
+	case "SETCANCEL":
+		s.arg.Cancel = arg
+	case "SETERROR":
+		s.arg.Err = arg
+	case "SETTITLE", "SETNOTOK", "SETREPEAT", "SETREPEATERROR", "SETQUALITYBAR",
+		"SETQUALITYBAR_TT", "SETKEYINFO", "SETTIMEOUT", "SETGENPIN", "SETGENPIN_TT", "NOP":
+		// Nothing we show.
+	case "OPTION":
+		name, value := arg, ""
+		if i := strings.IndexAny(arg, "= "); i >= 0 {
+			name, value = arg[:i], strings.TrimLeft(arg[i+1:], "= ")
+		}
+		s.options[strings.TrimPrefix(name, "--")] = value
+	case "GETINFO":
+		switch arg {
+		case "flavor":
+			err = send("D keybase")
+		case "version":
+			err = send("D 0.1")
+		case "pid":
+			err = send("D %d", os.Getpid())
+		case "ttyinfo":
+			err = send("D %s %s %s", orDash(s.options["ttyname"]), orDash(s.options["ttytype"]), orDash(s.options["display"]))
+		default:
+			return false, send("ERR %d Not implemented <Pinentry>", assuanErrNotImplemented)
+		}
+	case "GETPIN":
+		return false, s.getPin(send)
+	case "CONFIRM", "MESSAGE":
+		if strings.Contains(arg, "--one-button") || cmd == "MESSAGE" {
+			return false, send("ERR %d Not implemented <Pinentry>", assuanErrNotImplemented)
+		}
+		return false, send("ERR %d Not confirmed <Pinentry>", assuanErrNotConfirmed)
+	case "RESET":
+		s.arg = keybase1.SecretEntryArg{}
+	case "BYE":
+		return true, send("OK closing connection")
+	default:
+		return false, send("ERR %d Unknown command <Pinentry>", assuanErrUnknownCommand)
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, send("OK")
+}
+
+func (s *Server) getPin(send func(string, ...interface{}) error) error {
+	res, err := s.prompt(s.arg, s.options)
+	// An error is only shown once.
+	s.arg.Err = ""
+	if err != nil {
+		s.log.Debug("| pinentry prompt failed: %s", err)
+		return send("ERR %d %s <Pinentry>", assuanErrGeneral, assuanEncode(err.Error()))
+	}
+	if res == nil || res.Canceled {
+		return send("ERR %d Operation cancelled <Pinentry>", assuanErrCanceled)
+	}
+	if len(res.Text) > 0 {
+		if err := send("D %s", assuanEncode(res.Text)); err != nil {
+			return err
+		}
+	}
+	return send("OK")
+}
+
+func orDash(s string) string {
+	if len(s) == 0 {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,90 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package pinentry
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/keybase/client/go/logger"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+func TestAssuanEncoding(t *testing.T) {
+	for _, s := range []string{"", "plain", "100%", "two\nlines\r\n", "%0A", "%"} {
+		if dec := assuanDecode(assuanEncode(s)); dec != s {
+			t.Errorf("%q came back as %q", s, dec)
+		}
+	}
+	if dec := assuanDecode("%41%zz%4"); dec != "A%zz%4" {
+		t.Errorf("decoded malformed escapes to %q", dec)
+	}
+}
+
+func serve(t *testing.T, prompt PromptFunc, commands ...string) []string {
+	var out bytes.Buffer
+	in := strings.NewReader(strings.Join(commands, "\n") + "\n")
+	if err := NewServer(prompt, logger.NewTestLogger(t)).Serve(in, &out); err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+}
+
+func TestServerGetPin(t *testing.T) {
+	var got keybase1.SecretEntryArg
+	var gotOptions map[string]string
+	prompt := func(arg keybase1.SecretEntryArg, options map[string]string) (*keybase1.SecretEntryRes, error) {
+		got, gotOptions = arg, options
+		return &keybase1.SecretEntryRes{Text: "50% off\n"}, nil
+	}
+	lines := serve(t, prompt,
+		"OPTION ttyname=/dev/pts/3",
+		"SETDESC Unlock key%0Afor alice",
+		"SETPROMPT Passphrase:",
+		"SETERROR Bad passphrase",
+		"GETPIN",
+		"BYE",
+	)
+	expected := []string{
+		"OK Pleased to meet you",
+		"OK", "OK", "OK", "OK",
+		"D 50%25 off%0A", "OK",
+		"OK closing connection",
+	}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("got %q, expected %q", lines, expected)
+	}
+	if got.Desc != "Unlock key\nfor alice" || got.Prompt != "Passphrase:" || got.Err != "Bad passphrase" {
+		t.Errorf("prompt got %+v", got)
+	}
+	if gotOptions["ttyname"] != "/dev/pts/3" {
+		t.Errorf("prompt got options %v", gotOptions)
+	}
+}
+
+func TestServerErrors(t *testing.T) {
+	canceled := func(keybase1.SecretEntryArg, map[string]string) (*keybase1.SecretEntryRes, error) {
+		return &keybase1.SecretEntryRes{Canceled: true}, nil
+	}
+	lines := serve(t, canceled, "GETPIN", "CONFIRM", "FROB")
+	expected := []string{
+		"OK Pleased to meet you",
+		"ERR 83886179 Operation cancelled <Pinentry>",
+		"ERR 83886194 Not confirmed <Pinentry>",
+		"ERR 83886355 Unknown command <Pinentry>",
+	}
+	if strings.Join(lines, "|") != strings.Join(expected, "|") {
+		t.Errorf("got %q, expected %q", lines, expected)
+	}
+
+	failed := func(keybase1.SecretEntryArg, map[string]string) (*keybase1.SecretEntryRes, error) {
+		return nil, errors.New("no tty")
+	}
+	lines = serve(t, failed, "GETPIN")
+	if lines[1] != "ERR 83886081 no tty <Pinentry>" {
+		t.Errorf("got %q for a failed prompt", lines[1])
+	}
+}
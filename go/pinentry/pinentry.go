@@ -172,24 +172,13 @@ func (pi *pinentryInstance) Init() (err error) {
 	return
 }
 
-func descEncode(s string) string {
-	s = strings.Replace(s, "%", "%%", -1)
-	s = strings.Replace(s, "\n", "%0A", -1)
-	return s
-}
-
-func resDecode(s string) string {
-	s = strings.Replace(s, "%25", "%", -1)
-	return s
-}
-
 func (pi *pinentryInstance) Run(arg keybase1.SecretEntryArg) (res *keybase1.SecretEntryRes, err error) {
 
-	pi.Set("SETPROMPT", arg.Prompt, &err)
-	pi.Set("SETDESC", descEncode(arg.Desc), &err)
+	pi.Set("SETPROMPT", assuanEncode(arg.Prompt), &err)
+	pi.Set("SETDESC", assuanEncode(arg.Desc), &err)
 	pi.Set("SETOK", arg.Ok, &err)
 	pi.Set("SETCANCEL", arg.Cancel, &err)
-	pi.Set("SETERROR", arg.Err, &err)
+	pi.Set("SETERROR", assuanEncode(arg.Err), &err)
 
 	if err != nil {
 		return
@@ -210,8 +199,8 @@ func (pi *pinentryInstance) Run(arg keybase1.SecretEntryArg) (res *keybase1.Secr
 	line := string(lineb)
 	switch {
 	case strings.HasPrefix(line, "D "):
-		res = &keybase1.SecretEntryRes{Text: resDecode(line[2:])}
-	case strings.HasPrefix(line, "ERR 83886179 canceled"):
+		res = &keybase1.SecretEntryRes{Text: assuanDecode(line[2:])}
+	case strings.HasPrefix(line, fmt.Sprintf("ERR %d ", assuanErrCanceled)):
 		res = &keybase1.SecretEntryRes{Canceled: true}
 	case line == "OK":
 		res = &keybase1.SecretEntryRes{}
@@ -0,0 +1,91 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// +build !windows
+
+package pinentry
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/keybase/client/go/logger"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+func TestMain(m *testing.M) {
+	RunTestPinentry()
+	os.Exit(m.Run())
+}
+
+func newTestPinentry(t *testing.T, steps ...TestPinentryStep) (*TestPinentry, func()) {
+	dir, err := ioutil.TempDir("", "pinentry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tp, err := NewTestPinentry(dir, steps)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return tp, func() { os.RemoveAll(dir) }
+}
+
+// TestPinentryReprompt runs a passphrase prompt that's retried with an
+// error after a wrong answer, the way passphrase checks do.
+func TestPinentryReprompt(t *testing.T) {
+	tp, cleanup := newTestPinentry(t,
+		TestPinentryStep{Text: "wrong"},
+		TestPinentryStep{Text: "correct horse % battery"},
+	)
+	defer cleanup()
+
+	pe := New(tp.Program(), logger.NewTestLogger(t))
+	arg := keybase1.SecretEntryArg{
+		Desc:   "Please enter your passphrase\nto unlock the secret key",
+		Prompt: "Your passphrase",
+		Ok:     "Unlock",
+	}
+	var res *keybase1.SecretEntryRes
+	for i := 0; i < 3; i++ {
+		var err error
+		if res, err = pe.Get(arg); err != nil {
+			t.Fatal(err)
+		}
+		if res.Canceled || res.Text == "correct horse % battery" {
+			break
+		}
+		arg.Err = "Wrong passphrase"
+	}
+	if res.Canceled || res.Text != "correct horse % battery" {
+		t.Fatalf("got %+v", res)
+	}
+
+	args, err := tp.Args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 2 {
+		t.Fatalf("expected 2 prompts, got %d", len(args))
+	}
+	if args[0].Desc != arg.Desc || args[0].Prompt != arg.Prompt || args[0].Ok != arg.Ok || args[0].Err != "" {
+		t.Errorf("first prompt was %+v", args[0])
+	}
+	if args[1].Err != "Wrong passphrase" {
+		t.Errorf("second prompt had error %q", args[1].Err)
+	}
+}
+
+func TestPinentryCancel(t *testing.T) {
+	tp, cleanup := newTestPinentry(t, TestPinentryStep{Canceled: true})
+	defer cleanup()
+
+	res, err := New(tp.Program(), logger.NewTestLogger(t)).Get(keybase1.SecretEntryArg{Prompt: "Passphrase"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Canceled {
+		t.Errorf("expected a canceled prompt, got %+v", res)
+	}
+}
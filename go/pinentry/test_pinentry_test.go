@@ -0,0 +1,123 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// +build !windows
+
+package pinentry
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/keybase/client/go/logger"
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// testPinentryEnv names the directory of a TestPinentry in the
+// environment of the pinentry program it runs.
+const testPinentryEnv = "KEYBASE_TEST_PINENTRY_DIR"
+
+// TestPinentryStep is what a TestPinentry answers to one GETPIN.
+type TestPinentryStep struct {
+	Text     string `json:"text"`
+	Canceled bool   `json:"canceled"`
+}
+
+// TestPinentry is a pinentry program for tests, which answers GETPIN
+// from a script instead of asking anyone, and records what each prompt
+// asked for.  The program is the test binary itself, so TestMain calls
+// RunTestPinentry.  It needs a Unix shell.
+type TestPinentry struct {
+	dir string
+}
+
+// NewTestPinentry writes a pinentry program to dir that answers with
+// steps, in order, and cancels once they run out.
+func NewTestPinentry(dir string, steps []TestPinentryStep) (*TestPinentry, error) {
+	t := &TestPinentry{dir: dir}
+	if err := writeJSON(t.scriptFile(), steps); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(t.argsFile(), []keybase1.SecretEntryArg{}); err != nil {
+		return nil, err
+	}
+	script := fmt.Sprintf("#!/bin/sh\n%s=%q exec %q \"$@\"\n", testPinentryEnv, dir, os.Args[0])
+	if err := ioutil.WriteFile(t.Program(), []byte(script), 0700); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Program is the path to give New, or GnuPG's pinentry-program.
+func (t *TestPinentry) Program() string {
+	return filepath.Join(t.dir, "pinentry")
+}
+
+// Args are what the prompts so far were asked for.
+func (t *TestPinentry) Args() ([]keybase1.SecretEntryArg, error) {
+	var args []keybase1.SecretEntryArg
+	err := readJSON(t.argsFile(), &args)
+	return args, err
+}
+
+func (t *TestPinentry) scriptFile() string { return filepath.Join(t.dir, "script.json") }
+func (t *TestPinentry) argsFile() string   { return filepath.Join(t.dir, "args.json") }
+
+// prompt is the PromptFunc of the pinentry program: it takes the next
+// step of the script, and records arg.
+func (t *TestPinentry) prompt(arg keybase1.SecretEntryArg, options map[string]string) (*keybase1.SecretEntryRes, error) {
+	var args []keybase1.SecretEntryArg
+	if err := readJSON(t.argsFile(), &args); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(t.argsFile(), append(args, arg)); err != nil {
+		return nil, err
+	}
+
+	var steps []TestPinentryStep
+	if err := readJSON(t.scriptFile(), &steps); err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return &keybase1.SecretEntryRes{Canceled: true}, nil
+	}
+	if err := writeJSON(t.scriptFile(), steps[1:]); err != nil {
+		return nil, err
+	}
+	return &keybase1.SecretEntryRes{Text: steps[0].Text, Canceled: steps[0].Canceled}, nil
+}
+
+// RunTestPinentry makes the process a TestPinentry's pinentry program,
+// and exits, if a TestPinentry ran it.  Otherwise it does nothing.  Call
+// it first thing in TestMain.
+func RunTestPinentry() {
+	dir := os.Getenv(testPinentryEnv)
+	if len(dir) == 0 {
+		return
+	}
+	t := &TestPinentry{dir: dir}
+	if err := NewServer(t.prompt, logger.NewNull()).Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "test pinentry: %s\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func readJSON(name string, v interface{}) error {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func writeJSON(name string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(name, b, 0600)
+}
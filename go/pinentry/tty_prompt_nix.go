@@ -0,0 +1,54 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// +build !windows
+
+package pinentry
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// TTYPrompt is a PromptFunc that asks on the terminal named by the
+// ttyname option, which GnuPG sets to the terminal gpg runs in, or else
+// on /dev/tty.  An empty passphrase cancels.
+func TTYPrompt(arg keybase1.SecretEntryArg, options map[string]string) (*keybase1.SecretEntryRes, error) {
+	name := options["ttyname"]
+	if len(name) == 0 {
+		name = "/dev/tty"
+	}
+	tty, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer tty.Close()
+	if !terminal.IsTerminal(int(tty.Fd())) {
+		return nil, fmt.Errorf("%s isn't a terminal", name)
+	}
+
+	if len(arg.Err) > 0 {
+		fmt.Fprintf(tty, "%s\n", arg.Err)
+	}
+	if len(arg.Desc) > 0 {
+		fmt.Fprintf(tty, "%s\n", arg.Desc)
+	}
+	prompt := arg.Prompt
+	if len(prompt) == 0 {
+		prompt = "Passphrase"
+	}
+	fmt.Fprintf(tty, "%s: ", prompt)
+	b, err := terminal.ReadPassword(int(tty.Fd()))
+	fmt.Fprintf(tty, "\n")
+	if err == io.EOF || (err == nil && len(b) == 0) {
+		return &keybase1.SecretEntryRes{Canceled: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &keybase1.SecretEntryRes{Text: string(b)}, nil
+}
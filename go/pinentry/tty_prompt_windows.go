@@ -0,0 +1,18 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// +build windows
+
+package pinentry
+
+import (
+	"errors"
+
+	keybase1 "github.com/keybase/client/go/protocol"
+)
+
+// TTYPrompt can't prompt on Windows, where GnuPG doesn't name a terminal
+// for its pinentry to use.
+func TTYPrompt(arg keybase1.SecretEntryArg, options map[string]string) (*keybase1.SecretEntryRes, error) {
+	return nil, errors.New("prompting on a terminal isn't supported on Windows")
+}
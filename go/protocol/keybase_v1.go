@@ -120,6 +120,8 @@ type PublicKey struct {
 	DeviceType        string        `codec:"deviceType" json:"deviceType"`
 	CTime             Time          `codec:"cTime" json:"cTime"`
 	ETime             Time          `codec:"eTime" json:"eTime"`
+	PGPKeyETime       Time          `codec:"PGPKeyETime" json:"PGPKeyETime"`
+	PGPSubkeyETime    Time          `codec:"PGPSubkeyETime" json:"PGPSubkeyETime"`
 }
 
 type User struct {
@@ -133,6 +135,7 @@ type Device struct {
 	DeviceID DeviceID `codec:"deviceID" json:"deviceID"`
 	CTime    Time     `codec:"cTime" json:"cTime"`
 	MTime    Time     `codec:"mTime" json:"mTime"`
+	ETime    Time     `codec:"eTime" json:"eTime"`
 }
 
 type Stream struct {
@@ -618,6 +621,152 @@ func (c CryptoClient) UnboxBytes32(ctx context.Context, __arg UnboxBytes32Arg) (
 	return
 }
 
+type RegisterAddressRes struct {
+	Type string `codec:"type" json:"type"`
+}
+
+type RegisterAddressArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Address   string `codec:"address" json:"address"`
+	Type      string `codec:"type" json:"type"`
+	Force     bool   `codec:"force" json:"force"`
+}
+
+type RevokeAddressArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Address   string `codec:"address" json:"address"`
+}
+
+type CryptocurrencyInterface interface {
+	RegisterAddress(context.Context, RegisterAddressArg) (RegisterAddressRes, error)
+	RevokeAddress(context.Context, RevokeAddressArg) error
+}
+
+func CryptocurrencyProtocol(i CryptocurrencyInterface) rpc.Protocol {
+	return rpc.Protocol{
+		Name: "keybase.1.cryptocurrency",
+		Methods: map[string]rpc.ServeHandlerDescription{
+			"registerAddress": {
+				MakeArg: func() interface{} {
+					ret := make([]RegisterAddressArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]RegisterAddressArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]RegisterAddressArg)(nil), args)
+						return
+					}
+					ret, err = i.RegisterAddress(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"revokeAddress": {
+				MakeArg: func() interface{} {
+					ret := make([]RevokeAddressArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]RevokeAddressArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]RevokeAddressArg)(nil), args)
+						return
+					}
+					err = i.RevokeAddress(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+		},
+	}
+}
+
+type CryptocurrencyClient struct {
+	Cli GenericClient
+}
+
+func (c CryptocurrencyClient) RegisterAddress(ctx context.Context, __arg RegisterAddressArg) (res RegisterAddressRes, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.cryptocurrency.registerAddress", []interface{}{__arg}, &res)
+	return
+}
+
+func (c CryptocurrencyClient) RevokeAddress(ctx context.Context, __arg RevokeAddressArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.cryptocurrency.revokeAddress", []interface{}{__arg}, nil)
+	return
+}
+
+type StatCategory int
+
+const (
+	StatCategory_API    StatCategory = 0
+	StatCategory_XAPI   StatCategory = 1
+	StatCategory_ENGINE StatCategory = 2
+	StatCategory_RPC    StatCategory = 3
+	StatCategory_PROOF  StatCategory = 4
+	StatCategory_MERKLE StatCategory = 5
+)
+
+type LatencyBucket struct {
+	UpperBoundMs int64 `codec:"upperBoundMs" json:"upperBoundMs"`
+	Count        int   `codec:"count" json:"count"`
+}
+
+type StatEntry struct {
+	Category  StatCategory    `codec:"category" json:"category"`
+	Name      string          `codec:"name" json:"name"`
+	Count     int             `codec:"count" json:"count"`
+	Errors    int             `codec:"errors" json:"errors"`
+	TotalMs   int64           `codec:"totalMs" json:"totalMs"`
+	MaxMs     int64           `codec:"maxMs" json:"maxMs"`
+	Histogram []LatencyBucket `codec:"histogram" json:"histogram"`
+}
+
+type ServiceStats struct {
+	Since   Time        `codec:"since" json:"since"`
+	Entries []StatEntry `codec:"entries" json:"entries"`
+}
+
+type ProfileType int
+
+const (
+	ProfileType_CPU       ProfileType = 0
+	ProfileType_HEAP      ProfileType = 1
+	ProfileType_GOROUTINE ProfileType = 2
+)
+
+type OfflineOp struct {
+	Type     string `codec:"type" json:"type"`
+	Username string `codec:"username" json:"username"`
+	Seqno    int    `codec:"seqno" json:"seqno"`
+	SigID    SigID  `codec:"sigID" json:"sigID"`
+	Ctime    Time   `codec:"ctime" json:"ctime"`
+	Conflict string `codec:"conflict" json:"conflict"`
+}
+
+type OfflineStatus struct {
+	Offline bool        `codec:"offline" json:"offline"`
+	Queue   []OfflineOp `codec:"queue" json:"queue"`
+}
+
+type DbTableStats struct {
+	Table   string `codec:"table" json:"table"`
+	Entries int    `codec:"entries" json:"entries"`
+	Bytes   int64  `codec:"bytes" json:"bytes"`
+}
+
+type DbProblem struct {
+	Table   string `codec:"table" json:"table"`
+	Key     string `codec:"key" json:"key"`
+	Problem string `codec:"problem" json:"problem"`
+}
+
+type DbVerifyResult struct {
+	Checked  int         `codec:"checked" json:"checked"`
+	Problems []DbProblem `codec:"problems" json:"problems"`
+	Evicted  int         `codec:"evicted" json:"evicted"`
+}
+
 type StopArg struct {
 	SessionID int `codec:"sessionID" json:"sessionID"`
 }
@@ -629,22 +778,84 @@ type LogRotateArg struct {
 type SetLogLevelArg struct {
 	SessionID int      `codec:"sessionID" json:"sessionID"`
 	Level     LogLevel `codec:"level" json:"level"`
+	Module    string   `codec:"module" json:"module"`
 }
 
 type ReloadArg struct {
 	SessionID int `codec:"sessionID" json:"sessionID"`
 }
 
+type LockArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
 type DbNukeArg struct {
 	SessionID int `codec:"sessionID" json:"sessionID"`
 }
 
+type DbStatsArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
+type DbCompactArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
+type DbVerifyArg struct {
+	SessionID int  `codec:"sessionID" json:"sessionID"`
+	EvictBad  bool `codec:"evictBad" json:"evictBad"`
+}
+
+type DbEvictArg struct {
+	SessionID int      `codec:"sessionID" json:"sessionID"`
+	Tables    []string `codec:"tables" json:"tables"`
+	Usernames []string `codec:"usernames" json:"usernames"`
+}
+
+type GetStatsArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
+type ResetStatsArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
+type CaptureProfileArg struct {
+	SessionID       int         `codec:"sessionID" json:"sessionID"`
+	ProfileType     ProfileType `codec:"profileType" json:"profileType"`
+	DurationSeconds int         `codec:"durationSeconds" json:"durationSeconds"`
+}
+
+type GetOfflineStatusArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
+type SetOfflineArg struct {
+	SessionID int  `codec:"sessionID" json:"sessionID"`
+	Offline   bool `codec:"offline" json:"offline"`
+}
+
+type DiscardOfflineConflictsArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
 type CtlInterface interface {
 	Stop(context.Context, int) error
 	LogRotate(context.Context, int) error
 	SetLogLevel(context.Context, SetLogLevelArg) error
 	Reload(context.Context, int) error
+	Lock(context.Context, int) error
 	DbNuke(context.Context, int) error
+	DbStats(context.Context, int) ([]DbTableStats, error)
+	DbCompact(context.Context, int) error
+	DbVerify(context.Context, DbVerifyArg) (DbVerifyResult, error)
+	DbEvict(context.Context, DbEvictArg) (int, error)
+	GetStats(context.Context, int) (ServiceStats, error)
+	ResetStats(context.Context, int) error
+	CaptureProfile(context.Context, CaptureProfileArg) (string, error)
+	GetOfflineStatus(context.Context, int) (OfflineStatus, error)
+	SetOffline(context.Context, SetOfflineArg) (OfflineStatus, error)
+	DiscardOfflineConflicts(context.Context, int) error
 }
 
 func CtlProtocol(i CtlInterface) rpc.Protocol {
@@ -715,6 +926,22 @@ func CtlProtocol(i CtlInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"lock": {
+				MakeArg: func() interface{} {
+					ret := make([]LockArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]LockArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]LockArg)(nil), args)
+						return
+					}
+					err = i.Lock(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 			"dbNuke": {
 				MakeArg: func() interface{} {
 					ret := make([]DbNukeArg, 1)
@@ -731,6 +958,166 @@ func CtlProtocol(i CtlInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"dbStats": {
+				MakeArg: func() interface{} {
+					ret := make([]DbStatsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DbStatsArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DbStatsArg)(nil), args)
+						return
+					}
+					ret, err = i.DbStats(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"dbCompact": {
+				MakeArg: func() interface{} {
+					ret := make([]DbCompactArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DbCompactArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DbCompactArg)(nil), args)
+						return
+					}
+					err = i.DbCompact(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"dbVerify": {
+				MakeArg: func() interface{} {
+					ret := make([]DbVerifyArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DbVerifyArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DbVerifyArg)(nil), args)
+						return
+					}
+					ret, err = i.DbVerify(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"dbEvict": {
+				MakeArg: func() interface{} {
+					ret := make([]DbEvictArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DbEvictArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DbEvictArg)(nil), args)
+						return
+					}
+					ret, err = i.DbEvict(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"getStats": {
+				MakeArg: func() interface{} {
+					ret := make([]GetStatsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]GetStatsArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]GetStatsArg)(nil), args)
+						return
+					}
+					ret, err = i.GetStats(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"resetStats": {
+				MakeArg: func() interface{} {
+					ret := make([]ResetStatsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]ResetStatsArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]ResetStatsArg)(nil), args)
+						return
+					}
+					err = i.ResetStats(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"captureProfile": {
+				MakeArg: func() interface{} {
+					ret := make([]CaptureProfileArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]CaptureProfileArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]CaptureProfileArg)(nil), args)
+						return
+					}
+					ret, err = i.CaptureProfile(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"getOfflineStatus": {
+				MakeArg: func() interface{} {
+					ret := make([]GetOfflineStatusArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]GetOfflineStatusArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]GetOfflineStatusArg)(nil), args)
+						return
+					}
+					ret, err = i.GetOfflineStatus(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"setOffline": {
+				MakeArg: func() interface{} {
+					ret := make([]SetOfflineArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SetOfflineArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SetOfflineArg)(nil), args)
+						return
+					}
+					ret, err = i.SetOffline(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"discardOfflineConflicts": {
+				MakeArg: func() interface{} {
+					ret := make([]DiscardOfflineConflictsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DiscardOfflineConflictsArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DiscardOfflineConflictsArg)(nil), args)
+						return
+					}
+					err = i.DiscardOfflineConflicts(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 		},
 	}
 }
@@ -762,12 +1149,74 @@ func (c CtlClient) Reload(ctx context.Context, sessionID int) (err error) {
 	return
 }
 
+func (c CtlClient) Lock(ctx context.Context, sessionID int) (err error) {
+	__arg := LockArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.ctl.lock", []interface{}{__arg}, nil)
+	return
+}
+
 func (c CtlClient) DbNuke(ctx context.Context, sessionID int) (err error) {
 	__arg := DbNukeArg{SessionID: sessionID}
 	err = c.Cli.Call(ctx, "keybase.1.ctl.dbNuke", []interface{}{__arg}, nil)
 	return
 }
 
+func (c CtlClient) DbStats(ctx context.Context, sessionID int) (res []DbTableStats, err error) {
+	__arg := DbStatsArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.ctl.dbStats", []interface{}{__arg}, &res)
+	return
+}
+
+func (c CtlClient) DbCompact(ctx context.Context, sessionID int) (err error) {
+	__arg := DbCompactArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.ctl.dbCompact", []interface{}{__arg}, nil)
+	return
+}
+
+func (c CtlClient) DbVerify(ctx context.Context, __arg DbVerifyArg) (res DbVerifyResult, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.ctl.dbVerify", []interface{}{__arg}, &res)
+	return
+}
+
+func (c CtlClient) DbEvict(ctx context.Context, __arg DbEvictArg) (res int, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.ctl.dbEvict", []interface{}{__arg}, &res)
+	return
+}
+
+func (c CtlClient) GetStats(ctx context.Context, sessionID int) (res ServiceStats, err error) {
+	__arg := GetStatsArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.ctl.getStats", []interface{}{__arg}, &res)
+	return
+}
+
+func (c CtlClient) ResetStats(ctx context.Context, sessionID int) (err error) {
+	__arg := ResetStatsArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.ctl.resetStats", []interface{}{__arg}, nil)
+	return
+}
+
+func (c CtlClient) CaptureProfile(ctx context.Context, __arg CaptureProfileArg) (res string, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.ctl.captureProfile", []interface{}{__arg}, &res)
+	return
+}
+
+func (c CtlClient) GetOfflineStatus(ctx context.Context, sessionID int) (res OfflineStatus, err error) {
+	__arg := GetOfflineStatusArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.ctl.getOfflineStatus", []interface{}{__arg}, &res)
+	return
+}
+
+func (c CtlClient) SetOffline(ctx context.Context, __arg SetOfflineArg) (res OfflineStatus, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.ctl.setOffline", []interface{}{__arg}, &res)
+	return
+}
+
+func (c CtlClient) DiscardOfflineConflicts(ctx context.Context, sessionID int) (err error) {
+	__arg := DiscardOfflineConflictsArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.ctl.discardOfflineConflicts", []interface{}{__arg}, nil)
+	return
+}
+
 type FirstStepResult struct {
 	ValPlusTwo int `codec:"valPlusTwo" json:"valPlusTwo"`
 }
@@ -929,11 +1378,34 @@ type DeviceListArg struct {
 
 type DeviceAddArg struct {
 	SessionID int `codec:"sessionID" json:"sessionID"`
+	ExpireIn  int `codec:"expireIn" json:"expireIn"`
+}
+
+type DeviceRotateArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
+type DeviceExtendArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+	ExpireIn  int `codec:"expireIn" json:"expireIn"`
+}
+
+type DeviceProtectArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Protector string `codec:"protector" json:"protector"`
+}
+
+type DeviceGetProtectorArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
 }
 
 type DeviceInterface interface {
 	DeviceList(context.Context, int) ([]Device, error)
-	DeviceAdd(context.Context, int) error
+	DeviceAdd(context.Context, DeviceAddArg) error
+	DeviceRotate(context.Context, int) error
+	DeviceExtend(context.Context, DeviceExtendArg) error
+	DeviceProtect(context.Context, DeviceProtectArg) error
+	DeviceGetProtector(context.Context, int) (string, error)
 }
 
 func DeviceProtocol(i DeviceInterface) rpc.Protocol {
@@ -942,32 +1414,96 @@ func DeviceProtocol(i DeviceInterface) rpc.Protocol {
 		Methods: map[string]rpc.ServeHandlerDescription{
 			"deviceList": {
 				MakeArg: func() interface{} {
-					ret := make([]DeviceListArg, 1)
+					ret := make([]DeviceListArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DeviceListArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DeviceListArg)(nil), args)
+						return
+					}
+					ret, err = i.DeviceList(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"deviceAdd": {
+				MakeArg: func() interface{} {
+					ret := make([]DeviceAddArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DeviceAddArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DeviceAddArg)(nil), args)
+						return
+					}
+					err = i.DeviceAdd(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"deviceRotate": {
+				MakeArg: func() interface{} {
+					ret := make([]DeviceRotateArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DeviceRotateArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DeviceRotateArg)(nil), args)
+						return
+					}
+					err = i.DeviceRotate(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"deviceExtend": {
+				MakeArg: func() interface{} {
+					ret := make([]DeviceExtendArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]DeviceExtendArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]DeviceExtendArg)(nil), args)
+						return
+					}
+					err = i.DeviceExtend(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"deviceProtect": {
+				MakeArg: func() interface{} {
+					ret := make([]DeviceProtectArg, 1)
 					return &ret
 				},
 				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
-					typedArgs, ok := args.(*[]DeviceListArg)
+					typedArgs, ok := args.(*[]DeviceProtectArg)
 					if !ok {
-						err = rpc.NewTypeError((*[]DeviceListArg)(nil), args)
+						err = rpc.NewTypeError((*[]DeviceProtectArg)(nil), args)
 						return
 					}
-					ret, err = i.DeviceList(ctx, (*typedArgs)[0].SessionID)
+					err = i.DeviceProtect(ctx, (*typedArgs)[0])
 					return
 				},
 				MethodType: rpc.MethodCall,
 			},
-			"deviceAdd": {
+			"deviceGetProtector": {
 				MakeArg: func() interface{} {
-					ret := make([]DeviceAddArg, 1)
+					ret := make([]DeviceGetProtectorArg, 1)
 					return &ret
 				},
 				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
-					typedArgs, ok := args.(*[]DeviceAddArg)
+					typedArgs, ok := args.(*[]DeviceGetProtectorArg)
 					if !ok {
-						err = rpc.NewTypeError((*[]DeviceAddArg)(nil), args)
+						err = rpc.NewTypeError((*[]DeviceGetProtectorArg)(nil), args)
 						return
 					}
-					err = i.DeviceAdd(ctx, (*typedArgs)[0].SessionID)
+					ret, err = i.DeviceGetProtector(ctx, (*typedArgs)[0].SessionID)
 					return
 				},
 				MethodType: rpc.MethodCall,
@@ -986,12 +1522,33 @@ func (c DeviceClient) DeviceList(ctx context.Context, sessionID int) (res []Devi
 	return
 }
 
-func (c DeviceClient) DeviceAdd(ctx context.Context, sessionID int) (err error) {
-	__arg := DeviceAddArg{SessionID: sessionID}
+func (c DeviceClient) DeviceAdd(ctx context.Context, __arg DeviceAddArg) (err error) {
 	err = c.Cli.Call(ctx, "keybase.1.device.deviceAdd", []interface{}{__arg}, nil)
 	return
 }
 
+func (c DeviceClient) DeviceRotate(ctx context.Context, sessionID int) (err error) {
+	__arg := DeviceRotateArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.device.deviceRotate", []interface{}{__arg}, nil)
+	return
+}
+
+func (c DeviceClient) DeviceExtend(ctx context.Context, __arg DeviceExtendArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.device.deviceExtend", []interface{}{__arg}, nil)
+	return
+}
+
+func (c DeviceClient) DeviceProtect(ctx context.Context, __arg DeviceProtectArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.device.deviceProtect", []interface{}{__arg}, nil)
+	return
+}
+
+func (c DeviceClient) DeviceGetProtector(ctx context.Context, sessionID int) (res string, err error) {
+	__arg := DeviceGetProtectorArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.device.deviceGetProtector", []interface{}{__arg}, &res)
+	return
+}
+
 type Folder struct {
 	Name            string `codec:"name" json:"name"`
 	Private         bool   `codec:"private" json:"private"`
@@ -1311,6 +1868,8 @@ const (
 	TrackDiffType_REMOTE_FAIL    TrackDiffType = 6
 	TrackDiffType_REMOTE_WORKING TrackDiffType = 7
 	TrackDiffType_REMOTE_CHANGED TrackDiffType = 8
+	TrackDiffType_UNVERIFIED     TrackDiffType = 9
+	TrackDiffType_NEW_ELDEST     TrackDiffType = 10
 )
 
 type TrackDiff struct {
@@ -1344,6 +1903,13 @@ type IdentifyReason struct {
 	Reason string `codec:"reason" json:"reason"`
 }
 
+type AccountReset struct {
+	TrackedEldest KID      `codec:"trackedEldest" json:"trackedEldest"`
+	Eldest        KID      `codec:"eldest" json:"eldest"`
+	Ctime         Time     `codec:"ctime" json:"ctime"`
+	CarriedOver   []string `codec:"carriedOver" json:"carriedOver"`
+}
+
 type IdentifyOutcome struct {
 	Username          string         `codec:"username" json:"username"`
 	Status            *Status        `codec:"status,omitempty" json:"status,omitempty"`
@@ -1359,6 +1925,7 @@ type IdentifyOutcome struct {
 	TrackOptions      TrackOptions   `codec:"trackOptions" json:"trackOptions"`
 	ForPGPPull        bool           `codec:"forPGPPull" json:"forPGPPull"`
 	Reason            IdentifyReason `codec:"reason" json:"reason"`
+	Reset             *AccountReset  `codec:"reset,omitempty" json:"reset,omitempty"`
 }
 
 type IdentifyRes struct {
@@ -1382,6 +1949,7 @@ type IdentifyArg struct {
 	UserAssertion    string         `codec:"userAssertion" json:"userAssertion"`
 	TrackStatement   bool           `codec:"trackStatement" json:"trackStatement"`
 	ForceRemoteCheck bool           `codec:"forceRemoteCheck" json:"forceRemoteCheck"`
+	CacheOnly        bool           `codec:"cacheOnly" json:"cacheOnly"`
 	UseDelegateUI    bool           `codec:"useDelegateUI" json:"useDelegateUI"`
 	Reason           IdentifyReason `codec:"reason" json:"reason"`
 }
@@ -1445,6 +2013,7 @@ type Cryptocurrency struct {
 	RowId   int    `codec:"rowId" json:"rowId"`
 	Pkhash  []byte `codec:"pkhash" json:"pkhash"`
 	Address string `codec:"address" json:"address"`
+	Type    string `codec:"type" json:"type"`
 }
 
 type Identity struct {
@@ -1465,13 +2034,23 @@ type SigHint struct {
 type CheckResult struct {
 	ProofResult   ProofResult `codec:"proofResult" json:"proofResult"`
 	Time          Time        `codec:"time" json:"time"`
+	Stale         bool        `codec:"stale" json:"stale"`
 	DisplayMarkup string      `codec:"displayMarkup" json:"displayMarkup"`
 }
 
+type TorCheck int
+
+const (
+	TorCheck_NONE     TorCheck = 0
+	TorCheck_VERIFIED TorCheck = 1
+	TorCheck_SKIPPED  TorCheck = 2
+)
+
 type LinkCheckResult struct {
 	ProofId     int          `codec:"proofId" json:"proofId"`
 	ProofResult ProofResult  `codec:"proofResult" json:"proofResult"`
 	TorWarning  bool         `codec:"torWarning" json:"torWarning"`
+	TorCheck    TorCheck     `codec:"torCheck" json:"torCheck"`
 	Cached      *CheckResult `codec:"cached,omitempty" json:"cached,omitempty"`
 	Diff        *TrackDiff   `codec:"diff,omitempty" json:"diff,omitempty"`
 	RemoteDiff  *TrackDiff   `codec:"remoteDiff,omitempty" json:"remoteDiff,omitempty"`
@@ -2044,10 +2623,30 @@ type ConfiguredAccount struct {
 	HasStoredSecret bool   `codec:"hasStoredSecret" json:"hasStoredSecret"`
 }
 
+type AccountSession struct {
+	Username   string `codec:"username" json:"username"`
+	Active     bool   `codec:"active" json:"active"`
+	HasSession bool   `codec:"hasSession" json:"hasSession"`
+}
+
 type GetConfiguredAccountsArg struct {
 	SessionID int `codec:"sessionID" json:"sessionID"`
 }
 
+type GetAccountSessionsArg struct {
+	SessionID int `codec:"sessionID" json:"sessionID"`
+}
+
+type SwitchUserArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Username  string `codec:"username" json:"username"`
+}
+
+type SelectAccountArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Username  string `codec:"username" json:"username"`
+}
+
 type LoginArg struct {
 	SessionID  int    `codec:"sessionID" json:"sessionID"`
 	DeviceType string `codec:"deviceType" json:"deviceType"`
@@ -2074,6 +2673,7 @@ type RecoverAccountFromEmailAddressArg struct {
 
 type PaperKeyArg struct {
 	SessionID int `codec:"sessionID" json:"sessionID"`
+	ExpireIn  int `codec:"expireIn" json:"expireIn"`
 }
 
 type UnlockArg struct {
@@ -2082,12 +2682,15 @@ type UnlockArg struct {
 
 type LoginInterface interface {
 	GetConfiguredAccounts(context.Context, int) ([]ConfiguredAccount, error)
+	GetAccountSessions(context.Context, int) ([]AccountSession, error)
+	SwitchUser(context.Context, SwitchUserArg) error
+	SelectAccount(context.Context, SelectAccountArg) error
 	Login(context.Context, LoginArg) error
 	ClearStoredSecret(context.Context, ClearStoredSecretArg) error
 	Logout(context.Context, int) error
 	Deprovision(context.Context, DeprovisionArg) error
 	RecoverAccountFromEmailAddress(context.Context, string) error
-	PaperKey(context.Context, int) error
+	PaperKey(context.Context, PaperKeyArg) error
 	Unlock(context.Context, int) error
 }
 
@@ -2111,6 +2714,54 @@ func LoginProtocol(i LoginInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"getAccountSessions": {
+				MakeArg: func() interface{} {
+					ret := make([]GetAccountSessionsArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]GetAccountSessionsArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]GetAccountSessionsArg)(nil), args)
+						return
+					}
+					ret, err = i.GetAccountSessions(ctx, (*typedArgs)[0].SessionID)
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"switchUser": {
+				MakeArg: func() interface{} {
+					ret := make([]SwitchUserArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SwitchUserArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SwitchUserArg)(nil), args)
+						return
+					}
+					err = i.SwitchUser(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"selectAccount": {
+				MakeArg: func() interface{} {
+					ret := make([]SelectAccountArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SelectAccountArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SelectAccountArg)(nil), args)
+						return
+					}
+					err = i.SelectAccount(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 			"login": {
 				MakeArg: func() interface{} {
 					ret := make([]LoginArg, 1)
@@ -2202,7 +2853,7 @@ func LoginProtocol(i LoginInterface) rpc.Protocol {
 						err = rpc.NewTypeError((*[]PaperKeyArg)(nil), args)
 						return
 					}
-					err = i.PaperKey(ctx, (*typedArgs)[0].SessionID)
+					err = i.PaperKey(ctx, (*typedArgs)[0])
 					return
 				},
 				MethodType: rpc.MethodCall,
@@ -2237,6 +2888,22 @@ func (c LoginClient) GetConfiguredAccounts(ctx context.Context, sessionID int) (
 	return
 }
 
+func (c LoginClient) GetAccountSessions(ctx context.Context, sessionID int) (res []AccountSession, err error) {
+	__arg := GetAccountSessionsArg{SessionID: sessionID}
+	err = c.Cli.Call(ctx, "keybase.1.login.getAccountSessions", []interface{}{__arg}, &res)
+	return
+}
+
+func (c LoginClient) SwitchUser(ctx context.Context, __arg SwitchUserArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.login.switchUser", []interface{}{__arg}, nil)
+	return
+}
+
+func (c LoginClient) SelectAccount(ctx context.Context, __arg SelectAccountArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.login.selectAccount", []interface{}{__arg}, nil)
+	return
+}
+
 func (c LoginClient) Login(ctx context.Context, __arg LoginArg) (err error) {
 	err = c.Cli.Call(ctx, "keybase.1.login.login", []interface{}{__arg}, nil)
 	return
@@ -2264,8 +2931,7 @@ func (c LoginClient) RecoverAccountFromEmailAddress(ctx context.Context, email s
 	return
 }
 
-func (c LoginClient) PaperKey(ctx context.Context, sessionID int) (err error) {
-	__arg := PaperKeyArg{SessionID: sessionID}
+func (c LoginClient) PaperKey(ctx context.Context, __arg PaperKeyArg) (err error) {
 	err = c.Cli.Call(ctx, "keybase.1.login.paperKey", []interface{}{__arg}, nil)
 	return
 }
@@ -2900,8 +3566,23 @@ type UserChangedArg struct {
 	Uid UID `codec:"uid" json:"uid"`
 }
 
+type SigchainRollbackArg struct {
+	Uid      UID    `codec:"uid" json:"uid"`
+	Username string `codec:"username" json:"username"`
+	Reason   string `codec:"reason" json:"reason"`
+}
+
+type TrackedUserResetArg struct {
+	Uid           UID    `codec:"uid" json:"uid"`
+	Username      string `codec:"username" json:"username"`
+	TrackedEldest KID    `codec:"trackedEldest" json:"trackedEldest"`
+	Eldest        KID    `codec:"eldest" json:"eldest"`
+}
+
 type NotifyUsersInterface interface {
 	UserChanged(context.Context, UID) error
+	SigchainRollback(context.Context, SigchainRollbackArg) error
+	TrackedUserReset(context.Context, TrackedUserResetArg) error
 }
 
 func NotifyUsersProtocol(i NotifyUsersInterface) rpc.Protocol {
@@ -2924,6 +3605,38 @@ func NotifyUsersProtocol(i NotifyUsersInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodNotify,
 			},
+			"sigchainRollback": {
+				MakeArg: func() interface{} {
+					ret := make([]SigchainRollbackArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SigchainRollbackArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SigchainRollbackArg)(nil), args)
+						return
+					}
+					err = i.SigchainRollback(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodNotify,
+			},
+			"trackedUserReset": {
+				MakeArg: func() interface{} {
+					ret := make([]TrackedUserResetArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]TrackedUserResetArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]TrackedUserResetArg)(nil), args)
+						return
+					}
+					err = i.TrackedUserReset(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodNotify,
+			},
 		},
 	}
 }
@@ -2938,6 +3651,16 @@ func (c NotifyUsersClient) UserChanged(ctx context.Context, uid UID) (err error)
 	return
 }
 
+func (c NotifyUsersClient) SigchainRollback(ctx context.Context, __arg SigchainRollbackArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.NotifyUsers.sigchainRollback", []interface{}{__arg}, nil)
+	return
+}
+
+func (c NotifyUsersClient) TrackedUserReset(ctx context.Context, __arg TrackedUserResetArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.NotifyUsers.trackedUserReset", []interface{}{__arg}, nil)
+	return
+}
+
 type SignMode int
 
 const (
@@ -2954,12 +3677,13 @@ type PGPSignOptions struct {
 }
 
 type PGPEncryptOptions struct {
-	Recipients   []string     `codec:"recipients" json:"recipients"`
-	NoSign       bool         `codec:"noSign" json:"noSign"`
-	NoSelf       bool         `codec:"noSelf" json:"noSelf"`
-	BinaryOut    bool         `codec:"binaryOut" json:"binaryOut"`
-	KeyQuery     string       `codec:"keyQuery" json:"keyQuery"`
-	TrackOptions TrackOptions `codec:"trackOptions" json:"trackOptions"`
+	Recipients      []string     `codec:"recipients" json:"recipients"`
+	NoSign          bool         `codec:"noSign" json:"noSign"`
+	NoSelf          bool         `codec:"noSelf" json:"noSelf"`
+	BinaryOut       bool         `codec:"binaryOut" json:"binaryOut"`
+	KeyQuery        string       `codec:"keyQuery" json:"keyQuery"`
+	TrackOptions    TrackOptions `codec:"trackOptions" json:"trackOptions"`
+	KeyserverLookup bool         `codec:"keyserverLookup" json:"keyserverLookup"`
 }
 
 type PGPSigVerification struct {
@@ -2998,6 +3722,14 @@ type PGPCreateUids struct {
 	Ids        []PGPIdentity `codec:"ids" json:"ids"`
 }
 
+type PGPKeyAlgorithm int
+
+const (
+	PGPKeyAlgorithm_DEFAULT PGPKeyAlgorithm = 0
+	PGPKeyAlgorithm_RSA     PGPKeyAlgorithm = 1
+	PGPKeyAlgorithm_EDDSA   PGPKeyAlgorithm = 22
+)
+
 type PGPSignArg struct {
 	SessionID int            `codec:"sessionID" json:"sessionID"`
 	Source    Stream         `codec:"source" json:"source"`
@@ -3006,8 +3738,9 @@ type PGPSignArg struct {
 }
 
 type PGPPullArg struct {
-	SessionID   int      `codec:"sessionID" json:"sessionID"`
-	UserAsserts []string `codec:"userAsserts" json:"userAsserts"`
+	SessionID       int      `codec:"sessionID" json:"sessionID"`
+	UserAsserts     []string `codec:"userAsserts" json:"userAsserts"`
+	KeyserverLookup bool     `codec:"keyserverLookup" json:"keyserverLookup"`
 }
 
 type PGPEncryptArg struct {
@@ -3052,13 +3785,14 @@ type PGPExportByKIDArg struct {
 }
 
 type PGPKeyGenArg struct {
-	SessionID   int           `codec:"sessionID" json:"sessionID"`
-	PrimaryBits int           `codec:"primaryBits" json:"primaryBits"`
-	SubkeyBits  int           `codec:"subkeyBits" json:"subkeyBits"`
-	CreateUids  PGPCreateUids `codec:"createUids" json:"createUids"`
-	AllowMulti  bool          `codec:"allowMulti" json:"allowMulti"`
-	DoExport    bool          `codec:"doExport" json:"doExport"`
-	PushSecret  bool          `codec:"pushSecret" json:"pushSecret"`
+	SessionID   int             `codec:"sessionID" json:"sessionID"`
+	Algorithm   PGPKeyAlgorithm `codec:"algorithm" json:"algorithm"`
+	PrimaryBits int             `codec:"primaryBits" json:"primaryBits"`
+	SubkeyBits  int             `codec:"subkeyBits" json:"subkeyBits"`
+	CreateUids  PGPCreateUids   `codec:"createUids" json:"createUids"`
+	AllowMulti  bool            `codec:"allowMulti" json:"allowMulti"`
+	DoExport    bool            `codec:"doExport" json:"doExport"`
+	PushSecret  bool            `codec:"pushSecret" json:"pushSecret"`
 }
 
 type PGPKeyGenDefaultArg struct {
@@ -3084,6 +3818,20 @@ type PGPUpdateArg struct {
 	Fingerprints []string `codec:"fingerprints" json:"fingerprints"`
 }
 
+type PGPExtendArg struct {
+	SessionID    int      `codec:"sessionID" json:"sessionID"`
+	All          bool     `codec:"all" json:"all"`
+	Fingerprints []string `codec:"fingerprints" json:"fingerprints"`
+	Lifetime     int      `codec:"lifetime" json:"lifetime"`
+}
+
+type PGPRotateSubkeyArg struct {
+	SessionID    int      `codec:"sessionID" json:"sessionID"`
+	All          bool     `codec:"all" json:"all"`
+	Fingerprints []string `codec:"fingerprints" json:"fingerprints"`
+	Lifetime     int      `codec:"lifetime" json:"lifetime"`
+}
+
 type PGPInterface interface {
 	PGPSign(context.Context, PGPSignArg) error
 	PGPPull(context.Context, PGPPullArg) error
@@ -3099,6 +3847,8 @@ type PGPInterface interface {
 	PGPDeletePrimary(context.Context, int) error
 	PGPSelect(context.Context, PGPSelectArg) error
 	PGPUpdate(context.Context, PGPUpdateArg) error
+	PGPExtend(context.Context, PGPExtendArg) error
+	PGPRotateSubkey(context.Context, PGPRotateSubkeyArg) error
 }
 
 func PGPProtocol(i PGPInterface) rpc.Protocol {
@@ -3329,6 +4079,38 @@ func PGPProtocol(i PGPInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"pgpExtend": {
+				MakeArg: func() interface{} {
+					ret := make([]PGPExtendArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]PGPExtendArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]PGPExtendArg)(nil), args)
+						return
+					}
+					err = i.PGPExtend(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"pgpRotateSubkey": {
+				MakeArg: func() interface{} {
+					ret := make([]PGPRotateSubkeyArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]PGPRotateSubkeyArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]PGPRotateSubkeyArg)(nil), args)
+						return
+					}
+					err = i.PGPRotateSubkey(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 		},
 	}
 }
@@ -3408,6 +4190,16 @@ func (c PGPClient) PGPUpdate(ctx context.Context, __arg PGPUpdateArg) (err error
 	return
 }
 
+func (c PGPClient) PGPExtend(ctx context.Context, __arg PGPExtendArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.pgp.pgpExtend", []interface{}{__arg}, nil)
+	return
+}
+
+func (c PGPClient) PGPRotateSubkey(ctx context.Context, __arg PGPRotateSubkeyArg) (err error) {
+	err = c.Cli.Call(ctx, "keybase.1.pgp.pgpRotateSubkey", []interface{}{__arg}, nil)
+	return
+}
+
 type CheckProofStatus struct {
 	Found     bool        `codec:"found" json:"found"`
 	Status    ProofStatus `codec:"status" json:"status"`
@@ -3418,6 +4210,14 @@ type StartProofResult struct {
 	SigID SigID `codec:"sigID" json:"sigID"`
 }
 
+type ProofToPost struct {
+	SigID        SigID  `codec:"sigID" json:"sigID"`
+	Service      string `codec:"service" json:"service"`
+	RemoteName   string `codec:"remoteName" json:"remoteName"`
+	ProofText    string `codec:"proofText" json:"proofText"`
+	Instructions Text   `codec:"instructions" json:"instructions"`
+}
+
 type StartProofArg struct {
 	SessionID    int    `codec:"sessionID" json:"sessionID"`
 	Service      string `codec:"service" json:"service"`
@@ -3431,9 +4231,27 @@ type CheckProofArg struct {
 	SigID     SigID `codec:"sigID" json:"sigID"`
 }
 
+type MakeProofArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Service   string `codec:"service" json:"service"`
+	Username  string `codec:"username" json:"username"`
+	Force     bool   `codec:"force" json:"force"`
+}
+
+type WaitForProofArg struct {
+	SessionID    int    `codec:"sessionID" json:"sessionID"`
+	SigID        SigID  `codec:"sigID" json:"sigID"`
+	Service      string `codec:"service" json:"service"`
+	Username     string `codec:"username" json:"username"`
+	IntervalSecs int    `codec:"intervalSecs" json:"intervalSecs"`
+	TimeoutSecs  int    `codec:"timeoutSecs" json:"timeoutSecs"`
+}
+
 type ProveInterface interface {
 	StartProof(context.Context, StartProofArg) (StartProofResult, error)
 	CheckProof(context.Context, CheckProofArg) (CheckProofStatus, error)
+	MakeProof(context.Context, MakeProofArg) (ProofToPost, error)
+	WaitForProof(context.Context, WaitForProofArg) (CheckProofStatus, error)
 }
 
 func ProveProtocol(i ProveInterface) rpc.Protocol {
@@ -3472,6 +4290,38 @@ func ProveProtocol(i ProveInterface) rpc.Protocol {
 				},
 				MethodType: rpc.MethodCall,
 			},
+			"makeProof": {
+				MakeArg: func() interface{} {
+					ret := make([]MakeProofArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]MakeProofArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]MakeProofArg)(nil), args)
+						return
+					}
+					ret, err = i.MakeProof(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"waitForProof": {
+				MakeArg: func() interface{} {
+					ret := make([]WaitForProofArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]WaitForProofArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]WaitForProofArg)(nil), args)
+						return
+					}
+					ret, err = i.WaitForProof(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
 		},
 	}
 }
@@ -3490,6 +4340,16 @@ func (c ProveClient) CheckProof(ctx context.Context, __arg CheckProofArg) (res C
 	return
 }
 
+func (c ProveClient) MakeProof(ctx context.Context, __arg MakeProofArg) (res ProofToPost, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.prove.makeProof", []interface{}{__arg}, &res)
+	return
+}
+
+func (c ProveClient) WaitForProof(ctx context.Context, __arg WaitForProofArg) (res CheckProofStatus, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.prove.waitForProof", []interface{}{__arg}, &res)
+	return
+}
+
 type PromptOverwriteType int
 
 const (
@@ -3722,8 +4582,9 @@ const (
 )
 
 type SecretResponse struct {
-	Secret []byte `codec:"secret" json:"secret"`
-	Phrase string `codec:"phrase" json:"phrase"`
+	Secret  []byte `codec:"secret" json:"secret"`
+	Phrase  string `codec:"phrase" json:"phrase"`
+	Payload []byte `codec:"payload" json:"payload"`
 }
 
 type ChooseProvisioningMethodArg struct {
@@ -3740,6 +4601,8 @@ type DisplayAndPromptSecretArg struct {
 	Secret          []byte     `codec:"secret" json:"secret"`
 	Phrase          string     `codec:"phrase" json:"phrase"`
 	OtherDeviceType DeviceType `codec:"otherDeviceType" json:"otherDeviceType"`
+	Payload         []byte     `codec:"payload" json:"payload"`
+	PayloadText     string     `codec:"payloadText" json:"payloadText"`
 }
 
 type DisplaySecretExchangedArg struct {
@@ -4323,6 +5186,108 @@ func (c SessionClient) CurrentSession(ctx context.Context, sessionID int) (res S
 	return
 }
 
+type SigchainLink struct {
+	Seqno           int     `codec:"seqno" json:"seqno"`
+	SigID           SigID   `codec:"sigID" json:"sigID"`
+	LinkID          string  `codec:"linkID" json:"linkID"`
+	Prev            string  `codec:"prev" json:"prev"`
+	Type            string  `codec:"type" json:"type"`
+	CTime           Time    `codec:"cTime" json:"cTime"`
+	ETime           Time    `codec:"eTime" json:"eTime"`
+	Kid             KID     `codec:"kid" json:"kid"`
+	PayloadJSON     string  `codec:"payloadJSON" json:"payloadJSON"`
+	Revoked         bool    `codec:"revoked" json:"revoked"`
+	Revokes         []SigID `codec:"revokes" json:"revokes"`
+	RevokesKIDs     []KID   `codec:"revokesKIDs" json:"revokesKIDs"`
+	Delegates       KID     `codec:"delegates" json:"delegates"`
+	KeyFamilyChange bool    `codec:"keyFamilyChange" json:"keyFamilyChange"`
+	Checked         bool    `codec:"checked" json:"checked"`
+	Ignored         string  `codec:"ignored" json:"ignored"`
+	HashError       string  `codec:"hashError" json:"hashError"`
+	PayloadError    string  `codec:"payloadError" json:"payloadError"`
+	SigError        string  `codec:"sigError" json:"sigError"`
+	ChainError      string  `codec:"chainError" json:"chainError"`
+}
+
+type SigchainRes struct {
+	Uid          UID            `codec:"uid" json:"uid"`
+	Username     string         `codec:"username" json:"username"`
+	Links        []SigchainLink `codec:"links" json:"links"`
+	MerkleSeqno  int            `codec:"merkleSeqno" json:"merkleSeqno"`
+	MerkleLinkID string         `codec:"merkleLinkID" json:"merkleLinkID"`
+	MerkleError  string         `codec:"merkleError" json:"merkleError"`
+	Verified     bool           `codec:"verified" json:"verified"`
+}
+
+type SigchainShowArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Username  string `codec:"username" json:"username"`
+}
+
+type SigchainVerifyArg struct {
+	SessionID int    `codec:"sessionID" json:"sessionID"`
+	Username  string `codec:"username" json:"username"`
+}
+
+type SigchainInterface interface {
+	SigchainShow(context.Context, SigchainShowArg) (SigchainRes, error)
+	SigchainVerify(context.Context, SigchainVerifyArg) (SigchainRes, error)
+}
+
+func SigchainProtocol(i SigchainInterface) rpc.Protocol {
+	return rpc.Protocol{
+		Name: "keybase.1.sigchain",
+		Methods: map[string]rpc.ServeHandlerDescription{
+			"sigchainShow": {
+				MakeArg: func() interface{} {
+					ret := make([]SigchainShowArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SigchainShowArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SigchainShowArg)(nil), args)
+						return
+					}
+					ret, err = i.SigchainShow(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+			"sigchainVerify": {
+				MakeArg: func() interface{} {
+					ret := make([]SigchainVerifyArg, 1)
+					return &ret
+				},
+				Handler: func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+					typedArgs, ok := args.(*[]SigchainVerifyArg)
+					if !ok {
+						err = rpc.NewTypeError((*[]SigchainVerifyArg)(nil), args)
+						return
+					}
+					ret, err = i.SigchainVerify(ctx, (*typedArgs)[0])
+					return
+				},
+				MethodType: rpc.MethodCall,
+			},
+		},
+	}
+}
+
+type SigchainClient struct {
+	Cli GenericClient
+}
+
+func (c SigchainClient) SigchainShow(ctx context.Context, __arg SigchainShowArg) (res SigchainRes, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.sigchain.sigchainShow", []interface{}{__arg}, &res)
+	return
+}
+
+func (c SigchainClient) SigchainVerify(ctx context.Context, __arg SigchainVerifyArg) (res SigchainRes, err error) {
+	err = c.Cli.Call(ctx, "keybase.1.sigchain.sigchainVerify", []interface{}{__arg}, &res)
+	return
+}
+
 type SignupRes struct {
 	PassphraseOk bool `codec:"passphraseOk" json:"passphraseOk"`
 	PostOk       bool `codec:"postOk" json:"postOk"`
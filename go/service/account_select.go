@@ -0,0 +1,80 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"sync"
+
+	"github.com/keybase/client/go/libkb"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+	"golang.org/x/net/context"
+)
+
+// accountSelector is the account a connection has pinned itself to with
+// login.selectAccount, and the connection's handlers for each account
+// context its calls have run in.  There's one per connection.
+type accountSelector struct {
+	sync.Mutex
+	username  libkb.NormalizedUsername
+	protocols map[*libkb.GlobalContext][]rpc.Protocol
+}
+
+func (a *accountSelector) get() libkb.NormalizedUsername {
+	a.Lock()
+	defer a.Unlock()
+	return a.username
+}
+
+func (a *accountSelector) set(nu libkb.NormalizedUsername) {
+	a.Lock()
+	defer a.Unlock()
+	a.username = nu
+}
+
+// protocolsFor returns the connection's handlers for calls that run in g,
+// making them with mk the first time.
+func (a *accountSelector) protocolsFor(g *libkb.GlobalContext, mk func(*libkb.GlobalContext) []rpc.Protocol) []rpc.Protocol {
+	a.Lock()
+	defer a.Unlock()
+	if ret, ok := a.protocols[g]; ok {
+		return ret
+	}
+	if a.protocols == nil {
+		a.protocols = make(map[*libkb.GlobalContext][]rpc.Protocol)
+	}
+	ret := mk(g)
+	a.protocols[g] = ret
+	return ret
+}
+
+// pinProtocols wraps every handler in protocols, which mk made for g, so
+// that if the connection has pinned an account, the call is handled by
+// the handler mk makes for that account's own context (see
+// libkb.GlobalContext.ForAccount).  The active user is never switched, so
+// calls on other connections, and the service's background work, still
+// run as whoever they ran as.
+func pinProtocols(g *libkb.GlobalContext, sel *accountSelector, protocols []rpc.Protocol, mk func(*libkb.GlobalContext) []rpc.Protocol) []rpc.Protocol {
+	ret := make([]rpc.Protocol, len(protocols))
+	for i, proto := range protocols {
+		i := i
+		methods := make(map[string]rpc.ServeHandlerDescription, len(proto.Methods))
+		for name, desc := range proto.Methods {
+			name, handler := name, desc.Handler
+			desc.Handler = func(ctx context.Context, args interface{}) (interface{}, error) {
+				ag, err := g.ForAccount(sel.get())
+				if err != nil {
+					return nil, err
+				}
+				if ag == g {
+					return handler(ctx, args)
+				}
+				return sel.protocolsFor(ag, mk)[i].Methods[name].Handler(ctx, args)
+			}
+			methods[name] = desc
+		}
+		proto.Methods = methods
+		ret[i] = proto
+	}
+	return ret
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"github.com/keybase/client/go/engine"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+	"golang.org/x/net/context"
+)
+
+type CryptocurrencyHandler struct {
+	*BaseHandler
+	libkb.Contextified
+}
+
+func NewCryptocurrencyHandler(xp rpc.Transporter, g *libkb.GlobalContext) *CryptocurrencyHandler {
+	return &CryptocurrencyHandler{
+		BaseHandler:  NewBaseHandler(xp),
+		Contextified: libkb.NewContextified(g),
+	}
+}
+
+// RegisterAddress creates a CryptocurrencyEngine and runs it.
+func (h *CryptocurrencyHandler) RegisterAddress(_ context.Context, arg keybase1.RegisterAddressArg) (keybase1.RegisterAddressRes, error) {
+	ctx := engine.Context{
+		LogUI:    h.getLogUI(arg.SessionID),
+		SecretUI: h.getSecretUI(arg.SessionID),
+	}
+	eng := engine.NewCryptocurrencyEngine(h.G(), arg)
+	if err := engine.RunEngine(eng, &ctx); err != nil {
+		return keybase1.RegisterAddressRes{}, err
+	}
+	return eng.Result(), nil
+}
+
+// RevokeAddress creates a CryptocurrencyRevokeEngine and runs it.
+func (h *CryptocurrencyHandler) RevokeAddress(_ context.Context, arg keybase1.RevokeAddressArg) error {
+	ctx := engine.Context{
+		LogUI:    h.getLogUI(arg.SessionID),
+		SecretUI: h.getSecretUI(arg.SessionID),
+	}
+	eng := engine.NewCryptocurrencyRevokeEngine(h.G(), arg.Address)
+	return engine.RunEngine(eng, &ctx)
+}
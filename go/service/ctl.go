@@ -4,8 +4,11 @@
 package service
 
 import (
+	"time"
+
 	"github.com/keybase/client/go/engine"
 	"github.com/keybase/client/go/libkb"
+	"github.com/keybase/client/go/logger"
 	keybase1 "github.com/keybase/client/go/protocol"
 	rpc "github.com/keybase/go-framed-msgpack-rpc"
 	"golang.org/x/net/context"
@@ -39,8 +42,12 @@ func (c *CtlHandler) LogRotate(_ context.Context, sessionID int) error {
 }
 
 func (c *CtlHandler) SetLogLevel(_ context.Context, arg keybase1.SetLogLevelArg) error {
-	c.G().Log.SetExternalLogLevel(arg.Level)
-	return nil
+	if len(arg.Module) == 0 {
+		c.G().Log.SetExternalLogLevel(arg.Level)
+		return nil
+	}
+	c.G().Log.Info("Setting log level of %s to %d", arg.Module, arg.Level)
+	return logger.SetLogLevel(arg.Module, arg.Level)
 }
 
 func (c *CtlHandler) Reload(_ context.Context, sessionID int) error {
@@ -48,6 +55,11 @@ func (c *CtlHandler) Reload(_ context.Context, sessionID int) error {
 	return c.G().ConfigReload()
 }
 
+func (c *CtlHandler) Lock(_ context.Context, sessionID int) error {
+	c.G().Log.Info("Locking secrets")
+	return c.G().LockSecrets()
+}
+
 func (c *CtlHandler) DbNuke(_ context.Context, sessionID int) error {
 	ctx := engine.Context{
 		LogUI: c.getLogUI(sessionID),
@@ -63,3 +75,88 @@ func (c *CtlHandler) DbNuke(_ context.Context, sessionID int) error {
 	// Now drop caches, since we had the DB's state in-memory too.
 	return c.G().ConfigureCaches()
 }
+
+func (c *CtlHandler) DbStats(_ context.Context, sessionID int) ([]keybase1.DbTableStats, error) {
+	stats, err := c.G().LocalDb.Stats()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]keybase1.DbTableStats, len(stats))
+	for i, s := range stats {
+		ret[i] = keybase1.DbTableStats{Table: s.Name(), Entries: s.Entries, Bytes: s.Bytes}
+	}
+	return ret, nil
+}
+
+func (c *CtlHandler) DbCompact(_ context.Context, sessionID int) error {
+	c.G().Log.Info("Compacting database")
+	return c.G().LocalDb.Compact()
+}
+
+func (c *CtlHandler) DbVerify(_ context.Context, arg keybase1.DbVerifyArg) (keybase1.DbVerifyResult, error) {
+	ctx := &engine.Context{
+		LogUI: c.getLogUI(arg.SessionID),
+	}
+	eng := engine.NewDbVerify(c.G(), arg.EvictBad)
+	if err := engine.RunEngine(eng, ctx); err != nil {
+		return keybase1.DbVerifyResult{}, err
+	}
+	res := eng.Result()
+	if res.Evicted > 0 {
+		// Drop the evicted values from the caches too.
+		if err := c.G().ConfigureCaches(); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+func (c *CtlHandler) DbEvict(_ context.Context, arg keybase1.DbEvictArg) (int, error) {
+	ctx := &engine.Context{
+		LogUI: c.getLogUI(arg.SessionID),
+	}
+	eng := engine.NewDbEvict(c.G(), arg.Tables, arg.Usernames)
+	if err := engine.RunEngine(eng, ctx); err != nil {
+		return 0, err
+	}
+	// Drop the evicted values from the caches too.
+	return eng.Evicted(), c.G().ConfigureCaches()
+}
+
+func (c *CtlHandler) GetStats(_ context.Context, sessionID int) (keybase1.ServiceStats, error) {
+	return c.G().Stats.Export(), nil
+}
+
+func (c *CtlHandler) ResetStats(_ context.Context, sessionID int) error {
+	c.G().Log.Info("Resetting service stats")
+	c.G().Stats.Reset()
+	return nil
+}
+
+func (c *CtlHandler) CaptureProfile(_ context.Context, arg keybase1.CaptureProfileArg) (string, error) {
+	return captureProfile(c.G(), arg.ProfileType, time.Duration(arg.DurationSeconds)*time.Second)
+}
+
+func (c *CtlHandler) GetOfflineStatus(_ context.Context, sessionID int) (keybase1.OfflineStatus, error) {
+	return libkb.OfflineStatus(c.G())
+}
+
+func (c *CtlHandler) SetOffline(_ context.Context, arg keybase1.SetOfflineArg) (keybase1.OfflineStatus, error) {
+	if arg.Offline {
+		c.G().Log.Info("Going offline")
+		c.G().SetOffline(true)
+		return libkb.OfflineStatus(c.G())
+	}
+
+	c.G().Log.Info("Going back online")
+	c.G().SetOffline(false)
+	if err := libkb.FlushOfflineQueue(c.G()); err != nil {
+		c.G().Log.Warning("Failed to post signatures made while offline: %s", err)
+		return keybase1.OfflineStatus{}, err
+	}
+	return libkb.OfflineStatus(c.G())
+}
+
+func (c *CtlHandler) DiscardOfflineConflicts(_ context.Context, sessionID int) error {
+	return libkb.DiscardOfflineConflicts(c.G())
+}
@@ -37,11 +37,47 @@ func (h *DeviceHandler) DeviceList(_ context.Context, sessionID int) ([]keybase1
 
 // DeviceAdd starts the kex2 device provisioning on the
 // provisioner (device X/C1)
-func (h *DeviceHandler) DeviceAdd(_ context.Context, sessionID int) error {
+func (h *DeviceHandler) DeviceAdd(_ context.Context, arg keybase1.DeviceAddArg) error {
 	ctx := &engine.Context{
-		ProvisionUI: h.getProvisionUI(sessionID),
-		SecretUI:    h.getSecretUI(sessionID),
+		ProvisionUI: h.getProvisionUI(arg.SessionID),
+		SecretUI:    h.getSecretUI(arg.SessionID),
 	}
-	eng := engine.NewDeviceAdd(h.G())
+	eng := engine.NewDeviceAddWithExpiry(h.G(), arg.ExpireIn)
 	return engine.RunEngine(eng, ctx)
 }
+
+// DeviceExtend pushes back the expiration of the current device's keys.
+func (h *DeviceHandler) DeviceExtend(_ context.Context, arg keybase1.DeviceExtendArg) error {
+	ctx := &engine.Context{
+		LogUI:    h.getLogUI(arg.SessionID),
+		SecretUI: h.getSecretUI(arg.SessionID),
+	}
+	eng := engine.NewDeviceExtend(h.G(), arg.ExpireIn)
+	return engine.RunEngine(eng, ctx)
+}
+
+// DeviceRotate replaces the current device's keys with fresh ones.
+func (h *DeviceHandler) DeviceRotate(_ context.Context, sessionID int) error {
+	ctx := &engine.Context{
+		LogUI:    h.getLogUI(sessionID),
+		SecretUI: h.getSecretUI(sessionID),
+	}
+	eng := engine.NewDeviceRotate(h.G())
+	return engine.RunEngine(eng, ctx)
+}
+
+// DeviceProtect moves the client half of the device's local key
+// encryption secret to another key protector.
+func (h *DeviceHandler) DeviceProtect(_ context.Context, arg keybase1.DeviceProtectArg) error {
+	ctx := &engine.Context{
+		LogUI:    h.getLogUI(arg.SessionID),
+		SecretUI: h.getSecretUI(arg.SessionID),
+	}
+	eng := engine.NewDeviceProtect(h.G(), arg.Protector)
+	return engine.RunEngine(eng, ctx)
+}
+
+// DeviceGetProtector names the key protector in use.
+func (h *DeviceHandler) DeviceGetProtector(_ context.Context, sessionID int) (string, error) {
+	return libkb.CurrentKeyProtector(h.G()).Name(), nil
+}
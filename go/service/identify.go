@@ -42,7 +42,7 @@ func (h *IdentifyHandler) Identify(_ context.Context, arg keybase1.IdentifyArg)
 }
 
 func (h *IdentifyHandler) IdentifyDefault(_ context.Context, arg keybase1.IdentifyArg) (keybase1.IdentifyRes, error) {
-	iarg := keybase1.IdentifyArg{UserAssertion: arg.UserAssertion, ForceRemoteCheck: arg.ForceRemoteCheck}
+	iarg := keybase1.IdentifyArg{UserAssertion: arg.UserAssertion, ForceRemoteCheck: arg.ForceRemoteCheck, CacheOnly: arg.CacheOnly}
 	res, err := h.identify(arg.SessionID, iarg, true)
 	if err != nil {
 		return keybase1.IdentifyRes{}, err
@@ -0,0 +1,28 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// +build !windows
+
+package service
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// lockOnSignal locks cached secrets whenever the service gets SIGUSR1,
+// so that a screen locker's hook can lock them with nothing more than
+// `pkill -USR1 keybase`.
+func (d *Service) lockOnSignal() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGUSR1)
+	go func() {
+		for range c {
+			d.G().Log.Info("Locking secrets on SIGUSR1")
+			if err := d.G().LockSecrets(); err != nil {
+				d.G().Log.Warning("Failed to lock secrets: %s", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,10 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+// +build windows
+
+package service
+
+// lockOnSignal does nothing, since Windows has no SIGUSR1.  Screen lock
+// hooks there run `keybase lock` instead.
+func (d *Service) lockOnSignal() {}
@@ -4,6 +4,9 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/keybase/client/go/engine"
 	"github.com/keybase/client/go/libkb"
 	keybase1 "github.com/keybase/client/go/protocol"
@@ -15,12 +18,14 @@ type LoginHandler struct {
 	libkb.Contextified
 	*BaseHandler
 	identifyUI libkb.IdentifyUI
+	sel        *accountSelector
 }
 
-func NewLoginHandler(xp rpc.Transporter, g *libkb.GlobalContext) *LoginHandler {
+func NewLoginHandler(xp rpc.Transporter, sel *accountSelector, g *libkb.GlobalContext) *LoginHandler {
 	return &LoginHandler{
 		BaseHandler:  NewBaseHandler(xp),
 		Contextified: libkb.NewContextified(g),
+		sel:          sel,
 	}
 }
 
@@ -28,6 +33,37 @@ func (h *LoginHandler) GetConfiguredAccounts(_ context.Context, sessionID int) (
 	return libkb.GetConfiguredAccounts(h.G())
 }
 
+func (h *LoginHandler) GetAccountSessions(_ context.Context, sessionID int) ([]keybase1.AccountSession, error) {
+	return h.G().AccountSessions()
+}
+
+func (h *LoginHandler) SwitchUser(_ context.Context, arg keybase1.SwitchUserArg) error {
+	nu := libkb.NewNormalizedUsername(arg.Username)
+	if !libkb.CheckUsername.F(arg.Username) {
+		return errors.New("invalid username provided to switchUser")
+	}
+	return h.G().SwitchUser(nu)
+}
+
+func (h *LoginHandler) SelectAccount(_ context.Context, arg keybase1.SelectAccountArg) error {
+	nu := libkb.NewNormalizedUsername(arg.Username)
+	if len(nu) > 0 {
+		current, others, err := h.G().Env.GetConfig().GetAllUsernames()
+		if err != nil {
+			return err
+		}
+		found := current.Eq(nu)
+		for _, o := range others {
+			found = found || o.Eq(nu)
+		}
+		if !found {
+			return fmt.Errorf("%s isn't configured on this device", nu)
+		}
+	}
+	h.sel.set(nu)
+	return nil
+}
+
 func (h *LoginHandler) Logout(_ context.Context, sessionID int) error {
 	return h.G().Logout()
 }
@@ -63,13 +99,13 @@ func (h *LoginHandler) ClearStoredSecret(_ context.Context, arg keybase1.ClearSt
 	return libkb.ClearStoredSecret(libkb.NewNormalizedUsername(arg.Username))
 }
 
-func (h *LoginHandler) PaperKey(_ context.Context, sessionID int) error {
+func (h *LoginHandler) PaperKey(_ context.Context, arg keybase1.PaperKeyArg) error {
 	ctx := &engine.Context{
-		LogUI:    h.getLogUI(sessionID),
-		LoginUI:  h.getLoginUI(sessionID),
-		SecretUI: h.getSecretUI(sessionID),
+		LogUI:    h.getLogUI(arg.SessionID),
+		LoginUI:  h.getLoginUI(arg.SessionID),
+		SecretUI: h.getSecretUI(arg.SessionID),
 	}
-	eng := engine.NewPaperKey(h.G())
+	eng := engine.NewPaperKeyWithExpiry(h.G(), arg.ExpireIn)
 	return engine.RunEngine(eng, ctx)
 }
 
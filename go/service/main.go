@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"path"
+	"time"
 
 	"github.com/keybase/cli"
 	"github.com/keybase/client/go/libcmdline"
@@ -39,33 +40,42 @@ func (d *Service) GetStartChannel() <-chan struct{} {
 	return d.startCh
 }
 
-func (d *Service) RegisterProtocols(srv *rpc.Server, xp rpc.Transporter, connID libkb.ConnectionID, g *libkb.GlobalContext) error {
+func (d *Service) RegisterProtocols(srv *rpc.Server, xp rpc.Transporter, connID libkb.ConnectionID, sel *accountSelector, g *libkb.GlobalContext) error {
+	accountProtocols := func(g *libkb.GlobalContext) []rpc.Protocol {
+		return []rpc.Protocol{
+			keybase1.AccountProtocol(NewAccountHandler(xp, g)),
+			keybase1.BTCProtocol(NewBTCHandler(xp, g)),
+			keybase1.ConfigProtocol(NewConfigHandler(xp, g, d)),
+			keybase1.CryptoProtocol(NewCryptoHandler(xp, g)),
+			keybase1.CryptocurrencyProtocol(NewCryptocurrencyHandler(xp, g)),
+			keybase1.DeviceProtocol(NewDeviceHandler(xp, g)),
+			keybase1.FavoriteProtocol(NewFavoriteHandler(xp, g)),
+			keybase1.IdentifyProtocol(NewIdentifyHandler(xp, g)),
+			keybase1.KbfsProtocol(NewKBFSHandler(xp, g)),
+			keybase1.LoginProtocol(NewLoginHandler(xp, sel, g)),
+			keybase1.ProveProtocol(NewProveHandler(xp, g)),
+			keybase1.SessionProtocol(NewSessionHandler(xp, g)),
+			keybase1.SignupProtocol(NewSignupHandler(xp, g)),
+			keybase1.SigchainProtocol(NewSigchainHandler(xp, g)),
+			keybase1.SigsProtocol(NewSigsHandler(xp, g)),
+			keybase1.PGPProtocol(NewPGPHandler(xp, g)),
+			keybase1.RevokeProtocol(NewRevokeHandler(xp, g)),
+			keybase1.TestProtocol(NewTestHandler(xp, g)),
+			keybase1.TrackProtocol(NewTrackHandler(xp, g)),
+			keybase1.UserProtocol(NewUserHandler(xp, g)),
+		}
+	}
+	// These are about the service rather than any one account, so they
+	// aren't pinned.
 	protocols := []rpc.Protocol{
-		keybase1.AccountProtocol(NewAccountHandler(xp, g)),
-		keybase1.BTCProtocol(NewBTCHandler(xp, g)),
-		keybase1.ConfigProtocol(NewConfigHandler(xp, g, d)),
-		keybase1.CryptoProtocol(NewCryptoHandler(xp, g)),
 		keybase1.CtlProtocol(NewCtlHandler(xp, d, g)),
 		keybase1.DebuggingProtocol(NewDebuggingHandler(xp)),
-		keybase1.DeviceProtocol(NewDeviceHandler(xp, g)),
-		keybase1.FavoriteProtocol(NewFavoriteHandler(xp, g)),
-		keybase1.IdentifyProtocol(NewIdentifyHandler(xp, g)),
-		keybase1.KbfsProtocol(NewKBFSHandler(xp, g)),
-		keybase1.LoginProtocol(NewLoginHandler(xp, g)),
-		keybase1.ProveProtocol(NewProveHandler(xp, g)),
-		keybase1.SessionProtocol(NewSessionHandler(xp, g)),
-		keybase1.SignupProtocol(NewSignupHandler(xp, g)),
-		keybase1.SigsProtocol(NewSigsHandler(xp, g)),
-		keybase1.PGPProtocol(NewPGPHandler(xp, g)),
-		keybase1.RevokeProtocol(NewRevokeHandler(xp, g)),
-		keybase1.TestProtocol(NewTestHandler(xp, g)),
-		keybase1.TrackProtocol(NewTrackHandler(xp, g)),
-		keybase1.UserProtocol(NewUserHandler(xp, g)),
 		keybase1.NotifyCtlProtocol(NewNotifyCtlHandler(xp, connID, g)),
 		keybase1.DelegateUiCtlProtocol(NewDelegateUICtlHandler(xp, connID, g)),
 	}
+	protocols = append(protocols, pinProtocols(g, sel, accountProtocols(g), accountProtocols)...)
 	for _, proto := range protocols {
-		if err := srv.Register(proto); err != nil {
+		if err := srv.Register(instrumentProtocol(g, proto)); err != nil {
 			return err
 		}
 	}
@@ -81,7 +91,7 @@ func (d *Service) Handle(c net.Conn) {
 	server.AddCloseListener(cl)
 	connID := d.G().NotifyRouter.AddConnection(xp, cl)
 
-	if err := d.RegisterProtocols(server, xp, connID, d.G()); err != nil {
+	if err := d.RegisterProtocols(server, xp, connID, &accountSelector{}, d.G()); err != nil {
 		d.G().Log.Warning("RegisterProtocols error: %s", err)
 		return
 	}
@@ -133,6 +143,9 @@ func (d *Service) Run() (err error) {
 	if err = d.GetExclusiveLock(); err != nil {
 		return
 	}
+	if !d.G().Env.GetSplitLogOutput() {
+		d.G().Log.AutoRotate(d.G().Env.GetLogRotateOptions())
+	}
 	if err = d.OpenSocket(); err != nil {
 		return
 	}
@@ -141,12 +154,51 @@ func (d *Service) Run() (err error) {
 	if l, err = d.ConfigRPCServer(); err != nil {
 		return
 	}
+
+	d.flushOfflineQueue()
+	d.expireSecrets()
+	d.lockOnSignal()
+
 	if err = d.ListenLoopWithStopper(l); err != nil {
 		return
 	}
 	return
 }
 
+// flushOfflineQueue posts, in the background, signatures that were made
+// while offline the last time the service ran.
+func (d *Service) flushOfflineQueue() {
+	if d.G().IsOffline() {
+		return
+	}
+	go func() {
+		if err := libkb.FlushOfflineQueue(d.G()); err != nil {
+			d.G().Log.Warning("Failed to post signatures made while offline: %s", err)
+		}
+	}()
+}
+
+// How often to check whether cached secrets have timed out.
+const secretCacheCheckInterval = 15 * time.Second
+
+// expireSecrets locks, in the background, cached secrets that have
+// outlived the timeouts in the config, for every account.  The timeouts
+// are read each time, so that reloading the config changes them.
+func (d *Service) expireSecrets() {
+	go func() {
+		for range time.Tick(secretCacheCheckInterval) {
+			for _, g := range d.G().AccountContexts() {
+				locked, err := g.LoginState().ExpireSecrets()
+				if err != nil {
+					d.G().Log.Warning("Failed to expire cached secrets: %s", err)
+				} else if locked {
+					d.G().Log.Info("Locked cached secrets after their timeout")
+				}
+			}
+		}
+	}()
+}
+
 func (d *Service) StartLoopbackServer() error {
 
 	var l net.Listener
@@ -35,7 +35,8 @@ func (h *PGPHandler) PGPSign(_ context.Context, arg keybase1.PGPSignArg) (err er
 
 func (h *PGPHandler) PGPPull(_ context.Context, arg keybase1.PGPPullArg) error {
 	earg := engine.PGPPullEngineArg{
-		UserAsserts: arg.UserAsserts,
+		UserAsserts:     arg.UserAsserts,
+		KeyserverLookup: arg.KeyserverLookup,
 	}
 	ctx := engine.Context{
 		LogUI:      h.getLogUI(arg.SessionID),
@@ -50,14 +51,15 @@ func (h *PGPHandler) PGPEncrypt(_ context.Context, arg keybase1.PGPEncryptArg) e
 	src := libkb.NewRemoteStreamBuffered(arg.Source, cli, arg.SessionID)
 	snk := libkb.NewRemoteStreamBuffered(arg.Sink, cli, arg.SessionID)
 	earg := &engine.PGPEncryptArg{
-		Recips:       arg.Opts.Recipients,
-		Sink:         snk,
-		Source:       src,
-		NoSign:       arg.Opts.NoSign,
-		NoSelf:       arg.Opts.NoSelf,
-		BinaryOutput: arg.Opts.BinaryOut,
-		KeyQuery:     arg.Opts.KeyQuery,
-		TrackOptions: arg.Opts.TrackOptions,
+		Recips:          arg.Opts.Recipients,
+		Sink:            snk,
+		Source:          src,
+		NoSign:          arg.Opts.NoSign,
+		NoSelf:          arg.Opts.NoSelf,
+		BinaryOutput:    arg.Opts.BinaryOut,
+		KeyQuery:        arg.Opts.KeyQuery,
+		TrackOptions:    arg.Opts.TrackOptions,
+		KeyserverLookup: arg.Opts.KeyserverLookup,
 	}
 	ctx := &engine.Context{
 		IdentifyUI: h.NewRemoteIdentifyUI(arg.SessionID, h.G()),
@@ -226,3 +228,21 @@ func (h *PGPHandler) PGPUpdate(_ context.Context, arg keybase1.PGPUpdateArg) err
 	eng := engine.NewPGPUpdateEngine(arg.Fingerprints, arg.All, h.G())
 	return engine.RunEngine(eng, &ctx)
 }
+
+func (h *PGPHandler) PGPExtend(_ context.Context, arg keybase1.PGPExtendArg) error {
+	ctx := engine.Context{
+		LogUI:    h.getLogUI(arg.SessionID),
+		SecretUI: h.getSecretUI(arg.SessionID),
+	}
+	eng := engine.NewPGPExtendEngine(arg.Fingerprints, arg.All, arg.Lifetime, h.G())
+	return engine.RunEngine(eng, &ctx)
+}
+
+func (h *PGPHandler) PGPRotateSubkey(_ context.Context, arg keybase1.PGPRotateSubkeyArg) error {
+	ctx := engine.Context{
+		LogUI:    h.getLogUI(arg.SessionID),
+		SecretUI: h.getSecretUI(arg.SessionID),
+	}
+	eng := engine.NewPGPRotateSubkeyEngine(arg.Fingerprints, arg.All, arg.Lifetime, h.G())
+	return engine.RunEngine(eng, &ctx)
+}
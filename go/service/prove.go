@@ -91,3 +91,27 @@ func (ph *ProveHandler) CheckProof(_ context.Context, arg keybase1.CheckProofArg
 
 	return
 }
+
+// MakeProof handles the `keybase.1.makeProof` RPC, which is startProof
+// without the prove UI.
+func (ph *ProveHandler) MakeProof(_ context.Context, arg keybase1.MakeProofArg) (res keybase1.ProofToPost, err error) {
+	eng := engine.NewProveNoninteractive(&arg, ph.G())
+	ctx := engine.Context{
+		SecretUI: ph.getSecretUI(arg.SessionID),
+		LogUI:    ph.getLogUI(arg.SessionID),
+	}
+	if err = engine.RunEngine(eng, &ctx); err != nil {
+		return res, err
+	}
+	return eng.ProofToPost(), nil
+}
+
+func (ph *ProveHandler) WaitForProof(_ context.Context, arg keybase1.WaitForProofArg) (res keybase1.CheckProofStatus, err error) {
+	eng := engine.NewProveWait(ph.G(), &arg)
+	ctx := &engine.Context{LogUI: ph.getLogUI(arg.SessionID)}
+	if err = engine.RunEngine(eng, ctx); err != nil {
+		return res, err
+	}
+	res.Found, res.Status = eng.Results()
+	return res, nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"github.com/keybase/client/go/engine"
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+	"golang.org/x/net/context"
+)
+
+type SigchainHandler struct {
+	*BaseHandler
+	libkb.Contextified
+}
+
+func NewSigchainHandler(xp rpc.Transporter, g *libkb.GlobalContext) *SigchainHandler {
+	return &SigchainHandler{
+		BaseHandler:  NewBaseHandler(xp),
+		Contextified: libkb.NewContextified(g),
+	}
+}
+
+func (h *SigchainHandler) SigchainShow(_ context.Context, arg keybase1.SigchainShowArg) (keybase1.SigchainRes, error) {
+	return h.run(arg.SessionID, engine.SigchainArg{Username: arg.Username})
+}
+
+func (h *SigchainHandler) SigchainVerify(_ context.Context, arg keybase1.SigchainVerifyArg) (keybase1.SigchainRes, error) {
+	return h.run(arg.SessionID, engine.SigchainArg{Username: arg.Username, Verify: true})
+}
+
+func (h *SigchainHandler) run(sessionID int, arg engine.SigchainArg) (keybase1.SigchainRes, error) {
+	ctx := engine.Context{
+		LogUI: h.getLogUI(sessionID),
+	}
+	eng := engine.NewSigchain(h.G(), arg)
+	if err := engine.RunEngine(eng, &ctx); err != nil {
+		return keybase1.SigchainRes{}, err
+	}
+	return eng.Result(), nil
+}
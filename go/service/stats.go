@@ -0,0 +1,99 @@
+// Copyright 2015 Keybase, Inc. All rights reserved. Use of
+// this source code is governed by the included BSD license.
+
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/keybase/client/go/libkb"
+	keybase1 "github.com/keybase/client/go/protocol"
+	rpc "github.com/keybase/go-framed-msgpack-rpc"
+	"golang.org/x/net/context"
+)
+
+// instrumentProtocol wraps every handler in the protocol so that calls
+// are recorded in the global stats registry, keyed by the full method
+// name (e.g. "keybase.1.identify.identify").
+func instrumentProtocol(g *libkb.GlobalContext, proto rpc.Protocol) rpc.Protocol {
+	methods := make(map[string]rpc.ServeHandlerDescription, len(proto.Methods))
+	for name, desc := range proto.Methods {
+		fullName := proto.Name + "." + name
+		handler := desc.Handler
+		desc.Handler = func(ctx context.Context, args interface{}) (ret interface{}, err error) {
+			defer g.Stats.Start(keybase1.StatCategory_RPC, fullName)(&err)
+			return handler(ctx, args)
+		}
+		methods[name] = desc
+	}
+	proto.Methods = methods
+	return proto
+}
+
+const maxCPUProfileDuration = 5 * time.Minute
+
+// captureProfile writes a profile of type typ to the log directory, and
+// returns its filename.  A CPU profile is sampled for dur after it
+// returns, and the file is only complete once that's over; only one can
+// run at a time.
+func captureProfile(g *libkb.GlobalContext, typ keybase1.ProfileType, dur time.Duration) (string, error) {
+	var name string
+	switch typ {
+	case keybase1.ProfileType_CPU:
+		name = "cpu"
+	case keybase1.ProfileType_HEAP:
+		name = "heap"
+	case keybase1.ProfileType_GOROUTINE:
+		name = "goroutine"
+	default:
+		return "", fmt.Errorf("unknown profile type: %d", typ)
+	}
+
+	if typ == keybase1.ProfileType_CPU {
+		if dur <= 0 {
+			dur = 30 * time.Second
+		}
+		if dur > maxCPUProfileDuration {
+			return "", fmt.Errorf("CPU profile duration %s is longer than the maximum of %s", dur, maxCPUProfileDuration)
+		}
+	}
+
+	dir := g.Env.GetLogDir()
+	if err := os.MkdirAll(dir, libkb.PermDir); err != nil {
+		return "", err
+	}
+	filename := filepath.Join(dir, fmt.Sprintf("keybase.%s.%s.pprof", name, time.Now().Format("20060102T150405")))
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, libkb.PermFile)
+	if err != nil {
+		return "", err
+	}
+
+	g.Log.Info("Capturing %s profile to %s", name, filename)
+
+	if typ != keybase1.ProfileType_CPU {
+		defer f.Close()
+		if typ == keybase1.ProfileType_HEAP {
+			runtime.GC()
+		}
+		return filename, pprof.Lookup(name).WriteTo(f, 0)
+	}
+
+	// Fails if another CPU profile is still running.
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		os.Remove(filename)
+		return "", err
+	}
+	go func() {
+		time.Sleep(dur)
+		pprof.StopCPUProfile()
+		f.Close()
+		g.Log.Info("Wrote CPU profile to %s", filename)
+	}()
+	return filename, nil
+}
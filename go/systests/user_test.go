@@ -190,6 +190,14 @@ func (h *notifyHandler) UserChanged(_ context.Context, uid keybase1.UID) error {
 	return nil
 }
 
+func (h *notifyHandler) SigchainRollback(_ context.Context, arg keybase1.SigchainRollbackArg) error {
+	return nil
+}
+
+func (h *notifyHandler) TrackedUserReset(_ context.Context, arg keybase1.TrackedUserResetArg) error {
+	return nil
+}
+
 func TestSignupLogout(t *testing.T) {
 	tc := setupTest(t, "signup")
 	tc2 := cloneContext(tc)